@@ -1,11 +1,17 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/jessevdk/go-flags"
 	service_exporter "github.com/joshua-giumelli-deltatre/aws-service-quotas-exporter/pkg/service_exporter"
+	service_quotas "github.com/joshua-giumelli-deltatre/aws-service-quotas-exporter/pkg/service_quotas"
+	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	logging "github.com/sirupsen/logrus"
@@ -14,28 +20,311 @@ import (
 var log = logging.WithFields(logging.Fields{})
 
 var opts struct {
-	Port           int      `long:"port" short:"p" default:"9090" description:"Port on which to serve."`
-	Region         string   `long:"region" short:"r" env:"AWS_REGION" required:"true" description:"AWS region name"`
-	Profile        string   `long:"profile" short:"f" env:"AWS_PROFILE" default:"" description:"Named AWS profile to be used"`
-	RefreshPeriod  int      `long:"refresh-period" default:"300" description:"Refresh period in seconds"`
-	IncludeAWSTags []string `long:"include-aws-tag" description:"The aws resource tags to include as labels for returned metrics"`
+	Port                 int      `long:"port" short:"p" default:"9090" description:"Port on which to serve."`
+	Regions              []string `long:"region" short:"r" env:"AWS_REGION" env-delim:"," required:"true" description:"AWS region name. Can be a comma-separated list or be repeated to export the quotas of multiple regions"`
+	GlobalRegion         string   `long:"global-region" default:"us-east-1" description:"AWS region used for global services such as IAM, Route53 and CloudFront"`
+	ServiceRegions       []string `long:"service-region" description:"Retrieve the quotas and usage of a service in another region, as service=region (e.g. logs=us-east-1). Can be repeated"`
+	ForcedServices       []string `long:"force-service" description:"Run the usage checks of a service even in the regions where the AWS SDK does not list it as available. Can be repeated"`
+	ReportedUsage        bool     `long:"reported-usage" description:"Also export the usage reported by AWS through the CloudWatch usage metric of the quotas that have one"`
+	KMSCustomerKeysOnly  bool     `long:"kms-customer-managed-keys-only" description:"Describe every KMS key to only count the customer managed keys, otherwise the AWS managed keys are counted too"`
+	APIGatewayStages     bool     `long:"apigateway-stages-per-api" description:"Also export the stages of each API Gateway REST API, this gets the stages of every API"`
+	StrictRegion         bool     `long:"strict-region" description:"Fail the refreshes of the regions that are not enabled for the account instead of skipping them"`
+	Profile              string   `long:"profile" short:"f" env:"AWS_PROFILE" default:"" description:"Named AWS profile to be used"`
+	RefreshPeriod        int      `long:"refresh-period" default:"300" description:"Refresh period in seconds"`
+	NoCache              bool     `long:"no-cache" description:"Retrieve the quotas on each scrape instead of refreshing them every refresh period"`
+	ScrapeTimeout        int      `long:"scrape-timeout" default:"10" description:"Seconds a scrape waits for the quotas with --no-cache, 0 means no timeout"`
+	CheckTimeout         int      `long:"check-timeout" default:"0" description:"Seconds after which the AWS calls of a usage check are cancelled, 0 means no timeout"`
+	IncludeAWSTags       []string `long:"include-aws-tag" description:"The aws resource tags to include as labels for returned metrics"`
+	TagMapFile           string   `long:"tag-map-file" default:"" description:"JSON file mapping AWS tag keys to the label names used for them, the mapped tags are included as labels"`
+	AdjustableOnly       bool     `long:"adjustable-only" description:"Only export metrics for quotas that AWS marks as adjustable"`
+	StartupProbeScrape   bool     `long:"startup-probe-scrape" description:"Serve /ready with 503 until the first refresh succeeds and exit if it does not within --startup-probe-timeout"`
+	StartupProbeTimeout  int      `long:"startup-probe-timeout" default:"600" description:"Startup probe timeout in seconds"`
+	MaxStaleness         int      `long:"healthcheck-max-staleness" default:"0" description:"Seconds since the last completed refresh after which /ready is served with 503, 0 means twice the refresh period"`
+	TotalRulesPerSecGrp  bool     `long:"total-rules-per-security-group" description:"Also export the combined inbound and outbound rules per security group"`
+	MaxResources         int      `long:"max-resources-per-check" default:"0" description:"Stop paging resources in a check after this many resources, 0 means no limit"`
+	HoldEmptyRefreshes   int      `long:"hold-empty-refreshes" default:"0" description:"Number of consecutive refreshes reporting zero usage for which the previous non-zero usage is kept"`
+	ECRTaggedImagesOnly  bool     `long:"ecr-tagged-images-only" description:"Only count tagged images against the images per ECR repository quota"`
+	ZeroMetrics          bool     `long:"zero-metrics-at-startup" description:"Export zero-valued metrics for the known quotas until their first refresh"`
+	SampleInterval       int      `long:"sample-interval" default:"1" description:"Number of refreshes over which the checks calling AWS once per resource spread those calls"`
+	LogStreamsPerGroup   bool     `long:"log-streams-per-log-group" description:"Export the log streams of each CloudWatch Logs log group, this pages through every log stream"`
+	LogStreamsPrefix     string   `long:"log-streams-log-group-prefix" default:"" description:"Only export the log streams of the log groups whose names start with this prefix"`
+	KDAMaxPages          int      `long:"kda-max-pages" default:"100" description:"Stop paging the KDA applications after this many pages, 0 means no limit"`
+	KDAParallelismOffset int      `long:"kda-parallelism-offset" default:"1" description:"Added to the current parallelism of each KDA application to get its KPUs, AWS reports one less than what is billed. Set it to 0 to export the parallelism as reported"`
+	BestEffort           bool     `long:"best-effort" description:"Skip the usage checks that fail instead of failing the refresh"`
+	StaleTTL             int      `long:"stale-ttl" default:"0" description:"Seconds for which the last known usage of failed checks is exported and flagged as stale in best effort mode, 0 disables it"`
+	EC2SDKV2             bool     `long:"ec2-sdk-v2" description:"Use the AWS SDK for Go v2 for the EC2 usage checks"`
+	CacheBackend         string   `long:"cache-backend" default:"memory" choice:"memory" choice:"redis" description:"Keep the quotas in memory (memory) or share them between replicas through Redis (redis)"`
+	RedisAddress         string   `long:"redis-address" default:"localhost:6379" description:"Address of the Redis server used by --cache-backend=redis"`
+	RedisPassword        string   `long:"redis-password" env:"REDIS_PASSWORD" default:"" description:"Password of the Redis server used by --cache-backend=redis"`
+	RedisDB              int      `long:"redis-db" default:"0" description:"Redis database used by --cache-backend=redis"`
+	RedisKeyPrefix       string   `long:"redis-key-prefix" default:"aws-service-quotas-exporter" description:"Prefix of the Redis keys used by --cache-backend=redis"`
+	AlertSNSTopic        string   `long:"alert-sns-topic" default:"" description:"ARN of an SNS topic to publish a message to when a quota reaches --alert-threshold"`
+	AlertThreshold       float64  `long:"alert-threshold" default:"0.8" description:"Utilization ratio, usage divided by limit, from which quotas are published to --alert-sns-topic"`
+	ResourceIdentifier   string   `long:"resource-identifier" default:"id" choice:"id" choice:"arn" description:"Identify the resources of the per-resource quotas by ID or name (id) or by ARN where it can be built (arn)"`
+	AssumeRoleARNs       []string `long:"assume-role-arn" description:"Assume this role to export the quotas of its account, labelled with its account_id. Can be repeated"`
+	ExternalID           string   `long:"assume-role-external-id" default:"" description:"External ID passed when assuming the roles of --assume-role-arn"`
+	SelfTest             bool     `long:"selftest" description:"Validate the metric names and descriptions of the registered usage checks without calling AWS, then exit"`
+	MetricsGzip          string   `long:"metrics-gzip" default:"auto" choice:"auto" choice:"never" description:"Compress the metrics with gzip when the scraper accepts it (auto) or never (never)"`
+	MetricsMode          string   `long:"metrics-mode" default:"default" choice:"default" choice:"ratio" description:"Export the usage, limit and utilization ratio (default) or only the utilization ratio (ratio) of each quota"`
+	Namespace            string   `long:"namespace" default:"aws" description:"Namespace of the metric names"`
+	Subsystem            string   `long:"subsystem" default:"service_quota" description:"Subsystem of the metric names, the metrics are named <namespace>_<subsystem>_usage with the quota in a quota label"`
+	LegacyMetricNames    bool     `long:"legacy-metric-names" description:"Name the metrics of each quota after the quota, as aws_<quota>_used_total, ignoring --namespace and --subsystem"`
+	ReachedMargin        float64  `long:"reached-margin" default:"0" description:"How far below its limit the usage of a quota counts as reaching it in the quota reached metric"`
+	QuotaEndpoint        bool     `long:"quota-endpoint" description:"Serve /quota?code=<quota code>&resource=<resource> with the usage of a single resource of a per-resource quota, retrieved from AWS on each request"`
+	ConstLabels          []string `long:"const-label" description:"Constant label added to every metric, as name=value (e.g. environment=production). Can be repeated"`
+}
+
+// parseRegions returns the regions of --region, each of which can be a
+// comma-separated list of regions, or an error if one is empty
+func parseRegions(values []string) ([]string, error) {
+	regions := []string{}
+	for _, value := range values {
+		for _, region := range strings.Split(value, ",") {
+			region = strings.TrimSpace(region)
+			if region == "" {
+				return nil, fmt.Errorf("invalid regions %q, expected a comma-separated list of regions", value)
+			}
+			regions = append(regions, region)
+		}
+	}
+	return regions, nil
+}
+
+// parseServiceRegions parses the service=region pairs of
+// --service-region or returns an error
+func parseServiceRegions(values []string) (map[string]string, error) {
+	serviceRegions := map[string]string{}
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid service region %q, expected service=region", value)
+		}
+		serviceRegions[parts[0]] = parts[1]
+	}
+	return serviceRegions, nil
+}
+
+// metricsHandler returns the handler serving the metrics of the default
+// registry, compressed with gzip according to the --metrics-gzip
+// `mode`. Only the scrapers sending `Accept-Encoding: gzip`, such as
+// Prometheus, receive compressed metrics, unless the mode is never
+func metricsHandler(mode string) http.Handler {
+	return promhttp.InstrumentMetricHandler(
+		prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{
+			DisableCompression: mode == "never",
+		}),
+	)
+}
+
+// quotaHandler returns the handler serving as JSON the usage of the
+// resource of the `resource` query parameter for the quota of the
+// `code` query parameter, retrieved from AWS by `quotasExporter` on
+// each request
+func quotaHandler(quotasExporter *service_exporter.ServiceQuotasExporter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		quotaCode := r.URL.Query().Get("code")
+		resourceID := r.URL.Query().Get("resource")
+		if quotaCode == "" || resourceID == "" {
+			http.Error(w, "the code and resource query parameters are required", http.StatusBadRequest)
+			return
+		}
+
+		usage, err := quotasExporter.UsageForResource(r.Context(), quotaCode, resourceID)
+		switch {
+		case errors.Is(err, service_quotas.ErrInvalidQuota):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case errors.Is(err, service_quotas.ErrResourceNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		case err != nil:
+			log.Errorf("Failed to retrieve the usage of %s for quota %s: %s", resourceID, quotaCode, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(usage); err != nil {
+			log.Errorf("Failed to write the usage of %s for quota %s: %s", resourceID, quotaCode, err)
+		}
+	})
+}
+
+// selfTest logs the usage checks registered with `options` that would
+// export invalid metrics and returns the exit code
+func selfTest(options service_quotas.Options) int {
+	failures, err := service_quotas.SelfTest(options)
+	if err != nil {
+		log.Errorf("Failed to run the self-test: %s", err)
+		return 1
+	}
+	for _, failure := range failures {
+		log.Error(failure)
+	}
+	if len(failures) > 0 {
+		log.Errorf("Self-test failed for %d usages", len(failures))
+		return 1
+	}
+	log.Info("Self-test passed")
+	return 0
 }
 
 func main() {
 	flags.Parse(&opts)
-	quotasExporter, err := service_exporter.NewServiceQuotasExporter(opts.Region, opts.Profile, opts.RefreshPeriod, opts.IncludeAWSTags)
+	regions, err := parseRegions(opts.Regions)
 	if err != nil {
-		log.Fatalf("Failed to create exporter: %s", err)
+		log.Fatalf("Failed to parse regions: %s", err)
+	}
+	serviceRegions, err := parseServiceRegions(opts.ServiceRegions)
+	if err != nil {
+		log.Fatalf("Failed to parse service regions: %s", err)
+	}
+
+	tagLabels := map[string]string{}
+	if opts.TagMapFile != "" {
+		tagLabels, err = service_exporter.ReadTagMapFile(opts.TagMapFile)
+		if err != nil {
+			log.Fatalf("Failed to read tag map file: %s", err)
+		}
+	}
+
+	includeAWSTags := append([]string{}, opts.IncludeAWSTags...)
+	for tag := range tagLabels {
+		includeAWSTags = append(includeAWSTags, tag)
+	}
+
+	quotasOptions := service_quotas.Options{
+		TotalRulesPerSecurityGroup: opts.TotalRulesPerSecGrp,
+		MaxResourcesPerCheck:       opts.MaxResources,
+		GlobalRegion:               opts.GlobalRegion,
+		SampleInterval:             opts.SampleInterval,
+		LogStreamsPerLogGroup:      opts.LogStreamsPerGroup,
+		LogStreamsLogGroupPrefix:   opts.LogStreamsPrefix,
+		KDAMaxPages:                opts.KDAMaxPages,
+		KDAParallelismOffset:       &opts.KDAParallelismOffset,
+		ServiceRegions:             serviceRegions,
+		ForcedServices:             opts.ForcedServices,
+		ReportedUsage:              opts.ReportedUsage,
+		KMSCustomerManagedKeysOnly: opts.KMSCustomerKeysOnly,
+		APIGatewayStagesPerAPI:     opts.APIGatewayStages,
+		StrictRegion:               opts.StrictRegion,
+		EC2SDKV2:                   opts.EC2SDKV2,
+		BestEffort:                 opts.BestEffort,
+		ECRTaggedImagesOnly:        opts.ECRTaggedImagesOnly,
+		IncludeAWSTags:             includeAWSTags,
+		ResourceIdentifier:         opts.ResourceIdentifier,
+		CheckTimeout:               time.Duration(opts.CheckTimeout) * time.Second,
 	}
 
-	prometheus.Register(quotasExporter)
+	if opts.SelfTest {
+		os.Exit(selfTest(quotasOptions))
+	}
+
+	cacheOptions := service_exporter.CacheOptions{
+		Backend:        opts.CacheBackend,
+		RedisAddress:   opts.RedisAddress,
+		RedisPassword:  opts.RedisPassword,
+		RedisDB:        opts.RedisDB,
+		RedisKeyPrefix: opts.RedisKeyPrefix,
+	}
+
+	alertOptions := service_exporter.AlertOptions{
+		SNSTopicARN: opts.AlertSNSTopic,
+		Threshold:   opts.AlertThreshold,
+	}
+
+	constLabels, err := service_exporter.ParseConstLabels(opts.ConstLabels)
+	if err != nil {
+		log.Fatalf("Failed to parse constant labels: %s", err)
+	}
+	metricOptions := service_exporter.MetricOptions{
+		Namespace:     opts.Namespace,
+		Subsystem:     opts.Subsystem,
+		LegacyNames:   opts.LegacyMetricNames,
+		ConstLabels:   constLabels,
+		ReachedMargin: opts.ReachedMargin,
+	}
+
+	accountOptions := service_exporter.AccountOptions{
+		RoleARNs:   opts.AssumeRoleARNs,
+		ExternalID: opts.ExternalID,
+	}
+
+	quotasExporter, err := service_exporter.NewServiceQuotasExporter(service_exporter.ExporterOptions{
+		Regions:              regions,
+		Profile:              opts.Profile,
+		RefreshPeriod:        opts.RefreshPeriod,
+		IncludedAWSTags:      opts.IncludeAWSTags,
+		TagLabels:            tagLabels,
+		AdjustableOnly:       opts.AdjustableOnly,
+		MetricsMode:          opts.MetricsMode,
+		EmptyRefreshesToHold: opts.HoldEmptyRefreshes,
+		ZeroMetricsAtStartup: opts.ZeroMetrics,
+		StaleTTL:             time.Duration(opts.StaleTTL) * time.Second,
+		NoCache:              opts.NoCache,
+		ScrapeTimeout:        time.Duration(opts.ScrapeTimeout) * time.Second,
+		Quotas:               quotasOptions,
+		Cache:                cacheOptions,
+		Alert:                alertOptions,
+		Account:              accountOptions,
+		Metric:               metricOptions,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create exporter: %s", err)
+	}
 
 	log.Infof("Serving on port: %d", opts.Port)
 	log.Infof("Serving Prometheus metrics on /metrics")
-	http.Handle("/metrics", promhttp.Handler())
+	http.Handle("/metrics", metricsHandler(opts.MetricsGzip))
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, "OK")
 	})
+	if opts.QuotaEndpoint {
+		log.Infof("Serving the usage of single resources on /quota")
+		http.Handle("/quota", quotaHandler(quotasExporter))
+	}
+	maxStaleness := time.Duration(opts.MaxStaleness) * time.Second
+	if maxStaleness == 0 {
+		maxStaleness = 2 * time.Duration(opts.RefreshPeriod) * time.Second
+	}
+	http.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+		if !quotasExporter.Ready() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "NOT READY")
+			return
+		}
+		if quotasExporter.Stale(maxStaleness) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "STALE")
+			return
+		}
+		fmt.Fprintf(w, "OK")
+	})
+
+	if !opts.StartupProbeScrape {
+		prometheus.Register(quotasExporter)
+		log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", opts.Port), nil))
+	}
+
+	// Serve before the first refresh has completed so that /ready
+	// can report the exporter as not ready in the meantime
+	go func() {
+		log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", opts.Port), nil))
+	}()
+
+	// The zero-valued metrics are exported until the first refresh
+	// has completed
+	if opts.ZeroMetrics {
+		prometheus.Register(quotasExporter)
+	}
+	err = quotasExporter.WaitUntilReady(time.Duration(opts.StartupProbeTimeout) * time.Second)
+	if err != nil {
+		log.Fatalf("Failed to retrieve the initial quotas: %s", err)
+	}
+	if !opts.ZeroMetrics {
+		prometheus.Register(quotasExporter)
+	}
 
-	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", opts.Port), nil))
+	select {}
 }
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+// registerLargeGauge registers a gauge with enough series in the
+// default registry for its exposition to be worth compressing
+func registerLargeGauge(t *testing.T) {
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_metrics_handler_gauge", Help: "Gauge of the metrics handler tests"}, []string{"resource"})
+	for i := 0; i < 1000; i++ {
+		gauge.WithLabelValues("resource-" + strconv.Itoa(i)).Set(float64(i))
+	}
+	if err := prometheus.DefaultRegisterer.Register(gauge); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { prometheus.DefaultRegisterer.Unregister(gauge) })
+}
+
+func TestMetricsHandler(t *testing.T) {
+	registerLargeGauge(t)
+
+	testCases := []struct {
+		name         string
+		mode         string
+		acceptGzip   bool
+		expectedGzip bool
+	}{
+		{"AutoWithGzip", "auto", true, true},
+		{"AutoWithoutGzip", "auto", false, false},
+		{"NeverWithGzip", "never", true, false},
+		{"NeverWithoutGzip", "never", false, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(metricsHandler(tc.mode))
+			defer server.Close()
+
+			request, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.acceptGzip {
+				request.Header.Set("Accept-Encoding", "gzip")
+			}
+			// the transport would otherwise request and decompress gzip
+			// transparently
+			client := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+			response, err := client.Do(request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer response.Body.Close()
+
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			body := response.Body
+			if tc.expectedGzip {
+				assert.Equal(t, "gzip", response.Header.Get("Content-Encoding"))
+				body, err = gzip.NewReader(response.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				assert.Empty(t, response.Header.Get("Content-Encoding"))
+			}
+			metrics, err := ioutil.ReadAll(body)
+
+			assert.NoError(t, err)
+			assert.Contains(t, string(metrics), `test_metrics_handler_gauge{resource="resource-999"} 999`)
+		})
+	}
+}
@@ -3,10 +3,15 @@ module github.com/joshua-giumelli-deltatre/aws-service-quotas-exporter
 go 1.16
 
 require (
-	github.com/aws/aws-sdk-go v1.40.37
+	github.com/aws/aws-sdk-go v1.44.122
+	github.com/aws/aws-sdk-go-v2 v1.17.1
+	github.com/aws/aws-sdk-go-v2/service/ec2 v1.70.0
+	github.com/aws/smithy-go v1.13.4
+	github.com/go-redis/redis/v8 v8.11.4
 	github.com/jessevdk/go-flags v1.5.0
 	github.com/pkg/errors v0.9.1
 	github.com/prometheus/client_golang v1.11.0
+	github.com/prometheus/client_model v0.2.0
 	github.com/sirupsen/logrus v1.8.1
 	github.com/stretchr/testify v1.7.0
 )
@@ -0,0 +1,131 @@
+package serviceexporter
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+	service_quotas "github.com/joshua-giumelli-deltatre/aws-service-quotas-exporter/pkg/service_quotas"
+)
+
+// AlertOptions configures the alerts published when quotas cross a
+// utilization threshold. No alerts are published when SNSTopicARN is
+// empty
+type AlertOptions struct {
+	// SNSTopicARN is the SNS topic the alerts are published to
+	SNSTopicARN string
+	// Threshold is the utilization ratio, usage divided by limit, from
+	// which a quota is alerted on
+	Threshold float64
+}
+
+// snsAlerter publishes a message to an SNS topic when the utilization
+// of a quota reaches the threshold. A quota is only alerted on again
+// once a refresh has returned it below the threshold, so that refreshes
+// do not publish a message each time while it stays above
+type snsAlerter struct {
+	client    snsiface.SNSAPI
+	topicARN  string
+	threshold float64
+
+	mutex sync.Mutex
+	// alerting holds the metric keys of the quotas at or above the
+	// threshold that have been alerted on
+	alerting map[string]bool
+}
+
+// newSNSAlerter creates an snsAlerter publishing to the topic of
+// `options` with the credentials of `profile`
+func newSNSAlerter(region, profile string, options AlertOptions) (*snsAlerter, error) {
+	opts := session.Options{}
+	if profile != "" {
+		opts = session.Options{
+			Profile:                 profile,
+			AssumeRoleTokenProvider: stscreds.StdinTokenProvider,
+			SharedConfigState:       session.SharedConfigEnable,
+		}
+	}
+
+	awsSession, err := session.NewSessionWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	client := sns.New(awsSession, aws.NewConfig().WithRegion(region))
+	return newSNSAlerterWithClient(client, options), nil
+}
+
+func newSNSAlerterWithClient(client snsiface.SNSAPI, options AlertOptions) *snsAlerter {
+	return &snsAlerter{
+		client:    client,
+		topicARN:  options.SNSTopicARN,
+		threshold: options.Threshold,
+		alerting:  map[string]bool{},
+	}
+}
+
+// notify publishes an alert for each of `quotas` that reached the
+// threshold since the previous refresh. The quotas missing from a
+// `partial` refresh keep their alerting state, as their usage is not
+// known. Failing to publish an alert is logged and retried on the next
+// refresh
+func (a *snsAlerter) notify(quotas []service_quotas.QuotaUsage, partial bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	refreshed := map[string]bool{}
+	for _, quota := range quotas {
+		key := metricKey(quota)
+		refreshed[key] = true
+		if quota.Quota == 0 || quota.Usage/quota.Quota < a.threshold {
+			delete(a.alerting, key)
+			continue
+		}
+		if a.alerting[key] {
+			continue
+		}
+
+		if err := a.publish(quota); err != nil {
+			log.Errorf("Failed to publish the alert of %s for resource (%s): %s", quota.Name, quota.Identifier(), err)
+			continue
+		}
+		a.alerting[key] = true
+	}
+
+	if partial {
+		return
+	}
+	for key := range a.alerting {
+		if !refreshed[key] {
+			delete(a.alerting, key)
+		}
+	}
+}
+
+func (a *snsAlerter) publish(quota service_quotas.QuotaUsage) error {
+	utilization := quota.Usage / quota.Quota * 100
+	subject := fmt.Sprintf("AWS quota %s at %.0f%%", quota.Name, utilization)
+	message := fmt.Sprintf(
+		"The usage of %s for resource (%s) in region %s is %v of %v (%.1f%%), above the alert threshold of %.1f%%",
+		quota.Description, quota.Identifier(), quota.Region, quota.Usage, quota.Quota, utilization, a.threshold*100,
+	)
+
+	_, err := a.client.Publish(&sns.PublishInput{
+		TopicArn: aws.String(a.topicARN),
+		// SNS rejects subjects longer than 100 characters
+		Subject: aws.String(truncate(subject, 100)),
+		Message: aws.String(message),
+	})
+	return err
+}
+
+func truncate(s string, length int) string {
+	if len(s) <= length {
+		return s
+	}
+	return s[:length]
+}
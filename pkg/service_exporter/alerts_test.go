@@ -0,0 +1,99 @@
+package serviceexporter
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	service_quotas "github.com/joshua-giumelli-deltatre/aws-service-quotas-exporter/pkg/service_quotas"
+)
+
+type mockSNSClient struct {
+	snsiface.SNSAPI
+
+	err       error
+	published []*sns.PublishInput
+}
+
+func (m *mockSNSClient) Publish(input *sns.PublishInput) (*sns.PublishOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.published = append(m.published, input)
+	return &sns.PublishOutput{}, nil
+}
+
+func TestSNSAlerterNotify(t *testing.T) {
+	client := &mockSNSClient{}
+	alerter := newSNSAlerterWithClient(client, AlertOptions{SNSTopicARN: "arn:aws:sns:eu-west-1:123456789012:quotas", Threshold: 0.8})
+
+	quotas := []service_quotas.QuotaUsage{
+		{Name: "some_quota", Description: "some quota", Region: "eu-west-1", Usage: 9, Quota: 10},
+		{Name: "other_quota", Description: "other quota", Region: "eu-west-1", Usage: 1, Quota: 10},
+		{Name: "unlimited_quota", Description: "unlimited quota", Region: "eu-west-1", Usage: 5},
+	}
+	alerter.notify(quotas, false)
+
+	assert.Len(t, client.published, 1)
+	assert.Equal(t, "arn:aws:sns:eu-west-1:123456789012:quotas", aws.StringValue(client.published[0].TopicArn))
+	assert.Equal(t, "AWS quota some_quota at 90%", aws.StringValue(client.published[0].Subject))
+	assert.Contains(t, aws.StringValue(client.published[0].Message), "some quota for resource (some_quota) in region eu-west-1 is 9 of 10")
+}
+
+func TestSNSAlerterNotifyDebounce(t *testing.T) {
+	client := &mockSNSClient{}
+	alerter := newSNSAlerterWithClient(client, AlertOptions{SNSTopicARN: "topic", Threshold: 0.8})
+	above := []service_quotas.QuotaUsage{{Name: "some_quota", Usage: 9, Quota: 10}}
+	below := []service_quotas.QuotaUsage{{Name: "some_quota", Usage: 2, Quota: 10}}
+
+	alerter.notify(above, false)
+	alerter.notify(above, false)
+	assert.Len(t, client.published, 1)
+
+	// A partial refresh without the quota keeps it alerted on
+	alerter.notify([]service_quotas.QuotaUsage{}, true)
+	alerter.notify(above, false)
+	assert.Len(t, client.published, 1)
+
+	alerter.notify(below, false)
+	alerter.notify(above, false)
+	assert.Len(t, client.published, 2)
+
+	// A complete refresh without the quota resets it
+	alerter.notify([]service_quotas.QuotaUsage{}, false)
+	alerter.notify(above, false)
+	assert.Len(t, client.published, 3)
+}
+
+func TestSNSAlerterNotifyRetriesFailedPublish(t *testing.T) {
+	client := &mockSNSClient{err: errors.New("some err")}
+	alerter := newSNSAlerterWithClient(client, AlertOptions{SNSTopicARN: "topic", Threshold: 0.8})
+	above := []service_quotas.QuotaUsage{{Name: "some_quota", Usage: 9, Quota: 10}}
+
+	alerter.notify(above, false)
+	assert.Empty(t, client.published)
+
+	client.err = nil
+	alerter.notify(above, false)
+	assert.Len(t, client.published, 1)
+}
+
+func TestAlertSkipsNonAdjustableQuotas(t *testing.T) {
+	client := &mockSNSClient{}
+	exporter := &ServiceQuotasExporter{
+		adjustableOnly: true,
+		alerter:        newSNSAlerterWithClient(client, AlertOptions{SNSTopicARN: "topic", Threshold: 0.8}),
+	}
+
+	exporter.alert([]service_quotas.QuotaUsage{
+		{Name: "some_quota", Usage: 9, Quota: 10, Adjustable: true},
+		{Name: "other_quota", Usage: 9, Quota: 10},
+	}, false)
+
+	assert.Len(t, client.published, 1)
+	assert.Equal(t, "AWS quota some_quota at 90%", aws.StringValue(client.published[0].Subject))
+}
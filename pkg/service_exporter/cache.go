@@ -0,0 +1,211 @@
+package serviceexporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	service_quotas "github.com/joshua-giumelli-deltatre/aws-service-quotas-exporter/pkg/service_quotas"
+	"github.com/pkg/errors"
+)
+
+// Cache backends of the exporter
+const (
+	// CacheBackendMemory keeps the quotas and usage of each replica in
+	// its own memory
+	CacheBackendMemory = "memory"
+	// CacheBackendRedis shares the quotas and usage retrieved by one
+	// replica with the other replicas through Redis
+	CacheBackendRedis = "redis"
+)
+
+// CacheOptions configures where the exporter caches the quotas and
+// usage. Only the Redis backend uses the Redis options
+type CacheOptions struct {
+	// Backend is either CacheBackendMemory (the default when empty) or
+	// CacheBackendRedis
+	Backend       string
+	RedisAddress  string
+	RedisPassword string
+	RedisDB       int
+	// RedisKeyPrefix is prepended to the keys of the leader lock and
+	// the cached usage, together with the region
+	RedisKeyPrefix string
+}
+
+// refreshLeaderScript takes the leader lock `KEYS[1]` for the replica
+// `ARGV[1]` for `ARGV[2]` milliseconds if it is free, or extends it if
+// the replica already holds it. It returns 1 when the replica holds
+// the lock
+const refreshLeaderScript = `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return 1
+end
+return 0
+`
+
+// redisClient is the subset of the Redis client used by the shared
+// cache
+type redisClient interface {
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) *redis.Cmd
+	Get(ctx context.Context, key string) *redis.StringCmd
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+}
+
+// cachePollInterval is how often a replica that is not the leader
+// checks whether the leader has cached the quotas and usage
+const cachePollInterval = 5 * time.Second
+
+// redisQuotas shares the quotas and usage of `quotasClient` between
+// replicas. The replica holding the leader lock retrieves them from AWS
+// and stores them in Redis, the other replicas read them from Redis.
+// The other replicas wait for the leader until it has cached them, and
+// a replica retrieves them from AWS itself when Redis is unavailable
+type redisQuotas struct {
+	quotasClient service_quotas.QuotasInterface
+	client       redisClient
+	replicaID    string
+	leaderKey    string
+	usageKey     string
+	// ttl is how long the leader lock and the cached usage are kept
+	// without being refreshed
+	ttl time.Duration
+	// pollInterval is how often the cache is read while the leader has
+	// not cached the quotas and usage yet
+	pollInterval time.Duration
+}
+
+// newRedisQuotas creates a redisQuotas with keys for `region`. The
+// leader lock and cached usage expire after three refresh periods, so
+// another replica takes over when the leader stops refreshing
+func newRedisQuotas(quotasClient service_quotas.QuotasInterface, client redisClient, keyPrefix, region string, refreshPeriod int) *redisQuotas {
+	hostname, _ := os.Hostname()
+	return &redisQuotas{
+		quotasClient: quotasClient,
+		client:       client,
+		replicaID:    fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		leaderKey:    fmt.Sprintf("%s:%s:leader", keyPrefix, region),
+		usageKey:     fmt.Sprintf("%s:%s:usage", keyPrefix, region),
+		ttl:          3 * time.Duration(refreshPeriod) * time.Second,
+		pollInterval: cachePollInterval,
+	}
+}
+
+// QuotasAndUsage retrieves the quotas and usage from AWS if the replica
+// is the leader and from Redis otherwise
+func (q *redisQuotas) QuotasAndUsage() ([]service_quotas.QuotaUsage, error) {
+	return q.QuotasAndUsageWithContext(context.Background())
+}
+
+// QuotasAndUsageWithContext implements the ContextQuotasInterface
+// interface. A replica that is not the leader reads the cache every
+// poll interval until the leader has cached the quotas and usage, the
+// leader lock expires and the replica takes it over or `ctx` is done
+func (q *redisQuotas) QuotasAndUsageWithContext(ctx context.Context) ([]service_quotas.QuotaUsage, error) {
+	for {
+		leader, err := q.client.Eval(ctx, refreshLeaderScript, []string{q.leaderKey}, q.replicaID, q.ttl.Milliseconds()).Int()
+		if err != nil {
+			log.Warnf("Failed to refresh the leader lock, retrieving quotas from AWS: %s", err)
+			return q.retrieve(ctx)
+		}
+
+		if leader == 1 {
+			return q.refreshCache(ctx)
+		}
+
+		cached, err := q.client.Get(ctx, q.usageKey).Bytes()
+		if err == redis.Nil {
+			log.Infof("No quotas cached by the leader yet, retrying in %s", q.pollInterval)
+			select {
+			case <-ctx.Done():
+				return nil, errors.Wrap(ctx.Err(), "failed to wait for the quotas cached by the leader")
+			case <-time.After(q.pollInterval):
+			}
+			continue
+		}
+		if err != nil {
+			log.Warnf("Failed to read the cached quotas, retrieving quotas from AWS: %s", err)
+			return q.retrieve(ctx)
+		}
+
+		quotas := []service_quotas.QuotaUsage{}
+		if err := json.Unmarshal(cached, &quotas); err != nil {
+			log.Warnf("Failed to decode the cached quotas, retrieving quotas from AWS: %s", err)
+			return q.retrieve(ctx)
+		}
+		return quotas, nil
+	}
+}
+
+// retrieve retrieves the quotas and usage from AWS, bounded by `ctx`
+// if the quotas client supports it
+func (q *redisQuotas) retrieve(ctx context.Context) ([]service_quotas.QuotaUsage, error) {
+	if client, ok := q.quotasClient.(service_quotas.ContextQuotasInterface); ok {
+		return client.QuotasAndUsageWithContext(ctx)
+	}
+	return q.quotasClient.QuotasAndUsage()
+}
+
+// refreshCache retrieves the quotas and usage from AWS and stores them
+// in Redis for the other replicas, including partial usages in best
+// effort mode. Failing to store them is not an error, the other
+// replicas retrieve them from AWS instead
+func (q *redisQuotas) refreshCache(ctx context.Context) ([]service_quotas.QuotaUsage, error) {
+	quotas, usageErr := q.retrieve(ctx)
+	if usageErr != nil && !errors.Is(usageErr, service_quotas.ErrPartialUsage) {
+		return nil, usageErr
+	}
+
+	encoded, err := json.Marshal(quotas)
+	if err != nil {
+		log.Warnf("Failed to encode the quotas for the cache: %s", err)
+		return quotas, usageErr
+	}
+	if err := q.client.Set(ctx, q.usageKey, encoded, q.ttl).Err(); err != nil {
+		log.Warnf("Failed to cache the quotas: %s", err)
+	}
+	return quotas, usageErr
+}
+
+// UsageForResource implements the ResourceQuotasInterface interface.
+// The usage of a single resource is always retrieved from AWS
+func (q *redisQuotas) UsageForResource(ctx context.Context, quotaCode, resourceID string) (*service_quotas.QuotaUsage, error) {
+	client, ok := q.quotasClient.(service_quotas.ResourceQuotasInterface)
+	if !ok {
+		return nil, ErrResourceUsageNotSupported
+	}
+	return client.UsageForResource(ctx, quotaCode, resourceID)
+}
+
+// RegisteredChecks implements the CoverageQuotasInterface interface,
+// it returns 0 if the quotas client does not report its coverage
+func (q *redisQuotas) RegisteredChecks() int {
+	client, ok := q.quotasClient.(service_quotas.CoverageQuotasInterface)
+	if !ok {
+		return 0
+	}
+	return client.RegisteredChecks()
+}
+
+// AvailableQuotas implements the CoverageQuotasInterface interface.
+// The quotas are only listed by the replicas retrieving them from AWS,
+// so it is empty on the other replicas
+func (q *redisQuotas) AvailableQuotas() map[string]int {
+	client, ok := q.quotasClient.(service_quotas.CoverageQuotasInterface)
+	if !ok {
+		return map[string]int{}
+	}
+	return client.AvailableQuotas()
+}
+
+// DescribeQuotas implements the QuotasInterface, it does not call AWS
+// so it is not cached
+func (q *redisQuotas) DescribeQuotas() []service_quotas.QuotaUsage {
+	return q.quotasClient.DescribeQuotas()
+}
@@ -0,0 +1,241 @@
+package serviceexporter
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	service_quotas "github.com/joshua-giumelli-deltatre/aws-service-quotas-exporter/pkg/service_quotas"
+)
+
+type mockRedisClient struct {
+	leader   int
+	evalErr  error
+	evalArgs []interface{}
+	// leaderAfter is the number of calls to Eval after which the
+	// replica takes the leader lock over, it is ignored when 0
+	leaderAfter int
+	evals       int
+	cached      []byte
+	// pending is the number of calls to Get returning no cached quotas
+	// before those of cached
+	pending   int
+	gets      int
+	getErr    error
+	setKey    string
+	setValue  interface{}
+	setExpiry time.Duration
+}
+
+func (m *mockRedisClient) Eval(ctx context.Context, script string, keys []string, args ...interface{}) *redis.Cmd {
+	m.evalArgs = append(append([]interface{}{}, keys[0]), args...)
+	m.evals++
+	if m.leaderAfter > 0 && m.evals > m.leaderAfter {
+		return redis.NewCmdResult(int64(1), m.evalErr)
+	}
+	return redis.NewCmdResult(int64(m.leader), m.evalErr)
+}
+
+func (m *mockRedisClient) Get(ctx context.Context, key string) *redis.StringCmd {
+	m.gets++
+	if (m.cached == nil || m.gets <= m.pending) && m.getErr == nil {
+		return redis.NewStringResult("", redis.Nil)
+	}
+	return redis.NewStringResult(string(m.cached), m.getErr)
+}
+
+type coverageServiceQuotasMock struct {
+	ServiceQuotasMock
+}
+
+func (s *coverageServiceQuotasMock) RegisteredChecks() int {
+	return 3
+}
+
+func (s *coverageServiceQuotasMock) AvailableQuotas() map[string]int {
+	return map[string]int{"ec2": 2}
+}
+
+type contextServiceQuotasMock struct {
+	ServiceQuotasMock
+	ctx context.Context
+}
+
+func (s *contextServiceQuotasMock) QuotasAndUsageWithContext(ctx context.Context) ([]service_quotas.QuotaUsage, error) {
+	s.ctx = ctx
+	return s.quotas, s.err
+}
+
+func (m *mockRedisClient) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd {
+	m.setKey = key
+	m.setValue = value
+	m.setExpiry = expiration
+	return redis.NewStatusResult("OK", nil)
+}
+
+func TestRedisQuotasLeader(t *testing.T) {
+	quotas := []service_quotas.QuotaUsage{{Name: "quota", Usage: 5, Quota: 10}}
+	quotasClient := &ServiceQuotasMock{quotas: quotas}
+	redisClient := &mockRedisClient{leader: 1}
+
+	cache := newRedisQuotas(quotasClient, redisClient, "prefix", "eu-west-1", 60)
+	cache.replicaID = "replica1"
+	usage, err := cache.QuotasAndUsage()
+
+	assert.NoError(t, err)
+	assert.Equal(t, quotas, usage)
+	assert.Equal(t, []interface{}{"prefix:eu-west-1:leader", "replica1", int64(180000)}, redisClient.evalArgs)
+	assert.Equal(t, "prefix:eu-west-1:usage", redisClient.setKey)
+	assert.Equal(t, 180*time.Second, redisClient.setExpiry)
+
+	cached := []service_quotas.QuotaUsage{}
+	assert.NoError(t, json.Unmarshal(redisClient.setValue.([]byte), &cached))
+	assert.Equal(t, quotas, cached)
+}
+
+func TestRedisQuotasLeaderWithError(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{err: errors.New("some err")}
+	redisClient := &mockRedisClient{leader: 1}
+
+	cache := newRedisQuotas(quotasClient, redisClient, "prefix", "eu-west-1", 60)
+	usage, err := cache.QuotasAndUsage()
+
+	assert.Error(t, err)
+	assert.Nil(t, usage)
+	assert.Nil(t, redisClient.setValue)
+}
+
+func TestRedisQuotasLeaderWithPartialUsage(t *testing.T) {
+	quotas := []service_quotas.QuotaUsage{{Name: "quota", Usage: 5, Quota: 10}}
+	quotasClient := &ServiceQuotasMock{quotas: quotas, err: errors.Wrapf(service_quotas.ErrPartialUsage, "usage checks failed for ec2")}
+	redisClient := &mockRedisClient{leader: 1}
+
+	cache := newRedisQuotas(quotasClient, redisClient, "prefix", "eu-west-1", 60)
+	usage, err := cache.QuotasAndUsage()
+
+	assert.True(t, errors.Is(err, service_quotas.ErrPartialUsage))
+	assert.Equal(t, quotas, usage)
+	assert.NotNil(t, redisClient.setValue)
+}
+
+func TestRedisQuotasFollower(t *testing.T) {
+	cachedQuotas := []service_quotas.QuotaUsage{{Name: "quota", ResourceName: resourceName("i-asdasd1"), Usage: 5, Quota: 10}}
+	encoded, _ := json.Marshal(cachedQuotas)
+	quotasClient := &ServiceQuotasMock{quotas: []service_quotas.QuotaUsage{{Name: "quota", Usage: 1}}}
+	redisClient := &mockRedisClient{leader: 0, cached: encoded}
+
+	cache := newRedisQuotas(quotasClient, redisClient, "prefix", "eu-west-1", 60)
+	usage, err := cache.QuotasAndUsage()
+
+	assert.NoError(t, err)
+	assert.Equal(t, cachedQuotas, usage)
+	assert.Nil(t, redisClient.setValue)
+}
+
+func TestRedisQuotasFollowerWaitsForCachedQuotas(t *testing.T) {
+	cachedQuotas := []service_quotas.QuotaUsage{{Name: "quota", Usage: 5, Quota: 10}}
+	encoded, _ := json.Marshal(cachedQuotas)
+	quotasClient := &ServiceQuotasMock{err: errors.New("AWS should not be called")}
+	redisClient := &mockRedisClient{leader: 0, cached: encoded, pending: 2}
+
+	cache := newRedisQuotas(quotasClient, redisClient, "prefix", "eu-west-1", 60)
+	cache.pollInterval = time.Millisecond
+	usage, err := cache.QuotasAndUsage()
+
+	assert.NoError(t, err)
+	assert.Equal(t, cachedQuotas, usage)
+	assert.Equal(t, 3, redisClient.gets)
+}
+
+func TestRedisQuotasFollowerTakesOverLeaderLock(t *testing.T) {
+	quotas := []service_quotas.QuotaUsage{{Name: "quota", Usage: 1}}
+	quotasClient := &ServiceQuotasMock{quotas: quotas}
+	redisClient := &mockRedisClient{leader: 0, leaderAfter: 2}
+
+	cache := newRedisQuotas(quotasClient, redisClient, "prefix", "eu-west-1", 60)
+	cache.pollInterval = time.Millisecond
+	usage, err := cache.QuotasAndUsage()
+
+	assert.NoError(t, err)
+	assert.Equal(t, quotas, usage)
+	assert.Equal(t, 2, redisClient.gets)
+	assert.NotNil(t, redisClient.setValue)
+}
+
+func TestRedisQuotasFollowerWaitTimeout(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{quotas: []service_quotas.QuotaUsage{{Name: "quota", Usage: 1}}}
+	redisClient := &mockRedisClient{leader: 0}
+
+	cache := newRedisQuotas(quotasClient, redisClient, "prefix", "eu-west-1", 60)
+	cache.pollInterval = time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	usage, err := cache.QuotasAndUsageWithContext(ctx)
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Nil(t, usage)
+}
+
+func TestRedisQuotasLeaderWithContext(t *testing.T) {
+	quotasClient := &contextServiceQuotasMock{ServiceQuotasMock: ServiceQuotasMock{quotas: []service_quotas.QuotaUsage{{Name: "quota", Usage: 1}}}}
+	redisClient := &mockRedisClient{leader: 1}
+
+	cache := newRedisQuotas(quotasClient, redisClient, "prefix", "eu-west-1", 60)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err := cache.QuotasAndUsageWithContext(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ctx, quotasClient.ctx)
+}
+
+func TestRedisQuotasCoverage(t *testing.T) {
+	cache := newRedisQuotas(&coverageServiceQuotasMock{}, &mockRedisClient{}, "prefix", "eu-west-1", 60)
+
+	assert.Equal(t, 3, cache.RegisteredChecks())
+	assert.Equal(t, map[string]int{"ec2": 2}, cache.AvailableQuotas())
+
+	cache = newRedisQuotas(&ServiceQuotasMock{}, &mockRedisClient{}, "prefix", "eu-west-1", 60)
+
+	assert.Equal(t, 0, cache.RegisteredChecks())
+	assert.Empty(t, cache.AvailableQuotas())
+}
+
+func TestRedisQuotasFollowerWithCacheError(t *testing.T) {
+	quotas := []service_quotas.QuotaUsage{{Name: "quota", Usage: 1}}
+	quotasClient := &ServiceQuotasMock{quotas: quotas}
+	redisClient := &mockRedisClient{leader: 0, getErr: errors.New("connection refused")}
+
+	cache := newRedisQuotas(quotasClient, redisClient, "prefix", "eu-west-1", 60)
+	usage, err := cache.QuotasAndUsage()
+
+	assert.NoError(t, err)
+	assert.Equal(t, quotas, usage)
+}
+
+func TestRedisQuotasWithLeaderLockError(t *testing.T) {
+	quotas := []service_quotas.QuotaUsage{{Name: "quota", Usage: 1}}
+	quotasClient := &ServiceQuotasMock{quotas: quotas}
+	redisClient := &mockRedisClient{evalErr: errors.New("connection refused")}
+
+	cache := newRedisQuotas(quotasClient, redisClient, "prefix", "eu-west-1", 60)
+	usage, err := cache.QuotasAndUsage()
+
+	assert.NoError(t, err)
+	assert.Equal(t, quotas, usage)
+	assert.Nil(t, redisClient.setValue)
+}
+
+func TestRedisQuotasDescribeQuotas(t *testing.T) {
+	described := []service_quotas.QuotaUsage{{Name: "quota"}}
+	quotasClient := &ServiceQuotasMock{describedQuotas: described}
+
+	cache := newRedisQuotas(quotasClient, &mockRedisClient{}, "prefix", "eu-west-1", 60)
+
+	assert.Equal(t, described, cache.DescribeQuotas())
+}
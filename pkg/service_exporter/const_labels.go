@@ -0,0 +1,60 @@
+package serviceexporter
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrInvalidConstLabel is returned when a constant label can not be
+// used
+var ErrInvalidConstLabel = errors.New("invalid constant label")
+
+// ParseConstLabels parses the name=value pairs of the constant labels
+// added to every metric, e.g. `environment=production`, or returns an
+// error if a label name is invalid, reserved or repeated or a value is
+// empty or not valid UTF-8
+func ParseConstLabels(values []string) (map[string]string, error) {
+	constLabels := map[string]string{}
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Wrapf(ErrInvalidConstLabel, "invalid constant label %q, expected name=value", value)
+		}
+		name, labelValue := parts[0], parts[1]
+		if !labelNameRE.MatchString(name) || strings.HasPrefix(name, "__") || reservedLabels[name] {
+			return nil, errors.Wrapf(ErrInvalidConstLabel, "invalid label name %q", name)
+		}
+		if labelValue == "" || !utf8.ValidString(labelValue) {
+			return nil, errors.Wrapf(ErrInvalidConstLabel, "invalid value %q for label %q", labelValue, name)
+		}
+		if _, ok := constLabels[name]; ok {
+			return nil, errors.Wrapf(ErrInvalidConstLabel, "label %q is repeated", name)
+		}
+		constLabels[name] = labelValue
+	}
+	return constLabels, nil
+}
+
+// validateConstLabels returns an error if one of `constLabels` has the
+// name of the label of one of `includedAWSTags`
+func validateConstLabels(constLabels map[string]string, includedAWSTags []string, tagLabels map[string]string) error {
+	exporter := &ServiceQuotasExporter{tagLabels: tagLabels}
+	for _, tag := range includedTags(includedAWSTags, tagLabels) {
+		label := exporter.tagLabel(tag)
+		if _, ok := constLabels[label]; ok {
+			return errors.Wrapf(ErrInvalidConstLabel, "label %q is also the label of tag %q", label, tag)
+		}
+	}
+	return nil
+}
+
+// withConstLabels returns `labels` with `constLabels` added
+func withConstLabels(labels prometheus.Labels, constLabels map[string]string) prometheus.Labels {
+	for name, value := range constLabels {
+		labels[name] = value
+	}
+	return labels
+}
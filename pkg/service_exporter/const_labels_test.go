@@ -0,0 +1,102 @@
+package serviceexporter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	service_quotas "github.com/joshua-giumelli-deltatre/aws-service-quotas-exporter/pkg/service_quotas"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConstLabels(t *testing.T) {
+	constLabels, err := ParseConstLabels([]string{"environment=production", "cluster=eu=1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"environment": "production", "cluster": "eu=1"}, constLabels)
+}
+
+func TestParseConstLabelsWithError(t *testing.T) {
+	testCases := []struct {
+		name   string
+		values []string
+	}{
+		{name: "MissingValue", values: []string{"environment"}},
+		{name: "EmptyValue", values: []string{"environment="}},
+		{name: "InvalidValue", values: []string{"environment=\xff"}},
+		{name: "InvalidLabelName", values: []string{"cost-center=team"}},
+		{name: "DoubleUnderscoreLabelName", values: []string{"__name__=team"}},
+		{name: "ReservedLabelName", values: []string{"region=eu-west-1"}},
+		{name: "ReservedServiceLabelName", values: []string{"service=payments"}},
+		{name: "ReservedUnitLabelName", values: []string{"unit=ms"}},
+		{name: "ReservedCategoryLabelName", values: []string{"category=web"}},
+		{name: "ReservedCheckLabelName", values: []string{"check=nightly"}},
+		{name: "ReservedDescriptionLabelName", values: []string{"description=prod"}},
+		{name: "RepeatedLabelName", values: []string{"environment=production", "environment=staging"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			constLabels, err := ParseConstLabels(tc.values)
+
+			assert.Error(t, err)
+			assert.True(t, errors.Is(err, ErrInvalidConstLabel))
+			assert.Nil(t, constLabels)
+		})
+	}
+}
+
+func TestValidateConstLabels(t *testing.T) {
+	constLabels := map[string]string{"team": "platform"}
+
+	assert.NoError(t, validateConstLabels(constLabels, []string{"Name"}, map[string]string{"cost-center": "owner"}))
+	assert.True(t, errors.Is(validateConstLabels(constLabels, []string{"Team"}, nil), ErrInvalidConstLabel))
+	assert.True(t, errors.Is(validateConstLabels(constLabels, nil, map[string]string{"cost-center": "team"}), ErrInvalidConstLabel))
+}
+
+func TestCollectConstLabels(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "some_quota", Description: "some quota", Usage: 5, Quota: 10},
+		},
+	}
+
+	exporter := &ServiceQuotasExporter{
+		metricsRegion:  "eu-west-1",
+		quotasClient:   quotasClient,
+		metrics:        map[string]Metric{},
+		refreshPeriod:  360,
+		waitForMetrics: make(chan struct{}),
+		constLabels:    map[string]string{"environment": "production"},
+	}
+
+	exporter.createOrUpdateQuotasAndDescriptions(false)
+
+	expected := `
+# HELP aws_some_quota_used_total Used amount of some quota
+# TYPE aws_some_quota_used_total gauge
+aws_some_quota_used_total{environment="production",region="eu-west-1",resource="some_quota"} 5
+`
+	err := testutil.CollectAndCompare(exporter, strings.NewReader(expected), "aws_some_quota_used_total")
+	assert.NoError(t, err)
+}
+
+func TestCollectOnRequestConstLabels(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "some_quota", Description: "some quota", Usage: 5, Quota: 10},
+		},
+	}
+	exporter := newNoCacheExporter(quotasClient, time.Second)
+	exporter.constLabels = map[string]string{"environment": "production"}
+
+	expected := `
+# HELP aws_some_quota_used_total Used amount of some quota
+# TYPE aws_some_quota_used_total gauge
+aws_some_quota_used_total{environment="production",region="eu-west-1",resource="some_quota"} 5
+`
+	err := testutil.CollectAndCompare(exporter, strings.NewReader(expected), "aws_some_quota_used_total")
+	assert.NoError(t, err)
+}
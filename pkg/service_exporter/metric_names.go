@@ -0,0 +1,104 @@
+package serviceexporter
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Default namespace and subsystem of the metrics
+const (
+	DefaultNamespace = "aws"
+	DefaultSubsystem = "service_quota"
+)
+
+// legacySubsystem is the subsystem of the metrics about the checks with
+// the legacy metric names
+const legacySubsystem = "service_quotas"
+
+// MetricOptions configures the names and values of the exported metrics
+type MetricOptions struct {
+	// Namespace and Subsystem prefix the names of the metrics, as
+	// <namespace>_<subsystem>_usage with the quota in a quota label.
+	// They default to DefaultNamespace and DefaultSubsystem
+	Namespace string
+	Subsystem string
+	// LegacyNames names the metrics of each quota after the quota, as
+	// aws_<quota>_used_total, and the metrics about the checks
+	// aws_service_quotas_<name>, ignoring Namespace and Subsystem
+	LegacyNames bool
+	// ConstLabels are added to every metric, see ParseConstLabels
+	ConstLabels map[string]string
+	// ReachedMargin is how far below its limit the usage of a quota
+	// counts as reaching it in the quota reached metric
+	ReachedMargin float64
+}
+
+// metricNames names the metrics of the quotas after the namespace and
+// subsystem, with the quota in a label. A nil *metricNames gives the
+// legacy names of the metrics, named after their quota
+type metricNames struct {
+	namespace string
+	subsystem string
+}
+
+// newMetricNames returns the metric names configured by `options`
+func newMetricNames(options MetricOptions) *metricNames {
+	if options.LegacyNames {
+		return nil
+	}
+
+	names := &metricNames{namespace: options.Namespace, subsystem: options.Subsystem}
+	if names.namespace == "" {
+		names.namespace = DefaultNamespace
+	}
+	if names.subsystem == "" {
+		names.subsystem = DefaultSubsystem
+	}
+	return names
+}
+
+// quotaMetric describes a metric exported for each quota
+type quotaMetric struct {
+	// name and help are the name and help of the metric
+	name string
+	help string
+	// legacyName and legacyHelp are the name of the metric after the
+	// quota and its help, formatted with the quota description
+	legacyName string
+	legacyHelp string
+}
+
+// The metrics exported for each quota
+var (
+	usageMetric         = quotaMetric{"usage", "Used amount of the quota", "used_total", "Used amount of %s"}
+	limitMetric         = quotaMetric{"limit", "Limit of the quota", "limit_total", "Limit of %s"}
+	ratioMetric         = quotaMetric{"utilization_ratio", "Utilization ratio of the quota", "utilization_ratio", "Utilization ratio of %s"}
+	reportedUsageMetric = quotaMetric{"usage_reported", "Used amount of the quota as reported by AWS", "usage_reported", "Used amount of %s as reported by AWS"}
+	reachedMetric       = quotaMetric{"reached", "Whether the usage of the quota reached its limit", "reached", "Whether the usage of %s reached its limit"}
+)
+
+// quotaDesc returns the description of `metric` for the quota
+// `quotaName` with `description`. The metrics share their help for all
+// the quotas unless they have legacy names, as Prometheus requires a
+// single help per metric name
+func (n *metricNames) quotaDesc(constLabels prometheus.Labels, quotaName, description string, metric quotaMetric, labels []string) *prometheus.Desc {
+	if n == nil {
+		return newDesc(constLabels, quotaName, metric.legacyName, fmt.Sprintf(metric.legacyHelp, description), labels)
+	}
+	return prometheus.NewDesc(
+		prometheus.BuildFQName(n.namespace, n.subsystem, metric.name),
+		metric.help,
+		labels,
+		constLabels,
+	)
+}
+
+// checksPrefix returns the namespace and subsystem of the metrics
+// about the checks and the exported quotas
+func (n *metricNames) checksPrefix() (string, string) {
+	if n == nil {
+		return DefaultNamespace, legacySubsystem
+	}
+	return n.namespace, n.subsystem
+}
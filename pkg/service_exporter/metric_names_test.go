@@ -0,0 +1,37 @@
+package serviceexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMetricNames(t *testing.T) {
+	names := newMetricNames(MetricOptions{})
+
+	assert.Equal(t, &metricNames{namespace: "aws", subsystem: "service_quota"}, names)
+}
+
+func TestNewMetricNamesWithNamespaceAndSubsystem(t *testing.T) {
+	names := newMetricNames(MetricOptions{Namespace: "some", Subsystem: "quotas"})
+
+	usageDesc := names.quotaDesc(nil, "some_quota", "some quota", usageMetric, []string{"resource"})
+	namespace, subsystem := names.checksPrefix()
+
+	assert.Contains(t, usageDesc.String(), `fqName: "some_quotas_usage"`)
+	assert.Equal(t, "some", namespace)
+	assert.Equal(t, "quotas", subsystem)
+}
+
+func TestNewMetricNamesLegacyNames(t *testing.T) {
+	names := newMetricNames(MetricOptions{Namespace: "some", LegacyNames: true})
+
+	usageDesc := names.quotaDesc(nil, "some_quota", "some quota", usageMetric, []string{"resource"})
+	namespace, subsystem := names.checksPrefix()
+
+	assert.Nil(t, names)
+	assert.Contains(t, usageDesc.String(), `fqName: "aws_some_quota_used_total"`)
+	assert.Contains(t, usageDesc.String(), `help: "Used amount of some quota"`)
+	assert.Equal(t, "aws", namespace)
+	assert.Equal(t, "service_quotas", subsystem)
+}
@@ -1,24 +1,68 @@
 package serviceexporter
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/go-redis/redis/v8"
 	service_quotas "github.com/joshua-giumelli-deltatre/aws-service-quotas-exporter/pkg/service_quotas"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	logging "github.com/sirupsen/logrus"
 )
 
 var log = logging.WithFields(logging.Fields{})
 
+// Metrics modes of the exporter
+const (
+	// MetricsModeDefault exports the usage, limit and utilization
+	// ratio of each quota
+	MetricsModeDefault = "default"
+	// MetricsModeRatio only exports the utilization ratio of each
+	// quota and an info metric with the quota descriptions
+	MetricsModeRatio = "ratio"
+)
+
+// Errors returned from this package
+var (
+	ErrTimedOutWaitingForMetrics = errors.New("timed out waiting for metrics")
+	ErrResourceUsageNotSupported = errors.New("usage of a single resource not supported")
+)
+
 // Metric holds usage and limit desc and values
 type Metric struct {
+	// quotaName is the name of the quota the metric is exported for
+	quotaName   string
 	usageDesc   *prometheus.Desc
 	limitDesc   *prometheus.Desc
+	ratioDesc   *prometheus.Desc
+	reachedDesc *prometheus.Desc
 	usage       float64
 	limit       float64
 	labelValues []string
+	// unit is the unit of the usage and limit of the quota, it is
+	// empty for the counts
+	unit string
+	// reportedUsageDesc is only set when the exporter exports the
+	// usage reported by AWS, and reportedUsage when AWS reported it
+	reportedUsageDesc *prometheus.Desc
+	reportedUsage     *float64
+	// emptyRefreshes is the number of consecutive refreshes that
+	// reported no usage while a non-zero usage is being held
+	emptyRefreshes int
+	// updatedAt is the time of the last refresh that returned the
+	// usage of the metric
+	updatedAt time.Time
+	// stale is set when the last refresh had failed checks and did
+	// not return the usage of the metric, which is kept until it is
+	// older than the stale TTL of the exporter
+	stale bool
 }
 
 func metricKey(quota service_quotas.QuotaUsage) string {
@@ -28,118 +72,1232 @@ func metricKey(quota service_quotas.QuotaUsage) string {
 // ServiceQuotasExporter AWS service quotas and usage prometheus
 // exporter
 type ServiceQuotasExporter struct {
-	metricsRegion   string
-	quotasClient    service_quotas.QuotasInterface
-	metrics         map[string]Metric
+	metricsRegion string
+	quotasClient  service_quotas.QuotasInterface
+	metrics       map[string]Metric
+	// metricsMutex guards metrics, quotaDescriptions and
+	// truncatedChecks, which are refreshed while they are scraped
+	metricsMutex    sync.RWMutex
 	refreshPeriod   int
 	waitForMetrics  chan struct{}
 	includedAWSTags []string
+	adjustableOnly  bool
+	metricsMode     string
+	// tagLabels maps AWS tag keys to the label names used for them
+	tagLabels map[string]string
+	// emptyRefreshesToHold is the number of consecutive refreshes
+	// reporting no usage for which the previous non-zero usage is kept
+	emptyRefreshesToHold int
+	// quotaDescriptions holds the description of each exported quota
+	quotaDescriptions map[string]string
+	// truncatedChecks holds whether the usage for each check was
+	// truncated during the last refresh
+	truncatedChecks map[string]bool
+	// staleTTL is how long the last known usage of the metrics not
+	// returned by refreshes with failed checks is kept. Such metrics
+	// are not kept when it is 0
+	staleTTL time.Duration
+	clock    func() time.Time
+	// noCache retrieves the quotas and usage on each scrape instead of
+	// refreshing them in the background
+	noCache bool
+	// zeroMetrics is set when zero-valued metrics are exported before
+	// the first refresh, so the scrapes do not wait for it and the
+	// exporter does not exit if it fails
+	zeroMetrics bool
+	// scrapeTimeout is how long a scrape waits for the quotas and
+	// usage in no cache mode. There is no timeout when it is 0
+	scrapeTimeout time.Duration
+	// retrievalMutex guards retrieval
+	retrievalMutex sync.Mutex
+	// retrieval is the last retrieval of the quotas and usage in no
+	// cache mode. The scrapes share it while it is in progress and for
+	// refreshPeriod seconds after it succeeded, so that concurrent
+	// scrapes do not each retrieve the quotas and usage. A failed or
+	// partial retrieval is retried by the next scrape
+	retrieval *quotasRetrieval
+	// alerter publishes the quotas crossing the alert threshold, it is
+	// nil when alerts are disabled
+	alerter *snsAlerter
+	// checkErrors counts the failures of each usage check
+	checkErrors *prometheus.CounterVec
+	// checkDurations observes how long each usage check takes
+	checkDurations *prometheus.HistogramVec
+	// metricsAccountID is the account_id label of the metrics, it is
+	// empty when the quotas are retrieved with the credentials of the
+	// exporter
+	metricsAccountID string
+	// regionExporters are the exporters of each region when multiple
+	// regions are exported, the exporter then only collects their
+	// metrics
+	regionExporters []*ServiceQuotasExporter
+	// reachedMargin is how far below its limit the usage of a quota
+	// counts as reaching it
+	reachedMargin float64
+	// reportedUsage exports the usage reported by AWS alongside the
+	// usage computed by the checks, see service_quotas.Options
+	reportedUsage bool
+	// refreshedAt is the time the last refresh of the metrics
+	// completed, guarded by refreshedAtMutex as it is read by the
+	// readiness checks
+	refreshedAt      time.Time
+	refreshedAtMutex sync.Mutex
+	// refreshFailures is the number of consecutive refreshes that
+	// failed to retrieve the quotas, also guarded by refreshedAtMutex
+	refreshFailures int
+	// metricNames names the metrics, they have their legacy names when
+	// it is nil
+	metricNames *metricNames
+	// constLabels are added to the constant labels of every metric
+	constLabels map[string]string
+}
+
+// AccountOptions configures the accounts whose quotas are exported.
+// The quotas of the account of the credentials of the exporter are
+// exported when RoleARNs is empty
+type AccountOptions struct {
+	// RoleARNs are the roles assumed to retrieve the quotas of their
+	// accounts, the metrics of each account have its account_id label
+	RoleARNs []string
+	// ExternalID is passed when assuming the roles, if it is set
+	ExternalID string
+}
+
+// assumedRole is the role assumed to retrieve the quotas of an account
+// other than the one of the credentials of the exporter. Its zero
+// value uses the credentials of the exporter
+type assumedRole struct {
+	roleARN    string
+	externalID string
+	accountID  string
+}
+
+// assumedRoles returns the roles of `options` or an error if a role
+// ARN is invalid
+func assumedRoles(options AccountOptions) ([]assumedRole, error) {
+	if len(options.RoleARNs) == 0 {
+		return []assumedRole{{}}, nil
+	}
+
+	roles := []assumedRole{}
+	for _, roleARN := range options.RoleARNs {
+		parsed, err := arn.Parse(roleARN)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse role ARN %s", roleARN)
+		}
+		roles = append(roles, assumedRole{roleARN: roleARN, externalID: options.ExternalID, accountID: parsed.AccountID})
+	}
+	return roles, nil
+}
+
+// ExporterOptions configures a ServiceQuotasExporter
+type ExporterOptions struct {
+	// Regions are the regions whose quotas are retrieved and exported
+	// with their region label, the alerts are published in the first
+	Regions []string
+	// Profile is the AWS profile of the credentials, the default
+	// credentials are used when it is empty
+	Profile string
+	// RefreshPeriod is the number of seconds between the refreshes of
+	// the quotas
+	RefreshPeriod int
+	// IncludedAWSTags are the AWS tags exported as labels, in addition
+	// to the tags mapped by TagLabels
+	IncludedAWSTags []string
+	// TagLabels maps AWS tag keys to the label names used for them
+	TagLabels map[string]string
+	// AdjustableOnly limits the exported metrics to the quotas that AWS
+	// allows to be increased
+	AdjustableOnly bool
+	// MetricsMode selects the exported metrics, MetricsModeDefault or
+	// MetricsModeRatio
+	MetricsMode string
+	// EmptyRefreshesToHold is the number of refreshes for which a drop
+	// to zero usage is ignored
+	EmptyRefreshesToHold int
+	// ZeroMetricsAtStartup creates zero-valued metrics for the known
+	// quotas before the first refresh
+	ZeroMetricsAtStartup bool
+	// StaleTTL is how long the last known usage of failed checks is
+	// exported in best effort mode
+	StaleTTL time.Duration
+	// NoCache retrieves the quotas on each scrape within ScrapeTimeout
+	// instead of refreshing them every RefreshPeriod, the scrapes within
+	// RefreshPeriod of a retrieval reusing it
+	NoCache       bool
+	ScrapeTimeout time.Duration
+	// Quotas configures the usage checks
+	Quotas service_quotas.Options
+	// Cache configures whether the quotas are shared with other
+	// replicas
+	Cache CacheOptions
+	// Alert configures the alerts published on each refresh
+	Alert AlertOptions
+	// Account configures the roles assumed to export the quotas of
+	// other accounts
+	Account AccountOptions
+	// Metric configures the names and values of the exported metrics
+	Metric MetricOptions
 }
 
 // NewServiceQuotasExporter creates a new ServiceQuotasExporter
-func NewServiceQuotasExporter(region, profile string, refreshPeriod int, includedAWSTags []string) (*ServiceQuotasExporter, error) {
-	quotasClient, err := service_quotas.NewServiceQuotas(region, profile)
+// configured by `options`
+func NewServiceQuotasExporter(options ExporterOptions) (*ServiceQuotasExporter, error) {
+	regions := options.Regions
+	if len(regions) == 0 {
+		return nil, errors.New("failed to create the exporter without regions")
+	}
+	roles, err := assumedRoles(options.Account)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateConstLabels(options.Metric.ConstLabels, options.IncludedAWSTags, options.TagLabels); err != nil {
+		return nil, err
+	}
+	names := newMetricNames(options.Metric)
+	if len(regions) == 1 && len(roles) == 1 {
+		return newRegionExporter(regions[0], regions[0], roles[0], options, names)
+	}
+	// the services with a region override would be exported by every
+	// region with the same region label
+	if len(regions) > 1 && len(options.Quotas.ServiceRegions) > 0 {
+		return nil, errors.New("failed to create the exporter for multiple regions with service region overrides")
+	}
+
+	seen := map[string]bool{}
+	for _, role := range roles {
+		for _, region := range regions {
+			key := role.accountID + "/" + region
+			if seen[key] {
+				return nil, errors.Errorf("failed to create the exporter with duplicate region %s for account %s", region, role.accountID)
+			}
+			seen[key] = true
+		}
+	}
+
+	regionExporters := []*ServiceQuotasExporter{}
+	for _, role := range roles {
+		// the account ID is the same in every region of the role
+		accountID, err := service_quotas.ResolveAccountID(regions[0], options.Profile, role.roleARN, role.externalID, options.Quotas)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create the exporter for account %s", role.accountID)
+		}
+		for i, region := range regions {
+			// the usage of the global services is the same in every
+			// region, so it is only checked in the first region
+			regionOptions := options
+			regionOptions.Quotas.SkipGlobalChecks = options.Quotas.SkipGlobalChecks || i > 0
+			regionOptions.Quotas.AccountID = &accountID
+			regionExporter, err := newRegionExporter(region, regions[0], role, regionOptions, names)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to create the exporter for region %s", region)
+			}
+			regionExporters = append(regionExporters, regionExporter)
+		}
+	}
+	return newMultiRegionExporter(regionExporters), nil
+}
+
+// newMultiRegionExporter creates a ServiceQuotasExporter exporting the
+// metrics of `regionExporters`, which can be the exporters of several
+// regions and accounts. It is ready once all of them are
+func newMultiRegionExporter(regionExporters []*ServiceQuotasExporter) *ServiceQuotasExporter {
+	exporter := &ServiceQuotasExporter{
+		metricsRegion:   regionExporters[0].metricsRegion,
+		metrics:         map[string]Metric{},
+		waitForMetrics:  make(chan struct{}),
+		regionExporters: regionExporters,
+		zeroMetrics:     regionExporters[0].zeroMetrics,
+	}
+	go func() {
+		for _, regionExporter := range regionExporters {
+			<-regionExporter.waitForMetrics
+		}
+		close(exporter.waitForMetrics)
+	}()
+	return exporter
+}
+
+// newRegionExporter creates the ServiceQuotasExporter of `region` in
+// the account of `role` configured by `options`, publishing its alerts
+// in `alertRegion` and naming its metrics with `names`. The regions of
+// `options` are ignored
+func newRegionExporter(region, alertRegion string, role assumedRole, options ExporterOptions, names *metricNames) (*ServiceQuotasExporter, error) {
+	constLabels := options.Metric.ConstLabels
+	quotasOptions := options.Quotas
+	checkErrors := newCheckErrorsCounter(withConstLabels(metricsLabels(region, role.accountID), constLabels), names)
+	onCheckError := quotasOptions.OnCheckError
+	quotasOptions.OnCheckError = func(check string, err error) {
+		checkErrors.WithLabelValues(check, service_quotas.ErrorCategory(err)).Inc()
+		if onCheckError != nil {
+			onCheckError(check, err)
+		}
+	}
+	checkDurations := newCheckDurationsHistogram(withConstLabels(metricsLabels(region, role.accountID), constLabels), names)
+	onCheckDuration := quotasOptions.OnCheckDuration
+	quotasOptions.OnCheckDuration = func(check string, duration time.Duration) {
+		checkDurations.WithLabelValues(check).Observe(duration.Seconds())
+		if onCheckDuration != nil {
+			onCheckDuration(check, duration)
+		}
+	}
+
+	quotasClient, err := service_quotas.NewServiceQuotasWithRole(region, options.Profile, role.roleARN, role.externalID, quotasOptions)
 	if err != nil {
 		return nil, errors.Wrapf(err, "%w")
 	}
+	if options.Cache.Backend == CacheBackendRedis {
+		redisClient := redis.NewClient(&redis.Options{
+			Addr:     options.Cache.RedisAddress,
+			Password: options.Cache.RedisPassword,
+			DB:       options.Cache.RedisDB,
+		})
+		cacheRegion := region
+		if role.accountID != "" {
+			cacheRegion = fmt.Sprintf("%s:%s", role.accountID, region)
+		}
+		quotasClient = newRedisQuotas(quotasClient, redisClient, options.Cache.RedisKeyPrefix, cacheRegion, options.RefreshPeriod)
+	}
 
 	ch := make(chan struct{})
 	exporter := &ServiceQuotasExporter{
 		metricsRegion:   region,
 		quotasClient:    quotasClient,
 		metrics:         map[string]Metric{},
-		refreshPeriod:   refreshPeriod,
+		refreshPeriod:   options.RefreshPeriod,
 		waitForMetrics:  ch,
-		includedAWSTags: includedAWSTags,
+		includedAWSTags: includedTags(options.IncludedAWSTags, options.TagLabels),
+		tagLabels:       options.TagLabels,
+		adjustableOnly:  options.AdjustableOnly,
+		metricsMode:     options.MetricsMode,
+
+		emptyRefreshesToHold: options.EmptyRefreshesToHold,
+		staleTTL:             options.StaleTTL,
+		clock:                time.Now,
+		noCache:              options.NoCache,
+		scrapeTimeout:        options.ScrapeTimeout,
+		checkErrors:          checkErrors,
+		checkDurations:       checkDurations,
+		metricsAccountID:     role.accountID,
+		reportedUsage:        quotasOptions.ReportedUsage,
+		reachedMargin:        options.Metric.ReachedMargin,
+		metricNames:          names,
+		constLabels:          constLabels,
+	}
+	if options.Alert.SNSTopicARN != "" {
+		exporter.alerter, err = newSNSAlerter(alertRegion, options.Profile, options.Alert)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create the SNS alerter")
+		}
+	}
+	if options.NoCache {
+		close(exporter.waitForMetrics)
+		return exporter, nil
 	}
-	go exporter.createOrUpdateQuotasAndDescriptions(false)
-	go exporter.refreshMetrics()
+	// no zero-valued metrics are created when only adjustable quotas
+	// are exported, see createZeroMetrics
+	exporter.zeroMetrics = options.ZeroMetricsAtStartup && !options.AdjustableOnly
+	exporter.start()
 
 	return exporter, nil
 }
 
-func (e *ServiceQuotasExporter) refreshMetrics() {
-	<-e.waitForMetrics
+// start creates the zero-valued metrics if they are exported and
+// refreshes the metrics in the background
+func (e *ServiceQuotasExporter) start() {
+	if e.zeroMetrics {
+		e.createZeroMetrics()
+	}
+	go func() {
+		e.createOrUpdateQuotasAndDescriptions(false)
+		e.refreshMetrics()
+	}()
+}
+
+// Ready returns whether the first refresh of the metrics has
+// completed
+func (e *ServiceQuotasExporter) Ready() bool {
+	select {
+	case <-e.waitForMetrics:
+		return true
+	default:
+		return false
+	}
+}
+
+// Stale returns whether the last refresh of the metrics completed more
+// than `maxStaleness` ago, for instance because the refreshes are stuck
+// on AWS. The metrics are never stale when `maxStaleness` is 0, before
+// the first refresh has completed or when they are retrieved on each
+// scrape. The metrics of a multi-region exporter are stale when those
+// of any of its regions are
+func (e *ServiceQuotasExporter) Stale(maxStaleness time.Duration) bool {
+	if len(e.regionExporters) > 0 {
+		for _, regionExporter := range e.regionExporters {
+			if regionExporter.Stale(maxStaleness) {
+				return true
+			}
+		}
+		return false
+	}
+	if maxStaleness <= 0 || e.noCache || !e.Ready() {
+		return false
+	}
+
+	e.refreshedAtMutex.Lock()
+	defer e.refreshedAtMutex.Unlock()
+	return e.now().Sub(e.refreshedAt) > maxStaleness
+}
+
+// UsageForResource returns the usage of the resource `resourceID` for
+// the quota `quotaCode` retrieved from AWS instead of the refreshed
+// metrics, or an error. A multi-region exporter returns the usage from
+// the first of its regions that has the resource. The resource is not
+// found only if none of the regions has it, otherwise the first error
+// of the regions that failed is returned
+func (e *ServiceQuotasExporter) UsageForResource(ctx context.Context, quotaCode, resourceID string) (*service_quotas.QuotaUsage, error) {
+	if len(e.regionExporters) > 0 {
+		var notFoundErr, regionErr error
+		for _, regionExporter := range e.regionExporters {
+			usage, err := regionExporter.UsageForResource(ctx, quotaCode, resourceID)
+			switch {
+			case err == nil:
+				return usage, nil
+			case errors.Is(err, service_quotas.ErrResourceNotFound):
+				if notFoundErr == nil {
+					notFoundErr = err
+				}
+			case regionErr == nil:
+				regionErr = err
+			}
+		}
+		if regionErr != nil {
+			return nil, regionErr
+		}
+		return nil, notFoundErr
+	}
+
+	client, ok := e.quotasClient.(service_quotas.ResourceQuotasInterface)
+	if !ok {
+		return nil, errors.Wrapf(ErrResourceUsageNotSupported, "in %s", e.metricsRegion)
+	}
+	return client.UsageForResource(ctx, quotaCode, resourceID)
+}
 
+// WaitUntilReady blocks until the first refresh of the metrics has
+// completed or returns an error if that takes longer than `timeout`
+func (e *ServiceQuotasExporter) WaitUntilReady(timeout time.Duration) error {
+	select {
+	case <-e.waitForMetrics:
+		return nil
+	case <-time.After(timeout):
+		return ErrTimedOutWaitingForMetrics
+	}
+}
+
+// refreshMetrics refreshes the metrics every refresh period. The
+// metrics are created by the first refresh that succeeds, which can be
+// a later one when zero-valued metrics are exported
+func (e *ServiceQuotasExporter) refreshMetrics() {
 	for {
 		time.Sleep(time.Duration(e.refreshPeriod) * time.Second)
-		e.createOrUpdateQuotasAndDescriptions(true)
+		e.createOrUpdateQuotasAndDescriptions(e.Ready())
 	}
 }
 
+// createOrUpdateQuotasAndDescriptions retrieves the quotas and usage
+// and creates their metrics, or updates them when `update` is set. The
+// exporter exits if the first retrieval fails, unless zero-valued
+// metrics are exported until it succeeds. The later refreshes that
+// fail keep the previous metrics and are counted until a refresh
+// succeeds
 func (e *ServiceQuotasExporter) createOrUpdateQuotasAndDescriptions(update bool) {
 	quotas, err := e.quotasClient.QuotasAndUsage()
-	if err != nil {
-		log.Fatalf("Could not retrieve quotas and limits: %s", err)
+	partial := errors.Is(err, service_quotas.ErrPartialUsage)
+	if err != nil && !partial {
+		if !update && !e.zeroMetrics {
+			log.Fatalf("Could not retrieve quotas and limits: %s", err)
+		}
+		log.Errorf("Could not refresh quotas and limits, keeping the previous metrics: %s", err)
+		e.refreshedAtMutex.Lock()
+		e.refreshFailures++
+		e.refreshedAtMutex.Unlock()
+		return
+	}
+	if partial {
+		log.Warnf("Exporting the quotas and limits of the checks that did not fail: %s", err)
+	}
+
+	e.updateQuotas(quotas, update, partial)
+	e.alert(quotas, partial)
+
+	e.refreshedAtMutex.Lock()
+	e.refreshedAt = e.now()
+	e.refreshFailures = 0
+	e.refreshedAtMutex.Unlock()
+
+	if !update {
+		close(e.waitForMetrics)
 	}
+}
 
+// updateQuotas creates the metrics of `quotas` or updates the existing
+// metrics when `update` is set, creating those of the new resources. `partial` is set when some of the
+// checks failed to return their quotas
+func (e *ServiceQuotasExporter) updateQuotas(quotas []service_quotas.QuotaUsage, update, partial bool) {
+	e.metricsMutex.Lock()
+	defer e.metricsMutex.Unlock()
+
+	now := e.now()
+	refreshed := map[string]bool{}
+	quotaDescriptions := map[string]string{}
+	truncatedChecks := map[string]bool{}
 	for _, quota := range quotas {
+		if e.adjustableOnly && !quota.Adjustable {
+			continue
+		}
+		quotaDescriptions[quota.Name] = quota.Description
+		truncatedChecks[quota.Name] = truncatedChecks[quota.Name] || quota.Truncated
+
 		key := metricKey(quota)
 		resourceID := quota.Identifier()
-
-		labels := []string{"resource"}
-		labelValues := []string{resourceID}
-
-		for _, tag := range e.includedAWSTags {
-			prometheusFormatTag := service_quotas.ToPrometheusNamingFormat(tag)
-			labels = append(labels, prometheusFormatTag)
-			// Need to set empty label value to keep label name and value count the same
-			labelValues = append(labelValues, quota.Tags[prometheusFormatTag])
+		labels, labelValues := e.metricLabels(quota)
+		if refreshed[key] {
+			log.Warnf("Duplicate usage of %s for resource (%s), keeping the last one", quota.Name, resourceID)
 		}
+		refreshed[key] = true
 
-		if update {
-			if resourceMetric, ok := e.metrics[key]; ok {
-				log.Infof("Updating metrics for resource (%s)", resourceID)
+		if resourceMetric, ok := e.metrics[key]; update && ok {
+			log.Infof("Updating metrics for resource (%s)", resourceID)
+			if e.holdEmptyUsage(&resourceMetric, quota.Usage) {
+				log.Warnf("Keeping previous usage of %s for resource (%s) after a refresh reported no usage", quota.Name, resourceID)
+			} else {
 				resourceMetric.usage = quota.Usage
-				resourceMetric.limit = quota.Quota
-				resourceMetric.labelValues = labelValues
-				e.metrics[key] = resourceMetric
 			}
-		} else {
-			usageHelp := fmt.Sprintf("Used amount of %s", quota.Description)
-			usageDesc := newDesc(e.metricsRegion, quota.Name, "used_total", usageHelp, labels)
-
-			limitHelp := fmt.Sprintf("Limit of %s", quota.Description)
-			limitDesc := newDesc(e.metricsRegion, quota.Name, "limit_total", limitHelp, labels)
-			resourceMetric := Metric{
-				usageDesc:   usageDesc,
-				limitDesc:   limitDesc,
-				usage:       quota.Usage,
-				limit:       quota.Quota,
-				labelValues: labelValues,
+			resourceMetric.limit = quota.Quota
+			if e.reportedUsage {
+				resourceMetric.reportedUsage = quota.ReportedUsage
 			}
+			resourceMetric.labelValues = labelValues
+			resourceMetric.updatedAt = now
+			resourceMetric.stale = false
 			e.metrics[key] = resourceMetric
+			continue
 		}
+
+		// the resources created since the previous refresh, or that were
+		// removed and are back, get new metrics
+		if update {
+			log.Infof("Creating metrics for new resource (%s)", resourceID)
+		}
+		metric := e.newMetric(quota, labels, labelValues)
+		metric.updatedAt = now
+		e.metrics[key] = metric
 	}
 
-	if !update {
-		close(e.waitForMetrics)
+	if update {
+		e.removeMissingMetrics(refreshed, partial, now, quotaDescriptions)
+	}
+
+	e.quotaDescriptions = quotaDescriptions
+	e.truncatedChecks = truncatedChecks
+}
+
+// alert publishes the exported `quotas` that crossed the alert
+// threshold, if alerts are enabled
+func (e *ServiceQuotasExporter) alert(quotas []service_quotas.QuotaUsage, partial bool) {
+	if e.alerter == nil {
+		return
 	}
+
+	exported := []service_quotas.QuotaUsage{}
+	for _, quota := range quotas {
+		if e.adjustableOnly && !quota.Adjustable {
+			continue
+		}
+		exported = append(exported, quota)
+	}
+	e.alerter.notify(exported, partial)
 }
 
-// Describe writes descriptors to the prometheus desc channel
+// removeMissingMetrics removes the metrics not `refreshed` by a
+// refresh without failed checks, as their resources no longer exist.
+// The metrics not refreshed by a `partial` refresh may belong to the
+// failed checks, so they are kept until the next refresh without failed
+// checks. With a stale TTL they are flagged as stale instead, so their
+// last known usage is exported until it is older than the TTL
+func (e *ServiceQuotasExporter) removeMissingMetrics(refreshed map[string]bool, partial bool, now time.Time, quotaDescriptions map[string]string) {
+	for key, metric := range e.metrics {
+		if refreshed[key] {
+			continue
+		}
+		if !partial {
+			log.Infof("Removing metrics of %s for resource (%s) no longer returned", metric.quotaName, metric.labelValues[0])
+			delete(e.metrics, key)
+			continue
+		}
+		if e.staleTTL == 0 {
+			continue
+		}
+		if now.Sub(metric.updatedAt) > e.staleTTL {
+			log.Warnf("Removing metrics of %s for resource (%s) not refreshed for more than %s", metric.quotaName, metric.labelValues[0], e.staleTTL)
+			delete(e.metrics, key)
+			continue
+		}
+
+		metric.stale = true
+		e.metrics[key] = metric
+		if _, ok := quotaDescriptions[metric.quotaName]; !ok {
+			quotaDescriptions[metric.quotaName] = e.quotaDescriptions[metric.quotaName]
+		}
+	}
+}
+
+// now returns the time of the refreshes or the zero time if no clock
+// is set
+func (e *ServiceQuotasExporter) now() time.Time {
+	if e.clock == nil {
+		return time.Time{}
+	}
+	return e.clock()
+}
+
+// createZeroMetrics creates a zero-valued metric for each quota
+// described by the quotas client, so that their series are exported
+// before the first refresh has returned their usage. Whether a quota is
+// adjustable is not known before it is refreshed, so no metrics are
+// created when only adjustable quotas are exported
+func (e *ServiceQuotasExporter) createZeroMetrics() {
+	if e.adjustableOnly {
+		return
+	}
+	e.metricsMutex.Lock()
+	defer e.metricsMutex.Unlock()
+
+	quotaDescriptions := map[string]string{}
+	for _, quota := range e.quotasClient.DescribeQuotas() {
+		labels, labelValues := e.metricLabels(quota)
+		e.metrics[metricKey(quota)] = e.newMetric(quota, labels, labelValues)
+		quotaDescriptions[quota.Name] = quota.Description
+	}
+	e.quotaDescriptions = quotaDescriptions
+}
+
+// metricLabels returns the label names and values of the metrics of
+// `quota`. The quota is a label unless the metrics have their legacy
+// names, which include the quota
+func (e *ServiceQuotasExporter) metricLabels(quota service_quotas.QuotaUsage) ([]string, []string) {
+	labels := []string{"resource"}
+	labelValues := []string{quota.Identifier()}
+	if e.metricNames != nil {
+		labels = append(labels, "quota")
+		labelValues = append(labelValues, quota.Name)
+	}
+
+	for _, tag := range e.includedAWSTags {
+		prometheusFormatTag := service_quotas.ToPrometheusNamingFormat(tag)
+		labels = append(labels, e.tagLabel(tag))
+		// Need to set empty label value to keep label name and value count the same
+		labelValues = append(labelValues, quota.Tags[prometheusFormatTag])
+	}
+	return labels, labelValues
+}
+
+// newMetric returns the metric of `quota` with the descriptors for the
+// metrics mode of the exporter. The metric is labelled with the region
+// the quota was retrieved in, which can differ from the region of the
+// exporter for services with a region override
+func (e *ServiceQuotasExporter) newMetric(quota service_quotas.QuotaUsage, labels, labelValues []string) Metric {
+	region := e.metricsRegion
+	if quota.Region != "" {
+		region = quota.Region
+	}
+
+	constLabels := withConstLabels(metricsLabels(region, e.metricsAccountID), e.constLabels)
+
+	metric := Metric{
+		quotaName:   quota.Name,
+		usageDesc:   e.metricNames.quotaDesc(constLabels, quota.Name, quota.Description, usageMetric, labels),
+		limitDesc:   e.metricNames.quotaDesc(constLabels, quota.Name, quota.Description, limitMetric, labels),
+		ratioDesc:   e.metricNames.quotaDesc(constLabels, quota.Name, quota.Description, ratioMetric, labels),
+		reachedDesc: e.metricNames.quotaDesc(constLabels, quota.Name, quota.Description, reachedMetric, labels),
+		usage:       quota.Usage,
+		limit:       quota.Quota,
+		labelValues: labelValues,
+		unit:        quota.Unit,
+	}
+	if e.reportedUsage {
+		metric.reportedUsageDesc = e.metricNames.quotaDesc(constLabels, quota.Name, quota.Description, reportedUsageMetric, labels)
+		metric.reportedUsage = quota.ReportedUsage
+	}
+	return metric
+}
+
+// holdEmptyUsage returns whether the previous usage of `metric` should
+// be kept instead of `usage`. AWS APIs can briefly return empty results,
+// so a drop from a non-zero usage to zero is only published once it has
+// been reported by more than `emptyRefreshesToHold` consecutive
+// refreshes
+func (e *ServiceQuotasExporter) holdEmptyUsage(metric *Metric, usage float64) bool {
+	if usage != 0 || metric.usage == 0 {
+		metric.emptyRefreshes = 0
+		return false
+	}
+
+	metric.emptyRefreshes++
+	if metric.emptyRefreshes <= e.emptyRefreshesToHold {
+		return true
+	}
+
+	metric.emptyRefreshes = 0
+	return false
+}
+
+// Describe writes descriptors to the prometheus desc channel. The
+// metrics are not known before they are scraped in no cache mode, so
+// no descriptors are written and the exporter is an unchecked collector.
+// The descriptors shared by the regions of a multi-region exporter, such
+// as those of the quotas with a region override, are written once
 func (e *ServiceQuotasExporter) Describe(ch chan<- *prometheus.Desc) {
-	<-e.waitForMetrics
+	if !e.zeroMetrics {
+		<-e.waitForMetrics
+	}
+	if len(e.regionExporters) > 0 {
+		descs := make(chan *prometheus.Desc)
+		go func() {
+			for _, regionExporter := range e.regionExporters {
+				regionExporter.Describe(descs)
+			}
+			close(descs)
+		}()
 
-	for _, metric := range e.metrics {
-		ch <- metric.usageDesc
-		ch <- metric.limitDesc
+		described := map[string]bool{}
+		for desc := range descs {
+			if !described[desc.String()] {
+				described[desc.String()] = true
+				ch <- desc
+			}
+		}
+		return
+	}
+	if e.noCache {
+		return
+	}
+
+	e.metricsMutex.RLock()
+	if e.metricsMode == MetricsModeRatio {
+		for _, metric := range e.metrics {
+			ch <- metric.ratioDesc
+		}
+		ch <- newQuotaInfoDesc(e.metricsLabels(), e.metricNames)
+	} else {
+		for _, metric := range e.metrics {
+			ch <- metric.usageDesc
+			ch <- metric.limitDesc
+			ch <- metric.ratioDesc
+			ch <- metric.reachedDesc
+			if e.reportedUsage {
+				ch <- metric.reportedUsageDesc
+			}
+		}
+	}
+	e.metricsMutex.RUnlock()
+	ch <- newQuotaUnitDesc(e.metricsLabels(), e.metricNames)
+	ch <- newCheckTruncatedDesc(e.metricsLabels(), e.metricNames)
+	ch <- newRefreshFailuresDesc(e.metricsLabels(), e.metricNames)
+	if _, ok := e.quotasClient.(service_quotas.CoverageQuotasInterface); ok {
+		ch <- newChecksRegisteredDesc(e.metricsLabels(), e.metricNames)
+		ch <- newAvailableQuotasDesc(e.metricsLabels(), e.metricNames)
+	}
+	if e.staleTTL > 0 {
+		ch <- newCheckStaleDesc(e.metricsLabels(), e.metricNames)
+	}
+	if e.checkErrors != nil {
+		e.checkErrors.Describe(ch)
+	}
+	if e.checkDurations != nil {
+		e.checkDurations.Describe(ch)
 	}
 }
 
-// Collect implements the collect function for prometheus collectors
+// Collect implements the collect function for prometheus collectors.
+// The regions of a multi-region exporter are collected concurrently and
+// their duplicate metrics are dropped, see uniqueMetrics
 func (e *ServiceQuotasExporter) Collect(ch chan<- prometheus.Metric) {
+	if len(e.regionExporters) > 0 {
+		metrics := make(chan prometheus.Metric)
+		var wg sync.WaitGroup
+		for _, regionExporter := range e.regionExporters {
+			wg.Add(1)
+			go func(regionExporter *ServiceQuotasExporter) {
+				defer wg.Done()
+				regionExporter.Collect(metrics)
+			}(regionExporter)
+		}
+		go func() {
+			wg.Wait()
+			close(metrics)
+		}()
+
+		for _, metric := range uniqueMetrics(metrics) {
+			ch <- metric
+		}
+		return
+	}
+	if e.noCache {
+		e.collectOnRequest(ch)
+	} else {
+		e.collectMetrics(ch)
+		e.collectRefreshFailures(ch)
+	}
+	e.collectCoverage(ch)
+	if e.checkErrors != nil {
+		e.checkErrors.Collect(ch)
+	}
+	if e.checkDurations != nil {
+		e.checkDurations.Collect(ch)
+	}
+}
+
+// uniqueMetrics returns the metrics read from `metrics` until it is
+// closed, keeping the last one of the metrics with the same name and
+// labels. The regions of a multi-region exporter can export the same
+// metric, for instance for quotas with a region override, which would
+// otherwise fail the whole scrape
+func uniqueMetrics(metrics <-chan prometheus.Metric) []prometheus.Metric {
+	unique := []prometheus.Metric{}
+	indexes := map[string]int{}
+	for metric := range metrics {
+		id, err := metricID(metric)
+		if err != nil {
+			log.Warnf("Failed to read metric %s: %s", metric.Desc(), err)
+			unique = append(unique, metric)
+			continue
+		}
+		if i, ok := indexes[id]; ok {
+			log.Warnf("Dropping duplicate metric %s, keeping the last one collected", id)
+			unique[i] = metric
+			continue
+		}
+		indexes[id] = len(unique)
+		unique = append(unique, metric)
+	}
+	return unique
+}
+
+// metricID returns the name and labels identifying `metric`
+func metricID(metric prometheus.Metric) (string, error) {
+	var m dto.Metric
+	if err := metric.Write(&m); err != nil {
+		return "", err
+	}
+	labels := make([]string, 0, len(m.Label))
+	for _, label := range m.Label {
+		labels = append(labels, fmt.Sprintf("%s=%q", label.GetName(), label.GetValue()))
+	}
+	return fmt.Sprintf("%s{%s}", metric.Desc(), strings.Join(labels, ",")), nil
+}
+
+// collectOnRequest retrieves the quotas and usage and writes their
+// metrics to `ch`. Nothing is written when they cannot be retrieved
+// within the scrape timeout
+func (e *ServiceQuotasExporter) collectOnRequest(ch chan<- prometheus.Metric) {
+	quotas, err := e.quotasWithTimeout()
+	partial := errors.Is(err, service_quotas.ErrPartialUsage)
+	if err != nil && !partial {
+		log.Errorf("Could not retrieve quotas and limits: %s", err)
+		return
+	}
+	if partial {
+		log.Warnf("Exporting the quotas and limits of the checks that did not fail: %s", err)
+	}
+
+	scrape := &ServiceQuotasExporter{
+		metricsRegion:    e.metricsRegion,
+		metrics:          map[string]Metric{},
+		includedAWSTags:  e.includedAWSTags,
+		tagLabels:        e.tagLabels,
+		adjustableOnly:   e.adjustableOnly,
+		metricsMode:      e.metricsMode,
+		metricsAccountID: e.metricsAccountID,
+		reportedUsage:    e.reportedUsage,
+		reachedMargin:    e.reachedMargin,
+		metricNames:      e.metricNames,
+		constLabels:      e.constLabels,
+	}
+	scrape.updateQuotas(quotas, false, partial)
+	scrape.collectMetrics(ch)
+	e.alert(quotas, partial)
+}
+
+// quotasRetrieval is a retrieval of the quotas and usage in no cache
+// mode, shared by the scrapes
+type quotasRetrieval struct {
+	// done is closed once the quotas and usage are retrieved
+	done   chan struct{}
+	quotas []service_quotas.QuotaUsage
+	err    error
+	// completedAt is when the retrieval completed
+	completedAt time.Time
+}
+
+// quotasWithTimeout returns the quotas and usage or an error if
+// retrieving them takes longer than the scrape timeout. Quotas clients
+// that take a context return the quotas and usage retrieved before the
+// timeout as partial usage instead. A scrape that times out leaves the
+// retrieval running in the background, the next scrapes wait for it to
+// finish
+func (e *ServiceQuotasExporter) quotasWithTimeout() ([]service_quotas.QuotaUsage, error) {
+	retrieval := e.sharedRetrieval()
+
+	// The retrievals with a context end by themselves at the timeout
+	var timeout <-chan time.Time
+	if _, ok := e.quotasClient.(service_quotas.ContextQuotasInterface); !ok && e.scrapeTimeout > 0 {
+		timeout = time.After(e.scrapeTimeout)
+	}
+	select {
+	case <-retrieval.done:
+		return retrieval.quotas, retrieval.err
+	case <-timeout:
+		return nil, errors.Wrapf(ErrTimedOutWaitingForMetrics, "no quotas after %s", e.scrapeTimeout)
+	}
+}
+
+// sharedRetrieval returns the retrieval of the quotas and usage in
+// progress or completed within the refresh period, or starts a new one
+func (e *ServiceQuotasExporter) sharedRetrieval() *quotasRetrieval {
+	e.retrievalMutex.Lock()
+	defer e.retrievalMutex.Unlock()
+
+	if e.retrieval != nil {
+		select {
+		case <-e.retrieval.done:
+			ttl := time.Duration(e.refreshPeriod) * time.Second
+			if e.retrieval.err == nil && ttl > 0 && e.now().Sub(e.retrieval.completedAt) < ttl {
+				return e.retrieval
+			}
+		default:
+			return e.retrieval
+		}
+	}
+
+	retrieval := &quotasRetrieval{done: make(chan struct{})}
+	e.retrieval = retrieval
+	go func() {
+		defer close(retrieval.done)
+		if client, ok := e.quotasClient.(service_quotas.ContextQuotasInterface); ok && e.scrapeTimeout > 0 {
+			ctx, cancel := context.WithTimeout(context.Background(), e.scrapeTimeout)
+			defer cancel()
+			retrieval.quotas, retrieval.err = client.QuotasAndUsageWithContext(ctx)
+		} else {
+			retrieval.quotas, retrieval.err = e.quotasClient.QuotasAndUsage()
+		}
+		retrieval.completedAt = e.now()
+	}()
+	return retrieval
+}
+
+// collectMetrics writes the metrics of the exporter to `ch`
+func (e *ServiceQuotasExporter) collectMetrics(ch chan<- prometheus.Metric) {
+	e.metricsMutex.RLock()
+	defer e.metricsMutex.RUnlock()
+
+	if e.metricsMode == MetricsModeRatio {
+		e.collectRatios(ch)
+	} else {
+		for _, metric := range e.metrics {
+			sendGauge(ch, metric, metric.limitDesc, metric.limit)
+			sendGauge(ch, metric, metric.usageDesc, metric.usage)
+			sendRatio(ch, metric)
+			sendReached(ch, metric, e.reachedMargin)
+			if metric.reportedUsage != nil {
+				sendGauge(ch, metric, metric.reportedUsageDesc, *metric.reportedUsage)
+			}
+		}
+	}
+	e.collectQuotaUnits(ch)
+
+	truncatedDesc := newCheckTruncatedDesc(e.metricsLabels(), e.metricNames)
+	for check, truncated := range e.truncatedChecks {
+		var value float64
+		if truncated {
+			value = 1
+		}
+		ch <- prometheus.MustNewConstMetric(truncatedDesc, prometheus.GaugeValue, value, check)
+	}
+
+	if e.staleTTL > 0 {
+		e.collectStaleChecks(ch)
+	}
+}
+
+// collectCoverage writes the number of usage checks and of the quotas
+// listed by AWS for each service to `ch`, if the quotas client reports
+// them
+func (e *ServiceQuotasExporter) collectCoverage(ch chan<- prometheus.Metric) {
+	client, ok := e.quotasClient.(service_quotas.CoverageQuotasInterface)
+	if !ok {
+		return
+	}
+
+	registeredDesc := newChecksRegisteredDesc(e.metricsLabels(), e.metricNames)
+	ch <- prometheus.MustNewConstMetric(registeredDesc, prometheus.GaugeValue, float64(client.RegisteredChecks()))
+
+	availableDesc := newAvailableQuotasDesc(e.metricsLabels(), e.metricNames)
+	for service, count := range client.AvailableQuotas() {
+		ch <- prometheus.MustNewConstMetric(availableDesc, prometheus.GaugeValue, float64(count), service)
+	}
+}
+
+// collectRefreshFailures writes the number of consecutive refreshes
+// that failed to retrieve the quotas to `ch`
+func (e *ServiceQuotasExporter) collectRefreshFailures(ch chan<- prometheus.Metric) {
+	e.refreshedAtMutex.Lock()
+	refreshFailures := e.refreshFailures
+	e.refreshedAtMutex.Unlock()
+
+	desc := newRefreshFailuresDesc(e.metricsLabels(), e.metricNames)
+	ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(refreshFailures))
+}
+
+// collectQuotaUnits writes the unit of each exported quota to `ch`,
+// the quotas without a unit being counts
+func (e *ServiceQuotasExporter) collectQuotaUnits(ch chan<- prometheus.Metric) {
+	units := map[string]string{}
+	for _, metric := range e.metrics {
+		units[metric.quotaName] = metric.unit
+		if metric.unit == "" {
+			units[metric.quotaName] = service_quotas.UnitCount
+		}
+	}
+
+	unitDesc := newQuotaUnitDesc(e.metricsLabels(), e.metricNames)
+	for quotaName, unit := range units {
+		ch <- prometheus.MustNewConstMetric(unitDesc, prometheus.GaugeValue, 1, quotaName, unit)
+	}
+}
+
+// collectStaleChecks writes whether the exported usage of each check
+// includes stale metrics to `ch`
+func (e *ServiceQuotasExporter) collectStaleChecks(ch chan<- prometheus.Metric) {
+	staleChecks := map[string]bool{}
 	for _, metric := range e.metrics {
-		ch <- prometheus.MustNewConstMetric(metric.limitDesc, prometheus.GaugeValue, metric.limit, metric.labelValues...)
-		ch <- prometheus.MustNewConstMetric(metric.usageDesc, prometheus.GaugeValue, metric.usage, metric.labelValues...)
+		staleChecks[metric.quotaName] = staleChecks[metric.quotaName] || metric.stale
+	}
+
+	staleDesc := newCheckStaleDesc(e.metricsLabels(), e.metricNames)
+	for check, stale := range staleChecks {
+		var value float64
+		if stale {
+			value = 1
+		}
+		ch <- prometheus.MustNewConstMetric(staleDesc, prometheus.GaugeValue, value, check)
+	}
+}
+
+// collectRatios writes the utilization ratio of each quota and the
+// quota info metrics to `ch`. The ratio is not exported for quotas
+// without a limit
+func (e *ServiceQuotasExporter) collectRatios(ch chan<- prometheus.Metric) {
+	for _, metric := range e.metrics {
+		sendRatio(ch, metric)
+	}
+
+	infoDesc := newQuotaInfoDesc(e.metricsLabels(), e.metricNames)
+	for quotaName, description := range e.quotaDescriptions {
+		ch <- prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1, quotaName, description)
 	}
 }
 
-func newDesc(region, quotaName, metricName, help string, labels []string) *prometheus.Desc {
+// sendRatio writes the utilization ratio of `metric` to `ch`. The
+// ratio is not written for quotas without a limit
+func sendRatio(ch chan<- prometheus.Metric, metric Metric) {
+	if metric.limit == 0 {
+		return
+	}
+	sendGauge(ch, metric, metric.ratioDesc, metric.usage/metric.limit)
+}
+
+// sendReached writes whether the usage of `metric` is within `margin`
+// of its limit to `ch`. It is not written for quotas without a limit
+func sendReached(ch chan<- prometheus.Metric, metric Metric, margin float64) {
+	if metric.limit == 0 {
+		return
+	}
+	var reached float64
+	if metric.usage >= metric.limit-margin {
+		reached = 1
+	}
+	sendGauge(ch, metric, metric.reachedDesc, reached)
+}
+
+// sendGauge writes the gauge of `metric` with `desc` and `value` to
+// `ch`. NaN and infinite values are skipped with a warning instead, as
+// they can come from edge cases in the usage or ratio calculations and
+// are not meaningful quota values
+func sendGauge(ch chan<- prometheus.Metric, metric Metric, desc *prometheus.Desc, value float64) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		log.Warnf("Skipping invalid value %v of %s for resource (%s)", value, metric.quotaName, metric.labelValues[0])
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, metric.labelValues...)
+}
+
+// metricsLabels returns the constant labels of the metrics of `region`
+// in the account `accountID`, which are not labelled with the account
+// when it is empty
+func metricsLabels(region, accountID string) prometheus.Labels {
+	labels := prometheus.Labels{"region": region}
+	if accountID != "" {
+		labels["account_id"] = accountID
+	}
+	return labels
+}
+
+// metricsLabels returns the constant labels of the metrics of the
+// exporter
+func (e *ServiceQuotasExporter) metricsLabels() prometheus.Labels {
+	return withConstLabels(metricsLabels(e.metricsRegion, e.metricsAccountID), e.constLabels)
+}
+
+// The variable labels of the metrics about the usage checks and the
+// exported quotas, which are reserved, see reservedLabels
+var (
+	checkLabels           = []string{"check"}
+	checkErrorsLabels     = []string{"check", "category"}
+	availableQuotasLabels = []string{"service"}
+	quotaInfoLabels       = []string{"quota", "description"}
+	quotaUnitLabels       = []string{"quota", "unit"}
+)
+
+func newDesc(constLabels prometheus.Labels, quotaName, metricName, help string, labels []string) *prometheus.Desc {
 	return prometheus.NewDesc(
 		prometheus.BuildFQName("aws", quotaName, metricName),
 		help,
 		labels,
-		prometheus.Labels{"region": region},
+		constLabels,
+	)
+}
+
+// newCheckTruncatedDesc returns the description of the metric flagging
+// the checks that stopped paging after the maximum number of resources
+func newCheckTruncatedDesc(constLabels prometheus.Labels, names *metricNames) *prometheus.Desc {
+	namespace, subsystem := names.checksPrefix()
+	return prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystem, "check_truncated"),
+		"Whether the usage of the check is incomplete because it reached the maximum number of resources",
+		checkLabels,
+		constLabels,
+	)
+}
+
+// newCheckErrorsCounter returns the counter of the failures of each
+// usage check by error category, including the checks that are skipped,
+// see service_quotas.ErrorCategory
+func newCheckErrorsCounter(constLabels prometheus.Labels, names *metricNames) *prometheus.CounterVec {
+	namespace, subsystem := names.checksPrefix()
+	return prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   subsystem,
+			Name:        "check_errors_total",
+			Help:        "Number of times the usage check failed",
+			ConstLabels: constLabels,
+		},
+		checkErrorsLabels,
+	)
+}
+
+// checkDurationBuckets are the buckets of the durations of the usage
+// checks in seconds, from the checks making a single call to those
+// paging through many resources
+var checkDurationBuckets = prometheus.ExponentialBuckets(0.05, 2, 12)
+
+// newCheckDurationsHistogram returns the histogram of the durations of
+// each usage check, including the checks that fail
+func newCheckDurationsHistogram(constLabels prometheus.Labels, names *metricNames) *prometheus.HistogramVec {
+	namespace, subsystem := names.checksPrefix()
+	return prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Subsystem:   subsystem,
+			Name:        "check_duration_seconds",
+			Help:        "Duration of the usage check in seconds",
+			ConstLabels: constLabels,
+			Buckets:     checkDurationBuckets,
+		},
+		checkLabels,
+	)
+}
+
+// newCheckStaleDesc returns the description of the metric flagging
+// the checks whose exported usage is kept from before they failed
+func newCheckStaleDesc(constLabels prometheus.Labels, names *metricNames) *prometheus.Desc {
+	namespace, subsystem := names.checksPrefix()
+	return prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystem, "check_stale"),
+		"Whether the exported usage of the check is the last known usage from before the check failed",
+		checkLabels,
+		constLabels,
+	)
+}
+
+// newRefreshFailuresDesc returns the description of the metric
+// counting the consecutive refreshes that failed to retrieve the quotas
+func newRefreshFailuresDesc(constLabels prometheus.Labels, names *metricNames) *prometheus.Desc {
+	namespace, subsystem := names.checksPrefix()
+	return prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystem, "consecutive_refresh_failures"),
+		"Number of consecutive refreshes that failed to retrieve the quotas, reset by the next successful refresh",
+		nil,
+		constLabels,
+	)
+}
+
+// newChecksRegisteredDesc returns the description of the metric
+// counting the usage checks of the exporter
+func newChecksRegisteredDesc(constLabels prometheus.Labels, names *metricNames) *prometheus.Desc {
+	namespace, subsystem := names.checksPrefix()
+	return prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystem, "checks_registered"),
+		"Number of usage checks of the quotas counted by available_total",
+		nil,
+		constLabels,
+	)
+}
+
+// newAvailableQuotasDesc returns the description of the metric counting
+// the quotas listed by AWS for each service
+func newAvailableQuotasDesc(constLabels prometheus.Labels, names *metricNames) *prometheus.Desc {
+	namespace, subsystem := names.checksPrefix()
+	return prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystem, "available_total"),
+		"Number of quotas listed by AWS for the service",
+		availableQuotasLabels,
+		constLabels,
+	)
+}
+
+// newQuotaInfoDesc returns the description of the metric mapping the
+// exported quota names to their descriptions
+func newQuotaInfoDesc(constLabels prometheus.Labels, names *metricNames) *prometheus.Desc {
+	namespace, subsystem := names.checksPrefix()
+	return prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystem, "quota_info"),
+		"Description of the exported quotas",
+		quotaInfoLabels,
+		constLabels,
+	)
+}
+
+// newQuotaUnitDesc returns the description of the metric mapping the
+// exported quota names to the unit of their usage and limit
+func newQuotaUnitDesc(constLabels prometheus.Labels, names *metricNames) *prometheus.Desc {
+	namespace, subsystem := names.checksPrefix()
+	return prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystem, "unit"),
+		"Unit of the usage and limit of the exported quotas",
+		quotaUnitLabels,
+		constLabels,
 	)
 }
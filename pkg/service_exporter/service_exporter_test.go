@@ -1,8 +1,25 @@
 package serviceexporter
 
 import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	awsservicequotas "github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/aws/aws-sdk-go/service/servicequotas/servicequotasiface"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 
 	service_quotas "github.com/joshua-giumelli-deltatre/aws-service-quotas-exporter/pkg/service_quotas"
@@ -13,14 +30,19 @@ func resourceName(name string) *string {
 }
 
 type ServiceQuotasMock struct {
-	quotas []service_quotas.QuotaUsage
-	err    error
+	quotas          []service_quotas.QuotaUsage
+	describedQuotas []service_quotas.QuotaUsage
+	err             error
 }
 
 func (s *ServiceQuotasMock) QuotasAndUsage() ([]service_quotas.QuotaUsage, error) {
 	return s.quotas, s.err
 }
 
+func (s *ServiceQuotasMock) DescribeQuotas() []service_quotas.QuotaUsage {
+	return s.describedQuotas
+}
+
 func TestUpdateMetrics(t *testing.T) {
 	quotasClient := &ServiceQuotasMock{
 		quotas: []service_quotas.QuotaUsage{
@@ -43,11 +65,12 @@ func TestUpdateMetrics(t *testing.T) {
 
 	exporter.createOrUpdateQuotasAndDescriptions(true)
 
-	expectedMetrics := map[string]Metric{
-		"i-asdasd1": Metric{usage: 5, limit: 10, labelValues: []string{"i-asdasd1", "dummy-value"}},
-		"i-asdasd2": Metric{usage: 2, limit: 3, labelValues: []string{"i-asdasd2", ""}},
-	}
-	assert.Equal(t, expectedMetrics, exporter.metrics)
+	assert.Equal(t, Metric{usage: 5, limit: 10, labelValues: []string{"i-asdasd1", "dummy-value"}}, exporter.metrics["i-asdasd1"])
+	assert.Equal(t, Metric{usage: 2, limit: 3, labelValues: []string{"i-asdasd2", ""}}, exporter.metrics["i-asdasd2"])
+	// i-asdasd3 is new since the previous refresh
+	assert.Len(t, exporter.metrics, 3)
+	assert.Equal(t, float64(5), exporter.metrics["i-asdasd3"].usage)
+	assert.Equal(t, []string{"i-asdasd3", ""}, exporter.metrics["i-asdasd3"].labelValues)
 }
 
 func TestCreateQuotasAndDescriptions(t *testing.T) {
@@ -84,21 +107,31 @@ func TestCreateQuotasAndDescriptions(t *testing.T) {
 
 	exporter.createOrUpdateQuotasAndDescriptions(false)
 
-	firstUsageDesc := newDesc(region, firstQ.Name, "used_total", "Used amount of desc1", []string{"resource", "dummy_tag", "dummy_tag2"})
-	firstLimitDesc := newDesc(region, firstQ.Name, "limit_total", "Limit of desc1", []string{"resource", "dummy_tag", "dummy_tag2"})
-	secondUsageDesc := newDesc(region, secondQ.Name, "used_total", "Used amount of desc2", []string{"resource", "dummy_tag", "dummy_tag2"})
-	secondLimitDesc := newDesc(region, secondQ.Name, "limit_total", "Limit of desc2", []string{"resource", "dummy_tag", "dummy_tag2"})
+	firstUsageDesc := newDesc(metricsLabels(region, ""), firstQ.Name, "used_total", "Used amount of desc1", []string{"resource", "dummy_tag", "dummy_tag2"})
+	firstLimitDesc := newDesc(metricsLabels(region, ""), firstQ.Name, "limit_total", "Limit of desc1", []string{"resource", "dummy_tag", "dummy_tag2"})
+	secondUsageDesc := newDesc(metricsLabels(region, ""), secondQ.Name, "used_total", "Used amount of desc2", []string{"resource", "dummy_tag", "dummy_tag2"})
+	secondLimitDesc := newDesc(metricsLabels(region, ""), secondQ.Name, "limit_total", "Limit of desc2", []string{"resource", "dummy_tag", "dummy_tag2"})
+	firstRatioDesc := newDesc(metricsLabels(region, ""), firstQ.Name, "utilization_ratio", "Utilization ratio of desc1", []string{"resource", "dummy_tag", "dummy_tag2"})
+	firstReachedDesc := newDesc(metricsLabels(region, ""), firstQ.Name, "reached", "Whether the usage of desc1 reached its limit", []string{"resource", "dummy_tag", "dummy_tag2"})
+	secondRatioDesc := newDesc(metricsLabels(region, ""), secondQ.Name, "utilization_ratio", "Utilization ratio of desc2", []string{"resource", "dummy_tag", "dummy_tag2"})
+	secondReachedDesc := newDesc(metricsLabels(region, ""), secondQ.Name, "reached", "Whether the usage of desc2 reached its limit", []string{"resource", "dummy_tag", "dummy_tag2"})
 	expectedMetrics := map[string]Metric{
 		"Name1i-asdasd1": Metric{
+			quotaName:   "Name1",
 			usageDesc:   firstUsageDesc,
 			limitDesc:   firstLimitDesc,
+			ratioDesc:   firstRatioDesc,
+			reachedDesc: firstReachedDesc,
 			usage:       5,
 			limit:       10,
 			labelValues: []string{"i-asdasd1", "", ""},
 		},
 		"Name2i-asdasd2": Metric{
+			quotaName:   "Name2",
 			usageDesc:   secondUsageDesc,
 			limitDesc:   secondLimitDesc,
+			ratioDesc:   secondRatioDesc,
+			reachedDesc: secondReachedDesc,
 			usage:       1,
 			limit:       8,
 			labelValues: []string{"i-asdasd2", "dummy-value", "dummy-value2"},
@@ -108,6 +141,127 @@ func TestCreateQuotasAndDescriptions(t *testing.T) {
 	assert.Equal(t, expectedMetrics, exporter.metrics)
 }
 
+func TestCreateZeroMetrics(t *testing.T) {
+	region := "eu-west-1"
+
+	quotasClient := &ServiceQuotasMock{
+		describedQuotas: []service_quotas.QuotaUsage{
+			{Name: "Name1", Description: "desc1"},
+			{Name: "Name2", Description: "desc2", Quota: 1},
+		},
+	}
+
+	exporter := &ServiceQuotasExporter{
+		metricsRegion: region,
+		quotasClient:  quotasClient,
+		metrics:       map[string]Metric{},
+	}
+
+	exporter.createZeroMetrics()
+
+	expectedMetrics := map[string]Metric{
+		"Name1Name1": Metric{
+			quotaName:   "Name1",
+			usageDesc:   newDesc(metricsLabels(region, ""), "Name1", "used_total", "Used amount of desc1", []string{"resource"}),
+			limitDesc:   newDesc(metricsLabels(region, ""), "Name1", "limit_total", "Limit of desc1", []string{"resource"}),
+			ratioDesc:   newDesc(metricsLabels(region, ""), "Name1", "utilization_ratio", "Utilization ratio of desc1", []string{"resource"}),
+			reachedDesc: newDesc(metricsLabels(region, ""), "Name1", "reached", "Whether the usage of desc1 reached its limit", []string{"resource"}),
+			labelValues: []string{"Name1"},
+		},
+		"Name2Name2": Metric{
+			quotaName:   "Name2",
+			usageDesc:   newDesc(metricsLabels(region, ""), "Name2", "used_total", "Used amount of desc2", []string{"resource"}),
+			limitDesc:   newDesc(metricsLabels(region, ""), "Name2", "limit_total", "Limit of desc2", []string{"resource"}),
+			ratioDesc:   newDesc(metricsLabels(region, ""), "Name2", "utilization_ratio", "Utilization ratio of desc2", []string{"resource"}),
+			reachedDesc: newDesc(metricsLabels(region, ""), "Name2", "reached", "Whether the usage of desc2 reached its limit", []string{"resource"}),
+			limit:       1,
+			labelValues: []string{"Name2"},
+		},
+	}
+	assert.Equal(t, expectedMetrics, exporter.metrics)
+	assert.Equal(t, map[string]string{"Name1": "desc1", "Name2": "desc2"}, exporter.quotaDescriptions)
+}
+
+func TestCreateZeroMetricsAdjustableOnly(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		describedQuotas: []service_quotas.QuotaUsage{{Name: "Name1", Description: "desc1"}},
+	}
+
+	exporter := &ServiceQuotasExporter{
+		metricsRegion:  "eu-west-1",
+		quotasClient:   quotasClient,
+		metrics:        map[string]Metric{},
+		adjustableOnly: true,
+	}
+
+	exporter.createZeroMetrics()
+
+	assert.Empty(t, exporter.metrics)
+}
+
+func TestCollectZeroMetricsBeforeFirstRefresh(t *testing.T) {
+	quotasClient := &slowServiceQuotasMock{
+		ServiceQuotasMock: ServiceQuotasMock{
+			quotas:          []service_quotas.QuotaUsage{{Name: "some_quota", Description: "some quota", Usage: 5, Quota: 10}},
+			describedQuotas: []service_quotas.QuotaUsage{{Name: "some_quota", Description: "some quota", Quota: 10}},
+		},
+		release: make(chan struct{}),
+	}
+	exporter := &ServiceQuotasExporter{
+		metricsRegion:  "eu-west-1",
+		quotasClient:   quotasClient,
+		metrics:        map[string]Metric{},
+		waitForMetrics: make(chan struct{}),
+		refreshPeriod:  360,
+		zeroMetrics:    true,
+	}
+	exporter.start()
+
+	expected := `
+# HELP aws_some_quota_used_total Used amount of some quota
+# TYPE aws_some_quota_used_total gauge
+aws_some_quota_used_total{region="eu-west-1",resource="some_quota"} 0
+`
+	err := testutil.CollectAndCompare(exporter, strings.NewReader(expected), "aws_some_quota_used_total")
+	assert.NoError(t, err)
+	assert.False(t, exporter.Ready())
+
+	close(quotasClient.release)
+	assert.NoError(t, exporter.WaitUntilReady(time.Second))
+
+	expected = strings.Replace(expected, "} 0", "} 5", 1)
+	err = testutil.CollectAndCompare(exporter, strings.NewReader(expected), "aws_some_quota_used_total")
+	assert.NoError(t, err)
+}
+
+func TestCreateQuotasWithZeroMetricsAndError(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		describedQuotas: []service_quotas.QuotaUsage{{Name: "some_quota", Description: "some quota", Quota: 10}},
+		err:             errors.New("some err"),
+	}
+	exporter := &ServiceQuotasExporter{
+		metricsRegion:  "eu-west-1",
+		quotasClient:   quotasClient,
+		metrics:        map[string]Metric{},
+		waitForMetrics: make(chan struct{}),
+		zeroMetrics:    true,
+	}
+	exporter.createZeroMetrics()
+
+	// the exporter keeps exporting the zero-valued metrics instead of
+	// exiting, and the next refresh creates the metrics
+	exporter.createOrUpdateQuotasAndDescriptions(false)
+	assert.False(t, exporter.Ready())
+	assert.Equal(t, 1, exporter.refreshFailures)
+	assert.Len(t, exporter.metrics, 1)
+
+	quotasClient.err = nil
+	quotasClient.quotas = []service_quotas.QuotaUsage{{Name: "some_quota", Description: "some quota", Usage: 5, Quota: 10}}
+	exporter.createOrUpdateQuotasAndDescriptions(exporter.Ready())
+	assert.True(t, exporter.Ready())
+	assert.Equal(t, float64(5), exporter.metrics["some_quotasome_quota"].usage)
+}
+
 func TestCreateQuotasAndDescriptionsRefresh(t *testing.T) {
 	quotasClient := &ServiceQuotasMock{
 		quotas: []service_quotas.QuotaUsage{
@@ -121,7 +275,7 @@ func TestCreateQuotasAndDescriptionsRefresh(t *testing.T) {
 		},
 	}
 
-	desc := newDesc("eu-west-1", "some-quota", "some-metric", "help", []string{})
+	desc := newDesc(metricsLabels("eu-west-1", ""), "some-quota", "some-metric", "help", []string{})
 
 	ch := make(chan struct{})
 	exporter := &ServiceQuotasExporter{
@@ -137,11 +291,1399 @@ func TestCreateQuotasAndDescriptionsRefresh(t *testing.T) {
 
 	exporter.createOrUpdateQuotasAndDescriptions(true)
 
+	assert.Equal(t, Metric{usage: 5, limit: 10, labelValues: []string{"i-asdasd1", "dummy-value"}, usageDesc: desc}, exporter.metrics["i-asdasd1"])
+	// i-asdasd3 is new since the previous refresh
+	assert.Len(t, exporter.metrics, 2)
+	assert.Equal(t, []string{"i-asdasd3", ""}, exporter.metrics["i-asdasd3"].labelValues)
+
+	close(ch) // should panic if it was already closed
+}
+
+func TestCreateQuotasAndDescriptionsAdjustableOnly(t *testing.T) {
+	region := "eu-west-1"
+
+	adjustableQ := service_quotas.QuotaUsage{
+		Name:         "Name1",
+		ResourceName: resourceName("i-asdasd1"),
+		Description:  "desc1",
+		Usage:        5,
+		Quota:        10,
+		Adjustable:   true,
+	}
+	fixedQ := service_quotas.QuotaUsage{
+		Name:         "Name2",
+		ResourceName: resourceName("i-asdasd2"),
+		Description:  "desc2",
+		Usage:        1,
+		Quota:        8,
+	}
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{adjustableQ, fixedQ},
+	}
+
+	ch := make(chan struct{})
+	exporter := &ServiceQuotasExporter{
+		metricsRegion:  region,
+		quotasClient:   quotasClient,
+		metrics:        map[string]Metric{},
+		refreshPeriod:  360,
+		waitForMetrics: ch,
+		adjustableOnly: true,
+	}
+
+	exporter.createOrUpdateQuotasAndDescriptions(false)
+
+	usageDesc := newDesc(metricsLabels(region, ""), adjustableQ.Name, "used_total", "Used amount of desc1", []string{"resource"})
+	limitDesc := newDesc(metricsLabels(region, ""), adjustableQ.Name, "limit_total", "Limit of desc1", []string{"resource"})
+	ratioDesc := newDesc(metricsLabels(region, ""), adjustableQ.Name, "utilization_ratio", "Utilization ratio of desc1", []string{"resource"})
+	reachedDesc := newDesc(metricsLabels(region, ""), adjustableQ.Name, "reached", "Whether the usage of desc1 reached its limit", []string{"resource"})
 	expectedMetrics := map[string]Metric{
-		"i-asdasd1": Metric{usage: 5, limit: 10, labelValues: []string{"i-asdasd1", "dummy-value"}, usageDesc: desc},
+		"Name1i-asdasd1": Metric{
+			quotaName:   "Name1",
+			usageDesc:   usageDesc,
+			limitDesc:   limitDesc,
+			ratioDesc:   ratioDesc,
+			reachedDesc: reachedDesc,
+			usage:       5,
+			limit:       10,
+			labelValues: []string{"i-asdasd1"},
+		},
 	}
 
 	assert.Equal(t, expectedMetrics, exporter.metrics)
+}
 
-	close(ch) // should panic if it was already closed
+func TestWaitUntilReady(t *testing.T) {
+	ch := make(chan struct{})
+	exporter := &ServiceQuotasExporter{waitForMetrics: ch}
+
+	assert.False(t, exporter.Ready())
+	assert.Equal(t, ErrTimedOutWaitingForMetrics, exporter.WaitUntilReady(time.Millisecond))
+
+	close(ch)
+
+	assert.True(t, exporter.Ready())
+	assert.NoError(t, exporter.WaitUntilReady(time.Millisecond))
+}
+
+func TestStale(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	exporter := &ServiceQuotasExporter{
+		quotasClient:   &ServiceQuotasMock{},
+		metrics:        map[string]Metric{},
+		waitForMetrics: make(chan struct{}),
+		clock:          func() time.Time { return now },
+	}
+
+	// not stale before the first refresh, /ready is already failing
+	now = now.Add(time.Hour)
+	assert.False(t, exporter.Stale(time.Minute))
+
+	exporter.createOrUpdateQuotasAndDescriptions(false)
+	assert.False(t, exporter.Stale(time.Minute))
+
+	now = now.Add(2 * time.Minute)
+	assert.True(t, exporter.Stale(time.Minute))
+	assert.False(t, exporter.Stale(0))
+
+	exporter.createOrUpdateQuotasAndDescriptions(true)
+	assert.False(t, exporter.Stale(time.Minute))
+}
+
+func TestConsecutiveRefreshFailures(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "some_quota", ResourceName: resourceName("i-asdasd1"), Description: "some quota", Usage: 5, Quota: 10},
+		},
+	}
+	exporter := &ServiceQuotasExporter{
+		metricsRegion:  "eu-west-1",
+		quotasClient:   quotasClient,
+		metrics:        map[string]Metric{},
+		waitForMetrics: make(chan struct{}),
+	}
+	exporter.createOrUpdateQuotasAndDescriptions(false)
+
+	expected := func(failures int) string {
+		return fmt.Sprintf(`
+# HELP aws_service_quotas_consecutive_refresh_failures Number of consecutive refreshes that failed to retrieve the quotas, reset by the next successful refresh
+# TYPE aws_service_quotas_consecutive_refresh_failures gauge
+aws_service_quotas_consecutive_refresh_failures{region="eu-west-1"} %d
+`, failures)
+	}
+	err := testutil.CollectAndCompare(exporter, strings.NewReader(expected(0)), "aws_service_quotas_consecutive_refresh_failures")
+	assert.NoError(t, err)
+
+	quotasClient.err = errors.New("some err")
+	exporter.createOrUpdateQuotasAndDescriptions(true)
+	exporter.createOrUpdateQuotasAndDescriptions(true)
+
+	err = testutil.CollectAndCompare(exporter, strings.NewReader(expected(2)), "aws_service_quotas_consecutive_refresh_failures")
+	assert.NoError(t, err)
+	// the metrics of the last successful refresh are kept
+	assert.Equal(t, float64(5), exporter.metrics["some_quotai-asdasd1"].usage)
+
+	quotasClient.err = errors.Wrap(service_quotas.ErrPartialUsage, "some err")
+	exporter.createOrUpdateQuotasAndDescriptions(true)
+
+	err = testutil.CollectAndCompare(exporter, strings.NewReader(expected(0)), "aws_service_quotas_consecutive_refresh_failures")
+	assert.NoError(t, err)
+}
+
+func TestStaleAfterRefreshFailures(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	quotasClient := &ServiceQuotasMock{}
+	exporter := &ServiceQuotasExporter{
+		quotasClient:   quotasClient,
+		metrics:        map[string]Metric{},
+		waitForMetrics: make(chan struct{}),
+		clock:          func() time.Time { return now },
+	}
+	exporter.createOrUpdateQuotasAndDescriptions(false)
+
+	quotasClient.err = errors.New("some err")
+	now = now.Add(2 * time.Minute)
+	exporter.createOrUpdateQuotasAndDescriptions(true)
+	assert.True(t, exporter.Stale(time.Minute))
+}
+
+type coverageQuotasMock struct {
+	ServiceQuotasMock
+	registeredChecks int
+	availableQuotas  map[string]int
+}
+
+func (c *coverageQuotasMock) RegisteredChecks() int {
+	return c.registeredChecks
+}
+
+func (c *coverageQuotasMock) AvailableQuotas() map[string]int {
+	return c.availableQuotas
+}
+
+func TestCollectCoverage(t *testing.T) {
+	exporter := &ServiceQuotasExporter{
+		metricsRegion: "eu-west-1",
+		quotasClient: &coverageQuotasMock{
+			registeredChecks: 3,
+			availableQuotas:  map[string]int{"ec2": 40, "vpc": 25},
+		},
+		metrics:        map[string]Metric{},
+		waitForMetrics: make(chan struct{}),
+	}
+	exporter.createOrUpdateQuotasAndDescriptions(false)
+
+	expected := `
+# HELP aws_service_quotas_available_total Number of quotas listed by AWS for the service
+# TYPE aws_service_quotas_available_total gauge
+aws_service_quotas_available_total{region="eu-west-1",service="ec2"} 40
+aws_service_quotas_available_total{region="eu-west-1",service="vpc"} 25
+# HELP aws_service_quotas_checks_registered Number of usage checks of the quotas counted by available_total
+# TYPE aws_service_quotas_checks_registered gauge
+aws_service_quotas_checks_registered{region="eu-west-1"} 3
+`
+	err := testutil.CollectAndCompare(exporter, strings.NewReader(expected),
+		"aws_service_quotas_available_total", "aws_service_quotas_checks_registered")
+	assert.NoError(t, err)
+
+	// the quotas clients that do not report their coverage export
+	// neither metric
+	exporter.quotasClient = &ServiceQuotasMock{}
+	err = testutil.CollectAndCompare(exporter, strings.NewReader(""),
+		"aws_service_quotas_available_total", "aws_service_quotas_checks_registered")
+	assert.NoError(t, err)
+}
+
+func TestStaleMultipleRegions(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	regionExporters := []*ServiceQuotasExporter{}
+	for range []string{"eu-west-1", "us-east-1"} {
+		regionExporter := &ServiceQuotasExporter{
+			quotasClient:   &ServiceQuotasMock{},
+			metrics:        map[string]Metric{},
+			waitForMetrics: make(chan struct{}),
+			clock:          clock,
+		}
+		regionExporter.createOrUpdateQuotasAndDescriptions(false)
+		regionExporters = append(regionExporters, regionExporter)
+	}
+	exporter := newMultiRegionExporter(regionExporters)
+
+	now = now.Add(2 * time.Minute)
+	regionExporters[0].createOrUpdateQuotasAndDescriptions(true)
+
+	assert.True(t, exporter.Stale(time.Minute))
+
+	regionExporters[1].createOrUpdateQuotasAndDescriptions(true)
+
+	assert.False(t, exporter.Stale(time.Minute))
+}
+
+type resourceQuotasMock struct {
+	ServiceQuotasMock
+	// usages holds the usage of each resource
+	usages map[string]service_quotas.QuotaUsage
+	// usageErr is returned for every resource when it is set
+	usageErr error
+}
+
+func (s *resourceQuotasMock) UsageForResource(ctx context.Context, quotaCode, resourceID string) (*service_quotas.QuotaUsage, error) {
+	if s.usageErr != nil {
+		return nil, s.usageErr
+	}
+	usage, ok := s.usages[resourceID]
+	if !ok {
+		return nil, errors.Wrapf(service_quotas.ErrResourceNotFound, "no usage of quota %s for resource %s", quotaCode, resourceID)
+	}
+	return &usage, nil
+}
+
+func TestUsageForResource(t *testing.T) {
+	quotasClient := &resourceQuotasMock{
+		usages: map[string]service_quotas.QuotaUsage{
+			"sg-1": {Name: "rules_per_security_group", ResourceName: resourceName("sg-1"), Usage: 5, Quota: 60},
+		},
+	}
+	exporter := &ServiceQuotasExporter{metricsRegion: "eu-west-1", quotasClient: quotasClient}
+
+	usage, err := exporter.UsageForResource(context.Background(), "L-0EA8095F", "sg-1")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(5), usage.Usage)
+
+	usage, err = exporter.UsageForResource(context.Background(), "L-0EA8095F", "sg-2")
+	assert.True(t, errors.Is(err, service_quotas.ErrResourceNotFound))
+	assert.Nil(t, usage)
+}
+
+func TestUsageForResourceNotSupported(t *testing.T) {
+	exporter := &ServiceQuotasExporter{metricsRegion: "eu-west-1", quotasClient: &ServiceQuotasMock{}}
+
+	usage, err := exporter.UsageForResource(context.Background(), "L-0EA8095F", "sg-1")
+
+	assert.True(t, errors.Is(err, ErrResourceUsageNotSupported))
+	assert.Nil(t, usage)
+}
+
+func TestUsageForResourceMultipleRegions(t *testing.T) {
+	regionExporters := []*ServiceQuotasExporter{}
+	for _, resourceID := range []string{"sg-1", "sg-2"} {
+		waitForMetrics := make(chan struct{})
+		close(waitForMetrics)
+		regionExporters = append(regionExporters, &ServiceQuotasExporter{
+			quotasClient: &resourceQuotasMock{
+				usages: map[string]service_quotas.QuotaUsage{
+					resourceID: {Name: "rules_per_security_group", ResourceName: resourceName(resourceID), Usage: 5, Quota: 60},
+				},
+			},
+			metrics:        map[string]Metric{},
+			waitForMetrics: waitForMetrics,
+		})
+	}
+	exporter := newMultiRegionExporter(regionExporters)
+
+	usage, err := exporter.UsageForResource(context.Background(), "L-0EA8095F", "sg-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "sg-2", *usage.ResourceName)
+
+	usage, err = exporter.UsageForResource(context.Background(), "L-0EA8095F", "sg-3")
+	assert.True(t, errors.Is(err, service_quotas.ErrResourceNotFound))
+	assert.Nil(t, usage)
+
+	// the resource may exist in the region that failed, so the failure
+	// is returned instead of ErrResourceNotFound whatever its position
+	failingExporter := &ServiceQuotasExporter{
+		quotasClient: &resourceQuotasMock{usageErr: errors.Wrap(service_quotas.ErrFailedToListQuotas, "some err")},
+		metrics:      map[string]Metric{},
+	}
+	for _, exporters := range [][]*ServiceQuotasExporter{
+		{regionExporters[0], failingExporter, regionExporters[1]},
+		{failingExporter, regionExporters[0]},
+	} {
+		exporter = newMultiRegionExporter(exporters)
+
+		usage, err = exporter.UsageForResource(context.Background(), "L-0EA8095F", "sg-3")
+		assert.True(t, errors.Is(err, service_quotas.ErrFailedToListQuotas))
+		assert.False(t, errors.Is(err, service_quotas.ErrResourceNotFound))
+		assert.Nil(t, usage)
+
+		usage, err = exporter.UsageForResource(context.Background(), "L-0EA8095F", "sg-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "sg-1", *usage.ResourceName)
+	}
+}
+
+func TestCreateQuotasAndDescriptionsTruncatedChecks(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "Name1", ResourceName: resourceName("i-asdasd1"), Usage: 5, Quota: 10},
+			{Name: "Name1", ResourceName: resourceName("i-asdasd2"), Usage: 5, Quota: 10, Truncated: true},
+			{Name: "Name2", Usage: 1, Quota: 8},
+		},
+	}
+
+	ch := make(chan struct{})
+	exporter := &ServiceQuotasExporter{
+		metricsRegion:  "eu-west-1",
+		quotasClient:   quotasClient,
+		metrics:        map[string]Metric{},
+		refreshPeriod:  360,
+		waitForMetrics: ch,
+	}
+
+	exporter.createOrUpdateQuotasAndDescriptions(false)
+
+	expectedTruncatedChecks := map[string]bool{
+		"Name1": true,
+		"Name2": false,
+	}
+	assert.Equal(t, expectedTruncatedChecks, exporter.truncatedChecks)
+}
+
+func TestCollectRatioMode(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "some_quota", ResourceName: resourceName("i-asdasd1"), Description: "some quota", Usage: 5, Quota: 10},
+			{Name: "some_quota", ResourceName: resourceName("i-asdasd2"), Description: "some quota", Usage: 1, Quota: 0},
+		},
+	}
+
+	exporter := &ServiceQuotasExporter{
+		metricsRegion:  "eu-west-1",
+		quotasClient:   quotasClient,
+		metrics:        map[string]Metric{},
+		refreshPeriod:  360,
+		waitForMetrics: make(chan struct{}),
+		metricsMode:    MetricsModeRatio,
+	}
+
+	exporter.createOrUpdateQuotasAndDescriptions(false)
+
+	expected := `
+# HELP aws_some_quota_utilization_ratio Utilization ratio of some quota
+# TYPE aws_some_quota_utilization_ratio gauge
+aws_some_quota_utilization_ratio{region="eu-west-1",resource="i-asdasd1"} 0.5
+# HELP aws_service_quotas_quota_info Description of the exported quotas
+# TYPE aws_service_quotas_quota_info gauge
+aws_service_quotas_quota_info{description="some quota",quota="some_quota",region="eu-west-1"} 1
+`
+	err := testutil.CollectAndCompare(exporter, strings.NewReader(expected),
+		"aws_some_quota_utilization_ratio", "aws_some_quota_used_total", "aws_some_quota_limit_total", "aws_service_quotas_quota_info")
+	assert.NoError(t, err)
+}
+
+func TestCollectQuotaUnits(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "some_quota", ResourceName: resourceName("i-asdasd1"), Description: "some quota", Usage: 5, Quota: 10},
+			{Name: "some_quota", ResourceName: resourceName("i-asdasd2"), Description: "some quota", Usage: 1, Quota: 10},
+			{Name: "storage_quota", Description: "storage quota", Usage: 1.5, Quota: 50, Unit: service_quotas.UnitTiB},
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		metricNames *metricNames
+		metricName  string
+	}{
+		{name: "Legacy", metricName: "aws_service_quotas_unit"},
+		{name: "Default", metricNames: newMetricNames(MetricOptions{}), metricName: "aws_service_quota_unit"},
+	}
+
+	for _, tc := range testCases {
+		for _, metricsMode := range []string{MetricsModeDefault, MetricsModeRatio} {
+			t.Run(tc.name+"/"+metricsMode, func(t *testing.T) {
+				exporter := &ServiceQuotasExporter{
+					metricsRegion:  "eu-west-1",
+					quotasClient:   quotasClient,
+					metrics:        map[string]Metric{},
+					refreshPeriod:  360,
+					waitForMetrics: make(chan struct{}),
+					metricsMode:    metricsMode,
+					metricNames:    tc.metricNames,
+				}
+
+				exporter.createOrUpdateQuotasAndDescriptions(false)
+
+				expected := fmt.Sprintf(`
+# HELP %[1]s Unit of the usage and limit of the exported quotas
+# TYPE %[1]s gauge
+%[1]s{quota="some_quota",region="eu-west-1",unit="count"} 1
+%[1]s{quota="storage_quota",region="eu-west-1",unit="TiB"} 1
+`, tc.metricName)
+				err := testutil.CollectAndCompare(exporter, strings.NewReader(expected), tc.metricName)
+				assert.NoError(t, err)
+			})
+		}
+	}
+}
+
+func TestCollectQuotaReached(t *testing.T) {
+	testCases := []struct {
+		name     string
+		usage    float64
+		margin   float64
+		expected string
+	}{
+		{"AtLimit", 10, 0, "1"},
+		{"OverLimit", 12, 0, "1"},
+		{"NearLimit", 9, 0, "0"},
+		{"NearLimitWithinMargin", 9, 1, "1"},
+		{"UnderLimit", 5, 1, "0"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			quotasClient := &ServiceQuotasMock{
+				quotas: []service_quotas.QuotaUsage{
+					{Name: "some_quota", Description: "some quota", Usage: tc.usage, Quota: 10},
+					{Name: "unlimited_quota", Description: "unlimited quota", Usage: 10},
+				},
+			}
+			exporter := &ServiceQuotasExporter{
+				metricsRegion:  "eu-west-1",
+				quotasClient:   quotasClient,
+				metrics:        map[string]Metric{},
+				refreshPeriod:  360,
+				waitForMetrics: make(chan struct{}),
+				reachedMargin:  tc.margin,
+			}
+
+			exporter.createOrUpdateQuotasAndDescriptions(false)
+
+			expected := fmt.Sprintf(`
+# HELP aws_some_quota_reached Whether the usage of some quota reached its limit
+# TYPE aws_some_quota_reached gauge
+aws_some_quota_reached{region="eu-west-1",resource="some_quota"} %s
+`, tc.expected)
+			err := testutil.CollectAndCompare(exporter, strings.NewReader(expected), "aws_some_quota_reached", "aws_unlimited_quota_reached")
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCollectQuotaReachedMetricNames(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "some_quota", Description: "some quota", Usage: 10, Quota: 10},
+			{Name: "other_quota", Description: "other quota", Usage: 1, Quota: 10},
+		},
+	}
+	exporter := &ServiceQuotasExporter{
+		metricsRegion:  "eu-west-1",
+		quotasClient:   quotasClient,
+		metrics:        map[string]Metric{},
+		refreshPeriod:  360,
+		waitForMetrics: make(chan struct{}),
+		metricNames:    newMetricNames(MetricOptions{}),
+	}
+
+	exporter.createOrUpdateQuotasAndDescriptions(false)
+
+	expected := `
+# HELP aws_service_quota_reached Whether the usage of the quota reached its limit
+# TYPE aws_service_quota_reached gauge
+aws_service_quota_reached{quota="other_quota",region="eu-west-1",resource="other_quota"} 0
+aws_service_quota_reached{quota="some_quota",region="eu-west-1",resource="some_quota"} 1
+`
+	err := testutil.CollectAndCompare(exporter, strings.NewReader(expected), "aws_service_quota_reached")
+	assert.NoError(t, err)
+}
+
+func TestCollectDefaultModeRatio(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "some_quota", ResourceName: resourceName("i-asdasd1"), Description: "some quota", Usage: 5, Quota: 10},
+			{Name: "some_quota", ResourceName: resourceName("i-asdasd2"), Description: "some quota", Usage: 1, Quota: 0},
+		},
+	}
+
+	exporter := &ServiceQuotasExporter{
+		metricsRegion:  "eu-west-1",
+		quotasClient:   quotasClient,
+		metrics:        map[string]Metric{},
+		refreshPeriod:  360,
+		waitForMetrics: make(chan struct{}),
+	}
+
+	exporter.createOrUpdateQuotasAndDescriptions(false)
+
+	expected := `
+# HELP aws_some_quota_limit_total Limit of some quota
+# TYPE aws_some_quota_limit_total gauge
+aws_some_quota_limit_total{region="eu-west-1",resource="i-asdasd1"} 10
+aws_some_quota_limit_total{region="eu-west-1",resource="i-asdasd2"} 0
+# HELP aws_some_quota_used_total Used amount of some quota
+# TYPE aws_some_quota_used_total gauge
+aws_some_quota_used_total{region="eu-west-1",resource="i-asdasd1"} 5
+aws_some_quota_used_total{region="eu-west-1",resource="i-asdasd2"} 1
+# HELP aws_some_quota_utilization_ratio Utilization ratio of some quota
+# TYPE aws_some_quota_utilization_ratio gauge
+aws_some_quota_utilization_ratio{region="eu-west-1",resource="i-asdasd1"} 0.5
+`
+	err := testutil.CollectAndCompare(exporter, strings.NewReader(expected),
+		"aws_some_quota_utilization_ratio", "aws_some_quota_used_total", "aws_some_quota_limit_total")
+	assert.NoError(t, err)
+}
+
+func TestCollectReportedUsage(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "some_quota", Description: "some quota", Usage: 5, Quota: 10, ReportedUsage: aws.Float64(4)},
+			{Name: "other_quota", Description: "other quota", Usage: 1, Quota: 10},
+		},
+	}
+
+	exporter := &ServiceQuotasExporter{
+		metricsRegion:  "eu-west-1",
+		quotasClient:   quotasClient,
+		metrics:        map[string]Metric{},
+		refreshPeriod:  360,
+		waitForMetrics: make(chan struct{}),
+		reportedUsage:  true,
+	}
+
+	exporter.createOrUpdateQuotasAndDescriptions(false)
+
+	expected := `
+# HELP aws_some_quota_usage_reported Used amount of some quota as reported by AWS
+# TYPE aws_some_quota_usage_reported gauge
+aws_some_quota_usage_reported{region="eu-west-1",resource="some_quota"} 4
+`
+	err := testutil.CollectAndCompare(exporter, strings.NewReader(expected),
+		"aws_some_quota_usage_reported", "aws_other_quota_usage_reported")
+	assert.NoError(t, err)
+}
+
+func TestCollectMetricNames(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "some_quota", Description: "some quota", Usage: 5, Quota: 10},
+			{Name: "other_quota", Description: "other quota", Usage: 1, Quota: 10},
+		},
+	}
+
+	exporter := &ServiceQuotasExporter{
+		metricsRegion:  "eu-west-1",
+		quotasClient:   quotasClient,
+		metrics:        map[string]Metric{},
+		refreshPeriod:  360,
+		waitForMetrics: make(chan struct{}),
+		metricNames:    newMetricNames(MetricOptions{}),
+	}
+
+	exporter.createOrUpdateQuotasAndDescriptions(false)
+
+	expected := `
+# HELP aws_service_quota_usage Used amount of the quota
+# TYPE aws_service_quota_usage gauge
+aws_service_quota_usage{quota="other_quota",region="eu-west-1",resource="other_quota"} 1
+aws_service_quota_usage{quota="some_quota",region="eu-west-1",resource="some_quota"} 5
+# HELP aws_service_quota_limit Limit of the quota
+# TYPE aws_service_quota_limit gauge
+aws_service_quota_limit{quota="other_quota",region="eu-west-1",resource="other_quota"} 10
+aws_service_quota_limit{quota="some_quota",region="eu-west-1",resource="some_quota"} 10
+`
+	err := testutil.CollectAndCompare(exporter, strings.NewReader(expected),
+		"aws_service_quota_usage", "aws_service_quota_limit", "aws_some_quota_used_total")
+	assert.NoError(t, err)
+}
+
+func TestCollectCheckErrors(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "some_quota", Description: "some quota", Usage: 5, Quota: 10},
+		},
+	}
+
+	exporter := &ServiceQuotasExporter{
+		metricsRegion:  "eu-west-1",
+		quotasClient:   quotasClient,
+		metrics:        map[string]Metric{},
+		refreshPeriod:  360,
+		waitForMetrics: make(chan struct{}),
+		checkErrors:    newCheckErrorsCounter(metricsLabels("eu-west-1", ""), nil),
+	}
+	exporter.checkErrors.WithLabelValues("SomeCheck", service_quotas.ErrorCategoryOther).Inc()
+	exporter.checkErrors.WithLabelValues("SomeCheck", service_quotas.ErrorCategoryOther).Inc()
+	exporter.checkErrors.WithLabelValues("SomeCheck", service_quotas.ErrorCategoryThrottled).Inc()
+
+	exporter.createOrUpdateQuotasAndDescriptions(false)
+
+	expected := `
+# HELP aws_service_quotas_check_errors_total Number of times the usage check failed
+# TYPE aws_service_quotas_check_errors_total counter
+aws_service_quotas_check_errors_total{category="other",check="SomeCheck",region="eu-west-1"} 2
+aws_service_quotas_check_errors_total{category="throttled",check="SomeCheck",region="eu-west-1"} 1
+`
+	err := testutil.CollectAndCompare(exporter, strings.NewReader(expected), "aws_service_quotas_check_errors_total")
+	assert.NoError(t, err)
+}
+
+func TestCollectCheckDurations(t *testing.T) {
+	exporter := &ServiceQuotasExporter{
+		metricsRegion:  "eu-west-1",
+		quotasClient:   &ServiceQuotasMock{},
+		metrics:        map[string]Metric{},
+		refreshPeriod:  360,
+		waitForMetrics: make(chan struct{}),
+		checkDurations: newCheckDurationsHistogram(metricsLabels("eu-west-1", ""), nil),
+	}
+	exporter.checkDurations.WithLabelValues("SomeCheck").Observe(0.3)
+	exporter.checkDurations.WithLabelValues("SomeCheck").Observe(2)
+
+	exporter.createOrUpdateQuotasAndDescriptions(false)
+
+	expected := `
+# HELP aws_service_quotas_check_duration_seconds Duration of the usage check in seconds
+# TYPE aws_service_quotas_check_duration_seconds histogram
+aws_service_quotas_check_duration_seconds_bucket{check="SomeCheck",region="eu-west-1",le="0.05"} 0
+aws_service_quotas_check_duration_seconds_bucket{check="SomeCheck",region="eu-west-1",le="0.1"} 0
+aws_service_quotas_check_duration_seconds_bucket{check="SomeCheck",region="eu-west-1",le="0.2"} 0
+aws_service_quotas_check_duration_seconds_bucket{check="SomeCheck",region="eu-west-1",le="0.4"} 1
+aws_service_quotas_check_duration_seconds_bucket{check="SomeCheck",region="eu-west-1",le="0.8"} 1
+aws_service_quotas_check_duration_seconds_bucket{check="SomeCheck",region="eu-west-1",le="1.6"} 1
+aws_service_quotas_check_duration_seconds_bucket{check="SomeCheck",region="eu-west-1",le="3.2"} 2
+aws_service_quotas_check_duration_seconds_bucket{check="SomeCheck",region="eu-west-1",le="6.4"} 2
+aws_service_quotas_check_duration_seconds_bucket{check="SomeCheck",region="eu-west-1",le="12.8"} 2
+aws_service_quotas_check_duration_seconds_bucket{check="SomeCheck",region="eu-west-1",le="25.6"} 2
+aws_service_quotas_check_duration_seconds_bucket{check="SomeCheck",region="eu-west-1",le="51.2"} 2
+aws_service_quotas_check_duration_seconds_bucket{check="SomeCheck",region="eu-west-1",le="102.4"} 2
+aws_service_quotas_check_duration_seconds_bucket{check="SomeCheck",region="eu-west-1",le="+Inf"} 2
+aws_service_quotas_check_duration_seconds_sum{check="SomeCheck",region="eu-west-1"} 2.3
+aws_service_quotas_check_duration_seconds_count{check="SomeCheck",region="eu-west-1"} 2
+`
+	err := testutil.CollectAndCompare(exporter, strings.NewReader(expected), "aws_service_quotas_check_duration_seconds")
+	assert.NoError(t, err)
+}
+
+func TestCollectMultipleRegions(t *testing.T) {
+	regionExporters := []*ServiceQuotasExporter{}
+	for i, region := range []string{"eu-west-1", "us-east-1"} {
+		quotasClient := &ServiceQuotasMock{
+			quotas: []service_quotas.QuotaUsage{
+				{Name: "some_quota", Description: "some quota", Usage: float64(i + 1), Quota: 10},
+			},
+		}
+		regionExporter := &ServiceQuotasExporter{
+			metricsRegion:  region,
+			quotasClient:   quotasClient,
+			metrics:        map[string]Metric{},
+			refreshPeriod:  360,
+			waitForMetrics: make(chan struct{}),
+			checkErrors:    newCheckErrorsCounter(metricsLabels(region, ""), nil),
+		}
+		regionExporter.checkErrors.WithLabelValues("SomeCheck", service_quotas.ErrorCategoryOther).Inc()
+		regionExporter.createOrUpdateQuotasAndDescriptions(false)
+		regionExporters = append(regionExporters, regionExporter)
+	}
+	exporter := newMultiRegionExporter(regionExporters)
+
+	expected := `
+# HELP aws_some_quota_used_total Used amount of some quota
+# TYPE aws_some_quota_used_total gauge
+aws_some_quota_used_total{region="eu-west-1",resource="some_quota"} 1
+aws_some_quota_used_total{region="us-east-1",resource="some_quota"} 2
+# HELP aws_service_quotas_check_errors_total Number of times the usage check failed
+# TYPE aws_service_quotas_check_errors_total counter
+aws_service_quotas_check_errors_total{category="other",check="SomeCheck",region="eu-west-1"} 1
+aws_service_quotas_check_errors_total{category="other",check="SomeCheck",region="us-east-1"} 1
+`
+	err := testutil.CollectAndCompare(exporter, strings.NewReader(expected), "aws_some_quota_used_total", "aws_service_quotas_check_errors_total")
+	assert.NoError(t, err)
+	assert.True(t, exporter.Ready())
+}
+
+func TestNewServiceQuotasExporterWithDuplicateRegions(t *testing.T) {
+	_, err := NewServiceQuotasExporter(ExporterOptions{
+		Regions:       []string{"eu-west-1", "eu-west-1"},
+		RefreshPeriod: 360,
+		MetricsMode:   MetricsModeDefault,
+		NoCache:       true,
+	})
+
+	assert.Error(t, err)
+}
+
+func TestCollectMultipleAccounts(t *testing.T) {
+	accountExporters := []*ServiceQuotasExporter{}
+	for i, accountID := range []string{"111111111111", "222222222222"} {
+		quotasClient := &ServiceQuotasMock{
+			quotas: []service_quotas.QuotaUsage{
+				{Name: "some_quota", Description: "some quota", Usage: float64(i + 1), Quota: 10},
+			},
+		}
+		accountExporter := &ServiceQuotasExporter{
+			metricsRegion:    "eu-west-1",
+			metricsAccountID: accountID,
+			quotasClient:     quotasClient,
+			metrics:          map[string]Metric{},
+			refreshPeriod:    360,
+			waitForMetrics:   make(chan struct{}),
+		}
+		accountExporter.createOrUpdateQuotasAndDescriptions(false)
+		accountExporters = append(accountExporters, accountExporter)
+	}
+	exporter := newMultiRegionExporter(accountExporters)
+
+	expected := `
+# HELP aws_some_quota_used_total Used amount of some quota
+# TYPE aws_some_quota_used_total gauge
+aws_some_quota_used_total{account_id="111111111111",region="eu-west-1",resource="some_quota"} 1
+aws_some_quota_used_total{account_id="222222222222",region="eu-west-1",resource="some_quota"} 2
+`
+	err := testutil.CollectAndCompare(exporter, strings.NewReader(expected), "aws_some_quota_used_total")
+	assert.NoError(t, err)
+}
+
+func TestAssumedRoles(t *testing.T) {
+	roles, err := assumedRoles(AccountOptions{
+		RoleARNs:   []string{"arn:aws:iam::111111111111:role/quotas"},
+		ExternalID: "some-id",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []assumedRole{{roleARN: "arn:aws:iam::111111111111:role/quotas", externalID: "some-id", accountID: "111111111111"}}, roles)
+}
+
+func TestAssumedRolesWithoutRoles(t *testing.T) {
+	roles, err := assumedRoles(AccountOptions{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []assumedRole{{}}, roles)
+}
+
+func TestAssumedRolesWithInvalidARN(t *testing.T) {
+	_, err := assumedRoles(AccountOptions{RoleARNs: []string{"quotas"}})
+
+	assert.Error(t, err)
+}
+
+func TestCollectSkipsInvalidValues(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "some_quota", ResourceName: resourceName("i-asdasd1"), Description: "some quota", Usage: 5, Quota: 10},
+			{Name: "some_quota", ResourceName: resourceName("i-asdasd2"), Description: "some quota", Usage: math.NaN(), Quota: 10},
+			{Name: "some_quota", ResourceName: resourceName("i-asdasd3"), Description: "some quota", Usage: 1, Quota: math.Inf(1)},
+		},
+	}
+
+	exporter := &ServiceQuotasExporter{
+		metricsRegion:  "eu-west-1",
+		quotasClient:   quotasClient,
+		metrics:        map[string]Metric{},
+		refreshPeriod:  360,
+		waitForMetrics: make(chan struct{}),
+	}
+
+	exporter.createOrUpdateQuotasAndDescriptions(false)
+
+	expected := `
+# HELP aws_some_quota_limit_total Limit of some quota
+# TYPE aws_some_quota_limit_total gauge
+aws_some_quota_limit_total{region="eu-west-1",resource="i-asdasd1"} 10
+aws_some_quota_limit_total{region="eu-west-1",resource="i-asdasd2"} 10
+# HELP aws_some_quota_used_total Used amount of some quota
+# TYPE aws_some_quota_used_total gauge
+aws_some_quota_used_total{region="eu-west-1",resource="i-asdasd1"} 5
+aws_some_quota_used_total{region="eu-west-1",resource="i-asdasd3"} 1
+`
+	err := testutil.CollectAndCompare(exporter, strings.NewReader(expected),
+		"aws_some_quota_used_total", "aws_some_quota_limit_total")
+	assert.NoError(t, err)
+
+	exporter.metricsMode = MetricsModeRatio
+
+	expected = `
+# HELP aws_some_quota_utilization_ratio Utilization ratio of some quota
+# TYPE aws_some_quota_utilization_ratio gauge
+aws_some_quota_utilization_ratio{region="eu-west-1",resource="i-asdasd1"} 0.5
+aws_some_quota_utilization_ratio{region="eu-west-1",resource="i-asdasd3"} 0
+`
+	err = testutil.CollectAndCompare(exporter, strings.NewReader(expected), "aws_some_quota_utilization_ratio")
+	assert.NoError(t, err)
+}
+
+func TestCollectServiceRegion(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "some_quota", Description: "some quota", Usage: 5, Quota: 10, Region: "us-east-1"},
+			{Name: "other_quota", Description: "other quota", Usage: 1, Quota: 2},
+		},
+	}
+
+	exporter := &ServiceQuotasExporter{
+		metricsRegion:  "eu-west-1",
+		quotasClient:   quotasClient,
+		metrics:        map[string]Metric{},
+		refreshPeriod:  360,
+		waitForMetrics: make(chan struct{}),
+	}
+
+	exporter.createOrUpdateQuotasAndDescriptions(false)
+
+	expected := `
+# HELP aws_other_quota_used_total Used amount of other quota
+# TYPE aws_other_quota_used_total gauge
+aws_other_quota_used_total{region="eu-west-1",resource="other_quota"} 1
+# HELP aws_some_quota_used_total Used amount of some quota
+# TYPE aws_some_quota_used_total gauge
+aws_some_quota_used_total{region="us-east-1",resource="some_quota"} 5
+`
+	err := testutil.CollectAndCompare(exporter, strings.NewReader(expected),
+		"aws_some_quota_used_total", "aws_other_quota_used_total")
+	assert.NoError(t, err)
+}
+
+func TestUpdateMetricsHoldEmptyUsage(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{ResourceName: resourceName("i-asdasd1"), Usage: 0, Quota: 10},
+		},
+	}
+
+	exporter := &ServiceQuotasExporter{
+		metricsRegion: "eu-west-1",
+		quotasClient:  quotasClient,
+		metrics: map[string]Metric{
+			"i-asdasd1": Metric{usage: 3, limit: 10},
+		},
+		refreshPeriod:        360,
+		emptyRefreshesToHold: 1,
+	}
+
+	exporter.createOrUpdateQuotasAndDescriptions(true)
+	assert.Equal(t, Metric{usage: 3, limit: 10, labelValues: []string{"i-asdasd1"}, emptyRefreshes: 1}, exporter.metrics["i-asdasd1"])
+
+	exporter.createOrUpdateQuotasAndDescriptions(true)
+	assert.Equal(t, Metric{usage: 0, limit: 10, labelValues: []string{"i-asdasd1"}}, exporter.metrics["i-asdasd1"])
+
+	quotasClient.quotas[0].Usage = 4
+	exporter.createOrUpdateQuotasAndDescriptions(true)
+	assert.Equal(t, Metric{usage: 4, limit: 10, labelValues: []string{"i-asdasd1"}}, exporter.metrics["i-asdasd1"])
+}
+
+func TestUpdateMetricsStale(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "some_quota", Description: "some quota", ResourceName: resourceName("i-asdasd1"), Usage: 5, Quota: 10},
+			{Name: "other_quota", Description: "other quota", ResourceName: resourceName("sg-asdasd1"), Usage: 1, Quota: 2},
+		},
+	}
+
+	exporter := &ServiceQuotasExporter{
+		metricsRegion:  "eu-west-1",
+		quotasClient:   quotasClient,
+		metrics:        map[string]Metric{},
+		refreshPeriod:  360,
+		waitForMetrics: make(chan struct{}),
+		staleTTL:       time.Minute,
+		clock:          func() time.Time { return now },
+	}
+	exporter.createOrUpdateQuotasAndDescriptions(false)
+
+	quotasClient.quotas = quotasClient.quotas[:1]
+	quotasClient.err = errors.Wrapf(service_quotas.ErrPartialUsage, "usage checks failed for ec2")
+	now = now.Add(30 * time.Second)
+	exporter.createOrUpdateQuotasAndDescriptions(true)
+
+	expected := `
+# HELP aws_other_quota_used_total Used amount of other quota
+# TYPE aws_other_quota_used_total gauge
+aws_other_quota_used_total{region="eu-west-1",resource="sg-asdasd1"} 1
+# HELP aws_service_quotas_check_stale Whether the exported usage of the check is the last known usage from before the check failed
+# TYPE aws_service_quotas_check_stale gauge
+aws_service_quotas_check_stale{check="other_quota",region="eu-west-1"} 1
+aws_service_quotas_check_stale{check="some_quota",region="eu-west-1"} 0
+`
+	err := testutil.CollectAndCompare(exporter, strings.NewReader(expected),
+		"aws_other_quota_used_total", "aws_service_quotas_check_stale")
+	assert.NoError(t, err)
+	assert.Equal(t, "other quota", exporter.quotaDescriptions["other_quota"])
+
+	now = now.Add(time.Minute)
+	exporter.createOrUpdateQuotasAndDescriptions(true)
+
+	assert.Len(t, exporter.metrics, 1)
+	assert.Contains(t, exporter.metrics, "some_quotai-asdasd1")
+}
+
+func TestUpdateMetricsStaleRemovedByCompleteRefresh(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "some_quota", ResourceName: resourceName("i-asdasd1"), Usage: 5, Quota: 10},
+		},
+	}
+
+	exporter := &ServiceQuotasExporter{
+		metricsRegion: "eu-west-1",
+		quotasClient:  quotasClient,
+		metrics: map[string]Metric{
+			"some_quotai-asdasd1":  Metric{quotaName: "some_quota", usage: 3, limit: 10, labelValues: []string{"i-asdasd1"}},
+			"other_quotasg-asdasd": Metric{quotaName: "other_quota", usage: 1, limit: 2, labelValues: []string{"sg-asdasd"}, stale: true},
+			"other_quotasg-zxczxc": Metric{quotaName: "other_quota", usage: 1, limit: 2, labelValues: []string{"sg-zxczxc"}},
+		},
+		refreshPeriod: 360,
+		staleTTL:      time.Minute,
+	}
+	exporter.createOrUpdateQuotasAndDescriptions(true)
+
+	assert.Len(t, exporter.metrics, 1)
+	assert.NotContains(t, exporter.metrics, "other_quotasg-asdasd")
+	assert.NotContains(t, exporter.metrics, "other_quotasg-zxczxc")
+}
+
+func TestUpdateMetricsDeletedResources(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "some_quota", ResourceName: resourceName("sg-asdasd1"), Usage: 5, Quota: 10},
+			{Name: "some_quota", ResourceName: resourceName("sg-asdasd2"), Usage: 1, Quota: 10},
+			{Name: "other_quota", ResourceName: resourceName("subnet-asdasd1"), Usage: 1, Quota: 2},
+		},
+	}
+
+	exporter := &ServiceQuotasExporter{
+		metricsRegion:  "eu-west-1",
+		quotasClient:   quotasClient,
+		metrics:        map[string]Metric{},
+		refreshPeriod:  360,
+		waitForMetrics: make(chan struct{}),
+		staleTTL:       time.Minute,
+		clock:          func() time.Time { return now },
+	}
+	exporter.createOrUpdateQuotasAndDescriptions(false)
+	assert.Len(t, exporter.metrics, 3)
+
+	// sg-asdasd2 is deleted
+	quotasClient.quotas = []service_quotas.QuotaUsage{quotasClient.quotas[0], quotasClient.quotas[2]}
+	now = now.Add(30 * time.Second)
+	exporter.createOrUpdateQuotasAndDescriptions(true)
+
+	assert.Len(t, exporter.metrics, 2)
+	assert.NotContains(t, exporter.metrics, "some_quotasg-asdasd2")
+
+	// the check of other_quota fails, its subnet is kept as stale
+	quotasClient.quotas = quotasClient.quotas[:1]
+	quotasClient.err = errors.Wrapf(service_quotas.ErrPartialUsage, "usage checks failed for vpc")
+	now = now.Add(30 * time.Second)
+	exporter.createOrUpdateQuotasAndDescriptions(true)
+
+	assert.Len(t, exporter.metrics, 2)
+	assert.True(t, exporter.metrics["other_quotasubnet-asdasd1"].stale)
+
+	// the check recovers without the subnet, which was deleted
+	quotasClient.err = nil
+	now = now.Add(30 * time.Second)
+	exporter.createOrUpdateQuotasAndDescriptions(true)
+
+	assert.Len(t, exporter.metrics, 1)
+	assert.Contains(t, exporter.metrics, "some_quotasg-asdasd1")
+}
+
+func TestUpdateMetricsNewResource(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "some_quota", Description: "some quota", ResourceName: resourceName("sg-1"), Usage: 5, Quota: 10},
+		},
+	}
+	exporter := &ServiceQuotasExporter{
+		metricsRegion:  "eu-west-1",
+		quotasClient:   quotasClient,
+		metrics:        map[string]Metric{},
+		refreshPeriod:  360,
+		waitForMetrics: make(chan struct{}),
+	}
+	exporter.createOrUpdateQuotasAndDescriptions(false)
+
+	// sg-2 is created after the first refresh
+	quotasClient.quotas = append(quotasClient.quotas, service_quotas.QuotaUsage{Name: "some_quota", Description: "some quota", ResourceName: resourceName("sg-2"), Usage: 1, Quota: 10})
+	exporter.createOrUpdateQuotasAndDescriptions(true)
+
+	expected := `
+# HELP aws_some_quota_used_total Used amount of some quota
+# TYPE aws_some_quota_used_total gauge
+aws_some_quota_used_total{region="eu-west-1",resource="sg-1"} 5
+aws_some_quota_used_total{region="eu-west-1",resource="sg-2"} 1
+`
+	assert.Len(t, exporter.metrics, 2)
+	assert.NoError(t, testutil.CollectAndCompare(exporter, strings.NewReader(expected), "aws_some_quota_used_total"))
+}
+
+func TestUpdateMetricsRemovedThenAddedResource(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "some_quota", Description: "some quota", ResourceName: resourceName("sg-1"), Usage: 5, Quota: 10},
+		},
+	}
+	exporter := &ServiceQuotasExporter{
+		metricsRegion:  "eu-west-1",
+		quotasClient:   quotasClient,
+		metrics:        map[string]Metric{},
+		refreshPeriod:  360,
+		waitForMetrics: make(chan struct{}),
+	}
+	exporter.createOrUpdateQuotasAndDescriptions(false)
+
+	// sg-1 is deleted
+	quotasClient.quotas = []service_quotas.QuotaUsage{}
+	exporter.createOrUpdateQuotasAndDescriptions(true)
+	assert.Empty(t, exporter.metrics)
+
+	// sg-1 is back along with sg-2
+	quotasClient.quotas = []service_quotas.QuotaUsage{
+		{Name: "some_quota", Description: "some quota", ResourceName: resourceName("sg-1"), Usage: 3, Quota: 10},
+		{Name: "some_quota", Description: "some quota", ResourceName: resourceName("sg-2"), Usage: 1, Quota: 10},
+	}
+	exporter.createOrUpdateQuotasAndDescriptions(true)
+
+	expected := `
+# HELP aws_some_quota_used_total Used amount of some quota
+# TYPE aws_some_quota_used_total gauge
+aws_some_quota_used_total{region="eu-west-1",resource="sg-1"} 3
+aws_some_quota_used_total{region="eu-west-1",resource="sg-2"} 1
+`
+	assert.Len(t, exporter.metrics, 2)
+	assert.NoError(t, testutil.CollectAndCompare(exporter, strings.NewReader(expected), "aws_some_quota_used_total"))
+}
+
+func TestUpdateMetricsKeptByPartialRefreshWithoutStaleTTL(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "some_quota", ResourceName: resourceName("i-asdasd1"), Usage: 5, Quota: 10},
+		},
+		err: errors.Wrapf(service_quotas.ErrPartialUsage, "usage checks failed for ec2"),
+	}
+
+	exporter := &ServiceQuotasExporter{
+		metricsRegion: "eu-west-1",
+		quotasClient:  quotasClient,
+		metrics: map[string]Metric{
+			"some_quotai-asdasd1":  Metric{quotaName: "some_quota", usage: 3, limit: 10, labelValues: []string{"i-asdasd1"}},
+			"other_quotasg-asdasd": Metric{quotaName: "other_quota", usage: 1, limit: 2, labelValues: []string{"sg-asdasd"}},
+		},
+		refreshPeriod: 360,
+	}
+	exporter.createOrUpdateQuotasAndDescriptions(true)
+
+	assert.Len(t, exporter.metrics, 2)
+	assert.False(t, exporter.metrics["other_quotasg-asdasd"].stale)
+}
+
+type slowServiceQuotasMock struct {
+	ServiceQuotasMock
+	release chan struct{}
+}
+
+func (s *slowServiceQuotasMock) QuotasAndUsage() ([]service_quotas.QuotaUsage, error) {
+	<-s.release
+	return s.quotas, s.err
+}
+
+func newNoCacheExporter(quotasClient service_quotas.QuotasInterface, scrapeTimeout time.Duration) *ServiceQuotasExporter {
+	waitForMetrics := make(chan struct{})
+	close(waitForMetrics)
+	return &ServiceQuotasExporter{
+		metricsRegion:  "eu-west-1",
+		quotasClient:   quotasClient,
+		metrics:        map[string]Metric{},
+		waitForMetrics: waitForMetrics,
+		noCache:        true,
+		scrapeTimeout:  scrapeTimeout,
+	}
+}
+
+func TestCollectOnRequest(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "some_quota", Description: "some quota", Usage: 5, Quota: 10},
+		},
+	}
+	exporter := newNoCacheExporter(quotasClient, time.Second)
+
+	expected := `
+# HELP aws_some_quota_used_total Used amount of some quota
+# TYPE aws_some_quota_used_total gauge
+aws_some_quota_used_total{region="eu-west-1",resource="some_quota"} 5
+`
+	err := testutil.CollectAndCompare(exporter, strings.NewReader(expected), "aws_some_quota_used_total")
+	assert.NoError(t, err)
+
+	quotasClient.quotas[0].Usage = 7
+	expected = strings.Replace(expected, "} 5", "} 7", 1)
+	err = testutil.CollectAndCompare(exporter, strings.NewReader(expected), "aws_some_quota_used_total")
+	assert.NoError(t, err)
+	assert.Empty(t, exporter.metrics)
+}
+
+func TestCollectOnRequestReportedUsage(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "some_quota", Description: "some quota", Usage: 5, Quota: 10, ReportedUsage: aws.Float64(4)},
+		},
+	}
+	exporter := newNoCacheExporter(quotasClient, time.Second)
+	exporter.reportedUsage = true
+
+	expected := `
+# HELP aws_some_quota_usage_reported Used amount of some quota as reported by AWS
+# TYPE aws_some_quota_usage_reported gauge
+aws_some_quota_usage_reported{region="eu-west-1",resource="some_quota"} 4
+`
+	err := testutil.CollectAndCompare(exporter, strings.NewReader(expected), "aws_some_quota_usage_reported")
+	assert.NoError(t, err)
+}
+
+func TestCollectOnRequestMetricNames(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "some_quota", Description: "some quota", Usage: 5, Quota: 10},
+		},
+	}
+	exporter := newNoCacheExporter(quotasClient, time.Second)
+	exporter.metricNames = newMetricNames(MetricOptions{})
+
+	expected := `
+# HELP aws_service_quota_usage Used amount of the quota
+# TYPE aws_service_quota_usage gauge
+aws_service_quota_usage{quota="some_quota",region="eu-west-1",resource="some_quota"} 5
+`
+	err := testutil.CollectAndCompare(exporter, strings.NewReader(expected), "aws_service_quota_usage")
+	assert.NoError(t, err)
+}
+
+func TestCollectOnRequestWithError(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{err: errors.New("some err")}
+	exporter := newNoCacheExporter(quotasClient, time.Second)
+
+	assert.Equal(t, 0, testutil.CollectAndCount(exporter))
+}
+
+func TestCollectOnRequestTimeout(t *testing.T) {
+	quotasClient := &slowServiceQuotasMock{
+		ServiceQuotasMock: ServiceQuotasMock{
+			quotas: []service_quotas.QuotaUsage{{Name: "some_quota", Usage: 5, Quota: 10}},
+		},
+		release: make(chan struct{}),
+	}
+	defer close(quotasClient.release)
+	exporter := newNoCacheExporter(quotasClient, 50*time.Millisecond)
+
+	start := time.Now()
+	count := testutil.CollectAndCount(exporter)
+
+	assert.Equal(t, 0, count)
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+}
+
+type countingServiceQuotasMock struct {
+	slowServiceQuotasMock
+	calls int32
+}
+
+func (s *countingServiceQuotasMock) QuotasAndUsage() ([]service_quotas.QuotaUsage, error) {
+	atomic.AddInt32(&s.calls, 1)
+	return s.slowServiceQuotasMock.QuotasAndUsage()
+}
+
+func TestCollectOnRequestSharesRetrieval(t *testing.T) {
+	quotasClient := &countingServiceQuotasMock{
+		slowServiceQuotasMock: slowServiceQuotasMock{
+			ServiceQuotasMock: ServiceQuotasMock{
+				quotas: []service_quotas.QuotaUsage{{Name: "some_quota", Usage: 5, Quota: 10}},
+			},
+			release: make(chan struct{}),
+		},
+	}
+	exporter := newNoCacheExporter(quotasClient, time.Second)
+
+	counts := make(chan int, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			counts <- testutil.CollectAndCount(exporter, "aws_some_quota_used_total")
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(quotasClient.release)
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, 1, <-counts)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&quotasClient.calls))
+}
+
+func TestCollectOnRequestReusesRetrievalWithinRefreshPeriod(t *testing.T) {
+	now := time.Now()
+	quotasClient := &countingServiceQuotasMock{
+		slowServiceQuotasMock: slowServiceQuotasMock{
+			ServiceQuotasMock: ServiceQuotasMock{
+				quotas: []service_quotas.QuotaUsage{{Name: "some_quota", Usage: 5, Quota: 10}},
+			},
+			release: make(chan struct{}),
+		},
+	}
+	close(quotasClient.release)
+	exporter := newNoCacheExporter(quotasClient, time.Second)
+	exporter.refreshPeriod = 60
+	exporter.clock = func() time.Time { return now }
+
+	testutil.CollectAndCount(exporter)
+	now = now.Add(59 * time.Second)
+	testutil.CollectAndCount(exporter)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&quotasClient.calls))
+
+	now = now.Add(time.Second)
+	testutil.CollectAndCount(exporter)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&quotasClient.calls))
+}
+
+func TestCollectOnRequestRetriesFailedRetrieval(t *testing.T) {
+	now := time.Now()
+	quotasClient := &countingServiceQuotasMock{
+		slowServiceQuotasMock: slowServiceQuotasMock{
+			ServiceQuotasMock: ServiceQuotasMock{err: errors.New("some err")},
+			release:           make(chan struct{}),
+		},
+	}
+	close(quotasClient.release)
+	exporter := newNoCacheExporter(quotasClient, time.Second)
+	exporter.refreshPeriod = 60
+	exporter.clock = func() time.Time { return now }
+
+	assert.Equal(t, 0, testutil.CollectAndCount(exporter, "aws_some_quota_used_total"))
+
+	quotasClient.err = nil
+	quotasClient.quotas = []service_quotas.QuotaUsage{{Name: "some_quota", Usage: 5, Quota: 10}}
+	assert.Equal(t, 1, testutil.CollectAndCount(exporter, "aws_some_quota_used_total"))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&quotasClient.calls))
+}
+
+func TestCollectOnRequestRetriesPartialRetrieval(t *testing.T) {
+	quotasClient := &countingServiceQuotasMock{
+		slowServiceQuotasMock: slowServiceQuotasMock{
+			ServiceQuotasMock: ServiceQuotasMock{
+				quotas: []service_quotas.QuotaUsage{{Name: "some_quota", Usage: 5, Quota: 10}},
+				err:    errors.Wrap(service_quotas.ErrPartialUsage, "some err"),
+			},
+			release: make(chan struct{}),
+		},
+	}
+	close(quotasClient.release)
+	exporter := newNoCacheExporter(quotasClient, time.Second)
+	exporter.refreshPeriod = 60
+
+	testutil.CollectAndCount(exporter)
+	testutil.CollectAndCount(exporter)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&quotasClient.calls))
+}
+
+type emptyServiceQuotasClient struct {
+	servicequotasiface.ServiceQuotasAPI
+}
+
+func (c *emptyServiceQuotasClient) ListServiceQuotasPagesWithContext(ctx aws.Context, input *awsservicequotas.ListServiceQuotasInput, fn func(*awsservicequotas.ListServiceQuotasOutput, bool) bool, opts ...request.Option) error {
+	return nil
+}
+
+func (c *emptyServiceQuotasClient) ListAWSDefaultServiceQuotasPagesWithContext(ctx aws.Context, input *awsservicequotas.ListAWSDefaultServiceQuotasInput, fn func(*awsservicequotas.ListAWSDefaultServiceQuotasOutput, bool) bool, opts ...request.Option) error {
+	return nil
+}
+
+type slowUsageCheck struct {
+	release chan struct{}
+}
+
+func (c *slowUsageCheck) Usage(ctx context.Context) ([]service_quotas.QuotaUsage, error) {
+	<-c.release
+	return []service_quotas.QuotaUsage{{Name: "slow_quota", Usage: 1, Quota: 10}}, nil
+}
+
+func TestCollectOnRequestTimeoutWithPartialUsage(t *testing.T) {
+	slowCheck := &slowUsageCheck{release: make(chan struct{})}
+	defer close(slowCheck.release)
+	quotasClient, err := service_quotas.NewServiceQuotasWithChecks("eu-west-1", &emptyServiceQuotasClient{}, service_quotas.UsageChecks{
+		Other: []service_quotas.UsageCheck{
+			&service_quotas.FakeUsageCheck{Usages: []service_quotas.QuotaUsage{{Name: "fast_quota", Usage: 5, Quota: 10}}},
+			slowCheck,
+		},
+	}, service_quotas.Options{})
+	assert.NoError(t, err)
+	exporter := newNoCacheExporter(quotasClient, 50*time.Millisecond)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(exporter)
+	server := httptest.NewServer(promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	defer server.Close()
+
+	start := time.Now()
+	response, err := http.Get(server.URL)
+	assert.NoError(t, err)
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	assert.NoError(t, err)
+
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Contains(t, string(body), `aws_fast_quota_used_total{region="eu-west-1",resource="fast_quota"} 5`)
+	assert.NotContains(t, string(body), "slow_quota")
+}
+
+func TestCollectWithDuplicateUsage(t *testing.T) {
+	quotasClient := &ServiceQuotasMock{
+		quotas: []service_quotas.QuotaUsage{
+			{Name: "some_quota", Description: "some quota", ResourceName: aws.String("i-1"), Usage: 1, Quota: 10},
+			{Name: "some_quota", Description: "some quota", ResourceName: aws.String("i-1"), Usage: 2, Quota: 10},
+		},
+	}
+	exporter := &ServiceQuotasExporter{
+		metricsRegion:  "eu-west-1",
+		quotasClient:   quotasClient,
+		metrics:        map[string]Metric{},
+		refreshPeriod:  360,
+		waitForMetrics: make(chan struct{}),
+	}
+	exporter.createOrUpdateQuotasAndDescriptions(false)
+
+	expected := `
+# HELP aws_some_quota_used_total Used amount of some quota
+# TYPE aws_some_quota_used_total gauge
+aws_some_quota_used_total{region="eu-west-1",resource="i-1"} 2
+`
+	err := testutil.CollectAndCompare(exporter, strings.NewReader(expected), "aws_some_quota_used_total")
+	assert.NoError(t, err)
+}
+
+func TestCollectMultipleRegionsWithDuplicateMetrics(t *testing.T) {
+	regionExporters := []*ServiceQuotasExporter{}
+	for _, region := range []string{"eu-west-1", "us-east-1"} {
+		quotasClient := &ServiceQuotasMock{
+			quotas: []service_quotas.QuotaUsage{
+				{Name: "some_quota", Description: "some quota", Usage: 1, Quota: 10},
+				{Name: "global_quota", Description: "global quota", Region: "us-west-2", Usage: 3, Quota: 10},
+			},
+		}
+		regionExporter := &ServiceQuotasExporter{
+			metricsRegion:  region,
+			quotasClient:   quotasClient,
+			metrics:        map[string]Metric{},
+			refreshPeriod:  360,
+			waitForMetrics: make(chan struct{}),
+		}
+		regionExporter.createOrUpdateQuotasAndDescriptions(false)
+		regionExporters = append(regionExporters, regionExporter)
+	}
+	exporter := newMultiRegionExporter(regionExporters)
+
+	expected := `
+# HELP aws_global_quota_used_total Used amount of global quota
+# TYPE aws_global_quota_used_total gauge
+aws_global_quota_used_total{region="us-west-2",resource="global_quota"} 3
+# HELP aws_some_quota_used_total Used amount of some quota
+# TYPE aws_some_quota_used_total gauge
+aws_some_quota_used_total{region="eu-west-1",resource="some_quota"} 1
+aws_some_quota_used_total{region="us-east-1",resource="some_quota"} 1
+`
+	err := testutil.CollectAndCompare(exporter, strings.NewReader(expected), "aws_global_quota_used_total", "aws_some_quota_used_total")
+	assert.NoError(t, err)
 }
@@ -0,0 +1,95 @@
+package serviceexporter
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"regexp"
+	"sort"
+
+	service_quotas "github.com/joshua-giumelli-deltatre/aws-service-quotas-exporter/pkg/service_quotas"
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidTagMap is returned when a tag map file can not be used
+var ErrInvalidTagMap = errors.New("invalid tag map")
+
+var labelNameRE = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// reservedLabels are the label names set by the exporter itself: the
+// labels of the quota metrics, see metricLabels and metricsLabels, and
+// the variable labels of the other metrics
+var reservedLabels = labelNames(
+	[]string{"resource", "quota", "region", "account_id"},
+	checkLabels,
+	checkErrorsLabels,
+	availableQuotasLabels,
+	quotaInfoLabels,
+	quotaUnitLabels,
+)
+
+// labelNames returns the set of the label names of `labels`
+func labelNames(labels ...[]string) map[string]bool {
+	names := map[string]bool{}
+	for _, metricLabels := range labels {
+		for _, label := range metricLabels {
+			names[label] = true
+		}
+	}
+	return names
+}
+
+// ReadTagMapFile reads the JSON object in the file at `path` mapping
+// AWS tag keys to the label names used for them, e.g.
+// `{"cost-center": "team"}`, or returns an error
+func ReadTagMapFile(path string) (map[string]string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidTagMap, "failed to read %s: %s", path, err)
+	}
+
+	tagLabels := map[string]string{}
+	if err := json.Unmarshal(content, &tagLabels); err != nil {
+		return nil, errors.Wrapf(ErrInvalidTagMap, "failed to parse %s: %s", path, err)
+	}
+
+	tagsByLabel := map[string]string{}
+	for tag, label := range tagLabels {
+		if !labelNameRE.MatchString(label) || reservedLabels[label] {
+			return nil, errors.Wrapf(ErrInvalidTagMap, "invalid label name %q for tag %q", label, tag)
+		}
+		if otherTag, ok := tagsByLabel[label]; ok {
+			return nil, errors.Wrapf(ErrInvalidTagMap, "tags %q and %q are both mapped to label %q", otherTag, tag, label)
+		}
+		tagsByLabel[label] = tag
+	}
+	return tagLabels, nil
+}
+
+// includedTags returns `includedAWSTags` followed by the tags of
+// `tagLabels` that are not already included, in a stable order
+func includedTags(includedAWSTags []string, tagLabels map[string]string) []string {
+	tags := append([]string{}, includedAWSTags...)
+	included := map[string]bool{}
+	for _, tag := range includedAWSTags {
+		included[tag] = true
+	}
+
+	mappedTags := []string{}
+	for tag := range tagLabels {
+		if !included[tag] {
+			mappedTags = append(mappedTags, tag)
+		}
+	}
+	sort.Strings(mappedTags)
+	return append(tags, mappedTags...)
+}
+
+// tagLabel returns the label name for AWS tag `tag`, which is either
+// set in the tag map or the tag converted to the prometheus naming
+// format
+func (e *ServiceQuotasExporter) tagLabel(tag string) string {
+	if label, ok := e.tagLabels[tag]; ok {
+		return label
+	}
+	return service_quotas.ToPrometheusNamingFormat(tag)
+}
@@ -0,0 +1,76 @@
+package serviceexporter
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	service_quotas "github.com/joshua-giumelli-deltatre/aws-service-quotas-exporter/pkg/service_quotas"
+)
+
+func writeTagMapFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "tags.json")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestReadTagMapFile(t *testing.T) {
+	path := writeTagMapFile(t, `{"cost-center": "team", "Name": "name"}`)
+
+	tagLabels, err := ReadTagMapFile(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"cost-center": "team", "Name": "name"}, tagLabels)
+}
+
+func TestReadTagMapFileWithError(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{name: "InvalidJSON", content: `["cost-center"]`},
+		{name: "InvalidLabelName", content: `{"cost-center": "cost-center"}`},
+		{name: "ReservedLabelName", content: `{"cost-center": "resource"}`},
+		{name: "DuplicateLabelName", content: `{"cost-center": "team", "owner": "team"}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := writeTagMapFile(t, tc.content)
+
+			tagLabels, err := ReadTagMapFile(path)
+
+			assert.Error(t, err)
+			assert.True(t, errors.Is(err, ErrInvalidTagMap))
+			assert.Nil(t, tagLabels)
+		})
+	}
+}
+
+func TestMetricLabelsWithTagMap(t *testing.T) {
+	tagLabels := map[string]string{"cost-center": "team"}
+	exporter := &ServiceQuotasExporter{
+		includedAWSTags: includedTags([]string{"dummy-tag", "cost-center"}, tagLabels),
+		tagLabels:       tagLabels,
+	}
+	quota := service_quotas.QuotaUsage{
+		ResourceName: resourceName("i-asdasd1"),
+		Tags:         map[string]string{"dummy_tag": "dummy-value", "cost_center": "platform"},
+	}
+
+	labels, labelValues := exporter.metricLabels(quota)
+
+	assert.Equal(t, []string{"resource", "dummy_tag", "team"}, labels)
+	assert.Equal(t, []string{"i-asdasd1", "dummy-value", "platform"}, labelValues)
+}
+
+func TestIncludedTags(t *testing.T) {
+	tagLabels := map[string]string{"owner": "team", "cost-center": "cost", "dummy-tag": "dummy"}
+
+	tags := includedTags([]string{"dummy-tag"}, tagLabels)
+
+	assert.Equal(t, []string{"dummy-tag", "cost-center", "owner"}, tags)
+}
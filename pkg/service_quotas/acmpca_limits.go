@@ -0,0 +1,58 @@
+package servicequotas
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/acmpca"
+	"github.com/aws/aws-sdk-go/service/acmpca/acmpcaiface"
+)
+
+const (
+	certificateAuthoritiesName        = "private_certificate_authorities"
+	certificateAuthoritiesDescription = "private certificate authorities"
+)
+
+// AcmPcaCertificateAuthoritiesCheck implements the UsageCheck
+// interface for ACM Private CA certificate authorities
+type AcmPcaCertificateAuthoritiesCheck struct {
+	client acmpcaiface.ACMPCAAPI
+}
+
+// Usage returns the number of active and disabled private certificate
+// authorities or an error
+func (c *AcmPcaCertificateAuthoritiesCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var certificateAuthoritiesCount int
+
+	params := &acmpca.ListCertificateAuthoritiesInput{}
+	err := c.client.ListCertificateAuthoritiesPagesWithContext(ctx, params,
+		func(page *acmpca.ListCertificateAuthoritiesOutput, lastPage bool) bool {
+			if page != nil {
+				for _, ca := range page.CertificateAuthorities {
+					status := aws.StringValue(ca.Status)
+					if status == acmpca.CertificateAuthorityStatusActive || status == acmpca.CertificateAuthorityStatusDisabled {
+						certificateAuthoritiesCount++
+					}
+				}
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        certificateAuthoritiesName,
+			Description: certificateAuthoritiesDescription,
+			Usage:       float64(certificateAuthoritiesCount),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *AcmPcaCertificateAuthoritiesCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: certificateAuthoritiesName, Description: certificateAuthoritiesDescription}}
+}
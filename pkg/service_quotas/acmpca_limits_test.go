@@ -0,0 +1,58 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/acmpca"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockACMPCAClient) ListCertificateAuthoritiesPagesWithContext(ctx aws.Context, input *acmpca.ListCertificateAuthoritiesInput, fn func(*acmpca.ListCertificateAuthoritiesOutput, bool) bool, opts ...request.Option) error {
+	fn(m.ListCertificateAuthoritiesResponse, true)
+	return m.err
+}
+
+func TestAcmPcaCertificateAuthoritiesCheckWithError(t *testing.T) {
+	mockClient := &mockACMPCAClient{
+		err:                                errors.New("some err"),
+		ListCertificateAuthoritiesResponse: nil,
+	}
+
+	check := AcmPcaCertificateAuthoritiesCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestAcmPcaCertificateAuthoritiesCheck(t *testing.T) {
+	mockClient := &mockACMPCAClient{
+		ListCertificateAuthoritiesResponse: &acmpca.ListCertificateAuthoritiesOutput{
+			CertificateAuthorities: []*acmpca.CertificateAuthority{
+				{Status: aws.String(acmpca.CertificateAuthorityStatusActive)},
+				{Status: aws.String(acmpca.CertificateAuthorityStatusDisabled)},
+				{Status: aws.String(acmpca.CertificateAuthorityStatusDeleted)},
+				{Status: aws.String(acmpca.CertificateAuthorityStatusActive)},
+			},
+		},
+	}
+
+	check := AcmPcaCertificateAuthoritiesCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        certificateAuthoritiesName,
+			Description: certificateAuthoritiesDescription,
+			Usage:       3,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
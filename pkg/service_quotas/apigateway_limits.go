@@ -0,0 +1,149 @@
+package servicequotas
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/aws/aws-sdk-go/service/apigateway/apigatewayiface"
+	"github.com/aws/aws-sdk-go/service/apigatewayv2"
+	"github.com/aws/aws-sdk-go/service/apigatewayv2/apigatewayv2iface"
+)
+
+const (
+	restAPIsPerRegionName        = "apigateway_rest_apis_per_region"
+	restAPIsPerRegionDescription = "API Gateway REST APIs per region"
+
+	httpAPIsPerRegionName        = "apigateway_http_apis_per_region"
+	httpAPIsPerRegionDescription = "API Gateway HTTP and WebSocket APIs per region"
+
+	stagesPerRestAPIName        = "apigateway_stages_per_rest_api"
+	stagesPerRestAPIDescription = "stages per API Gateway REST API"
+)
+
+// The types of APIs counted by APIsPerRegionCheck
+const (
+	restAPIType = "rest"
+	// httpAPIType includes the HTTP and WebSocket APIs, which are
+	// both listed by the API Gateway v2 API
+	httpAPIType = "http"
+)
+
+// APIsPerRegionCheck implements the UsageCheck interface for the REST
+// APIs or the HTTP and WebSocket APIs per region, depending on its
+// `apiType`, either restAPIType or httpAPIType
+type APIsPerRegionCheck struct {
+	client   apigatewayiface.APIGatewayAPI
+	v2Client apigatewayv2iface.ApiGatewayV2API
+	apiType  string
+}
+
+// Usage returns the number of APIs of the type of the check or an
+// error
+func (c *APIsPerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var apisCount int
+	var err error
+	if c.apiType == httpAPIType {
+		apisCount, err = c.httpAPIsCount(ctx)
+	} else {
+		apisCount, err = c.restAPIsCount(ctx)
+	}
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := c.DescribeUsage()
+	usage[0].Usage = float64(apisCount)
+	return usage, nil
+}
+
+// restAPIsCount returns the number of REST APIs or an error
+func (c *APIsPerRegionCheck) restAPIsCount(ctx context.Context) (int, error) {
+	var apisCount int
+
+	err := c.client.GetRestApisPagesWithContext(ctx, &apigateway.GetRestApisInput{},
+		func(page *apigateway.GetRestApisOutput, lastPage bool) bool {
+			if page != nil {
+				apisCount += len(page.Items)
+			}
+			return !lastPage
+		},
+	)
+	return apisCount, err
+}
+
+// httpAPIsCount returns the number of HTTP and WebSocket APIs or an
+// error. The API Gateway v2 API has no paging helpers, so the pages are
+// requested until there is no next token
+func (c *APIsPerRegionCheck) httpAPIsCount(ctx context.Context) (int, error) {
+	var apisCount int
+
+	params := &apigatewayv2.GetApisInput{}
+	for {
+		output, err := c.v2Client.GetApisWithContext(ctx, params)
+		if err != nil {
+			return 0, err
+		}
+		apisCount += len(output.Items)
+		if aws.StringValue(output.NextToken) == "" {
+			return apisCount, nil
+		}
+		params.NextToken = output.NextToken
+	}
+}
+
+// variant implements the checkVariant interface
+func (c *APIsPerRegionCheck) variant() string {
+	return c.apiType
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *APIsPerRegionCheck) DescribeUsage() []QuotaUsage {
+	if c.apiType == httpAPIType {
+		return []QuotaUsage{{Name: httpAPIsPerRegionName, Description: httpAPIsPerRegionDescription}}
+	}
+	return []QuotaUsage{{Name: restAPIsPerRegionName, Description: restAPIsPerRegionDescription}}
+}
+
+// StagesPerAPICheck implements the UsageCheck interface for the stages
+// of each REST API
+type StagesPerAPICheck struct {
+	client apigatewayiface.APIGatewayAPI
+}
+
+// Usage returns the number of stages of each REST API or an error
+func (c *StagesPerAPICheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	apis := []*apigateway.RestApi{}
+	err := c.client.GetRestApisPagesWithContext(ctx, &apigateway.GetRestApisInput{},
+		func(page *apigateway.GetRestApisOutput, lastPage bool) bool {
+			if page != nil {
+				apis = append(apis, page.Items...)
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	quotaUsages := []QuotaUsage{}
+	for _, api := range apis {
+		stages, err := c.client.GetStagesWithContext(ctx, &apigateway.GetStagesInput{RestApiId: api.Id})
+		if err != nil {
+			return nil, wrapUsageErr(err)
+		}
+
+		quotaUsages = append(quotaUsages, QuotaUsage{
+			Name:         stagesPerRestAPIName,
+			ResourceName: api.Id,
+			Description:  stagesPerRestAPIDescription,
+			Usage:        float64(len(stages.Item)),
+		})
+	}
+	return quotaUsages, nil
+}
+
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *StagesPerAPICheck) DescribeResourceUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: stagesPerRestAPIName, Description: stagesPerRestAPIDescription}}
+}
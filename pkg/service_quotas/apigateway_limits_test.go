@@ -0,0 +1,167 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/aws/aws-sdk-go/service/apigatewayv2"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockAPIGatewayClient) GetRestApisPagesWithContext(ctx aws.Context, input *apigateway.GetRestApisInput, fn func(*apigateway.GetRestApisOutput, bool) bool, opts ...request.Option) error {
+	for i, page := range m.GetRestApisResponses {
+		if !fn(page, i == len(m.GetRestApisResponses)-1) {
+			break
+		}
+	}
+	return m.err
+}
+
+func (m *mockAPIGatewayClient) GetStagesWithContext(ctx aws.Context, input *apigateway.GetStagesInput, opts ...request.Option) (*apigateway.GetStagesOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.GetStagesResponses[*input.RestApiId], nil
+}
+
+func (m *mockAPIGatewayV2Client) GetApisWithContext(ctx aws.Context, input *apigatewayv2.GetApisInput, opts ...request.Option) (*apigatewayv2.GetApisOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.GetApisResponses[aws.StringValue(input.NextToken)], nil
+}
+
+func restAPIs(ids ...string) *apigateway.GetRestApisOutput {
+	output := &apigateway.GetRestApisOutput{}
+	for _, id := range ids {
+		output.Items = append(output.Items, &apigateway.RestApi{Id: aws.String(id)})
+	}
+	return output
+}
+
+func TestRestAPIsPerRegionCheckWithError(t *testing.T) {
+	mockClient := &mockAPIGatewayClient{
+		err: errors.New("some err"),
+	}
+
+	check := APIsPerRegionCheck{client: mockClient, apiType: restAPIType}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestRestAPIsPerRegionCheck(t *testing.T) {
+	mockClient := &mockAPIGatewayClient{
+		GetRestApisResponses: []*apigateway.GetRestApisOutput{restAPIs("api1", "api2"), restAPIs("api3")},
+	}
+
+	check := APIsPerRegionCheck{client: mockClient, apiType: restAPIType}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        restAPIsPerRegionName,
+			Description: restAPIsPerRegionDescription,
+			Usage:       3,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestHTTPAPIsPerRegionCheckWithError(t *testing.T) {
+	mockClient := &mockAPIGatewayV2Client{
+		err: errors.New("some err"),
+	}
+
+	check := APIsPerRegionCheck{v2Client: mockClient, apiType: httpAPIType}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestHTTPAPIsPerRegionCheck(t *testing.T) {
+	mockClient := &mockAPIGatewayV2Client{
+		GetApisResponses: map[string]*apigatewayv2.GetApisOutput{
+			"": {
+				Items: []*apigatewayv2.Api{
+					{ApiId: aws.String("api1"), ProtocolType: aws.String(apigatewayv2.ProtocolTypeHttp)},
+					{ApiId: aws.String("api2"), ProtocolType: aws.String(apigatewayv2.ProtocolTypeWebsocket)},
+				},
+				NextToken: aws.String("token"),
+			},
+			"token": {
+				Items: []*apigatewayv2.Api{
+					{ApiId: aws.String("api3"), ProtocolType: aws.String(apigatewayv2.ProtocolTypeHttp)},
+				},
+			},
+		},
+	}
+
+	check := APIsPerRegionCheck{v2Client: mockClient, apiType: httpAPIType}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        httpAPIsPerRegionName,
+			Description: httpAPIsPerRegionDescription,
+			Usage:       3,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestStagesPerAPICheckWithError(t *testing.T) {
+	mockClient := &mockAPIGatewayClient{
+		err: errors.New("some err"),
+	}
+
+	check := StagesPerAPICheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestStagesPerAPICheck(t *testing.T) {
+	mockClient := &mockAPIGatewayClient{
+		GetRestApisResponses: []*apigateway.GetRestApisOutput{restAPIs("api1", "api2")},
+		GetStagesResponses: map[string]*apigateway.GetStagesOutput{
+			"api1": {Item: []*apigateway.Stage{{StageName: aws.String("dev")}, {StageName: aws.String("prod")}}},
+			"api2": {},
+		},
+	}
+
+	check := StagesPerAPICheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:         stagesPerRestAPIName,
+			ResourceName: aws.String("api1"),
+			Description:  stagesPerRestAPIDescription,
+			Usage:        2,
+		},
+		{
+			Name:         stagesPerRestAPIName,
+			ResourceName: aws.String("api2"),
+			Description:  stagesPerRestAPIDescription,
+			Usage:        0,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
@@ -0,0 +1,111 @@
+package servicequotas
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/aws/aws-sdk-go/service/appmesh/appmeshiface"
+)
+
+const (
+	meshesPerAccountName        = "meshes_per_account"
+	meshesPerAccountDescription = "meshes per account"
+
+	virtualNodesPerMeshName        = "virtual_nodes_per_mesh"
+	virtualNodesPerMeshDescription = "virtual nodes per mesh"
+)
+
+// MeshesPerAccountCheck implements the UsageCheck interface for App
+// Mesh meshes per account
+type MeshesPerAccountCheck struct {
+	client appmeshiface.AppMeshAPI
+}
+
+// Usage returns the number of meshes or an error
+func (c *MeshesPerAccountCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	meshNames, err := listMeshes(ctx, c.client)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        meshesPerAccountName,
+			Description: meshesPerAccountDescription,
+			Usage:       float64(len(meshNames)),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *MeshesPerAccountCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: meshesPerAccountName, Description: meshesPerAccountDescription}}
+}
+
+// VirtualNodesPerMeshCheck implements the UsageCheck interface for App
+// Mesh virtual nodes per mesh
+type VirtualNodesPerMeshCheck struct {
+	client appmeshiface.AppMeshAPI
+}
+
+// Usage returns the number of virtual nodes for each mesh or an error
+func (c *VirtualNodesPerMeshCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	meshNames, err := listMeshes(ctx, c.client)
+	if err != nil {
+		return nil, err
+	}
+
+	quotaUsages := []QuotaUsage{}
+	for _, meshName := range meshNames {
+		var virtualNodesCount int
+
+		params := &appmesh.ListVirtualNodesInput{MeshName: meshName}
+		err := c.client.ListVirtualNodesPagesWithContext(ctx, params,
+			func(page *appmesh.ListVirtualNodesOutput, lastPage bool) bool {
+				if page != nil {
+					virtualNodesCount += len(page.VirtualNodes)
+				}
+				return !lastPage
+			},
+		)
+		if err != nil {
+			return nil, wrapUsageErr(err)
+		}
+
+		usage := QuotaUsage{
+			Name:         virtualNodesPerMeshName,
+			Description:  virtualNodesPerMeshDescription,
+			ResourceName: meshName,
+			Usage:        float64(virtualNodesCount),
+		}
+		quotaUsages = append(quotaUsages, usage)
+	}
+	return quotaUsages, nil
+}
+
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *VirtualNodesPerMeshCheck) DescribeResourceUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: virtualNodesPerMeshName, Description: virtualNodesPerMeshDescription}}
+}
+
+// listMeshes returns the names of all the meshes or an error
+func listMeshes(ctx context.Context, client appmeshiface.AppMeshAPI) ([]*string, error) {
+	var meshNames []*string
+
+	params := &appmesh.ListMeshesInput{}
+	err := client.ListMeshesPagesWithContext(ctx, params,
+		func(page *appmesh.ListMeshesOutput, lastPage bool) bool {
+			if page != nil {
+				for _, mesh := range page.Meshes {
+					meshNames = append(meshNames, mesh.MeshName)
+				}
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+	return meshNames, nil
+}
@@ -0,0 +1,120 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockAppMeshClient) ListMeshesPagesWithContext(ctx aws.Context, input *appmesh.ListMeshesInput, fn func(*appmesh.ListMeshesOutput, bool) bool, opts ...request.Option) error {
+	fn(m.ListMeshesResponse, true)
+	return m.err
+}
+
+func (m *mockAppMeshClient) ListVirtualNodesPagesWithContext(ctx aws.Context, input *appmesh.ListVirtualNodesInput, fn func(*appmesh.ListVirtualNodesOutput, bool) bool, opts ...request.Option) error {
+	fn(m.ListVirtualNodesResponses[*input.MeshName], true)
+	return m.err
+}
+
+func TestMeshesPerAccountCheckWithError(t *testing.T) {
+	mockClient := &mockAppMeshClient{
+		err:                errors.New("some err"),
+		ListMeshesResponse: nil,
+	}
+
+	check := MeshesPerAccountCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestMeshesPerAccountCheck(t *testing.T) {
+	mockClient := &mockAppMeshClient{
+		ListMeshesResponse: &appmesh.ListMeshesOutput{
+			Meshes: []*appmesh.MeshRef{
+				{MeshName: aws.String("mesh1")},
+				{MeshName: aws.String("mesh2")},
+			},
+		},
+	}
+
+	check := MeshesPerAccountCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        meshesPerAccountName,
+			Description: meshesPerAccountDescription,
+			Usage:       2,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestVirtualNodesPerMeshCheckWithError(t *testing.T) {
+	mockClient := &mockAppMeshClient{
+		err:                errors.New("some err"),
+		ListMeshesResponse: nil,
+	}
+
+	check := VirtualNodesPerMeshCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestVirtualNodesPerMeshCheck(t *testing.T) {
+	mockClient := &mockAppMeshClient{
+		ListMeshesResponse: &appmesh.ListMeshesOutput{
+			Meshes: []*appmesh.MeshRef{
+				{MeshName: aws.String("mesh1")},
+				{MeshName: aws.String("mesh2")},
+			},
+		},
+		ListVirtualNodesResponses: map[string]*appmesh.ListVirtualNodesOutput{
+			"mesh1": {
+				VirtualNodes: []*appmesh.VirtualNodeRef{
+					{VirtualNodeName: aws.String("node1")},
+					{VirtualNodeName: aws.String("node2")},
+				},
+			},
+			"mesh2": {
+				VirtualNodes: []*appmesh.VirtualNodeRef{
+					{VirtualNodeName: aws.String("node3")},
+				},
+			},
+		},
+	}
+
+	check := VirtualNodesPerMeshCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:         virtualNodesPerMeshName,
+			Description:  virtualNodesPerMeshDescription,
+			ResourceName: aws.String("mesh1"),
+			Usage:        2,
+		},
+		{
+			Name:         virtualNodesPerMeshName,
+			Description:  virtualNodesPerMeshDescription,
+			ResourceName: aws.String("mesh2"),
+			Usage:        1,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
@@ -1,9 +1,10 @@
 package servicequotas
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
-	"github.com/pkg/errors"
 )
 
 const (
@@ -20,11 +21,11 @@ type ASGUsageCheck struct {
 // Usage returns usage per auto scaling group - the maximum number of
 // instances per ASG and the current number of "running" instances per
 // ASG.
-func (c *ASGUsageCheck) Usage() ([]QuotaUsage, error) {
+func (c *ASGUsageCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
 
 	params := &autoscaling.DescribeAutoScalingGroupsInput{}
-	err := c.client.DescribeAutoScalingGroupsPages(params,
+	err := c.client.DescribeAutoScalingGroupsPagesWithContext(ctx, params,
 		func(page *autoscaling.DescribeAutoScalingGroupsOutput, lastPage bool) bool {
 			if page != nil {
 				for _, asg := range page.AutoScalingGroups {
@@ -50,12 +51,17 @@ func (c *ASGUsageCheck) Usage() ([]QuotaUsage, error) {
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 
 	return quotaUsages, nil
 }
 
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *ASGUsageCheck) DescribeResourceUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: numInstancesPerASGName, Description: numInstancesPerASGDescription}}
+}
+
 func isRunning(instance *autoscaling.Instance) bool {
 	notRunningStates := map[string]bool{
 		"Terminating":         true,
@@ -1,15 +1,17 @@
 package servicequotas
 
 import (
+	"context"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
-func (m *mockAutoScalingClient) DescribeAutoScalingGroupsPages(input *autoscaling.DescribeAutoScalingGroupsInput, fn func(*autoscaling.DescribeAutoScalingGroupsOutput, bool) bool) error {
+func (m *mockAutoScalingClient) DescribeAutoScalingGroupsPagesWithContext(ctx aws.Context, input *autoscaling.DescribeAutoScalingGroupsInput, fn func(*autoscaling.DescribeAutoScalingGroupsOutput, bool) bool, opts ...request.Option) error {
 	fn(m.DescribeAutoScalingGroupsResponse, true)
 	return m.err
 }
@@ -21,7 +23,7 @@ func TestASGUsageCheckWithError(t *testing.T) {
 	}
 
 	check := ASGUsageCheck{mockClient}
-	usage, err := check.Usage()
+	usage, err := check.Usage(context.Background())
 
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
@@ -66,7 +68,7 @@ func TestASGUsageCheck(t *testing.T) {
 	}
 
 	check := ASGUsageCheck{mockClient}
-	usage, err := check.Usage()
+	usage, err := check.Usage(context.Background())
 
 	expectedUsage := []QuotaUsage{
 		{
@@ -0,0 +1,169 @@
+package servicequotas
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/athena"
+	"github.com/aws/aws-sdk-go/service/athena/athenaiface"
+)
+
+const (
+	concurrentAthenaQueriesName                    = "concurrent_athena_queries"
+	concurrentAthenaQueriesDescription             = "concurrent Athena queries"
+	concurrentAthenaQueriesPerWorkGroupName        = "concurrent_athena_queries_per_workgroup"
+	concurrentAthenaQueriesPerWorkGroupDescription = "concurrent Athena queries per workgroup"
+)
+
+// batchGetQueryExecutionMaxIDs is the maximum number of query
+// execution IDs accepted by a BatchGetQueryExecution call
+const batchGetQueryExecutionMaxIDs = 50
+
+// activeQueriesWindow is how long ago the oldest query that can still
+// be queued or running was submitted. It is comfortably longer than the
+// DML query timeout of Athena, the older query executions are not
+// described
+const activeQueriesWindow = 6 * time.Hour
+
+// ActiveQueriesCheck implements the UsageCheck interface for the
+// running and queued Athena queries of the account, which count against
+// the same quota whatever their workgroup. The queries of each
+// workgroup are reported too, to find the workgroups using the quota
+type ActiveQueriesCheck struct {
+	client       athenaiface.AthenaAPI
+	maxResources int
+	// clock returns the current time, time.Now when nil
+	clock func() time.Time
+}
+
+// Usage returns the number of running and queued queries of all the
+// workgroups, followed by the number of each workgroup, or an error.
+// The usages of the workgroups are named differently, so that their
+// resource label does not clash with the usage of the account.
+// ListQueryExecutions cannot filter on the
+// state of the queries, so the query executions of each workgroup are
+// paged through, most recent first, and described page by page until
+// they were submitted more than `activeQueriesWindow` ago, up to
+// `maxResources` executions per workgroup
+func (c *ActiveQueriesCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	workGroups := []*string{}
+	err := c.client.ListWorkGroupsPagesWithContext(ctx, &athena.ListWorkGroupsInput{},
+		func(page *athena.ListWorkGroupsOutput, lastPage bool) bool {
+			if page != nil {
+				for _, workGroup := range page.WorkGroups {
+					workGroups = append(workGroups, workGroup.Name)
+				}
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	now := time.Now
+	if c.clock != nil {
+		now = c.clock
+	}
+	submittedAfter := now().Add(-activeQueriesWindow)
+
+	usages := []QuotaUsage{{
+		Name:        concurrentAthenaQueriesName,
+		Description: concurrentAthenaQueriesDescription,
+	}}
+	for _, workGroup := range workGroups {
+		activeQueries, truncated, err := c.activeQueries(ctx, workGroup, submittedAfter)
+		if err != nil {
+			return nil, wrapUsageErr(err)
+		}
+		usages[0].Usage += float64(activeQueries)
+		usages[0].Truncated = usages[0].Truncated || truncated
+		usages = append(usages, QuotaUsage{
+			Name:         concurrentAthenaQueriesPerWorkGroupName,
+			ResourceName: workGroup,
+			Description:  concurrentAthenaQueriesPerWorkGroupDescription,
+			Usage:        float64(activeQueries),
+			Truncated:    truncated,
+		})
+	}
+	return usages, nil
+}
+
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *ActiveQueriesCheck) DescribeResourceUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: concurrentAthenaQueriesPerWorkGroupName, Description: concurrentAthenaQueriesPerWorkGroupDescription}}
+}
+
+// activeQueries returns the number of running and queued queries of
+// `workGroup` and whether paging its query executions was stopped
+// after `maxResources` executions, or an error. Paging stops at the
+// first page with an execution submitted before `submittedAfter`
+func (c *ActiveQueriesCheck) activeQueries(ctx context.Context, workGroup *string, submittedAfter time.Time) (int, bool, error) {
+	executionsCap := &resourceCap{max: c.maxResources}
+	var activeQueries int
+	var describeErr error
+	params := &athena.ListQueryExecutionsInput{WorkGroup: workGroup}
+	err := c.client.ListQueryExecutionsPagesWithContext(ctx, params,
+		func(page *athena.ListQueryExecutionsOutput, lastPage bool) bool {
+			if page == nil {
+				return !lastPage
+			}
+			active, older, err := c.describeQueries(ctx, page.QueryExecutionIds, submittedAfter)
+			if err != nil {
+				describeErr = err
+				return false
+			}
+			activeQueries += active
+			if older {
+				return false
+			}
+			return executionsCap.add(len(page.QueryExecutionIds), lastPage)
+		},
+	)
+	if err == nil {
+		err = describeErr
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	if executionsCap.truncated {
+		log.Warnf("Stopped paging the query executions of Athena workgroup %s after %d executions, usage is incomplete", aws.StringValue(workGroup), executionsCap.count)
+	}
+	return activeQueries, executionsCap.truncated, nil
+}
+
+// describeQueries returns the number of running and queued queries out
+// of `executionIDs` and whether one of them was submitted before
+// `submittedAfter`, or an error
+func (c *ActiveQueriesCheck) describeQueries(ctx context.Context, executionIDs []*string, submittedAfter time.Time) (int, bool, error) {
+	var activeQueries int
+	var older bool
+	for start := 0; start < len(executionIDs); start += batchGetQueryExecutionMaxIDs {
+		end := start + batchGetQueryExecutionMaxIDs
+		if end > len(executionIDs) {
+			end = len(executionIDs)
+		}
+
+		output, err := c.client.BatchGetQueryExecutionWithContext(ctx, &athena.BatchGetQueryExecutionInput{
+			QueryExecutionIds: executionIDs[start:end],
+		})
+		if err != nil {
+			return 0, false, err
+		}
+		for _, execution := range output.QueryExecutions {
+			if execution.Status == nil {
+				continue
+			}
+			if submitted := execution.Status.SubmissionDateTime; submitted != nil && submitted.Before(submittedAfter) {
+				older = true
+			}
+			switch aws.StringValue(execution.Status.State) {
+			case athena.QueryExecutionStateRunning, athena.QueryExecutionStateQueued:
+				activeQueries++
+			}
+		}
+	}
+	return activeQueries, older, nil
+}
@@ -0,0 +1,229 @@
+package servicequotas
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/athena"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockAthenaClient) ListWorkGroupsPagesWithContext(ctx aws.Context, input *athena.ListWorkGroupsInput, fn func(*athena.ListWorkGroupsOutput, bool) bool, opts ...request.Option) error {
+	fn(m.ListWorkGroupsResponse, true)
+	return m.err
+}
+
+func (m *mockAthenaClient) ListQueryExecutionsPagesWithContext(ctx aws.Context, input *athena.ListQueryExecutionsInput, fn func(*athena.ListQueryExecutionsOutput, bool) bool, opts ...request.Option) error {
+	pages := m.ListQueryExecutionsResponses[*input.WorkGroup]
+	for i, page := range pages {
+		if !fn(page, i == len(pages)-1) {
+			break
+		}
+	}
+	return m.err
+}
+
+func (m *mockAthenaClient) BatchGetQueryExecutionWithContext(ctx aws.Context, input *athena.BatchGetQueryExecutionInput, opts ...request.Option) (*athena.BatchGetQueryExecutionOutput, error) {
+	m.BatchGetQueryExecutionInputs = append(m.BatchGetQueryExecutionInputs, input)
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.BatchGetQueryExecutionErr != nil {
+		return nil, m.BatchGetQueryExecutionErr
+	}
+
+	output := &athena.BatchGetQueryExecutionOutput{}
+	for _, id := range input.QueryExecutionIds {
+		output.QueryExecutions = append(output.QueryExecutions, m.QueryExecutions[*id])
+	}
+	return output, nil
+}
+
+// athenaNow is the current time of the Athena tests, the query
+// executions of queryExecution are submitted just before it
+var athenaNow = time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func queryExecution(id, state string) *athena.QueryExecution {
+	return submittedQueryExecution(id, state, athenaNow.Add(-time.Minute))
+}
+
+func submittedQueryExecution(id, state string, submitted time.Time) *athena.QueryExecution {
+	return &athena.QueryExecution{
+		QueryExecutionId: aws.String(id),
+		Status:           &athena.QueryExecutionStatus{State: aws.String(state), SubmissionDateTime: aws.Time(submitted)},
+	}
+}
+
+func athenaClock() time.Time {
+	return athenaNow
+}
+
+func TestActiveQueriesCheckWithError(t *testing.T) {
+	mockClient := &mockAthenaClient{
+		err:                    errors.New("some err"),
+		ListWorkGroupsResponse: &athena.ListWorkGroupsOutput{},
+	}
+
+	check := ActiveQueriesCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestActiveQueriesCheck(t *testing.T) {
+	mockClient := &mockAthenaClient{
+		ListWorkGroupsResponse: &athena.ListWorkGroupsOutput{
+			WorkGroups: []*athena.WorkGroupSummary{
+				{Name: aws.String("primary")},
+				{Name: aws.String("idle")},
+			},
+		},
+		ListQueryExecutionsResponses: map[string][]*athena.ListQueryExecutionsOutput{
+			"primary": {
+				{QueryExecutionIds: []*string{aws.String("q1"), aws.String("q2")}},
+				{QueryExecutionIds: []*string{aws.String("q3"), aws.String("q4")}},
+			},
+			"idle": {
+				{QueryExecutionIds: []*string{aws.String("q5")}},
+			},
+		},
+		QueryExecutions: map[string]*athena.QueryExecution{
+			"q1": queryExecution("q1", athena.QueryExecutionStateRunning),
+			"q2": queryExecution("q2", athena.QueryExecutionStateQueued),
+			"q3": queryExecution("q3", athena.QueryExecutionStateSucceeded),
+			"q4": queryExecution("q4", athena.QueryExecutionStateRunning),
+			"q5": queryExecution("q5", athena.QueryExecutionStateFailed),
+		},
+	}
+
+	check := ActiveQueriesCheck{client: mockClient, clock: athenaClock}
+	usage, err := check.Usage(context.Background())
+
+	// the queries of every workgroup count against the account quota
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        concurrentAthenaQueriesName,
+			Description: concurrentAthenaQueriesDescription,
+			Usage:       3,
+		},
+		{
+			Name:         concurrentAthenaQueriesPerWorkGroupName,
+			ResourceName: aws.String("primary"),
+			Description:  concurrentAthenaQueriesPerWorkGroupDescription,
+			Usage:        3,
+		},
+		{
+			Name:         concurrentAthenaQueriesPerWorkGroupName,
+			ResourceName: aws.String("idle"),
+			Description:  concurrentAthenaQueriesPerWorkGroupDescription,
+			Usage:        0,
+		},
+	}
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestActiveQueriesCheckBatchesQueryExecutionIDs(t *testing.T) {
+	ids := []*string{}
+	executions := map[string]*athena.QueryExecution{}
+	for i := 0; i < 120; i++ {
+		id := fmt.Sprintf("q%d", i)
+		ids = append(ids, aws.String(id))
+		executions[id] = queryExecution(id, athena.QueryExecutionStateRunning)
+	}
+	mockClient := &mockAthenaClient{
+		ListWorkGroupsResponse: &athena.ListWorkGroupsOutput{
+			WorkGroups: []*athena.WorkGroupSummary{{Name: aws.String("primary")}},
+		},
+		ListQueryExecutionsResponses: map[string][]*athena.ListQueryExecutionsOutput{
+			"primary": {{QueryExecutionIds: ids}},
+		},
+		QueryExecutions: executions,
+	}
+
+	check := ActiveQueriesCheck{client: mockClient, clock: athenaClock}
+	usage, err := check.Usage(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(120), usage[0].Usage)
+	assert.Len(t, mockClient.BatchGetQueryExecutionInputs, 3)
+}
+
+func TestActiveQueriesCheckWithMaxResources(t *testing.T) {
+	mockClient := &mockAthenaClient{
+		ListWorkGroupsResponse: &athena.ListWorkGroupsOutput{
+			WorkGroups: []*athena.WorkGroupSummary{{Name: aws.String("primary")}},
+		},
+		ListQueryExecutionsResponses: map[string][]*athena.ListQueryExecutionsOutput{
+			"primary": {
+				{QueryExecutionIds: []*string{aws.String("q1")}},
+				{QueryExecutionIds: []*string{aws.String("q2")}},
+			},
+		},
+		QueryExecutions: map[string]*athena.QueryExecution{
+			"q1": queryExecution("q1", athena.QueryExecutionStateRunning),
+			"q2": queryExecution("q2", athena.QueryExecutionStateRunning),
+		},
+	}
+
+	check := ActiveQueriesCheck{client: mockClient, maxResources: 1, clock: athenaClock}
+	usage, err := check.Usage(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), usage[0].Usage)
+	assert.True(t, usage[0].Truncated)
+	assert.Equal(t, float64(1), usage[1].Usage)
+	assert.True(t, usage[1].Truncated)
+}
+
+func TestActiveQueriesCheckStopsAtOlderQueries(t *testing.T) {
+	mockClient := &mockAthenaClient{
+		ListWorkGroupsResponse: &athena.ListWorkGroupsOutput{
+			WorkGroups: []*athena.WorkGroupSummary{{Name: aws.String("primary")}},
+		},
+		ListQueryExecutionsResponses: map[string][]*athena.ListQueryExecutionsOutput{
+			"primary": {
+				{QueryExecutionIds: []*string{aws.String("q1"), aws.String("q2")}},
+				{QueryExecutionIds: []*string{aws.String("q3")}},
+			},
+		},
+		QueryExecutions: map[string]*athena.QueryExecution{
+			"q1": queryExecution("q1", athena.QueryExecutionStateRunning),
+			"q2": submittedQueryExecution("q2", athena.QueryExecutionStateSucceeded, athenaNow.Add(-activeQueriesWindow-time.Minute)),
+			"q3": submittedQueryExecution("q3", athena.QueryExecutionStateRunning, athenaNow.Add(-activeQueriesWindow-time.Hour)),
+		},
+	}
+
+	check := ActiveQueriesCheck{client: mockClient, clock: athenaClock}
+	usage, err := check.Usage(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), usage[0].Usage)
+	assert.False(t, usage[0].Truncated)
+	// the executions listed after the older ones are not described
+	assert.Len(t, mockClient.BatchGetQueryExecutionInputs, 1)
+}
+
+func TestActiveQueriesCheckWithDescribeError(t *testing.T) {
+	mockClient := &mockAthenaClient{
+		ListWorkGroupsResponse: &athena.ListWorkGroupsOutput{
+			WorkGroups: []*athena.WorkGroupSummary{{Name: aws.String("primary")}},
+		},
+		ListQueryExecutionsResponses: map[string][]*athena.ListQueryExecutionsOutput{
+			"primary": {{QueryExecutionIds: []*string{aws.String("q1")}}},
+		},
+		BatchGetQueryExecutionErr: errors.New("some err"),
+	}
+
+	check := ActiveQueriesCheck{client: mockClient, clock: athenaClock}
+	usage, err := check.Usage(context.Background())
+
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
@@ -0,0 +1,171 @@
+package servicequotas
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/smithy-go"
+	"github.com/pkg/errors"
+)
+
+// The categories of the errors of the usage checks, see ErrorCategory
+const (
+	ErrorCategoryAccessDenied = "access_denied"
+	ErrorCategoryThrottled    = "throttled"
+	ErrorCategoryOther        = "other"
+)
+
+// accessDeniedErrorCodes are the AWS error codes returned when the
+// credentials are not authorized to call an API
+var accessDeniedErrorCodes = map[string]bool{
+	"AccessDenied":          true,
+	"AccessDeniedException": true,
+	"UnauthorizedOperation": true,
+}
+
+// regionNotEnabledErrorCodes are the AWS error codes returned when the
+// region is an opt-in region that is not enabled for the account. The
+// authentication errors returned by such regions are not included, as
+// they are the same as those of invalid or expired credentials
+var regionNotEnabledErrorCodes = map[string]bool{
+	"OptInRequired": true,
+}
+
+// usageError is the error of a usage check that failed to get its
+// usage. It matches ErrFailedToGetUsage and unwraps to the error it
+// was caused by, so that the AWS error remains available
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrFailedToGetUsage, e.err)
+}
+
+// Is matches ErrFailedToGetUsage
+func (e *usageError) Is(target error) bool {
+	return target == ErrFailedToGetUsage
+}
+
+// Unwrap returns the error the usage check failed with
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+// wrapUsageErr returns the error of a usage check that failed with
+// `err`
+func wrapUsageErr(err error) error {
+	return &usageError{err: err}
+}
+
+// CheckError is the error of a usage check, identifying the check that
+// failed. It unwraps to the error returned by the check, so it matches
+// ErrFailedToGetUsage and the AWS error it was caused by
+type CheckError struct {
+	// Check is the name of the usage check, see Options.OnCheckError
+	Check string
+	// Service is the service code of the quota of the check, it is
+	// empty for the checks not mapped to a service
+	Service string
+	// QuotaCode is the code of the quota of the check, it is empty for
+	// the checks that are not retrieved through service quotas
+	QuotaCode string
+	// Err is the error returned by the check
+	Err error
+}
+
+func (e *CheckError) Error() string {
+	if e.QuotaCode != "" {
+		return fmt.Sprintf("usage check %s of %s quota %s: %s", e.Check, e.Service, e.QuotaCode, e.Err)
+	}
+	if e.Service != "" {
+		return fmt.Sprintf("usage check %s of %s: %s", e.Check, e.Service, e.Err)
+	}
+	return fmt.Sprintf("usage check %s: %s", e.Check, e.Err)
+}
+
+// Unwrap returns the error returned by the check
+func (e *CheckError) Unwrap() error {
+	return e.Err
+}
+
+// isAccessDenied returns whether `err` was caused by the credentials
+// not being authorized to call an AWS API
+func isAccessDenied(err error) bool {
+	var aerr awserr.Error
+	if errors.As(err, &aerr) {
+		return accessDeniedErrorCodes[aerr.Code()]
+	}
+	// the errors of the clients of the v2 SDK, see Options.EC2SDKV2
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && accessDeniedErrorCodes[apiErr.ErrorCode()]
+}
+
+// isThrottled returns whether `err` was caused by an AWS API call being
+// throttled
+func isThrottled(err error) bool {
+	var aerr awserr.Error
+	if errors.As(err, &aerr) {
+		return request.IsErrorThrottle(aerr)
+	}
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	_, throttled := retry.DefaultThrottleErrorCodes[apiErr.ErrorCode()]
+	return throttled
+}
+
+// ErrorCategory returns the category of the error `err` of a usage
+// check, ErrorCategoryAccessDenied when the credentials are not
+// authorized to call an AWS API, ErrorCategoryThrottled when a call was
+// throttled and ErrorCategoryOther otherwise
+func ErrorCategory(err error) string {
+	switch {
+	case isAccessDenied(err):
+		return ErrorCategoryAccessDenied
+	case isThrottled(err):
+		return ErrorCategoryThrottled
+	default:
+		return ErrorCategoryOther
+	}
+}
+
+// isRegionNotEnabled returns whether `err` was caused by the region of
+// an AWS API call not being enabled for the account
+func isRegionNotEnabled(err error) bool {
+	if errors.Is(err, ErrRegionNotEnabled) {
+		return true
+	}
+	var aerr awserr.Error
+	if errors.As(err, &aerr) {
+		return regionNotEnabledErrorCodes[aerr.Code()]
+	}
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && regionNotEnabledErrorCodes[apiErr.ErrorCode()]
+}
+
+// checkVariant is implemented by the usage checks whose type is
+// registered more than once, with a different configuration each time
+type checkVariant interface {
+	// variant returns what tells the check apart from the other
+	// checks of its type
+	variant() string
+}
+
+// checkName returns the name of the type of `check`, followed by its
+// variant for the checks implementing checkVariant, which identifies
+// the check in logs and metrics
+func checkName(check UsageCheck) string {
+	checkType := reflect.TypeOf(check)
+	for checkType.Kind() == reflect.Ptr {
+		checkType = checkType.Elem()
+	}
+	if variant, ok := check.(checkVariant); ok {
+		return checkType.Name() + "/" + variant.variant()
+	}
+	return checkType.Name()
+}
@@ -0,0 +1,241 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/smithy-go"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapUsageErr(t *testing.T) {
+	awsErr := awserr.New("AccessDenied", "some message", nil)
+
+	err := wrapUsageErr(awsErr)
+
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	var aerr awserr.Error
+	assert.True(t, errors.As(err, &aerr))
+	assert.Equal(t, "AccessDenied", aerr.Code())
+}
+
+// newV2APIError returns an error with the AWS error code `code` as
+// returned by the clients of the v2 SDK
+func newV2APIError(code string) error {
+	return &smithy.OperationError{
+		ServiceID:     "EC2",
+		OperationName: "DescribeInstances",
+		Err:           &smithy.GenericAPIError{Code: code, Message: "some message"},
+	}
+}
+
+func TestIsAccessDenied(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"AccessDenied", wrapUsageErr(awserr.New("AccessDenied", "some message", nil)), true},
+		{"AccessDeniedException", wrapUsageErr(awserr.New("AccessDeniedException", "some message", nil)), true},
+		{"UnauthorizedOperation", wrapUsageErr(awserr.New("UnauthorizedOperation", "some message", nil)), true},
+		{"OtherAWSError", wrapUsageErr(awserr.New("Throttling", "some message", nil)), false},
+		{"OtherError", wrapUsageErr(errors.New("some err")), false},
+		{"V2UnauthorizedOperation", wrapUsageErr(newV2APIError("UnauthorizedOperation")), true},
+		{"V2AccessDenied", wrapUsageErr(newV2APIError("AccessDenied")), true},
+		{"V2OtherAPIError", wrapUsageErr(newV2APIError("RequestLimitExceeded")), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isAccessDenied(tc.err))
+		})
+	}
+}
+
+func TestErrorCategory(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"AccessDenied", wrapUsageErr(awserr.New("AccessDenied", "some message", nil)), ErrorCategoryAccessDenied},
+		{"Throttling", wrapUsageErr(awserr.New("Throttling", "some message", nil)), ErrorCategoryThrottled},
+		{"TooManyRequestsException", wrapUsageErr(awserr.New("TooManyRequestsException", "some message", nil)), ErrorCategoryThrottled},
+		{"CheckError", &CheckError{Check: "SomeCheck", Err: wrapUsageErr(awserr.New("RequestLimitExceeded", "some message", nil))}, ErrorCategoryThrottled},
+		{"OtherAWSError", wrapUsageErr(awserr.New("InternalError", "some message", nil)), ErrorCategoryOther},
+		{"OtherError", wrapUsageErr(errors.New("some err")), ErrorCategoryOther},
+		{"V2UnauthorizedOperation", wrapUsageErr(newV2APIError("UnauthorizedOperation")), ErrorCategoryAccessDenied},
+		{"V2RequestLimitExceeded", &CheckError{Check: "SomeCheck", Err: wrapUsageErr(newV2APIError("RequestLimitExceeded"))}, ErrorCategoryThrottled},
+		{"V2OtherAPIError", wrapUsageErr(newV2APIError("InternalError")), ErrorCategoryOther},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ErrorCategory(tc.err))
+		})
+	}
+}
+
+func TestIsRegionNotEnabled(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"OptInRequired", wrapUsageErr(awserr.New("OptInRequired", "some message", nil)), true},
+		{"V2OptInRequired", wrapUsageErr(newV2APIError("OptInRequired")), true},
+		{"InvalidClientTokenId", wrapUsageErr(awserr.New("InvalidClientTokenId", "some message", nil)), false},
+		{"UnrecognizedClientException", awserr.New("UnrecognizedClientException", "some message", nil), false},
+		{"AuthFailure", awserr.New("AuthFailure", "some message", nil), false},
+		{"ErrRegionNotEnabled", errors.Wrapf(ErrRegionNotEnabled, "some message"), true},
+		{"AccessDenied", wrapUsageErr(awserr.New("AccessDenied", "some message", nil)), false},
+		{"OtherError", wrapUsageErr(errors.New("some err")), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isRegionNotEnabled(tc.err))
+		})
+	}
+}
+
+func TestQuotasAndUsageSkipsRegionNotEnabled(t *testing.T) {
+	mockClient := &mockServiceQuotasClient{err: awserr.New("OptInRequired", "The region is not enabled for the account", nil)}
+	otherCheck := &FakeUsageCheck{Usages: []QuotaUsage{{Name: "other_quota", Usage: 2}}}
+
+	serviceQuotas, err := NewServiceQuotasWithChecks("af-south-1", mockClient, UsageChecks{
+		Other: []UsageCheck{otherCheck},
+	}, Options{})
+	assert.NoError(t, err)
+
+	usages, err := serviceQuotas.QuotasAndUsage()
+
+	// the skipped region makes the usage partial
+	assert.True(t, errors.Is(err, ErrPartialUsage))
+	assert.Empty(t, usages)
+	// the remaining services and checks of the region are skipped
+	assert.Equal(t, 1, mockClient.timesCalled)
+	assert.Equal(t, 0, otherCheck.Calls())
+
+	// the region is checked again on the next refresh
+	_, err = serviceQuotas.QuotasAndUsage()
+	assert.True(t, errors.Is(err, ErrPartialUsage))
+	assert.Equal(t, 2, mockClient.timesCalled)
+}
+
+func TestQuotasAndUsageFailsWithInvalidCredentials(t *testing.T) {
+	mockClient := &mockServiceQuotasClient{err: awserr.New("UnrecognizedClientException", "The security token included in the request is invalid", nil)}
+	otherCheck := &FakeUsageCheck{Usages: []QuotaUsage{{Name: "other_quota", Usage: 2}}}
+
+	serviceQuotas, err := NewServiceQuotasWithChecks("af-south-1", mockClient, UsageChecks{
+		Other: []UsageCheck{otherCheck},
+	}, Options{})
+	assert.NoError(t, err)
+
+	usages, err := serviceQuotas.QuotasAndUsage()
+
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrPartialUsage))
+	assert.Nil(t, usages)
+}
+
+func TestQuotasAndUsageSkipsOtherChecksRegionNotEnabled(t *testing.T) {
+	notEnabledCheck := &FakeUsageCheck{Err: wrapUsageErr(awserr.New("OptInRequired", "some message", nil))}
+	otherCheck := &FakeUsageCheck{Usages: []QuotaUsage{{Name: "other_quota", Usage: 2}}}
+
+	serviceQuotas, err := NewServiceQuotasWithChecks("me-south-1", &mockServiceQuotasClient{}, UsageChecks{
+		Other: []UsageCheck{notEnabledCheck, otherCheck},
+	}, Options{})
+	assert.NoError(t, err)
+
+	usages, err := serviceQuotas.QuotasAndUsage()
+
+	assert.True(t, errors.Is(err, ErrPartialUsage))
+	assert.Empty(t, usages)
+	assert.Equal(t, 0, otherCheck.Calls())
+}
+
+func TestQuotasAndUsageStrictRegion(t *testing.T) {
+	mockClient := &mockServiceQuotasClient{err: awserr.New("OptInRequired", "The region is not enabled for the account", nil)}
+
+	serviceQuotas, err := NewServiceQuotasWithChecks("af-south-1", mockClient, UsageChecks{}, Options{StrictRegion: true})
+	assert.NoError(t, err)
+
+	usages, err := serviceQuotas.QuotasAndUsage()
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrRegionNotEnabled))
+	assert.Nil(t, usages)
+}
+
+func TestQuotasAndUsageSkipsUnauthorizedChecks(t *testing.T) {
+	deniedCheck := &FakeUsageCheck{Err: wrapUsageErr(awserr.New("AccessDenied", "some message", nil))}
+	otherCheck := &FakeUsageCheck{Usages: []QuotaUsage{{Name: "other_quota", Usage: 2}}}
+	checkErrors := map[string]int{}
+
+	serviceQuotas, err := NewServiceQuotasWithChecks("eu-west-1", &mockServiceQuotasClient{}, UsageChecks{
+		Other: []UsageCheck{deniedCheck, otherCheck},
+	}, Options{OnCheckError: func(check string, err error) { checkErrors[check]++ }})
+	assert.NoError(t, err)
+
+	usages, err := serviceQuotas.QuotasAndUsage()
+
+	assert.NoError(t, err)
+	assert.Len(t, usages, 1)
+	assert.Equal(t, "other_quota", usages[0].Name)
+	assert.Equal(t, map[string]int{"FakeUsageCheck": 1}, checkErrors)
+}
+
+func TestQuotasAndUsageFailsOnOtherErrors(t *testing.T) {
+	failingCheck := &FakeUsageCheck{Err: wrapUsageErr(awserr.New("Throttling", "some message", nil))}
+	checkErrors := map[string]int{}
+
+	serviceQuotas, err := NewServiceQuotasWithChecks("eu-west-1", &mockServiceQuotasClient{}, UsageChecks{
+		Other: []UsageCheck{failingCheck},
+	}, Options{OnCheckError: func(check string, err error) { checkErrors[check]++ }})
+	assert.NoError(t, err)
+
+	usages, err := serviceQuotas.QuotasAndUsage()
+
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usages)
+	assert.Equal(t, map[string]int{"FakeUsageCheck": 1}, checkErrors)
+}
+
+func TestQuotasAndUsageReturnsCheckError(t *testing.T) {
+	failingCheck := &FakeUsageCheck{Err: wrapUsageErr(awserr.New("Throttling", "some message", nil))}
+	var checkErr error
+
+	serviceQuotas, err := NewServiceQuotasWithChecks("eu-west-1", &mockServiceQuotasClient{}, UsageChecks{
+		Other: []UsageCheck{failingCheck},
+	}, Options{OnCheckError: func(check string, err error) { checkErr = err }})
+	assert.NoError(t, err)
+
+	_, err = serviceQuotas.QuotasAndUsage()
+
+	var checkError *CheckError
+	assert.True(t, errors.As(err, &checkError))
+	assert.Equal(t, "FakeUsageCheck", checkError.Check)
+	assert.Empty(t, checkError.QuotaCode)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	var aerr awserr.Error
+	assert.True(t, errors.As(err, &aerr))
+	assert.Equal(t, "Throttling", aerr.Code())
+	assert.Equal(t, err, checkErr)
+}
+
+func TestRunCheckReturnsCheckError(t *testing.T) {
+	serviceQuotas := &ServiceQuotas{}
+	check := &FakeUsageCheck{Err: wrapUsageErr(errors.New("some err"))}
+
+	usages, err := serviceQuotas.runCheck(context.Background(), check, "ec2", "L-1216C47A")
+
+	assert.Nil(t, usages)
+	var checkError *CheckError
+	assert.True(t, errors.As(err, &checkError))
+	assert.Equal(t, &CheckError{Check: "FakeUsageCheck", Service: "ec2", QuotaCode: "L-1216C47A", Err: check.Err}, checkError)
+	assert.Contains(t, err.Error(), "usage check FakeUsageCheck of ec2 quota L-1216C47A")
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+}
@@ -0,0 +1,52 @@
+package servicequotas
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go/service/cloudfront/cloudfrontiface"
+)
+
+const (
+	distributionsPerAccountName        = "cloudfront_distributions_per_account"
+	distributionsPerAccountDescription = "CloudFront distributions per account"
+)
+
+// DistributionsPerAccountCheck implements the UsageCheck interface for
+// CloudFront distributions per account. CloudFront is a global service,
+// so its client must be in the global region
+type DistributionsPerAccountCheck struct {
+	client cloudfrontiface.CloudFrontAPI
+}
+
+// Usage returns the number of CloudFront distributions, enabled or
+// not, or an error
+func (c *DistributionsPerAccountCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var distributionsCount int
+
+	err := c.client.ListDistributionsPagesWithContext(ctx, &cloudfront.ListDistributionsInput{},
+		func(page *cloudfront.ListDistributionsOutput, lastPage bool) bool {
+			if page != nil && page.DistributionList != nil {
+				distributionsCount += len(page.DistributionList.Items)
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        distributionsPerAccountName,
+			Description: distributionsPerAccountDescription,
+			Usage:       float64(distributionsCount),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *DistributionsPerAccountCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: distributionsPerAccountName, Description: distributionsPerAccountDescription}}
+}
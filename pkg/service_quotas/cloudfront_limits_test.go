@@ -0,0 +1,66 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockCloudFrontClient) ListDistributionsPagesWithContext(ctx aws.Context, input *cloudfront.ListDistributionsInput, fn func(*cloudfront.ListDistributionsOutput, bool) bool, opts ...request.Option) error {
+	for i, page := range m.ListDistributionsResponses {
+		if !fn(page, i == len(m.ListDistributionsResponses)-1) {
+			break
+		}
+	}
+	return m.err
+}
+
+func distributions(ids ...string) *cloudfront.ListDistributionsOutput {
+	list := &cloudfront.DistributionList{}
+	for _, id := range ids {
+		list.Items = append(list.Items, &cloudfront.DistributionSummary{Id: aws.String(id)})
+	}
+	return &cloudfront.ListDistributionsOutput{DistributionList: list}
+}
+
+func TestDistributionsPerAccountCheckWithError(t *testing.T) {
+	mockClient := &mockCloudFrontClient{
+		err: errors.New("some err"),
+	}
+
+	check := DistributionsPerAccountCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestDistributionsPerAccountCheck(t *testing.T) {
+	mockClient := &mockCloudFrontClient{
+		ListDistributionsResponses: []*cloudfront.ListDistributionsOutput{
+			distributions("E1", "E2"),
+			distributions("E3"),
+			{},
+		},
+	}
+
+	check := DistributionsPerAccountCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        distributionsPerAccountName,
+			Description: distributionsPerAccountDescription,
+			Usage:       3,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
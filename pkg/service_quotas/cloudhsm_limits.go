@@ -0,0 +1,104 @@
+package servicequotas
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudhsmv2"
+	"github.com/aws/aws-sdk-go/service/cloudhsmv2/cloudhsmv2iface"
+)
+
+const (
+	clustersPerRegionName        = "cloudhsm_clusters_per_region"
+	clustersPerRegionDescription = "CloudHSM clusters per region"
+
+	hsmsPerClusterName        = "hsms_per_cluster"
+	hsmsPerClusterDescription = "HSMs per CloudHSM cluster"
+)
+
+// ClustersPerRegionCheck implements the UsageCheck interface for
+// CloudHSM clusters per region
+type ClustersPerRegionCheck struct {
+	client cloudhsmv2iface.CloudHSMV2API
+}
+
+// Usage returns the number of CloudHSM clusters that are not deleted
+// or an error
+func (c *ClustersPerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	clusters, err := listClusters(ctx, c.client)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        clustersPerRegionName,
+			Description: clustersPerRegionDescription,
+			Usage:       float64(len(clusters)),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *ClustersPerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: clustersPerRegionName, Description: clustersPerRegionDescription}}
+}
+
+// HsmsPerClusterCheck implements the UsageCheck interface for HSMs per
+// CloudHSM cluster
+type HsmsPerClusterCheck struct {
+	client cloudhsmv2iface.CloudHSMV2API
+}
+
+// Usage returns the number of HSMs for each CloudHSM cluster that is
+// not deleted or an error
+func (c *HsmsPerClusterCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	clusters, err := listClusters(ctx, c.client)
+	if err != nil {
+		return nil, err
+	}
+
+	quotaUsages := []QuotaUsage{}
+	for _, cluster := range clusters {
+		usage := QuotaUsage{
+			Name:         hsmsPerClusterName,
+			Description:  hsmsPerClusterDescription,
+			ResourceName: cluster.ClusterId,
+			Usage:        float64(len(cluster.Hsms)),
+		}
+		quotaUsages = append(quotaUsages, usage)
+	}
+	return quotaUsages, nil
+}
+
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *HsmsPerClusterCheck) DescribeResourceUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: hsmsPerClusterName, Description: hsmsPerClusterDescription}}
+}
+
+// listClusters returns the CloudHSM clusters that are not deleted or
+// an error. Deleted clusters are still described for a while after
+// their deletion but no longer count against the quotas
+func listClusters(ctx context.Context, client cloudhsmv2iface.CloudHSMV2API) ([]*cloudhsmv2.Cluster, error) {
+	var clusters []*cloudhsmv2.Cluster
+
+	params := &cloudhsmv2.DescribeClustersInput{}
+	err := client.DescribeClustersPagesWithContext(ctx, params,
+		func(page *cloudhsmv2.DescribeClustersOutput, lastPage bool) bool {
+			if page != nil {
+				for _, cluster := range page.Clusters {
+					if aws.StringValue(cluster.State) == cloudhsmv2.ClusterStateDeleted {
+						continue
+					}
+					clusters = append(clusters, cluster)
+				}
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+	return clusters, nil
+}
@@ -0,0 +1,113 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudhsmv2"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockCloudHSMClient) DescribeClustersPagesWithContext(ctx aws.Context, input *cloudhsmv2.DescribeClustersInput, fn func(*cloudhsmv2.DescribeClustersOutput, bool) bool, opts ...request.Option) error {
+	fn(m.DescribeClustersResponse, true)
+	return m.err
+}
+
+func testClusters() *cloudhsmv2.DescribeClustersOutput {
+	return &cloudhsmv2.DescribeClustersOutput{
+		Clusters: []*cloudhsmv2.Cluster{
+			{
+				ClusterId: aws.String("cluster-1"),
+				State:     aws.String(cloudhsmv2.ClusterStateActive),
+				Hsms:      []*cloudhsmv2.Hsm{{HsmId: aws.String("hsm-1")}, {HsmId: aws.String("hsm-2")}},
+			},
+			{
+				ClusterId: aws.String("cluster-2"),
+				State:     aws.String(cloudhsmv2.ClusterStateUninitialized),
+			},
+			{
+				ClusterId: aws.String("cluster-3"),
+				State:     aws.String(cloudhsmv2.ClusterStateDeleted),
+				Hsms:      []*cloudhsmv2.Hsm{{HsmId: aws.String("hsm-3")}},
+			},
+		},
+	}
+}
+
+func TestClustersPerRegionCheckWithError(t *testing.T) {
+	mockClient := &mockCloudHSMClient{
+		err:                      errors.New("some err"),
+		DescribeClustersResponse: nil,
+	}
+
+	check := ClustersPerRegionCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestClustersPerRegionCheck(t *testing.T) {
+	mockClient := &mockCloudHSMClient{
+		DescribeClustersResponse: testClusters(),
+	}
+
+	check := ClustersPerRegionCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        clustersPerRegionName,
+			Description: clustersPerRegionDescription,
+			Usage:       2,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestHsmsPerClusterCheckWithError(t *testing.T) {
+	mockClient := &mockCloudHSMClient{
+		err:                      errors.New("some err"),
+		DescribeClustersResponse: nil,
+	}
+
+	check := HsmsPerClusterCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestHsmsPerClusterCheck(t *testing.T) {
+	mockClient := &mockCloudHSMClient{
+		DescribeClustersResponse: testClusters(),
+	}
+
+	check := HsmsPerClusterCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:         hsmsPerClusterName,
+			Description:  hsmsPerClusterDescription,
+			ResourceName: aws.String("cluster-1"),
+			Usage:        2,
+		},
+		{
+			Name:         hsmsPerClusterName,
+			Description:  hsmsPerClusterDescription,
+			ResourceName: aws.String("cluster-2"),
+			Usage:        0,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
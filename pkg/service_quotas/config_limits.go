@@ -0,0 +1,53 @@
+package servicequotas
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/configservice"
+	"github.com/aws/aws-sdk-go/service/configservice/configserviceiface"
+)
+
+const (
+	configRulesPerRegionName        = "config_rules_per_region"
+	configRulesPerRegionDescription = "config rules per region"
+)
+
+// ConfigRulesPerRegionCheck implements the UsageCheck interface for
+// AWS Config rules per region
+type ConfigRulesPerRegionCheck struct {
+	client configserviceiface.ConfigServiceAPI
+}
+
+// Usage returns the number of AWS Config rules in the region or an
+// error
+func (c *ConfigRulesPerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	quotaUsages := []QuotaUsage{}
+
+	var configRulesCount int
+
+	params := &configservice.DescribeConfigRulesInput{}
+	err := c.client.DescribeConfigRulesPagesWithContext(ctx, params,
+		func(page *configservice.DescribeConfigRulesOutput, lastPage bool) bool {
+			if page != nil {
+				configRulesCount += len(page.ConfigRules)
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+	usage := QuotaUsage{
+		Name:        configRulesPerRegionName,
+		Description: configRulesPerRegionDescription,
+		Usage:       float64(configRulesCount),
+	}
+	quotaUsages = append(quotaUsages, usage)
+
+	return quotaUsages, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *ConfigRulesPerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: configRulesPerRegionName, Description: configRulesPerRegionDescription}}
+}
@@ -0,0 +1,90 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/configservice"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockConfigClient) DescribeConfigRulesPagesWithContext(ctx aws.Context, input *configservice.DescribeConfigRulesInput, fn func(*configservice.DescribeConfigRulesOutput, bool) bool, opts ...request.Option) error {
+	for i, page := range m.DescribeConfigRulesResponses {
+		if !fn(page, i == len(m.DescribeConfigRulesResponses)-1) {
+			break
+		}
+	}
+	return m.err
+}
+
+func configRules(names ...string) *configservice.DescribeConfigRulesOutput {
+	output := &configservice.DescribeConfigRulesOutput{}
+	for _, name := range names {
+		output.ConfigRules = append(output.ConfigRules, &configservice.ConfigRule{ConfigRuleName: aws.String(name)})
+	}
+	return output
+}
+
+func TestConfigRulesPerRegionCheckWithError(t *testing.T) {
+	mockClient := &mockConfigClient{
+		err: errors.New("some err"),
+	}
+
+	check := ConfigRulesPerRegionCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestConfigRulesPerRegionCheck(t *testing.T) {
+	testCases := []struct {
+		name          string
+		pages         []*configservice.DescribeConfigRulesOutput
+		expectedUsage float64
+	}{
+		{
+			name:          "NoRules",
+			pages:         []*configservice.DescribeConfigRulesOutput{configRules()},
+			expectedUsage: 0,
+		},
+		{
+			name:          "SinglePage",
+			pages:         []*configservice.DescribeConfigRulesOutput{configRules("rule1", "rule2")},
+			expectedUsage: 2,
+		},
+		{
+			name: "MultiplePages",
+			pages: []*configservice.DescribeConfigRulesOutput{
+				configRules("rule1", "rule2"),
+				configRules("rule3"),
+				configRules("rule4", "rule5"),
+			},
+			expectedUsage: 5,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &mockConfigClient{DescribeConfigRulesResponses: tc.pages}
+
+			check := ConfigRulesPerRegionCheck{mockClient}
+			usage, err := check.Usage(context.Background())
+
+			expectedUsage := []QuotaUsage{
+				{
+					Name:        configRulesPerRegionName,
+					Description: configRulesPerRegionDescription,
+					Usage:       tc.expectedUsage,
+				},
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, expectedUsage, usage)
+		})
+	}
+}
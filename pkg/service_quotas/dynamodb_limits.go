@@ -0,0 +1,145 @@
+package servicequotas
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+const (
+	tablesPerRegionName        = "dynamodb_tables_per_region"
+	tablesPerRegionDescription = "DynamoDB tables per region"
+
+	readCapacityPerTableName        = "dynamodb_read_capacity_units_per_table"
+	readCapacityPerTableDescription = "provisioned read capacity units per DynamoDB table"
+
+	writeCapacityPerTableName        = "dynamodb_write_capacity_units_per_table"
+	writeCapacityPerTableDescription = "provisioned write capacity units per DynamoDB table"
+)
+
+// TablesPerRegionCheck implements the UsageCheck interface for
+// DynamoDB tables per region
+type TablesPerRegionCheck struct {
+	client dynamodbiface.DynamoDBAPI
+}
+
+// Usage returns the number of DynamoDB tables or an error
+func (c *TablesPerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	tables, err := listTables(ctx, c.client)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        tablesPerRegionName,
+			Description: tablesPerRegionDescription,
+			Usage:       float64(len(tables)),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *TablesPerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: tablesPerRegionName, Description: tablesPerRegionDescription}}
+}
+
+// ReadCapacityPerTableCheck implements the UsageCheck interface for
+// the provisioned read capacity units per DynamoDB table
+type ReadCapacityPerTableCheck struct {
+	client dynamodbiface.DynamoDBAPI
+}
+
+// Usage returns the provisioned read capacity units of each DynamoDB
+// table in provisioned capacity mode or an error
+func (c *ReadCapacityPerTableCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	return provisionedCapacityPerTable(ctx, c.client, readCapacityPerTableName, readCapacityPerTableDescription,
+		func(throughput *dynamodb.ProvisionedThroughputDescription) int64 {
+			return aws.Int64Value(throughput.ReadCapacityUnits)
+		},
+	)
+}
+
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *ReadCapacityPerTableCheck) DescribeResourceUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: readCapacityPerTableName, Description: readCapacityPerTableDescription}}
+}
+
+// WriteCapacityPerTableCheck implements the UsageCheck interface for
+// the provisioned write capacity units per DynamoDB table
+type WriteCapacityPerTableCheck struct {
+	client dynamodbiface.DynamoDBAPI
+}
+
+// Usage returns the provisioned write capacity units of each DynamoDB
+// table in provisioned capacity mode or an error
+func (c *WriteCapacityPerTableCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	return provisionedCapacityPerTable(ctx, c.client, writeCapacityPerTableName, writeCapacityPerTableDescription,
+		func(throughput *dynamodb.ProvisionedThroughputDescription) int64 {
+			return aws.Int64Value(throughput.WriteCapacityUnits)
+		},
+	)
+}
+
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *WriteCapacityPerTableCheck) DescribeResourceUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: writeCapacityPerTableName, Description: writeCapacityPerTableDescription}}
+}
+
+// provisionedCapacityPerTable returns a usage named `name` with the
+// capacity units returned by `capacity` for each DynamoDB table or an
+// error. On-demand tables have no provisioned capacity and do not count
+// against the per-table throughput quotas, so they are skipped
+func provisionedCapacityPerTable(ctx context.Context, client dynamodbiface.DynamoDBAPI, name, description string, capacity func(*dynamodb.ProvisionedThroughputDescription) int64) ([]QuotaUsage, error) {
+	tables, err := listTables(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	quotaUsages := []QuotaUsage{}
+	for _, table := range tables {
+		output, err := client.DescribeTableWithContext(ctx, &dynamodb.DescribeTableInput{TableName: table})
+		if err != nil {
+			return nil, wrapUsageErr(err)
+		}
+
+		tableDescription := output.Table
+		if tableDescription.BillingModeSummary != nil && aws.StringValue(tableDescription.BillingModeSummary.BillingMode) == dynamodb.BillingModePayPerRequest {
+			continue
+		}
+		if tableDescription.ProvisionedThroughput == nil {
+			continue
+		}
+
+		usage := QuotaUsage{
+			Name:         name,
+			Description:  description,
+			ResourceName: table,
+			Usage:        float64(capacity(tableDescription.ProvisionedThroughput)),
+		}
+		quotaUsages = append(quotaUsages, usage)
+	}
+	return quotaUsages, nil
+}
+
+// listTables returns the names of the DynamoDB tables or an error
+func listTables(ctx context.Context, client dynamodbiface.DynamoDBAPI) ([]*string, error) {
+	var tables []*string
+
+	params := &dynamodb.ListTablesInput{}
+	err := client.ListTablesPagesWithContext(ctx, params,
+		func(page *dynamodb.ListTablesOutput, lastPage bool) bool {
+			if page != nil {
+				tables = append(tables, page.TableNames...)
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+	return tables, nil
+}
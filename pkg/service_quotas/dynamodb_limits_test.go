@@ -0,0 +1,161 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockDynamoDBClient) ListTablesPagesWithContext(ctx aws.Context, input *dynamodb.ListTablesInput, fn func(*dynamodb.ListTablesOutput, bool) bool, opts ...request.Option) error {
+	fn(m.ListTablesResponse, true)
+	return m.err
+}
+
+func (m *mockDynamoDBClient) DescribeTableWithContext(ctx aws.Context, input *dynamodb.DescribeTableInput, opts ...request.Option) (*dynamodb.DescribeTableOutput, error) {
+	return m.DescribeTableResponses[*input.TableName], m.err
+}
+
+func testDynamoDBClient() *mockDynamoDBClient {
+	return &mockDynamoDBClient{
+		ListTablesResponse: &dynamodb.ListTablesOutput{
+			TableNames: []*string{aws.String("table1"), aws.String("table2"), aws.String("table3")},
+		},
+		DescribeTableResponses: map[string]*dynamodb.DescribeTableOutput{
+			"table1": {
+				Table: &dynamodb.TableDescription{
+					ProvisionedThroughput: &dynamodb.ProvisionedThroughputDescription{
+						ReadCapacityUnits:  aws.Int64(100),
+						WriteCapacityUnits: aws.Int64(50),
+					},
+				},
+			},
+			"table2": {
+				Table: &dynamodb.TableDescription{
+					BillingModeSummary: &dynamodb.BillingModeSummary{BillingMode: aws.String(dynamodb.BillingModePayPerRequest)},
+					ProvisionedThroughput: &dynamodb.ProvisionedThroughputDescription{
+						ReadCapacityUnits:  aws.Int64(0),
+						WriteCapacityUnits: aws.Int64(0),
+					},
+				},
+			},
+			"table3": {
+				Table: &dynamodb.TableDescription{
+					BillingModeSummary: &dynamodb.BillingModeSummary{BillingMode: aws.String(dynamodb.BillingModeProvisioned)},
+					ProvisionedThroughput: &dynamodb.ProvisionedThroughputDescription{
+						ReadCapacityUnits:  aws.Int64(5),
+						WriteCapacityUnits: aws.Int64(10),
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestTablesPerRegionCheckWithError(t *testing.T) {
+	mockClient := &mockDynamoDBClient{
+		err:                errors.New("some err"),
+		ListTablesResponse: nil,
+	}
+
+	check := TablesPerRegionCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestTablesPerRegionCheck(t *testing.T) {
+	check := TablesPerRegionCheck{testDynamoDBClient()}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        tablesPerRegionName,
+			Description: tablesPerRegionDescription,
+			Usage:       3,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestReadCapacityPerTableCheckWithError(t *testing.T) {
+	mockClient := &mockDynamoDBClient{
+		err:                errors.New("some err"),
+		ListTablesResponse: nil,
+	}
+
+	check := ReadCapacityPerTableCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestReadCapacityPerTableCheck(t *testing.T) {
+	check := ReadCapacityPerTableCheck{testDynamoDBClient()}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:         readCapacityPerTableName,
+			Description:  readCapacityPerTableDescription,
+			ResourceName: aws.String("table1"),
+			Usage:        100,
+		},
+		{
+			Name:         readCapacityPerTableName,
+			Description:  readCapacityPerTableDescription,
+			ResourceName: aws.String("table3"),
+			Usage:        5,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestWriteCapacityPerTableCheckWithError(t *testing.T) {
+	mockClient := &mockDynamoDBClient{
+		err:                errors.New("some err"),
+		ListTablesResponse: nil,
+	}
+
+	check := WriteCapacityPerTableCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestWriteCapacityPerTableCheck(t *testing.T) {
+	check := WriteCapacityPerTableCheck{testDynamoDBClient()}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:         writeCapacityPerTableName,
+			Description:  writeCapacityPerTableDescription,
+			ResourceName: aws.String("table1"),
+			Usage:        50,
+		},
+		{
+			Name:         writeCapacityPerTableName,
+			Description:  writeCapacityPerTableDescription,
+			ResourceName: aws.String("table3"),
+			Usage:        10,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
@@ -1,8 +1,10 @@
 package servicequotas
 
 import (
+	"context"
 	"math"
-	"strconv"
+	"net"
+	"sort"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -19,9 +21,15 @@ const (
 	outboundRulesPerSecGrpName = "outbound_rules_per_security_group"
 	outboundRulesPerSecGrpDesc = "outbound rules per security group"
 
+	totalRulesPerSecGrpName = "total_rules_per_security_group"
+	totalRulesPerSecGrpDesc = "inbound and outbound rules per security group"
+
 	eNIsPerRegionName        = "enis_per_region"
 	eNIsPerRegionDescription = "ENIs per region"
 
+	eNIsPerAZName        = "enis_per_az"
+	eNIsPerAZDescription = "ENIs per availability zone"
+
 	secGroupsPerENIName = "security_groups_per_network_interface"
 	secGroupsPerENIDesc = "security groups per network interface"
 
@@ -31,6 +39,9 @@ const (
 	spotInstanceRequestsName = "spot_instance_requests"
 	spotInstanceRequestsDesc = "spot instance requests"
 
+	spotInstanceRequestsCountName = "spot_instance_requests_count"
+	spotInstanceRequestsCountDesc = "open and active spot instance requests"
+
 	onDemandInstanceRequestsName = "ondemand_instance_requests"
 	onDemandInstanceRequestsDesc = "ondemand instance requests"
 
@@ -66,21 +77,52 @@ const (
 
 	maxIo1IopsPerRegionName        = "total_io1_iops_per_region"
 	maxIo1IopsPerRegionDescription = "total IO1 IOPS per region"
+
+	managedPrefixListsPerRegionName        = "managed_prefix_lists_per_region"
+	managedPrefixListsPerRegionDescription = "customer managed prefix lists per region"
+
+	entriesPerPrefixListName        = "entries_per_prefix_list"
+	entriesPerPrefixListDescription = "entries per customer managed prefix list"
+
+	fastSnapshotRestoresPerRegionName        = "ebs_fast_snapshot_restores_per_region"
+	fastSnapshotRestoresPerRegionDescription = "EBS snapshot and availability zone pairs with fast snapshot restore enabled per region"
+
+	eNIsPerInstanceName        = "enis_per_instance"
+	eNIsPerInstanceDescription = "network interfaces attached per instance"
+
+	vpnGatewaysPerRegionName        = "vpn_gateways_per_region"
+	vpnGatewaysPerRegionDescription = "virtual private gateways per region"
+
+	customerGatewaysPerRegionName        = "customer_gateways_per_region"
+	customerGatewaysPerRegionDescription = "customer gateways per region"
 )
 
+// awsManagedPrefixListOwner is the owner ID of the prefix lists managed
+// by AWS, which do not count against the quotas of the account
+const awsManagedPrefixListOwner = "AWS"
+
 // RulesPerSecurityGroupUsageCheck implements the UsageCheck interface
 // for rules per security group
 type RulesPerSecurityGroupUsageCheck struct {
 	client ec2iface.EC2API
+	// includeTotal additionally reports the sum of the inbound and
+	// outbound rules for each security group
+	includeTotal bool
 }
 
-// Usage returns the usage for each security group ID with the usage
-// value being the sum of their inbound and outbound rules or an error
-func (c *RulesPerSecurityGroupUsageCheck) Usage() ([]QuotaUsage, error) {
+// Usage returns the inbound and the outbound rules usage for each
+// security group ID or an error
+// The "inbound or outbound rules per security group" quota applies to
+// the inbound and the outbound rules of each security group
+// separately, so the same quota value is set on every returned usage
+// when the service quotas are retrieved
+// If `includeTotal` is set, the sum of the inbound and outbound rules
+// is also returned for each security group
+func (c *RulesPerSecurityGroupUsageCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
 
 	params := &ec2.DescribeSecurityGroupsInput{}
-	err := c.client.DescribeSecurityGroupsPages(params,
+	err := c.client.DescribeSecurityGroupsPagesWithContext(ctx, params,
 		func(page *ec2.DescribeSecurityGroupsOutput, lastPage bool) bool {
 			if page != nil {
 				for _, group := range page.SecurityGroups {
@@ -104,7 +146,7 @@ func (c *RulesPerSecurityGroupUsageCheck) Usage() ([]QuotaUsage, error) {
 
 					for _, rule := range group.IpPermissionsEgress {
 						outboundRules += len(rule.IpRanges)
-						inboundRules += len(rule.UserIdGroupPairs)
+						outboundRules += len(rule.UserIdGroupPairs)
 					}
 
 					outboundUsage := QuotaUsage{
@@ -116,32 +158,67 @@ func (c *RulesPerSecurityGroupUsageCheck) Usage() ([]QuotaUsage, error) {
 					}
 
 					quotaUsages = append(quotaUsages, []QuotaUsage{inboundUsage, outboundUsage}...)
+
+					if c.includeTotal {
+						totalUsage := QuotaUsage{
+							Name:         totalRulesPerSecGrpName,
+							ResourceName: group.GroupId,
+							Description:  totalRulesPerSecGrpDesc,
+							Usage:        float64(inboundRules + outboundRules),
+							Tags:         tags,
+						}
+						quotaUsages = append(quotaUsages, totalUsage)
+					}
 				}
 			}
 			return !lastPage
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 
 	return quotaUsages, nil
 }
 
+// limitScale implements the limitScaler interface. The quota limits the
+// inbound and the outbound rules separately, so the combined rules are
+// limited by twice the quota
+func (c *RulesPerSecurityGroupUsageCheck) limitScale(usage QuotaUsage) float64 {
+	if usage.Name == totalRulesPerSecGrpName {
+		return 2
+	}
+	return 1
+}
+
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *RulesPerSecurityGroupUsageCheck) DescribeResourceUsage() []QuotaUsage {
+	usages := []QuotaUsage{
+		{Name: inboundRulesPerSecGrpName, Description: inboundRulesPerSecGrpDesc},
+		{Name: outboundRulesPerSecGrpName, Description: outboundRulesPerSecGrpDesc},
+	}
+	if c.includeTotal {
+		usages = append(usages, QuotaUsage{Name: totalRulesPerSecGrpName, Description: totalRulesPerSecGrpDesc})
+	}
+	return usages
+}
+
 // SecurityGroupsPerENIUsageCheck implements the UsageCheck interface
 // for security groups per ENI
 type SecurityGroupsPerENIUsageCheck struct {
-	client ec2iface.EC2API
+	client       ec2iface.EC2API
+	maxResources int
 }
 
 // Usage returns usage for each Elastic Network Interface ID with the
 // usage value being the number of security groups for each ENI or an
 // error
-func (c *SecurityGroupsPerENIUsageCheck) Usage() ([]QuotaUsage, error) {
+func (c *SecurityGroupsPerENIUsageCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
+	eniCap := &resourceCap{max: c.maxResources}
 
 	params := &ec2.DescribeNetworkInterfacesInput{}
-	err := c.client.DescribeNetworkInterfacesPages(params,
+	err := c.client.DescribeNetworkInterfacesPagesWithContext(ctx, params,
 		func(page *ec2.DescribeNetworkInterfacesOutput, lastPage bool) bool {
 			if page != nil {
 				for _, eni := range page.NetworkInterfaces {
@@ -154,17 +231,24 @@ func (c *SecurityGroupsPerENIUsageCheck) Usage() ([]QuotaUsage, error) {
 					}
 					quotaUsages = append(quotaUsages, usage)
 				}
+				return eniCap.add(len(page.NetworkInterfaces), lastPage)
 			}
 			return !lastPage
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 
+	eniCap.markTruncated(quotaUsages)
 	return quotaUsages, nil
 }
 
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *SecurityGroupsPerENIUsageCheck) DescribeResourceUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: secGroupsPerENIName, Description: secGroupsPerENIDesc}}
+}
+
 // SecurityGroupsPerRegionUsageCheck implements the UsageCheck interface
 // for security groups per region
 type SecurityGroupsPerRegionUsageCheck struct {
@@ -173,11 +257,11 @@ type SecurityGroupsPerRegionUsageCheck struct {
 
 // Usage returns usage for security groups per region as the number of
 // all security groups for the region specified with `cfgs` or an error
-func (c *SecurityGroupsPerRegionUsageCheck) Usage() ([]QuotaUsage, error) {
+func (c *SecurityGroupsPerRegionUsageCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	numGroups := 0
 
 	params := &ec2.DescribeSecurityGroupsInput{}
-	err := c.client.DescribeSecurityGroupsPages(params,
+	err := c.client.DescribeSecurityGroupsPagesWithContext(ctx, params,
 		func(page *ec2.DescribeSecurityGroupsOutput, lastPage bool) bool {
 			if page != nil {
 				numGroups += len(page.SecurityGroups)
@@ -186,7 +270,7 @@ func (c *SecurityGroupsPerRegionUsageCheck) Usage() ([]QuotaUsage, error) {
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 
 	usage := []QuotaUsage{
@@ -199,6 +283,11 @@ func (c *SecurityGroupsPerRegionUsageCheck) Usage() ([]QuotaUsage, error) {
 	return usage, nil
 }
 
+// DescribeUsage implements the UsageDescriber interface
+func (c *SecurityGroupsPerRegionUsageCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: securityGroupsPerRegionName, Description: securityGroupsPerRegionDesc}}
+}
+
 func standardInstanceTypeFilter() *ec2.Filter {
 	return &ec2.Filter{
 		Name: aws.String("instance-type"),
@@ -226,14 +315,24 @@ func activeInstanceFilter() *ec2.Filter {
 	}
 }
 
+// describeInstanceTypesBatchSize is the maximum number of instance
+// types DescribeInstanceTypes accepts in a single call
+const describeInstanceTypesBatchSize = 100
+
 // standardInstancesCPUs returns the number of vCPUs for all standard
 // (A, C, D, H, I, M, R, T, Z) EC2 instances
 // Note that we are working out the number of vCPUs for each instance
 // here because instances can have custom CPU options specified during
 // launch. More information can be found at
 // https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/instance-optimize-cpu.html
-func standardInstancesCPUs(ec2Service ec2iface.EC2API, spotInstances bool) (int64, error) {
+// Instances launched by EC2 Fleet or Spot Fleet count against the quota
+// with their vCPUs like any other instance, the instance weights of the
+// fleet only apply to its target capacity. The default vCPUs of the
+// instance type, from `instanceTypes`, are counted for instances
+// without CPU options
+func standardInstancesCPUs(ctx context.Context, ec2Service ec2iface.EC2API, instanceTypes *instanceTypesCache, spotInstances bool) (int64, error) {
 	var totalvCPUs int64
+	instancesWithoutCPUOptions := map[string]int64{}
 	instanceTypeFilter := standardInstanceTypeFilter()
 	instanceStateFilter := activeInstanceFilter()
 	filters := []*ec2.Filter{instanceTypeFilter, instanceStateFilter}
@@ -250,7 +349,7 @@ func standardInstancesCPUs(ec2Service ec2iface.EC2API, spotInstances bool) (int6
 	}
 
 	params := &ec2.DescribeInstancesInput{Filters: filters}
-	err := ec2Service.DescribeInstancesPages(params,
+	err := ec2Service.DescribeInstancesPagesWithContext(ctx, params,
 		func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
 			if page != nil {
 				for _, reservation := range page.Reservations {
@@ -261,9 +360,11 @@ func standardInstancesCPUs(ec2Service ec2iface.EC2API, spotInstances bool) (int6
 						}
 
 						cpuOptions := instance.CpuOptions
-						if cpuOptions.CoreCount != nil && cpuOptions.ThreadsPerCore != nil {
+						if cpuOptions != nil && cpuOptions.CoreCount != nil && cpuOptions.ThreadsPerCore != nil {
 							numvCPUs := *cpuOptions.CoreCount * *cpuOptions.ThreadsPerCore
 							totalvCPUs += numvCPUs
+						} else {
+							instancesWithoutCPUOptions[aws.StringValue(instance.InstanceType)]++
 						}
 					}
 				}
@@ -275,13 +376,73 @@ func standardInstancesCPUs(ec2Service ec2iface.EC2API, spotInstances bool) (int6
 		return 0, err
 	}
 
+	if len(instancesWithoutCPUOptions) > 0 {
+		names := []string{}
+		for instanceType := range instancesWithoutCPUOptions {
+			names = append(names, instanceType)
+		}
+		defaultvCPUs, err := instanceTypesDefaultVCPUs(ctx, ec2Service, instanceTypes, names)
+		if err != nil {
+			return 0, err
+		}
+		for instanceType, count := range instancesWithoutCPUOptions {
+			totalvCPUs += count * defaultvCPUs[instanceType]
+		}
+	}
+
 	return totalvCPUs, nil
 }
 
+// instanceTypesDefaultVCPUs returns the default number of vCPUs of
+// each of `names` from `instanceTypes` or an error
+func instanceTypesDefaultVCPUs(ctx context.Context, ec2Service ec2iface.EC2API, instanceTypes *instanceTypesCache, names []string) (map[string]int64, error) {
+	infos, err := instanceTypes.get(ctx, ec2Service, names)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultvCPUs := map[string]int64{}
+	for name, info := range infos {
+		if info.VCpuInfo != nil {
+			defaultvCPUs[name] = aws.Int64Value(info.VCpuInfo.DefaultVCpus)
+		}
+	}
+	return defaultvCPUs, nil
+}
+
+// describeInstanceTypes calls `fn` with the information of each of
+// `instanceTypes`, describing them in batches of
+// `describeInstanceTypesBatchSize`
+func describeInstanceTypes(ctx context.Context, ec2Service ec2iface.EC2API, instanceTypes []string, fn func(instanceType *ec2.InstanceTypeInfo)) error {
+	for start := 0; start < len(instanceTypes); start += describeInstanceTypesBatchSize {
+		end := start + describeInstanceTypesBatchSize
+		if end > len(instanceTypes) {
+			end = len(instanceTypes)
+		}
+
+		params := &ec2.DescribeInstanceTypesInput{InstanceTypes: aws.StringSlice(instanceTypes[start:end])}
+		err := ec2Service.DescribeInstanceTypesPagesWithContext(ctx, params,
+			func(page *ec2.DescribeInstanceTypesOutput, lastPage bool) bool {
+				if page != nil {
+					for _, instanceType := range page.InstanceTypes {
+						fn(instanceType)
+					}
+				}
+				return !lastPage
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // StandardSpotInstanceRequestsUsageCheck implements the UsageCheck interface
 // for standard spot instance requests
 type StandardSpotInstanceRequestsUsageCheck struct {
-	client ec2iface.EC2API
+	client        ec2iface.EC2API
+	instanceTypes *instanceTypesCache
 }
 
 // Usage returns vCPU usage for all standard (A, C, D, H, I, M, R, T,
@@ -289,10 +450,10 @@ type StandardSpotInstanceRequestsUsageCheck struct {
 // vCPUs are returned instead of the number of images due to the
 // service quota reporting the number of vCPUs
 // https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/using-spot-limits.html
-func (c *StandardSpotInstanceRequestsUsageCheck) Usage() ([]QuotaUsage, error) {
-	cpus, err := standardInstancesCPUs(c.client, true)
+func (c *StandardSpotInstanceRequestsUsageCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	cpus, err := standardInstancesCPUs(ctx, c.client, c.instanceTypes, true)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 
 	usage := []QuotaUsage{
@@ -305,10 +466,64 @@ func (c *StandardSpotInstanceRequestsUsageCheck) Usage() ([]QuotaUsage, error) {
 	return usage, nil
 }
 
+// DescribeUsage implements the UsageDescriber interface
+func (c *StandardSpotInstanceRequestsUsageCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: spotInstanceRequestsName, Description: spotInstanceRequestsDesc}}
+}
+
+// SpotInstanceRequestsCountCheck implements the UsageCheck interface
+// for the number of spot instance requests
+type SpotInstanceRequestsCountCheck struct {
+	client ec2iface.EC2API
+}
+
+// Usage returns the number of open and active spot instance requests
+// or an error. Unlike StandardSpotInstanceRequestsUsageCheck the
+// requests are counted instead of their vCPUs. The quota is not
+// published by Service Quotas so no limit is reported
+func (c *SpotInstanceRequestsCountCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var requestsCount int
+
+	params := &ec2.DescribeSpotInstanceRequestsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("state"),
+				Values: aws.StringSlice([]string{ec2.SpotInstanceStateOpen, ec2.SpotInstanceStateActive}),
+			},
+		},
+	}
+	err := c.client.DescribeSpotInstanceRequestsPagesWithContext(ctx, params,
+		func(page *ec2.DescribeSpotInstanceRequestsOutput, lastPage bool) bool {
+			if page != nil {
+				requestsCount += len(page.SpotInstanceRequests)
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        spotInstanceRequestsCountName,
+			Description: spotInstanceRequestsCountDesc,
+			Usage:       float64(requestsCount),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *SpotInstanceRequestsCountCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: spotInstanceRequestsCountName, Description: spotInstanceRequestsCountDesc}}
+}
+
 // RunningOnDemandStandardInstancesUsageCheck implements the UsageCheck interface
 // for standard on-demand instances
 type RunningOnDemandStandardInstancesUsageCheck struct {
-	client ec2iface.EC2API
+	client        ec2iface.EC2API
+	instanceTypes *instanceTypesCache
 }
 
 // Usage returns vCPU usage for all running on-demand standard (A, C,
@@ -316,10 +531,10 @@ type RunningOnDemandStandardInstancesUsageCheck struct {
 // of the number of images due to the service quota reporting the number
 // of vCPUs
 // https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/using-spot-limits.html
-func (c *RunningOnDemandStandardInstancesUsageCheck) Usage() ([]QuotaUsage, error) {
-	cpus, err := standardInstancesCPUs(c.client, false)
+func (c *RunningOnDemandStandardInstancesUsageCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	cpus, err := standardInstancesCPUs(ctx, c.client, c.instanceTypes, false)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 
 	usage := []QuotaUsage{
@@ -332,6 +547,15 @@ func (c *RunningOnDemandStandardInstancesUsageCheck) Usage() ([]QuotaUsage, erro
 	return usage, nil
 }
 
+// DescribeUsage implements the UsageDescriber interface
+func (c *RunningOnDemandStandardInstancesUsageCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: onDemandInstanceRequestsName, Description: onDemandInstanceRequestsDesc}}
+}
+
+// reservedIPsPerSubnet is the number of IPv4 addresses AWS reserves in
+// every subnet, which are not counted in its available addresses
+const reservedIPsPerSubnet = 5
+
 // AvailableIpsPerSubnetUsageCheck implements the UsageCheckInterface
 // for available IPs per subnet
 type AvailableIpsPerSubnetUsageCheck struct {
@@ -340,27 +564,34 @@ type AvailableIpsPerSubnetUsageCheck struct {
 
 // Usage returns the usage for each subnet ID with the usage value
 // being the number of available IPv4 addresses in that subnet or
-// an error
+// an error. The quota of a subnet excludes the addresses reserved by
+// AWS. IPv6-only subnets are skipped
 // Note that the Description of the resource here is constructed
 // using `availableIPsPerSubnetDesc` defined previously as well as
 // the subnet's CIDR block
-func (c *AvailableIpsPerSubnetUsageCheck) Usage() ([]QuotaUsage, error) {
+func (c *AvailableIpsPerSubnetUsageCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	availabilityInfos := []QuotaUsage{}
 	var conversionErr error
 
 	params := &ec2.DescribeSubnetsInput{}
-	err := c.client.DescribeSubnetsPages(params,
+	err := c.client.DescribeSubnetsPagesWithContext(ctx, params,
 		func(page *ec2.DescribeSubnetsOutput, lastPage bool) bool {
 			if page != nil {
 				for _, subnet := range page.Subnets {
-					cidrBlock := *subnet.CidrBlock
-					blockedBits, err := strconv.Atoi(cidrBlock[len(cidrBlock)-2:])
+					// IPv6-only subnets have no IPv4 addresses, and the
+					// IPv6 CIDR blocks of dual-stack subnets are not
+					// counted in their available addresses
+					if aws.BoolValue(subnet.Ipv6Native) || subnet.CidrBlock == nil {
+						log.Debugf("Skipping IPv6-only subnet %s", aws.StringValue(subnet.SubnetId))
+						continue
+					}
+					_, ipNet, err := net.ParseCIDR(*subnet.CidrBlock)
 					if err != nil {
-						conversionErr = errors.Wrapf(ErrFailedToConvertCidr, "%w", err)
-						// stops paging if strconv experiences an error
-						return true
+						conversionErr = errors.Wrapf(ErrFailedToConvertCidr, "%s", err)
+						return false
 					}
-					maxNumOfIPs := math.Pow(2, 32-float64(blockedBits))
+					ones, bits := ipNet.Mask.Size()
+					maxNumOfIPs := math.Pow(2, float64(bits-ones)) - reservedIPsPerSubnet
 					usage := float64(maxNumOfIPs - float64(*subnet.AvailableIpAddressCount))
 					availabilityInfo := QuotaUsage{
 						Name:         availableIPsPerSubnetName,
@@ -377,7 +608,7 @@ func (c *AvailableIpsPerSubnetUsageCheck) Usage() ([]QuotaUsage, error) {
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 
 	if conversionErr != nil {
@@ -387,6 +618,11 @@ func (c *AvailableIpsPerSubnetUsageCheck) Usage() ([]QuotaUsage, error) {
 	return availabilityInfos, nil
 }
 
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *AvailableIpsPerSubnetUsageCheck) DescribeResourceUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: availableIPsPerSubnetName, Description: availableIPsPerSubnetDesc}}
+}
+
 func ec2TagsToQuotaUsageTags(tags []*ec2.Tag) map[string]string {
 	length := len(tags)
 	if length == 0 {
@@ -405,10 +641,10 @@ type MaxGP2StoragePerRegionCheck struct {
 	client ec2iface.EC2API
 }
 
-func (c *MaxGP2StoragePerRegionCheck) Usage() ([]QuotaUsage, error) {
+func (c *MaxGP2StoragePerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
 
-	var totalStorageCount int
+	var totalStorageCount int64
 
 	params := &ec2.DescribeVolumesInput{
 		Filters: []*ec2.Filter{
@@ -418,23 +654,23 @@ func (c *MaxGP2StoragePerRegionCheck) Usage() ([]QuotaUsage, error) {
 			},
 		},
 	}
-	err := c.client.DescribeVolumesPages(params,
+	err := c.client.DescribeVolumesPagesWithContext(ctx, params,
 		func(page *ec2.DescribeVolumesOutput, lastPage bool) bool {
 			if page != nil {
 				for _, vol := range page.Volumes {
-					totalStorageCount += int(*vol.Size) // Size is in GiB
+					totalStorageCount += *vol.Size // Size is in GiB
 				}
 			}
 			return !lastPage
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 	usage := QuotaUsage{
 		Name:        maxGp2StoragePerRegionName,
 		Description: maxGp2StoragePerRegionDescription,
-		Usage:       float64(totalStorageCount / 1024), // The limit is in TiB
+		Usage:       float64(totalStorageCount) / 1024, // The limit is in TiB
 	}
 	quotaUsages = append(quotaUsages, usage)
 
@@ -442,14 +678,19 @@ func (c *MaxGP2StoragePerRegionCheck) Usage() ([]QuotaUsage, error) {
 
 }
 
+// DescribeUsage implements the UsageDescriber interface
+func (c *MaxGP2StoragePerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: maxGp2StoragePerRegionName, Description: maxGp2StoragePerRegionDescription}}
+}
+
 type MaxIo1StoragePerRegionCheck struct {
 	client ec2iface.EC2API
 }
 
-func (c *MaxIo1StoragePerRegionCheck) Usage() ([]QuotaUsage, error) {
+func (c *MaxIo1StoragePerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
 
-	var totalStorageCount int
+	var totalStorageCount int64
 
 	params := &ec2.DescribeVolumesInput{
 		Filters: []*ec2.Filter{
@@ -459,23 +700,23 @@ func (c *MaxIo1StoragePerRegionCheck) Usage() ([]QuotaUsage, error) {
 			},
 		},
 	}
-	err := c.client.DescribeVolumesPages(params,
+	err := c.client.DescribeVolumesPagesWithContext(ctx, params,
 		func(page *ec2.DescribeVolumesOutput, lastPage bool) bool {
 			if page != nil {
 				for _, vol := range page.Volumes {
-					totalStorageCount += int(*vol.Size) // Size is in GiB
+					totalStorageCount += *vol.Size // Size is in GiB
 				}
 			}
 			return !lastPage
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 	usage := QuotaUsage{
 		Name:        maxIo1StoragePerRegionName,
 		Description: maxIo1StoragePerRegionDescription,
-		Usage:       float64(totalStorageCount / 1024), // The limit is in TiB
+		Usage:       float64(totalStorageCount) / 1024, // The limit is in TiB
 	}
 	quotaUsages = append(quotaUsages, usage)
 
@@ -483,14 +724,19 @@ func (c *MaxIo1StoragePerRegionCheck) Usage() ([]QuotaUsage, error) {
 
 }
 
+// DescribeUsage implements the UsageDescriber interface
+func (c *MaxIo1StoragePerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: maxIo1StoragePerRegionName, Description: maxIo1StoragePerRegionDescription}}
+}
+
 type MaxIo2StoragePerRegionCheck struct {
 	client ec2iface.EC2API
 }
 
-func (c *MaxIo2StoragePerRegionCheck) Usage() ([]QuotaUsage, error) {
+func (c *MaxIo2StoragePerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
 
-	var totalStorageCount int
+	var totalStorageCount int64
 
 	params := &ec2.DescribeVolumesInput{
 		Filters: []*ec2.Filter{
@@ -500,23 +746,23 @@ func (c *MaxIo2StoragePerRegionCheck) Usage() ([]QuotaUsage, error) {
 			},
 		},
 	}
-	err := c.client.DescribeVolumesPages(params,
+	err := c.client.DescribeVolumesPagesWithContext(ctx, params,
 		func(page *ec2.DescribeVolumesOutput, lastPage bool) bool {
 			if page != nil {
 				for _, vol := range page.Volumes {
-					totalStorageCount += int(*vol.Size) // Size is in GiB
+					totalStorageCount += *vol.Size // Size is in GiB
 				}
 			}
 			return !lastPage
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 	usage := QuotaUsage{
 		Name:        maxIo2StoragePerRegionName,
 		Description: maxIo2StoragePerRegionDescription,
-		Usage:       float64(totalStorageCount / 1024), // The limit is in TiB
+		Usage:       float64(totalStorageCount) / 1024, // The limit is in TiB
 	}
 	quotaUsages = append(quotaUsages, usage)
 
@@ -524,14 +770,19 @@ func (c *MaxIo2StoragePerRegionCheck) Usage() ([]QuotaUsage, error) {
 
 }
 
+// DescribeUsage implements the UsageDescriber interface
+func (c *MaxIo2StoragePerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: maxIo2StoragePerRegionName, Description: maxIo2StoragePerRegionDescription}}
+}
+
 type MaxGP3StoragePerRegionCheck struct {
 	client ec2iface.EC2API
 }
 
-func (c *MaxGP3StoragePerRegionCheck) Usage() ([]QuotaUsage, error) {
+func (c *MaxGP3StoragePerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
 
-	var totalStorageCount int
+	var totalStorageCount int64
 
 	params := &ec2.DescribeVolumesInput{
 		Filters: []*ec2.Filter{
@@ -541,23 +792,23 @@ func (c *MaxGP3StoragePerRegionCheck) Usage() ([]QuotaUsage, error) {
 			},
 		},
 	}
-	err := c.client.DescribeVolumesPages(params,
+	err := c.client.DescribeVolumesPagesWithContext(ctx, params,
 		func(page *ec2.DescribeVolumesOutput, lastPage bool) bool {
 			if page != nil {
 				for _, vol := range page.Volumes {
-					totalStorageCount += int(*vol.Size) // Size is in GiB
+					totalStorageCount += *vol.Size // Size is in GiB
 				}
 			}
 			return !lastPage
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 	usage := QuotaUsage{
 		Name:        maxGp3StoragePerRegionName,
 		Description: maxGp3StoragePerRegionDescription,
-		Usage:       float64(totalStorageCount / 1024), // The limit is in TiB
+		Usage:       float64(totalStorageCount) / 1024, // The limit is in TiB
 	}
 	quotaUsages = append(quotaUsages, usage)
 
@@ -565,14 +816,19 @@ func (c *MaxGP3StoragePerRegionCheck) Usage() ([]QuotaUsage, error) {
 
 }
 
+// DescribeUsage implements the UsageDescriber interface
+func (c *MaxGP3StoragePerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: maxGp3StoragePerRegionName, Description: maxGp3StoragePerRegionDescription}}
+}
+
 type MaxSt1StoragePerRegionCheck struct {
 	client ec2iface.EC2API
 }
 
-func (c *MaxSt1StoragePerRegionCheck) Usage() ([]QuotaUsage, error) {
+func (c *MaxSt1StoragePerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
 
-	var totalStorageCount int
+	var totalStorageCount int64
 
 	params := &ec2.DescribeVolumesInput{
 		Filters: []*ec2.Filter{
@@ -582,23 +838,23 @@ func (c *MaxSt1StoragePerRegionCheck) Usage() ([]QuotaUsage, error) {
 			},
 		},
 	}
-	err := c.client.DescribeVolumesPages(params,
+	err := c.client.DescribeVolumesPagesWithContext(ctx, params,
 		func(page *ec2.DescribeVolumesOutput, lastPage bool) bool {
 			if page != nil {
 				for _, vol := range page.Volumes {
-					totalStorageCount += int(*vol.Size) // Size is in GiB
+					totalStorageCount += *vol.Size // Size is in GiB
 				}
 			}
 			return !lastPage
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 	usage := QuotaUsage{
 		Name:        maxSt1StoragePerRegionName,
 		Description: maxSt1StoragePerRegionDescription,
-		Usage:       float64(totalStorageCount / 1024), // The limit is in TiB
+		Usage:       float64(totalStorageCount) / 1024, // The limit is in TiB
 	}
 	quotaUsages = append(quotaUsages, usage)
 
@@ -606,14 +862,19 @@ func (c *MaxSt1StoragePerRegionCheck) Usage() ([]QuotaUsage, error) {
 
 }
 
+// DescribeUsage implements the UsageDescriber interface
+func (c *MaxSt1StoragePerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: maxSt1StoragePerRegionName, Description: maxSt1StoragePerRegionDescription}}
+}
+
 type MaxStandardStoragePerRegionCheck struct {
 	client ec2iface.EC2API
 }
 
-func (c *MaxStandardStoragePerRegionCheck) Usage() ([]QuotaUsage, error) {
+func (c *MaxStandardStoragePerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
 
-	var totalStorageCount int
+	var totalStorageCount int64
 
 	params := &ec2.DescribeVolumesInput{
 		Filters: []*ec2.Filter{
@@ -623,23 +884,23 @@ func (c *MaxStandardStoragePerRegionCheck) Usage() ([]QuotaUsage, error) {
 			},
 		},
 	}
-	err := c.client.DescribeVolumesPages(params,
+	err := c.client.DescribeVolumesPagesWithContext(ctx, params,
 		func(page *ec2.DescribeVolumesOutput, lastPage bool) bool {
 			if page != nil {
 				for _, vol := range page.Volumes {
-					totalStorageCount += int(*vol.Size) // Size is in GiB
+					totalStorageCount += *vol.Size // Size is in GiB
 				}
 			}
 			return !lastPage
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 	usage := QuotaUsage{
 		Name:        maxStandardStoragePerRegionName,
 		Description: maxStandardStoragePerRegionDescription,
-		Usage:       float64(totalStorageCount / 1024), // The limit is in TiB
+		Usage:       float64(totalStorageCount) / 1024, // The limit is in TiB
 	}
 	quotaUsages = append(quotaUsages, usage)
 
@@ -647,14 +908,19 @@ func (c *MaxStandardStoragePerRegionCheck) Usage() ([]QuotaUsage, error) {
 
 }
 
+// DescribeUsage implements the UsageDescriber interface
+func (c *MaxStandardStoragePerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: maxStandardStoragePerRegionName, Description: maxStandardStoragePerRegionDescription}}
+}
+
 type MaxSc1StoragePerRegionCheck struct {
 	client ec2iface.EC2API
 }
 
-func (c *MaxSc1StoragePerRegionCheck) Usage() ([]QuotaUsage, error) {
+func (c *MaxSc1StoragePerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
 
-	var totalStorageCount int
+	var totalStorageCount int64
 
 	params := &ec2.DescribeVolumesInput{
 		Filters: []*ec2.Filter{
@@ -664,23 +930,23 @@ func (c *MaxSc1StoragePerRegionCheck) Usage() ([]QuotaUsage, error) {
 			},
 		},
 	}
-	err := c.client.DescribeVolumesPages(params,
+	err := c.client.DescribeVolumesPagesWithContext(ctx, params,
 		func(page *ec2.DescribeVolumesOutput, lastPage bool) bool {
 			if page != nil {
 				for _, vol := range page.Volumes {
-					totalStorageCount += int(*vol.Size) // Size is in GiB
+					totalStorageCount += *vol.Size // Size is in GiB
 				}
 			}
 			return !lastPage
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 	usage := QuotaUsage{
 		Name:        maxSc1StoragePerRegionName,
 		Description: maxSc1StoragePerRegionDescription,
-		Usage:       float64(totalStorageCount / 1024), // The limit is in TiB
+		Usage:       float64(totalStorageCount) / 1024, // The limit is in TiB
 	}
 	quotaUsages = append(quotaUsages, usage)
 
@@ -688,26 +954,34 @@ func (c *MaxSc1StoragePerRegionCheck) Usage() ([]QuotaUsage, error) {
 
 }
 
+// DescribeUsage implements the UsageDescriber interface
+func (c *MaxSc1StoragePerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: maxSc1StoragePerRegionName, Description: maxSc1StoragePerRegionDescription}}
+}
+
 type EbsSnapshotsPerRegionCheck struct {
-	client ec2iface.EC2API
+	client       ec2iface.EC2API
+	maxResources int
 }
 
-func (c *EbsSnapshotsPerRegionCheck) Usage() ([]QuotaUsage, error) {
+func (c *EbsSnapshotsPerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
+	snapshotsCap := &resourceCap{max: c.maxResources}
 
 	var totalSnapshotsCount int
 
 	params := &ec2.DescribeSnapshotsInput{}
-	err := c.client.DescribeSnapshotsPages(params,
+	err := c.client.DescribeSnapshotsPagesWithContext(ctx, params,
 		func(page *ec2.DescribeSnapshotsOutput, lastPage bool) bool {
 			if page != nil {
 				totalSnapshotsCount += len(page.Snapshots)
+				return snapshotsCap.add(len(page.Snapshots), lastPage)
 			}
 			return !lastPage
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 	usage := QuotaUsage{
 		Name:        ebsSnapshotsPerRegionName,
@@ -715,14 +989,20 @@ func (c *EbsSnapshotsPerRegionCheck) Usage() ([]QuotaUsage, error) {
 		Usage:       float64(totalSnapshotsCount),
 	}
 	quotaUsages = append(quotaUsages, usage)
+	snapshotsCap.markTruncated(quotaUsages)
 	return quotaUsages, nil
 }
 
+// DescribeUsage implements the UsageDescriber interface
+func (c *EbsSnapshotsPerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: ebsSnapshotsPerRegionName, Description: ebsSnapshotsPerRegionDescription}}
+}
+
 type MaxIo2IopsPerRegionCheck struct {
 	client ec2iface.EC2API
 }
 
-func (c *MaxIo2IopsPerRegionCheck) Usage() ([]QuotaUsage, error) {
+func (c *MaxIo2IopsPerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
 
 	var totalIopsCount int
@@ -735,23 +1015,23 @@ func (c *MaxIo2IopsPerRegionCheck) Usage() ([]QuotaUsage, error) {
 			},
 		},
 	}
-	err := c.client.DescribeVolumesPages(params,
+	err := c.client.DescribeVolumesPagesWithContext(ctx, params,
 		func(page *ec2.DescribeVolumesOutput, lastPage bool) bool {
 			if page != nil {
 				for _, vol := range page.Volumes {
-					totalIopsCount += int(*vol.Iops) // Size is in GiB
+					totalIopsCount += int(*vol.Iops)
 				}
 			}
 			return !lastPage
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 	usage := QuotaUsage{
 		Name:        maxIo2IopsPerRegionName,
 		Description: maxIo2IopsPerRegionDescription,
-		Usage:       float64(totalIopsCount), // The limit is in TiB
+		Usage:       float64(totalIopsCount),
 	}
 	quotaUsages = append(quotaUsages, usage)
 
@@ -759,11 +1039,16 @@ func (c *MaxIo2IopsPerRegionCheck) Usage() ([]QuotaUsage, error) {
 
 }
 
+// DescribeUsage implements the UsageDescriber interface
+func (c *MaxIo2IopsPerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: maxIo2IopsPerRegionName, Description: maxIo2IopsPerRegionDescription}}
+}
+
 type MaxIo1IopsPerRegionCheck struct {
 	client ec2iface.EC2API
 }
 
-func (c *MaxIo1IopsPerRegionCheck) Usage() ([]QuotaUsage, error) {
+func (c *MaxIo1IopsPerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
 
 	var totalIopsCount int
@@ -776,23 +1061,23 @@ func (c *MaxIo1IopsPerRegionCheck) Usage() ([]QuotaUsage, error) {
 			},
 		},
 	}
-	err := c.client.DescribeVolumesPages(params,
+	err := c.client.DescribeVolumesPagesWithContext(ctx, params,
 		func(page *ec2.DescribeVolumesOutput, lastPage bool) bool {
 			if page != nil {
 				for _, vol := range page.Volumes {
-					totalIopsCount += int(*vol.Iops) // Size is in GiB
+					totalIopsCount += int(*vol.Iops)
 				}
 			}
 			return !lastPage
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 	usage := QuotaUsage{
 		Name:        maxIo1IopsPerRegionName,
 		Description: maxIo1IopsPerRegionDescription,
-		Usage:       float64(totalIopsCount), // The limit is in TiB
+		Usage:       float64(totalIopsCount),
 	}
 	quotaUsages = append(quotaUsages, usage)
 
@@ -800,33 +1085,411 @@ func (c *MaxIo1IopsPerRegionCheck) Usage() ([]QuotaUsage, error) {
 
 }
 
+// DescribeUsage implements the UsageDescriber interface
+func (c *MaxIo1IopsPerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: maxIo1IopsPerRegionName, Description: maxIo1IopsPerRegionDescription}}
+}
+
 type ENIsPerRegionCheck struct {
 	client ec2iface.EC2API
 }
 
-func (c *ENIsPerRegionCheck) Usage() ([]QuotaUsage, error) {
+func (c *ENIsPerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
 
+	eniCounts, err := networkInterfacesPerAZ(ctx, c.client)
+	if err != nil {
+		return nil, err
+	}
 	var totalENIsCount int
+	for _, count := range eniCounts {
+		totalENIsCount += count
+	}
+
+	usage := QuotaUsage{
+		Name:        eNIsPerRegionName,
+		Description: eNIsPerRegionDescription,
+		Usage:       float64(totalENIsCount),
+	}
+	quotaUsages = append(quotaUsages, usage)
+	return quotaUsages, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *ENIsPerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: eNIsPerRegionName, Description: eNIsPerRegionDescription}}
+}
+
+// ENIsPerAZCheck implements the UsageCheck interface for ENIs per
+// availability zone. There is no quota per availability zone, it
+// breaks down the usage of the ENIs per region quota to show the
+// availability zones running out of network interfaces
+type ENIsPerAZCheck struct {
+	client ec2iface.EC2API
+}
+
+// Usage returns the number of ENIs in each availability zone with
+// network interfaces or an error
+func (c *ENIsPerAZCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	eniCounts, err := networkInterfacesPerAZ(ctx, c.client)
+	if err != nil {
+		return nil, err
+	}
+
+	availabilityZones := make([]string, 0, len(eniCounts))
+	for availabilityZone := range eniCounts {
+		availabilityZones = append(availabilityZones, availabilityZone)
+	}
+	sort.Strings(availabilityZones)
+
+	quotaUsages := []QuotaUsage{}
+	for _, availabilityZone := range availabilityZones {
+		usage := QuotaUsage{
+			Name:         eNIsPerAZName,
+			Description:  eNIsPerAZDescription,
+			ResourceName: aws.String(availabilityZone),
+			Usage:        float64(eniCounts[availabilityZone]),
+		}
+		quotaUsages = append(quotaUsages, usage)
+	}
+	return quotaUsages, nil
+}
+
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *ENIsPerAZCheck) DescribeResourceUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: eNIsPerAZName, Description: eNIsPerAZDescription}}
+}
+
+// networkInterfacesPerAZ returns the number of network interfaces in
+// each availability zone or an error
+func networkInterfacesPerAZ(ctx context.Context, client ec2iface.EC2API) (map[string]int, error) {
+	eniCounts := map[string]int{}
 
 	params := &ec2.DescribeNetworkInterfacesInput{}
-	err := c.client.DescribeNetworkInterfacesPages(params,
+	err := client.DescribeNetworkInterfacesPagesWithContext(ctx, params,
 		func(page *ec2.DescribeNetworkInterfacesOutput, lastPage bool) bool {
 			if page != nil {
-				pageENICount := len(page.NetworkInterfaces)
-				totalENIsCount += pageENICount
+				for _, eni := range page.NetworkInterfaces {
+					eniCounts[aws.StringValue(eni.AvailabilityZone)]++
+				}
 			}
 			return !lastPage
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
-	usage := QuotaUsage{
-		Name:        eNIsPerRegionName,
-		Description: eNIsPerRegionDescription,
-		Usage:       float64(totalENIsCount),
+	return eniCounts, nil
+}
+
+// ManagedPrefixListsPerRegionCheck implements the UsageCheck interface
+// for customer managed prefix lists per region
+type ManagedPrefixListsPerRegionCheck struct {
+	client ec2iface.EC2API
+}
+
+// Usage returns the number of customer managed prefix lists or an
+// error
+func (c *ManagedPrefixListsPerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	prefixLists, err := customerManagedPrefixLists(ctx, c.client)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        managedPrefixListsPerRegionName,
+			Description: managedPrefixListsPerRegionDescription,
+			Usage:       float64(len(prefixLists)),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *ManagedPrefixListsPerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: managedPrefixListsPerRegionName, Description: managedPrefixListsPerRegionDescription}}
+}
+
+// EntriesPerPrefixListCheck implements the UsageCheck interface for
+// entries per customer managed prefix list
+type EntriesPerPrefixListCheck struct {
+	client ec2iface.EC2API
+}
+
+// Usage returns the number of entries for each customer managed prefix
+// list ID or an error
+func (c *EntriesPerPrefixListCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	prefixLists, err := customerManagedPrefixLists(ctx, c.client)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	quotaUsages := []QuotaUsage{}
+	for _, prefixList := range prefixLists {
+		var entriesCount int
+
+		params := &ec2.GetManagedPrefixListEntriesInput{PrefixListId: prefixList.PrefixListId}
+		err := c.client.GetManagedPrefixListEntriesPagesWithContext(ctx, params,
+			func(page *ec2.GetManagedPrefixListEntriesOutput, lastPage bool) bool {
+				if page != nil {
+					entriesCount += len(page.Entries)
+				}
+				return !lastPage
+			},
+		)
+		if err != nil {
+			return nil, wrapUsageErr(err)
+		}
+
+		usage := QuotaUsage{
+			Name:         entriesPerPrefixListName,
+			Description:  entriesPerPrefixListDescription,
+			ResourceName: prefixList.PrefixListId,
+			Usage:        float64(entriesCount),
+			Tags:         ec2TagsToQuotaUsageTags(prefixList.Tags),
+		}
+		quotaUsages = append(quotaUsages, usage)
 	}
-	quotaUsages = append(quotaUsages, usage)
 	return quotaUsages, nil
 }
+
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *EntriesPerPrefixListCheck) DescribeResourceUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: entriesPerPrefixListName, Description: entriesPerPrefixListDescription}}
+}
+
+// customerManagedPrefixLists returns the prefix lists that are not
+// managed by AWS or an error
+func customerManagedPrefixLists(ctx context.Context, ec2Service ec2iface.EC2API) ([]*ec2.ManagedPrefixList, error) {
+	var prefixLists []*ec2.ManagedPrefixList
+
+	params := &ec2.DescribeManagedPrefixListsInput{}
+	err := ec2Service.DescribeManagedPrefixListsPagesWithContext(ctx, params,
+		func(page *ec2.DescribeManagedPrefixListsOutput, lastPage bool) bool {
+			if page != nil {
+				for _, prefixList := range page.PrefixLists {
+					if aws.StringValue(prefixList.OwnerId) == awsManagedPrefixListOwner {
+						continue
+					}
+					prefixLists = append(prefixLists, prefixList)
+				}
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return prefixLists, nil
+}
+
+// FastSnapshotRestoresPerRegionCheck implements the UsageCheck
+// interface for EBS fast snapshot restores per region
+type FastSnapshotRestoresPerRegionCheck struct {
+	client ec2iface.EC2API
+}
+
+// Usage returns the number of snapshot and availability zone pairs
+// with fast snapshot restore enabled or an error. Fast snapshot restore
+// is enabled per availability zone, and the pairs being enabled or
+// optimized count against the quota as much as the enabled ones
+func (c *FastSnapshotRestoresPerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var restoresCount int
+
+	params := &ec2.DescribeFastSnapshotRestoresInput{
+		Filters: []*ec2.Filter{
+			{
+				Name: aws.String("state"),
+				Values: aws.StringSlice([]string{
+					ec2.FastSnapshotRestoreStateCodeEnabling,
+					ec2.FastSnapshotRestoreStateCodeOptimizing,
+					ec2.FastSnapshotRestoreStateCodeEnabled,
+				}),
+			},
+		},
+	}
+	err := c.client.DescribeFastSnapshotRestoresPagesWithContext(ctx, params,
+		func(page *ec2.DescribeFastSnapshotRestoresOutput, lastPage bool) bool {
+			if page != nil {
+				restoresCount += len(page.FastSnapshotRestores)
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        fastSnapshotRestoresPerRegionName,
+			Description: fastSnapshotRestoresPerRegionDescription,
+			Usage:       float64(restoresCount),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *FastSnapshotRestoresPerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: fastSnapshotRestoresPerRegionName, Description: fastSnapshotRestoresPerRegionDescription}}
+}
+
+// VpnGatewaysPerRegionCheck implements the UsageCheck interface for
+// virtual private gateways per region
+type VpnGatewaysPerRegionCheck struct {
+	client ec2iface.EC2API
+}
+
+// Usage returns the number of virtual private gateways in the region
+// that are not deleted or an error
+func (c *VpnGatewaysPerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var gatewaysCount int
+
+	output, err := c.client.DescribeVpnGatewaysWithContext(ctx, &ec2.DescribeVpnGatewaysInput{})
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+	for _, gateway := range output.VpnGateways {
+		if aws.StringValue(gateway.State) != ec2.VpnStateDeleted {
+			gatewaysCount++
+		}
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        vpnGatewaysPerRegionName,
+			Description: vpnGatewaysPerRegionDescription,
+			Usage:       float64(gatewaysCount),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *VpnGatewaysPerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: vpnGatewaysPerRegionName, Description: vpnGatewaysPerRegionDescription}}
+}
+
+// CustomerGatewaysPerRegionCheck implements the UsageCheck interface
+// for customer gateways per region
+type CustomerGatewaysPerRegionCheck struct {
+	client ec2iface.EC2API
+}
+
+// Usage returns the number of customer gateways in the region that are
+// not deleted or an error
+func (c *CustomerGatewaysPerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var gatewaysCount int
+
+	output, err := c.client.DescribeCustomerGatewaysWithContext(ctx, &ec2.DescribeCustomerGatewaysInput{})
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+	for _, gateway := range output.CustomerGateways {
+		if aws.StringValue(gateway.State) != ec2.VpnStateDeleted {
+			gatewaysCount++
+		}
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        customerGatewaysPerRegionName,
+			Description: customerGatewaysPerRegionDescription,
+			Usage:       float64(gatewaysCount),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *CustomerGatewaysPerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: customerGatewaysPerRegionName, Description: customerGatewaysPerRegionDescription}}
+}
+
+// ENIsPerInstanceCheck implements the UsageCheck interface for the
+// network interfaces attached to each running instance. There is no
+// service quota for it, the limit of each instance is the maximum
+// number of network interfaces of its instance type, which the ENIs per
+// region quota does not show
+type ENIsPerInstanceCheck struct {
+	client        ec2iface.EC2API
+	maxResources  int
+	instanceTypes *instanceTypesCache
+}
+
+// Usage returns the number of network interfaces attached to each
+// running instance, with the maximum of its instance type as the quota,
+// or an error. Each instance type is only described once per refresh,
+// through the cache shared with the other EC2 checks. The instances
+// whose instance type cannot be described have no quota
+func (c *ENIsPerInstanceCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	quotaUsages := []QuotaUsage{}
+	instanceTypes := map[string][]int{}
+	instanceCap := &resourceCap{max: c.maxResources}
+
+	params := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: []*string{aws.String("running")},
+			},
+		},
+	}
+	err := c.client.DescribeInstancesPagesWithContext(ctx, params,
+		func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+			if page == nil {
+				return !lastPage
+			}
+
+			var instancesCount int
+			for _, reservation := range page.Reservations {
+				for _, instance := range reservation.Instances {
+					instanceType := aws.StringValue(instance.InstanceType)
+					instanceTypes[instanceType] = append(instanceTypes[instanceType], len(quotaUsages))
+					usage := QuotaUsage{
+						Name:         eNIsPerInstanceName,
+						ResourceName: instance.InstanceId,
+						Description:  eNIsPerInstanceDescription,
+						Usage:        float64(len(instance.NetworkInterfaces)),
+						Tags:         ec2TagsToQuotaUsageTags(instance.Tags),
+					}
+					quotaUsages = append(quotaUsages, usage)
+					instancesCount++
+				}
+			}
+			return instanceCap.add(instancesCount, lastPage)
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	names := make([]string, 0, len(instanceTypes))
+	for instanceType := range instanceTypes {
+		names = append(names, instanceType)
+	}
+	sort.Strings(names)
+	infos, err := c.instanceTypes.get(ctx, c.client, names)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+	for name, info := range infos {
+		if info.NetworkInfo == nil {
+			continue
+		}
+		maxENIs := float64(aws.Int64Value(info.NetworkInfo.MaximumNetworkInterfaces))
+		for _, i := range instanceTypes[name] {
+			quotaUsages[i].Quota = maxENIs
+		}
+	}
+
+	instanceCap.markTruncated(quotaUsages)
+	return quotaUsages, nil
+}
+
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *ENIsPerInstanceCheck) DescribeResourceUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: eNIsPerInstanceName, Description: eNIsPerInstanceDescription}}
+}
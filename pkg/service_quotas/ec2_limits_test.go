@@ -1,43 +1,100 @@
 package servicequotas
 
 import (
+	"context"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	awsservicequotas "github.com/aws/aws-sdk-go/service/servicequotas"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
-func (m *mockEC2Client) DescribeSecurityGroupsPages(input *ec2.DescribeSecurityGroupsInput, fn func(*ec2.DescribeSecurityGroupsOutput, bool) bool) error {
+func (m *mockEC2Client) DescribeSecurityGroupsPagesWithContext(ctx aws.Context, input *ec2.DescribeSecurityGroupsInput, fn func(*ec2.DescribeSecurityGroupsOutput, bool) bool, opts ...request.Option) error {
 	fn(m.DescribeSecurityGroupsResponse, true)
 	return m.err
 }
 
-func (m *mockEC2Client) DescribeNetworkInterfacesPages(input *ec2.DescribeNetworkInterfacesInput, fn func(*ec2.DescribeNetworkInterfacesOutput, bool) bool) error {
+func (m *mockEC2Client) DescribeNetworkInterfacesPagesWithContext(ctx aws.Context, input *ec2.DescribeNetworkInterfacesInput, fn func(*ec2.DescribeNetworkInterfacesOutput, bool) bool, opts ...request.Option) error {
 	fn(m.DescribeNetworkInterfacesResponse, true)
 	return m.err
 }
 
-func (m *mockEC2Client) DescribeInstancesPages(input *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool) error {
+func (m *mockEC2Client) DescribeInstancesPagesWithContext(ctx aws.Context, input *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool, opts ...request.Option) error {
 	m.InstancesFilters = input.Filters
 	fn(m.DescribeInstancesResponse, true)
 	return m.err
 }
 
-func (m *mockEC2Client) DescribeSubnetsPages(input *ec2.DescribeSubnetsInput, fn func(*ec2.DescribeSubnetsOutput, bool) bool) error {
+func (m *mockEC2Client) DescribeSpotInstanceRequestsPagesWithContext(ctx aws.Context, input *ec2.DescribeSpotInstanceRequestsInput, fn func(*ec2.DescribeSpotInstanceRequestsOutput, bool) bool, opts ...request.Option) error {
+	m.SpotInstanceRequestsFilters = input.Filters
+	fn(m.DescribeSpotInstanceRequestsResponse, true)
+	return m.err
+}
+
+func (m *mockEC2Client) DescribeFastSnapshotRestoresPagesWithContext(ctx aws.Context, input *ec2.DescribeFastSnapshotRestoresInput, fn func(*ec2.DescribeFastSnapshotRestoresOutput, bool) bool, opts ...request.Option) error {
+	m.FastSnapshotRestoresFilters = input.Filters
+	fn(m.DescribeFastSnapshotRestoresResponse, true)
+	return m.err
+}
+
+func (m *mockEC2Client) DescribeManagedPrefixListsPagesWithContext(ctx aws.Context, input *ec2.DescribeManagedPrefixListsInput, fn func(*ec2.DescribeManagedPrefixListsOutput, bool) bool, opts ...request.Option) error {
+	fn(m.DescribeManagedPrefixListsResponse, true)
+	return m.err
+}
+
+func (m *mockEC2Client) GetManagedPrefixListEntriesPagesWithContext(ctx aws.Context, input *ec2.GetManagedPrefixListEntriesInput, fn func(*ec2.GetManagedPrefixListEntriesOutput, bool) bool, opts ...request.Option) error {
+	fn(m.GetManagedPrefixListEntriesResponses[*input.PrefixListId], true)
+	return m.err
+}
+
+func (m *mockEC2Client) DescribeInstanceTypesPagesWithContext(ctx aws.Context, input *ec2.DescribeInstanceTypesInput, fn func(*ec2.DescribeInstanceTypesOutput, bool) bool, opts ...request.Option) error {
+	m.InstanceTypes = append(m.InstanceTypes, input.InstanceTypes...)
+	if m.DescribeInstanceTypesErr != nil {
+		return m.DescribeInstanceTypesErr
+	}
+	fn(m.DescribeInstanceTypesResponse, true)
+	return m.err
+}
+
+func (m *mockEC2Client) DescribeSubnetsPagesWithContext(ctx aws.Context, input *ec2.DescribeSubnetsInput, fn func(*ec2.DescribeSubnetsOutput, bool) bool, opts ...request.Option) error {
 	fn(m.DescribeSubnetsResponse, true)
 	return m.err
 }
 
+func (m *mockEC2Client) DescribeSnapshotsPagesWithContext(ctx aws.Context, input *ec2.DescribeSnapshotsInput, fn func(*ec2.DescribeSnapshotsOutput, bool) bool, opts ...request.Option) error {
+	for i, page := range m.DescribeSnapshotsResponses {
+		if !fn(page, i == len(m.DescribeSnapshotsResponses)-1) {
+			break
+		}
+	}
+	return m.err
+}
+
+func (m *mockEC2Client) DescribeVolumesPagesWithContext(ctx aws.Context, input *ec2.DescribeVolumesInput, fn func(*ec2.DescribeVolumesOutput, bool) bool, opts ...request.Option) error {
+	m.VolumesFilters = input.Filters
+	fn(m.DescribeVolumesResponse, true)
+	return m.err
+}
+
+func (m *mockEC2Client) DescribeVpnGatewaysWithContext(ctx aws.Context, input *ec2.DescribeVpnGatewaysInput, opts ...request.Option) (*ec2.DescribeVpnGatewaysOutput, error) {
+	return m.DescribeVpnGatewaysResponse, m.err
+}
+
+func (m *mockEC2Client) DescribeCustomerGatewaysWithContext(ctx aws.Context, input *ec2.DescribeCustomerGatewaysInput, opts ...request.Option) (*ec2.DescribeCustomerGatewaysOutput, error) {
+	return m.DescribeCustomerGatewaysResponse, m.err
+}
+
 func TestRulesPerSecurityGroupUsageWithError(t *testing.T) {
 	mockClient := &mockEC2Client{
 		err:                            errors.New("some err"),
 		DescribeSecurityGroupsResponse: nil,
 	}
 
-	check := RulesPerSecurityGroupUsageCheck{mockClient}
-	usage, err := check.Usage()
+	check := RulesPerSecurityGroupUsageCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
 
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
@@ -129,6 +186,52 @@ func TestRulesPerSecurityGroupUsage(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "WithGroupPairRulesInBothDirections",
+			securityGroups: []*ec2.SecurityGroup{
+				{
+					GroupId: aws.String("groupwithpairs"),
+					IpPermissions: []*ec2.IpPermission{
+						{
+							IpRanges: []*ec2.IpRange{
+								{CidrIp: aws.String("10.0.0.10/32")},
+							},
+							UserIdGroupPairs: []*ec2.UserIdGroupPair{
+								{GroupId: aws.String("sg-inbound1")},
+								{GroupId: aws.String("sg-inbound2")},
+							},
+						},
+					},
+					IpPermissionsEgress: []*ec2.IpPermission{
+						{
+							IpRanges: []*ec2.IpRange{
+								{CidrIp: aws.String("0.0.0.0/0")},
+								{CidrIp: aws.String("10.0.0.0/8")},
+							},
+							UserIdGroupPairs: []*ec2.UserIdGroupPair{
+								{GroupId: aws.String("sg-outbound1")},
+								{GroupId: aws.String("sg-outbound2")},
+								{GroupId: aws.String("sg-outbound3")},
+							},
+						},
+					},
+				},
+			},
+			expectedUsage: []QuotaUsage{
+				{
+					Name:         inboundRulesPerSecGrpName,
+					ResourceName: aws.String("groupwithpairs"),
+					Description:  inboundRulesPerSecGrpDesc,
+					Usage:        3,
+				},
+				{
+					Name:         outboundRulesPerSecGrpName,
+					ResourceName: aws.String("groupwithpairs"),
+					Description:  outboundRulesPerSecGrpDesc,
+					Usage:        5,
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -140,8 +243,8 @@ func TestRulesPerSecurityGroupUsage(t *testing.T) {
 				},
 			}
 
-			check := RulesPerSecurityGroupUsageCheck{mockClient}
-			usage, err := check.Usage()
+			check := RulesPerSecurityGroupUsageCheck{client: mockClient}
+			usage, err := check.Usage(context.Background())
 
 			assert.NoError(t, err)
 			assert.Equal(t, tc.expectedUsage, usage)
@@ -149,14 +252,158 @@ func TestRulesPerSecurityGroupUsage(t *testing.T) {
 	}
 }
 
+func TestRulesPerSecurityGroupUsageWithTotal(t *testing.T) {
+	mockClient := &mockEC2Client{
+		DescribeSecurityGroupsResponse: &ec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []*ec2.SecurityGroup{
+				{
+					GroupId: aws.String("groupwithrules"),
+					IpPermissions: []*ec2.IpPermission{
+						{
+							IpRanges: []*ec2.IpRange{
+								{CidrIp: aws.String("10.0.0.10/32")},
+								{CidrIp: aws.String("10.0.0.5/32")},
+							},
+						},
+					},
+					IpPermissionsEgress: []*ec2.IpPermission{
+						{
+							IpRanges: []*ec2.IpRange{
+								{CidrIp: aws.String("0.0.0.0/0")},
+							},
+							UserIdGroupPairs: []*ec2.UserIdGroupPair{
+								{GroupId: aws.String("sg-outbound1")},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	check := RulesPerSecurityGroupUsageCheck{client: mockClient, includeTotal: true}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:         inboundRulesPerSecGrpName,
+			ResourceName: aws.String("groupwithrules"),
+			Description:  inboundRulesPerSecGrpDesc,
+			Usage:        2,
+		},
+		{
+			Name:         outboundRulesPerSecGrpName,
+			ResourceName: aws.String("groupwithrules"),
+			Description:  outboundRulesPerSecGrpDesc,
+			Usage:        2,
+		},
+		{
+			Name:         totalRulesPerSecGrpName,
+			ResourceName: aws.String("groupwithrules"),
+			Description:  totalRulesPerSecGrpDesc,
+			Usage:        4,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestRulesPerSecurityGroupTotalLimit(t *testing.T) {
+	mockClient := &mockEC2Client{
+		DescribeSecurityGroupsResponse: &ec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []*ec2.SecurityGroup{{GroupId: aws.String("sg-1")}},
+		},
+	}
+	serviceQuotas := ServiceQuotas{
+		quotasService: &mockServiceQuotasClient{
+			serviceName: "vpc",
+			ListServiceQuotasResponse: &awsservicequotas.ListServiceQuotasOutput{
+				Quotas: []*awsservicequotas.ServiceQuota{{QuotaCode: aws.String("L-0EA8095F"), Value: aws.Float64(60)}},
+			},
+		},
+		serviceQuotasUsageChecks: map[string]UsageCheck{
+			"L-0EA8095F": &RulesPerSecurityGroupUsageCheck{client: mockClient, includeTotal: true},
+		},
+	}
+	usages, err := serviceQuotas.quotasForService(context.Background(), "vpc")
+
+	assert.NoError(t, err)
+	limits := map[string]float64{}
+	for _, usage := range usages {
+		limits[usage.Name] = usage.Quota
+	}
+	// the quota limits each direction, so the combined rules can reach
+	// twice the quota
+	expectedLimits := map[string]float64{
+		inboundRulesPerSecGrpName:  60,
+		outboundRulesPerSecGrpName: 60,
+		totalRulesPerSecGrpName:    120,
+	}
+	assert.Equal(t, expectedLimits, limits)
+}
+
+func TestENIsPerAZCheckWithError(t *testing.T) {
+	mockClient := &mockEC2Client{
+		err:                               errors.New("some err"),
+		DescribeNetworkInterfacesResponse: nil,
+	}
+
+	check := ENIsPerAZCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestENIsPerAZCheck(t *testing.T) {
+	mockClient := &mockEC2Client{
+		DescribeNetworkInterfacesResponse: &ec2.DescribeNetworkInterfacesOutput{
+			NetworkInterfaces: []*ec2.NetworkInterface{
+				{NetworkInterfaceId: aws.String("eni1"), AvailabilityZone: aws.String("eu-west-1b")},
+				{NetworkInterfaceId: aws.String("eni2"), AvailabilityZone: aws.String("eu-west-1a")},
+				{NetworkInterfaceId: aws.String("eni3"), AvailabilityZone: aws.String("eu-west-1b")},
+			},
+		},
+	}
+
+	check := ENIsPerAZCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:         eNIsPerAZName,
+			Description:  eNIsPerAZDescription,
+			ResourceName: aws.String("eu-west-1a"),
+			Usage:        1,
+		},
+		{
+			Name:         eNIsPerAZName,
+			Description:  eNIsPerAZDescription,
+			ResourceName: aws.String("eu-west-1b"),
+			Usage:        2,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+
+	regionCheck := ENIsPerRegionCheck{mockClient}
+	regionUsage, err := regionCheck.Usage(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(3), regionUsage[0].Usage)
+}
+
 func TestSecurityGroupsPerENIUsageWithError(t *testing.T) {
 	mockClient := &mockEC2Client{
 		err:                               errors.New("some err"),
 		DescribeNetworkInterfacesResponse: nil,
 	}
 
-	check := SecurityGroupsPerENIUsageCheck{mockClient}
-	usage, err := check.Usage()
+	check := SecurityGroupsPerENIUsageCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
 
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
@@ -211,8 +458,8 @@ func TestSecurityGroupsPerENIUsage(t *testing.T) {
 				},
 			}
 
-			check := SecurityGroupsPerENIUsageCheck{mockClient}
-			usage, err := check.Usage()
+			check := SecurityGroupsPerENIUsageCheck{client: mockClient}
+			usage, err := check.Usage(context.Background())
 
 			assert.NoError(t, err)
 			assert.Equal(t, tc.expectedUsage, usage)
@@ -227,7 +474,7 @@ func TestSecurityGroupsPerRegionUsageWithError(t *testing.T) {
 	}
 
 	check := SecurityGroupsPerRegionUsageCheck{mockClient}
-	usage, err := check.Usage()
+	usage, err := check.Usage(context.Background())
 
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
@@ -281,7 +528,7 @@ func TestSecurityGroupsPerRegionUsage(t *testing.T) {
 			}
 
 			check := SecurityGroupsPerRegionUsageCheck{mockClient}
-			usage, err := check.Usage()
+			usage, err := check.Usage(context.Background())
 
 			assert.NoError(t, err)
 			assert.Equal(t, tc.expectedUsage, usage)
@@ -295,7 +542,7 @@ func TestStandardInstancesCPUsWithError(t *testing.T) {
 		DescribeInstancesResponse: nil,
 	}
 
-	cpus, err := standardInstancesCPUs(mockClient, true)
+	cpus, err := standardInstancesCPUs(context.Background(), mockClient, nil, true)
 
 	assert.Error(t, err)
 	assert.Equal(t, int64(0), cpus)
@@ -333,7 +580,7 @@ func TestStandardInstancesCPUsFilters(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			mockClient := &mockEC2Client{err: nil, DescribeInstancesResponse: nil}
 
-			cpus, err := standardInstancesCPUs(mockClient, tc.spotInstances)
+			cpus, err := standardInstancesCPUs(context.Background(), mockClient, nil, tc.spotInstances)
 
 			assert.NoError(t, err)
 			assert.Equal(t, int64(0), cpus)
@@ -378,11 +625,146 @@ func TestStandardInstancesCPUs(t *testing.T) {
 		},
 	}
 
-	cpus, err := standardInstancesCPUs(mockClient, false)
+	cpus, err := standardInstancesCPUs(context.Background(), mockClient, nil, false)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(12), cpus)
 }
 
+func TestStandardInstancesCPUsWithoutCPUOptions(t *testing.T) {
+	mockClient := &mockEC2Client{
+		err: nil,
+		DescribeInstancesResponse: &ec2.DescribeInstancesOutput{
+			Reservations: []*ec2.Reservation{
+				{
+					Instances: []*ec2.Instance{
+						{
+							InstanceType: aws.String("m5.large"),
+							CpuOptions: &ec2.CpuOptions{
+								CoreCount:      aws.Int64(1),
+								ThreadsPerCore: aws.Int64(2),
+							},
+						},
+						{InstanceType: aws.String("m5.xlarge")},
+						{InstanceType: aws.String("m5.xlarge"), CpuOptions: &ec2.CpuOptions{}},
+					},
+				},
+			},
+		},
+		DescribeInstanceTypesResponse: &ec2.DescribeInstanceTypesOutput{
+			InstanceTypes: []*ec2.InstanceTypeInfo{
+				{
+					InstanceType: aws.String("m5.xlarge"),
+					VCpuInfo:     &ec2.VCpuInfo{DefaultVCpus: aws.Int64(4)},
+				},
+			},
+		},
+	}
+
+	cpus, err := standardInstancesCPUs(context.Background(), mockClient, nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), cpus)
+	assert.Equal(t, []*string{aws.String("m5.xlarge")}, mockClient.InstanceTypes)
+}
+
+func TestStandardInstancesCPUsFleetInstances(t *testing.T) {
+	// Instances launched by a fleet with instance weights count their
+	// own vCPUs, regardless of the weight given to their type
+	mockClient := &mockEC2Client{
+		err: nil,
+		DescribeInstancesResponse: &ec2.DescribeInstancesOutput{
+			Reservations: []*ec2.Reservation{
+				{
+					Instances: []*ec2.Instance{
+						{
+							InstanceLifecycle: aws.String("spot"),
+							InstanceType:      aws.String("m5.large"),
+							CpuOptions: &ec2.CpuOptions{
+								CoreCount:      aws.Int64(1),
+								ThreadsPerCore: aws.Int64(2),
+							},
+							Tags: []*ec2.Tag{{Key: aws.String("aws:ec2:fleet-id"), Value: aws.String("fleet-1")}},
+						},
+						{
+							InstanceLifecycle: aws.String("spot"),
+							InstanceType:      aws.String("m5.2xlarge"),
+							CpuOptions: &ec2.CpuOptions{
+								CoreCount:      aws.Int64(4),
+								ThreadsPerCore: aws.Int64(2),
+							},
+							Tags: []*ec2.Tag{{Key: aws.String("aws:ec2:fleet-id"), Value: aws.String("fleet-1")}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	cpus, err := standardInstancesCPUs(context.Background(), mockClient, nil, true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), cpus)
+	assert.Empty(t, mockClient.InstanceTypes)
+}
+
+func TestStandardInstancesCPUsWithInstanceTypesError(t *testing.T) {
+	mockClient := &mockEC2Client{
+		DescribeInstancesResponse: &ec2.DescribeInstancesOutput{
+			Reservations: []*ec2.Reservation{
+				{Instances: []*ec2.Instance{{InstanceType: aws.String("m5.xlarge")}}},
+			},
+		},
+		DescribeInstanceTypesErr: errors.New("some err"),
+	}
+	cpus, err := standardInstancesCPUs(context.Background(), mockClient, nil, false)
+
+	assert.Error(t, err)
+	assert.Equal(t, int64(0), cpus)
+}
+
+func TestSpotInstanceRequestsCountWithError(t *testing.T) {
+	mockClient := &mockEC2Client{
+		err: errors.New("some err"),
+	}
+
+	check := SpotInstanceRequestsCountCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestSpotInstanceRequestsCount(t *testing.T) {
+	mockClient := &mockEC2Client{
+		DescribeSpotInstanceRequestsResponse: &ec2.DescribeSpotInstanceRequestsOutput{
+			SpotInstanceRequests: []*ec2.SpotInstanceRequest{
+				{SpotInstanceRequestId: aws.String("sir-1"), State: aws.String(ec2.SpotInstanceStateOpen)},
+				{SpotInstanceRequestId: aws.String("sir-2"), State: aws.String(ec2.SpotInstanceStateActive)},
+			},
+		},
+	}
+
+	check := SpotInstanceRequestsCountCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        spotInstanceRequestsCountName,
+			Description: spotInstanceRequestsCountDesc,
+			Usage:       2,
+		},
+	}
+	expectedFilters := []*ec2.Filter{
+		{
+			Name:   aws.String("state"),
+			Values: []*string{aws.String("open"), aws.String("active")},
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+	assert.Equal(t, expectedFilters, mockClient.SpotInstanceRequestsFilters)
+}
+
 func TestAvailableIpsPerSubnetUsageWithError(t *testing.T) {
 	mockClient := &mockEC2Client{
 		err:                     errors.New("some err"),
@@ -390,7 +772,7 @@ func TestAvailableIpsPerSubnetUsageWithError(t *testing.T) {
 	}
 
 	check := AvailableIpsPerSubnetUsageCheck{mockClient}
-	usage, err := check.Usage()
+	usage, err := check.Usage(context.Background())
 
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
@@ -411,7 +793,7 @@ func TestAvailableIpsPerSubnetUsageWithInvalidCidrConversion(t *testing.T) {
 		},
 	}
 	check := AvailableIpsPerSubnetUsageCheck{mockClient}
-	usage, err := check.Usage()
+	usage, err := check.Usage(context.Background())
 
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, ErrFailedToConvertCidr))
@@ -434,7 +816,7 @@ func TestAvailableIpsPerSubnetUsage(t *testing.T) {
 			subnets: []*ec2.Subnet{
 				{
 					AvailabilityZone:        aws.String("eu-west-1"),
-					AvailableIpAddressCount: aws.Int64(4096),
+					AvailableIpAddressCount: aws.Int64(4091),
 					CidrBlock:               aws.String("100.10.10.0/20"),
 					SubnetId:                aws.String("subnet-id"),
 				},
@@ -445,7 +827,7 @@ func TestAvailableIpsPerSubnetUsage(t *testing.T) {
 					ResourceName: aws.String("subnet-id"),
 					Description:  availableIPsPerSubnetDesc,
 					Usage:        float64(0),
-					Quota:        float64(4096),
+					Quota:        float64(4091),
 				},
 			},
 		},
@@ -454,7 +836,7 @@ func TestAvailableIpsPerSubnetUsage(t *testing.T) {
 			subnets: []*ec2.Subnet{
 				{
 					AvailabilityZone:        aws.String("eu-west-1"),
-					AvailableIpAddressCount: aws.Int64(4096),
+					AvailableIpAddressCount: aws.Int64(4091),
 					CidrBlock:               aws.String("100.10.10.0/20"),
 					SubnetId:                aws.String("subnet-id-1"),
 				},
@@ -472,7 +854,7 @@ func TestAvailableIpsPerSubnetUsage(t *testing.T) {
 				},
 				{
 					AvailabilityZone:        aws.String("eu-west-1"),
-					AvailableIpAddressCount: aws.Int64(1024),
+					AvailableIpAddressCount: aws.Int64(1019),
 					CidrBlock:               aws.String("100.10.10.0/22"),
 					SubnetId:                aws.String("subnet-id-3"),
 				},
@@ -483,46 +865,618 @@ func TestAvailableIpsPerSubnetUsage(t *testing.T) {
 					ResourceName: aws.String("subnet-id-1"),
 					Description:  availableIPsPerSubnetDesc,
 					Usage:        float64(0),
-					Quota:        float64(4096),
+					Quota:        float64(4091),
 				},
 				{
 					Name:         availableIPsPerSubnetName,
 					ResourceName: aws.String("subnet-id-2"),
 					Description:  availableIPsPerSubnetDesc,
-					Usage:        float64(2048),
-					Quota:        float64(2048),
+					Usage:        float64(2043),
+					Quota:        float64(2043),
 				},
 				{
 					Name:         availableIPsPerSubnetName,
 					ResourceName: aws.String("subnet-id-2"),
 					Description:  availableIPsPerSubnetDesc,
-					Usage:        float64(1948),
-					Quota:        float64(2048),
+					Usage:        float64(1943),
+					Quota:        float64(2043),
 				},
 				{
 					Name:         availableIPsPerSubnetName,
 					ResourceName: aws.String("subnet-id-3"),
 					Description:  availableIPsPerSubnetDesc,
 					Usage:        float64(0),
-					Quota:        float64(1024),
+					Quota:        float64(1019),
 				},
 			},
 		},
-	}
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			mockClient := &mockEC2Client{
-				err: nil,
-				DescribeSubnetsResponse: &ec2.DescribeSubnetsOutput{
-					Subnets: tc.subnets,
+		{
+			name: "WithSingleDigitSuffix",
+			subnets: []*ec2.Subnet{
+				{
+					AvailabilityZone:        aws.String("eu-west-1"),
+					AvailableIpAddressCount: aws.Int64(16777111),
+					CidrBlock:               aws.String("10.0.0.0/8"),
+					SubnetId:                aws.String("subnet-id"),
 				},
-			}
-
-			check := AvailableIpsPerSubnetUsageCheck{mockClient}
-			usage, err := check.Usage()
-
-			assert.NoError(t, err)
-			assert.Equal(t, tc.expectedUsage, usage)
-		})
-	}
+			},
+			expectedUsage: []QuotaUsage{
+				{
+					Name:         availableIPsPerSubnetName,
+					ResourceName: aws.String("subnet-id"),
+					Description:  availableIPsPerSubnetDesc,
+					Usage:        float64(100),
+					Quota:        float64(16777211),
+				},
+			},
+		},
+		{
+			name: "WithSmallSubnet",
+			subnets: []*ec2.Subnet{
+				{
+					AvailabilityZone:        aws.String("eu-west-1"),
+					AvailableIpAddressCount: aws.Int64(6),
+					CidrBlock:               aws.String("10.0.0.16/28"),
+					SubnetId:                aws.String("subnet-id"),
+				},
+			},
+			expectedUsage: []QuotaUsage{
+				{
+					Name:         availableIPsPerSubnetName,
+					ResourceName: aws.String("subnet-id"),
+					Description:  availableIPsPerSubnetDesc,
+					Usage:        float64(5),
+					Quota:        float64(11),
+				},
+			},
+		},
+		{
+			name: "WithDualStackSubnet",
+			subnets: []*ec2.Subnet{
+				{
+					AvailabilityZone:        aws.String("eu-west-1"),
+					AvailableIpAddressCount: aws.Int64(200),
+					CidrBlock:               aws.String("10.0.0.0/24"),
+					Ipv6CidrBlockAssociationSet: []*ec2.SubnetIpv6CidrBlockAssociation{
+						{Ipv6CidrBlock: aws.String("2001:db8:1234:1a00::/64")},
+					},
+					SubnetId: aws.String("subnet-id"),
+				},
+			},
+			expectedUsage: []QuotaUsage{
+				{
+					Name:         availableIPsPerSubnetName,
+					ResourceName: aws.String("subnet-id"),
+					Description:  availableIPsPerSubnetDesc,
+					Usage:        float64(51),
+					Quota:        float64(251),
+				},
+			},
+		},
+		{
+			name: "WithIPv6OnlySubnet",
+			subnets: []*ec2.Subnet{
+				{
+					AvailabilityZone:        aws.String("eu-west-1"),
+					AvailableIpAddressCount: aws.Int64(0),
+					Ipv6CidrBlockAssociationSet: []*ec2.SubnetIpv6CidrBlockAssociation{
+						{Ipv6CidrBlock: aws.String("2001:db8:1234:1a00::/64")},
+					},
+					Ipv6Native: aws.Bool(true),
+					SubnetId:   aws.String("subnet-id"),
+				},
+			},
+			expectedUsage: []QuotaUsage{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &mockEC2Client{
+				err: nil,
+				DescribeSubnetsResponse: &ec2.DescribeSubnetsOutput{
+					Subnets: tc.subnets,
+				},
+			}
+
+			check := AvailableIpsPerSubnetUsageCheck{mockClient}
+			usage, err := check.Usage(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedUsage, usage)
+		})
+	}
+}
+
+func TestAvailableIpsPerSubnetUsageExcludesReservedIPs(t *testing.T) {
+	mockClient := &mockEC2Client{
+		DescribeSubnetsResponse: &ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{
+				{
+					AvailabilityZone:        aws.String("eu-west-1"),
+					AvailableIpAddressCount: aws.Int64(251),
+					CidrBlock:               aws.String("10.0.1.0/24"),
+					SubnetId:                aws.String("subnet-id"),
+				},
+			},
+		},
+	}
+
+	check := AvailableIpsPerSubnetUsageCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, usage, 1)
+	assert.Equal(t, float64(251), usage[0].Quota)
+	assert.Equal(t, float64(0), usage[0].Usage)
+}
+
+func TestEbsSnapshotsPerRegionCheck(t *testing.T) {
+	snapshotsPage := &ec2.DescribeSnapshotsOutput{
+		Snapshots: []*ec2.Snapshot{
+			{SnapshotId: aws.String("snap-1")},
+			{SnapshotId: aws.String("snap-2")},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		maxResources  int
+		expectedUsage []QuotaUsage
+	}{
+		{
+			name:         "WithoutMaxResources",
+			maxResources: 0,
+			expectedUsage: []QuotaUsage{
+				{
+					Name:        ebsSnapshotsPerRegionName,
+					Description: ebsSnapshotsPerRegionDescription,
+					Usage:       6,
+				},
+			},
+		},
+		{
+			name:         "WithMaxResourcesReached",
+			maxResources: 3,
+			expectedUsage: []QuotaUsage{
+				{
+					Name:        ebsSnapshotsPerRegionName,
+					Description: ebsSnapshotsPerRegionDescription,
+					Usage:       4,
+					Truncated:   true,
+				},
+			},
+		},
+		{
+			name:         "WithMaxResourcesOnLastPage",
+			maxResources: 6,
+			expectedUsage: []QuotaUsage{
+				{
+					Name:        ebsSnapshotsPerRegionName,
+					Description: ebsSnapshotsPerRegionDescription,
+					Usage:       6,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &mockEC2Client{
+				DescribeSnapshotsResponses: []*ec2.DescribeSnapshotsOutput{snapshotsPage, snapshotsPage, snapshotsPage},
+			}
+
+			check := EbsSnapshotsPerRegionCheck{client: mockClient, maxResources: tc.maxResources}
+			usage, err := check.Usage(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedUsage, usage)
+		})
+	}
+}
+
+func testManagedPrefixLists() *ec2.DescribeManagedPrefixListsOutput {
+	return &ec2.DescribeManagedPrefixListsOutput{
+		PrefixLists: []*ec2.ManagedPrefixList{
+			{
+				PrefixListId: aws.String("pl-1"),
+				OwnerId:      aws.String("123456789012"),
+				Tags:         []*ec2.Tag{{Key: aws.String("team"), Value: aws.String("network")}},
+			},
+			{PrefixListId: aws.String("pl-2"), OwnerId: aws.String("123456789012")},
+			{PrefixListId: aws.String("pl-s3"), OwnerId: aws.String("AWS")},
+		},
+	}
+}
+
+func TestManagedPrefixListsPerRegionCheckWithError(t *testing.T) {
+	mockClient := &mockEC2Client{
+		err: errors.New("some err"),
+	}
+
+	check := ManagedPrefixListsPerRegionCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestManagedPrefixListsPerRegionCheck(t *testing.T) {
+	mockClient := &mockEC2Client{
+		DescribeManagedPrefixListsResponse: testManagedPrefixLists(),
+	}
+
+	check := ManagedPrefixListsPerRegionCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        managedPrefixListsPerRegionName,
+			Description: managedPrefixListsPerRegionDescription,
+			Usage:       2,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestEntriesPerPrefixListCheckWithError(t *testing.T) {
+	mockClient := &mockEC2Client{
+		err: errors.New("some err"),
+	}
+
+	check := EntriesPerPrefixListCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestEntriesPerPrefixListCheck(t *testing.T) {
+	mockClient := &mockEC2Client{
+		DescribeManagedPrefixListsResponse: testManagedPrefixLists(),
+		GetManagedPrefixListEntriesResponses: map[string]*ec2.GetManagedPrefixListEntriesOutput{
+			"pl-1": {
+				Entries: []*ec2.PrefixListEntry{{Cidr: aws.String("10.0.0.0/16")}, {Cidr: aws.String("10.1.0.0/16")}},
+			},
+			"pl-2": {},
+		},
+	}
+
+	check := EntriesPerPrefixListCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:         entriesPerPrefixListName,
+			Description:  entriesPerPrefixListDescription,
+			ResourceName: aws.String("pl-1"),
+			Usage:        2,
+			Tags:         map[string]string{"team": "network"},
+		},
+		{
+			Name:         entriesPerPrefixListName,
+			Description:  entriesPerPrefixListDescription,
+			ResourceName: aws.String("pl-2"),
+			Usage:        0,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestFastSnapshotRestoresPerRegionCheckWithError(t *testing.T) {
+	mockClient := &mockEC2Client{
+		err: errors.New("some err"),
+	}
+
+	check := FastSnapshotRestoresPerRegionCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestFastSnapshotRestoresPerRegionCheck(t *testing.T) {
+	mockClient := &mockEC2Client{
+		DescribeFastSnapshotRestoresResponse: &ec2.DescribeFastSnapshotRestoresOutput{
+			FastSnapshotRestores: []*ec2.DescribeFastSnapshotRestoreSuccessItem{
+				{SnapshotId: aws.String("snap-1"), AvailabilityZone: aws.String("eu-west-1a"), State: aws.String(ec2.FastSnapshotRestoreStateCodeEnabled)},
+				{SnapshotId: aws.String("snap-1"), AvailabilityZone: aws.String("eu-west-1b"), State: aws.String(ec2.FastSnapshotRestoreStateCodeEnabling)},
+				{SnapshotId: aws.String("snap-2"), AvailabilityZone: aws.String("eu-west-1a"), State: aws.String(ec2.FastSnapshotRestoreStateCodeOptimizing)},
+			},
+		},
+	}
+
+	check := FastSnapshotRestoresPerRegionCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        fastSnapshotRestoresPerRegionName,
+			Description: fastSnapshotRestoresPerRegionDescription,
+			Usage:       3,
+		},
+	}
+	expectedFilters := []*ec2.Filter{
+		{
+			Name:   aws.String("state"),
+			Values: aws.StringSlice([]string{"enabling", "optimizing", "enabled"}),
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+	assert.Equal(t, expectedFilters, mockClient.FastSnapshotRestoresFilters)
+}
+
+func TestStoragePerRegionChecksWithError(t *testing.T) {
+	mockClient := &mockEC2Client{
+		err: errors.New("some err"),
+	}
+
+	check := MaxGP2StoragePerRegionCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestStoragePerRegionChecks(t *testing.T) {
+	testCases := []struct {
+		volumeType string
+		name       string
+		newCheck   func(client *mockEC2Client) UsageCheck
+	}{
+		{"gp2", maxGp2StoragePerRegionName, func(client *mockEC2Client) UsageCheck { return &MaxGP2StoragePerRegionCheck{client} }},
+		{"gp3", maxGp3StoragePerRegionName, func(client *mockEC2Client) UsageCheck { return &MaxGP3StoragePerRegionCheck{client} }},
+		{"io1", maxIo1StoragePerRegionName, func(client *mockEC2Client) UsageCheck { return &MaxIo1StoragePerRegionCheck{client} }},
+		{"io2", maxIo2StoragePerRegionName, func(client *mockEC2Client) UsageCheck { return &MaxIo2StoragePerRegionCheck{client} }},
+		{"st1", maxSt1StoragePerRegionName, func(client *mockEC2Client) UsageCheck { return &MaxSt1StoragePerRegionCheck{client} }},
+		{"standard", maxStandardStoragePerRegionName, func(client *mockEC2Client) UsageCheck { return &MaxStandardStoragePerRegionCheck{client} }},
+		{"sc1", maxSc1StoragePerRegionName, func(client *mockEC2Client) UsageCheck { return &MaxSc1StoragePerRegionCheck{client} }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.volumeType, func(t *testing.T) {
+			mockClient := &mockEC2Client{
+				DescribeVolumesResponse: &ec2.DescribeVolumesOutput{
+					Volumes: []*ec2.Volume{
+						{Size: aws.Int64(2000)},
+						{Size: aws.Int64(500)},
+					},
+				},
+			}
+
+			usage, err := tc.newCheck(mockClient).Usage(context.Background())
+
+			expectedFilters := []*ec2.Filter{
+				{
+					Name:   aws.String("volume-type"),
+					Values: []*string{aws.String(tc.volumeType)},
+				},
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, usage, 1)
+			assert.Equal(t, tc.name, usage[0].Name)
+			// 2500 GiB is not a whole number of TiB
+			assert.Equal(t, 2.44140625, usage[0].Usage)
+			assert.Equal(t, expectedFilters, mockClient.VolumesFilters)
+		})
+	}
+}
+
+func TestStoragePerRegionChecksBelowOneTiB(t *testing.T) {
+	mockClient := &mockEC2Client{
+		DescribeVolumesResponse: &ec2.DescribeVolumesOutput{
+			Volumes: []*ec2.Volume{{Size: aws.Int64(512)}},
+		},
+	}
+
+	check := MaxGP3StoragePerRegionCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, usage, 1)
+	assert.Equal(t, 0.5, usage[0].Usage)
+}
+
+func TestVpnGatewaysPerRegionCheckWithError(t *testing.T) {
+	mockClient := &mockEC2Client{
+		err: errors.New("some err"),
+	}
+
+	check := VpnGatewaysPerRegionCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestVpnGatewaysPerRegionCheck(t *testing.T) {
+	mockClient := &mockEC2Client{
+		DescribeVpnGatewaysResponse: &ec2.DescribeVpnGatewaysOutput{
+			VpnGateways: []*ec2.VpnGateway{
+				{VpnGatewayId: aws.String("vgw-1"), State: aws.String(ec2.VpnStateAvailable)},
+				{VpnGatewayId: aws.String("vgw-2"), State: aws.String(ec2.VpnStateDeleted)},
+				{VpnGatewayId: aws.String("vgw-3"), State: aws.String(ec2.VpnStatePending)},
+			},
+		},
+	}
+
+	check := VpnGatewaysPerRegionCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        vpnGatewaysPerRegionName,
+			Description: vpnGatewaysPerRegionDescription,
+			Usage:       2,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestCustomerGatewaysPerRegionCheckWithError(t *testing.T) {
+	mockClient := &mockEC2Client{
+		err: errors.New("some err"),
+	}
+
+	check := CustomerGatewaysPerRegionCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestCustomerGatewaysPerRegionCheck(t *testing.T) {
+	mockClient := &mockEC2Client{
+		DescribeCustomerGatewaysResponse: &ec2.DescribeCustomerGatewaysOutput{
+			CustomerGateways: []*ec2.CustomerGateway{
+				{CustomerGatewayId: aws.String("cgw-1"), State: aws.String(ec2.VpnStateAvailable)},
+				{CustomerGatewayId: aws.String("cgw-2"), State: aws.String(ec2.VpnStateDeleted)},
+			},
+		},
+	}
+
+	check := CustomerGatewaysPerRegionCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        customerGatewaysPerRegionName,
+			Description: customerGatewaysPerRegionDescription,
+			Usage:       1,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestENIsPerInstanceCheckWithError(t *testing.T) {
+	mockClient := &mockEC2Client{
+		err: errors.New("some err"),
+	}
+
+	check := ENIsPerInstanceCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestENIsPerInstanceCheckWithInstanceTypesError(t *testing.T) {
+	mockClient := &mockEC2Client{
+		DescribeInstancesResponse: &ec2.DescribeInstancesOutput{
+			Reservations: []*ec2.Reservation{{Instances: []*ec2.Instance{
+				{InstanceId: aws.String("i-1"), InstanceType: aws.String("m5.large")},
+			}}},
+		},
+		DescribeInstanceTypesErr: errors.New("some err"),
+	}
+
+	check := ENIsPerInstanceCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestENIsPerInstanceCheck(t *testing.T) {
+	mockClient := &mockEC2Client{
+		DescribeInstancesResponse: &ec2.DescribeInstancesOutput{
+			Reservations: []*ec2.Reservation{
+				{Instances: []*ec2.Instance{
+					{
+						InstanceId:        aws.String("i-1"),
+						InstanceType:      aws.String("m5.large"),
+						NetworkInterfaces: []*ec2.InstanceNetworkInterface{{}, {}},
+						Tags:              []*ec2.Tag{{Key: aws.String("team"), Value: aws.String("a")}},
+					},
+					{
+						InstanceId:        aws.String("i-2"),
+						InstanceType:      aws.String("c5.xlarge"),
+						NetworkInterfaces: []*ec2.InstanceNetworkInterface{{}},
+					},
+				}},
+				{Instances: []*ec2.Instance{
+					{
+						InstanceId:        aws.String("i-3"),
+						InstanceType:      aws.String("m5.large"),
+						NetworkInterfaces: []*ec2.InstanceNetworkInterface{{}, {}, {}},
+					},
+					{
+						InstanceId:        aws.String("i-4"),
+						InstanceType:      aws.String("unknown"),
+						NetworkInterfaces: []*ec2.InstanceNetworkInterface{{}},
+					},
+				}},
+			},
+		},
+		DescribeInstanceTypesResponse: &ec2.DescribeInstanceTypesOutput{
+			InstanceTypes: []*ec2.InstanceTypeInfo{
+				{InstanceType: aws.String("m5.large"), NetworkInfo: &ec2.NetworkInfo{MaximumNetworkInterfaces: aws.Int64(3)}},
+				{InstanceType: aws.String("c5.xlarge"), NetworkInfo: &ec2.NetworkInfo{MaximumNetworkInterfaces: aws.Int64(4)}},
+			},
+		},
+	}
+
+	check := ENIsPerInstanceCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:         eNIsPerInstanceName,
+			ResourceName: aws.String("i-1"),
+			Description:  eNIsPerInstanceDescription,
+			Usage:        2,
+			Quota:        3,
+			Tags:         map[string]string{"team": "a"},
+		},
+		{
+			Name:         eNIsPerInstanceName,
+			ResourceName: aws.String("i-2"),
+			Description:  eNIsPerInstanceDescription,
+			Usage:        1,
+			Quota:        4,
+		},
+		{
+			Name:         eNIsPerInstanceName,
+			ResourceName: aws.String("i-3"),
+			Description:  eNIsPerInstanceDescription,
+			Usage:        3,
+			Quota:        3,
+		},
+		{
+			Name:         eNIsPerInstanceName,
+			ResourceName: aws.String("i-4"),
+			Description:  eNIsPerInstanceDescription,
+			Usage:        1,
+		},
+	}
+	expectedFilters := []*ec2.Filter{
+		{
+			Name:   aws.String("instance-state-name"),
+			Values: []*string{aws.String("running")},
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+	assert.Equal(t, expectedFilters, mockClient.InstancesFilters)
+	// each instance type is described once
+	assert.Equal(t, aws.StringSlice([]string{"c5.xlarge", "m5.large", "unknown"}), mockClient.InstanceTypes)
 }
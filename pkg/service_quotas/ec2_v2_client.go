@@ -0,0 +1,434 @@
+package servicequotas
+
+import (
+	"context"
+	"encoding/json"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+)
+
+// ec2V2API is the subset of the aws-sdk-go-v2 EC2 client used by the
+// EC2 usage checks
+type ec2V2API interface {
+	DescribeCustomerGateways(context.Context, *ec2v2.DescribeCustomerGatewaysInput, ...func(*ec2v2.Options)) (*ec2v2.DescribeCustomerGatewaysOutput, error)
+	DescribeFastSnapshotRestores(context.Context, *ec2v2.DescribeFastSnapshotRestoresInput, ...func(*ec2v2.Options)) (*ec2v2.DescribeFastSnapshotRestoresOutput, error)
+	DescribeInstances(context.Context, *ec2v2.DescribeInstancesInput, ...func(*ec2v2.Options)) (*ec2v2.DescribeInstancesOutput, error)
+	DescribeInstanceTypes(context.Context, *ec2v2.DescribeInstanceTypesInput, ...func(*ec2v2.Options)) (*ec2v2.DescribeInstanceTypesOutput, error)
+	DescribeManagedPrefixLists(context.Context, *ec2v2.DescribeManagedPrefixListsInput, ...func(*ec2v2.Options)) (*ec2v2.DescribeManagedPrefixListsOutput, error)
+	DescribeNetworkInterfaces(context.Context, *ec2v2.DescribeNetworkInterfacesInput, ...func(*ec2v2.Options)) (*ec2v2.DescribeNetworkInterfacesOutput, error)
+	DescribeSecurityGroups(context.Context, *ec2v2.DescribeSecurityGroupsInput, ...func(*ec2v2.Options)) (*ec2v2.DescribeSecurityGroupsOutput, error)
+	DescribeSpotInstanceRequests(context.Context, *ec2v2.DescribeSpotInstanceRequestsInput, ...func(*ec2v2.Options)) (*ec2v2.DescribeSpotInstanceRequestsOutput, error)
+	DescribeSnapshots(context.Context, *ec2v2.DescribeSnapshotsInput, ...func(*ec2v2.Options)) (*ec2v2.DescribeSnapshotsOutput, error)
+	DescribeSubnets(context.Context, *ec2v2.DescribeSubnetsInput, ...func(*ec2v2.Options)) (*ec2v2.DescribeSubnetsOutput, error)
+	DescribeVolumes(context.Context, *ec2v2.DescribeVolumesInput, ...func(*ec2v2.Options)) (*ec2v2.DescribeVolumesOutput, error)
+	DescribeVpnGateways(context.Context, *ec2v2.DescribeVpnGatewaysInput, ...func(*ec2v2.Options)) (*ec2v2.DescribeVpnGatewaysOutput, error)
+	GetManagedPrefixListEntries(context.Context, *ec2v2.GetManagedPrefixListEntriesInput, ...func(*ec2v2.Options)) (*ec2v2.GetManagedPrefixListEntriesOutput, error)
+}
+
+// ec2V2Client implements the methods of `ec2iface.EC2API` used by the
+// EC2 usage checks with an aws-sdk-go-v2 client, so the checks
+// can move to the v2 SDK without being rewritten. The aws-sdk-go
+// request options are ignored. Calling any other method panics
+type ec2V2Client struct {
+	ec2iface.EC2API
+
+	client ec2V2API
+}
+
+// newEC2V2Client creates an aws-sdk-go-v2 backed EC2 client for the
+// region and with the credentials `c` configures for EC2
+func newEC2V2Client(c client.ConfigProvider, cfgs ...*aws.Config) ec2iface.EC2API {
+	clientCfg := c.ClientConfig(ec2.EndpointsID, cfgs...)
+	cfg := awsv2.Config{
+		Region:      clientCfg.SigningRegion,
+		Credentials: v1CredentialsProvider{clientCfg.Config.Credentials},
+	}
+	return &ec2V2Client{client: ec2v2.NewFromConfig(cfg)}
+}
+
+// v1CredentialsProvider retrieves aws-sdk-go-v2 credentials from
+// aws-sdk-go credentials, so both SDKs share the session's credential
+// chain
+type v1CredentialsProvider struct {
+	credentials *credentials.Credentials
+}
+
+// Retrieve implements the aws-sdk-go-v2 CredentialsProvider interface
+func (p v1CredentialsProvider) Retrieve(ctx context.Context) (awsv2.Credentials, error) {
+	value, err := p.credentials.GetWithContext(ctx)
+	if err != nil {
+		return awsv2.Credentials{}, err
+	}
+
+	creds := awsv2.Credentials{
+		AccessKeyID:     value.AccessKeyID,
+		SecretAccessKey: value.SecretAccessKey,
+		SessionToken:    value.SessionToken,
+		Source:          value.ProviderName,
+	}
+	if expires, err := p.credentials.ExpiresAt(); err == nil {
+		creds.CanExpire = true
+		creds.Expires = expires
+	}
+	return creds, nil
+}
+
+// convertShape copies the fields of an aws-sdk-go input or output to
+// its aws-sdk-go-v2 counterpart or the other way around. Both SDKs
+// generate their shapes from the same API models, so the fields share
+// their names and only differ in pointer and integer types, which
+// JSON does not distinguish. The v2 SDK uses non-pointer enums, so
+// empty strings are dropped to leave the unset v1 fields nil
+func convertShape(from, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+
+	var fields interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	data, err = json.Marshal(withoutEmptyStrings(fields))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
+
+// withoutEmptyStrings removes the empty string fields from the objects
+// in a decoded JSON value
+func withoutEmptyStrings(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if field == "" {
+				delete(v, key)
+				continue
+			}
+			v[key] = withoutEmptyStrings(field)
+		}
+	case []interface{}:
+		for i, element := range v {
+			v[i] = withoutEmptyStrings(element)
+		}
+	}
+	return value
+}
+
+// DescribeInstancesPagesWithContext pages through the instances with the v2 client
+func (c *ec2V2Client) DescribeInstancesPagesWithContext(ctx aws.Context, input *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool, _ ...request.Option) error {
+	params := &ec2v2.DescribeInstancesInput{}
+	if err := convertShape(input, params); err != nil {
+		return err
+	}
+
+	paginator := ec2v2.NewDescribeInstancesPaginator(c.client, params)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		output := &ec2.DescribeInstancesOutput{}
+		if err := convertShape(page, output); err != nil {
+			return err
+		}
+		if !fn(output, !paginator.HasMorePages()) {
+			break
+		}
+	}
+	return nil
+}
+
+// DescribeInstanceTypesPagesWithContext pages through the instance types with the
+// v2 client
+func (c *ec2V2Client) DescribeInstanceTypesPagesWithContext(ctx aws.Context, input *ec2.DescribeInstanceTypesInput, fn func(*ec2.DescribeInstanceTypesOutput, bool) bool, _ ...request.Option) error {
+	params := &ec2v2.DescribeInstanceTypesInput{}
+	if err := convertShape(input, params); err != nil {
+		return err
+	}
+
+	paginator := ec2v2.NewDescribeInstanceTypesPaginator(c.client, params)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		output := &ec2.DescribeInstanceTypesOutput{}
+		if err := convertShape(page, output); err != nil {
+			return err
+		}
+		if !fn(output, !paginator.HasMorePages()) {
+			break
+		}
+	}
+	return nil
+}
+
+// DescribeFastSnapshotRestoresPagesWithContext pages through the fast snapshot
+// restores with the v2 client
+func (c *ec2V2Client) DescribeFastSnapshotRestoresPagesWithContext(ctx aws.Context, input *ec2.DescribeFastSnapshotRestoresInput, fn func(*ec2.DescribeFastSnapshotRestoresOutput, bool) bool, _ ...request.Option) error {
+	params := &ec2v2.DescribeFastSnapshotRestoresInput{}
+	if err := convertShape(input, params); err != nil {
+		return err
+	}
+
+	paginator := ec2v2.NewDescribeFastSnapshotRestoresPaginator(c.client, params)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		output := &ec2.DescribeFastSnapshotRestoresOutput{}
+		if err := convertShape(page, output); err != nil {
+			return err
+		}
+		if !fn(output, !paginator.HasMorePages()) {
+			break
+		}
+	}
+	return nil
+}
+
+// DescribeManagedPrefixListsPagesWithContext pages through the managed prefix
+// lists with the v2 client
+func (c *ec2V2Client) DescribeManagedPrefixListsPagesWithContext(ctx aws.Context, input *ec2.DescribeManagedPrefixListsInput, fn func(*ec2.DescribeManagedPrefixListsOutput, bool) bool, _ ...request.Option) error {
+	params := &ec2v2.DescribeManagedPrefixListsInput{}
+	if err := convertShape(input, params); err != nil {
+		return err
+	}
+
+	paginator := ec2v2.NewDescribeManagedPrefixListsPaginator(c.client, params)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		output := &ec2.DescribeManagedPrefixListsOutput{}
+		if err := convertShape(page, output); err != nil {
+			return err
+		}
+		if !fn(output, !paginator.HasMorePages()) {
+			break
+		}
+	}
+	return nil
+}
+
+// DescribeNetworkInterfacesPagesWithContext pages through the network interfaces
+// with the v2 client
+func (c *ec2V2Client) DescribeNetworkInterfacesPagesWithContext(ctx aws.Context, input *ec2.DescribeNetworkInterfacesInput, fn func(*ec2.DescribeNetworkInterfacesOutput, bool) bool, _ ...request.Option) error {
+	params := &ec2v2.DescribeNetworkInterfacesInput{}
+	if err := convertShape(input, params); err != nil {
+		return err
+	}
+
+	paginator := ec2v2.NewDescribeNetworkInterfacesPaginator(c.client, params)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		output := &ec2.DescribeNetworkInterfacesOutput{}
+		if err := convertShape(page, output); err != nil {
+			return err
+		}
+		if !fn(output, !paginator.HasMorePages()) {
+			break
+		}
+	}
+	return nil
+}
+
+// DescribeSecurityGroupsPagesWithContext pages through the security groups with
+// the v2 client
+func (c *ec2V2Client) DescribeSecurityGroupsPagesWithContext(ctx aws.Context, input *ec2.DescribeSecurityGroupsInput, fn func(*ec2.DescribeSecurityGroupsOutput, bool) bool, _ ...request.Option) error {
+	params := &ec2v2.DescribeSecurityGroupsInput{}
+	if err := convertShape(input, params); err != nil {
+		return err
+	}
+
+	paginator := ec2v2.NewDescribeSecurityGroupsPaginator(c.client, params)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		output := &ec2.DescribeSecurityGroupsOutput{}
+		if err := convertShape(page, output); err != nil {
+			return err
+		}
+		if !fn(output, !paginator.HasMorePages()) {
+			break
+		}
+	}
+	return nil
+}
+
+// DescribeSnapshotsPagesWithContext pages through the snapshots with the v2
+// client
+func (c *ec2V2Client) DescribeSnapshotsPagesWithContext(ctx aws.Context, input *ec2.DescribeSnapshotsInput, fn func(*ec2.DescribeSnapshotsOutput, bool) bool, _ ...request.Option) error {
+	params := &ec2v2.DescribeSnapshotsInput{}
+	if err := convertShape(input, params); err != nil {
+		return err
+	}
+
+	paginator := ec2v2.NewDescribeSnapshotsPaginator(c.client, params)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		output := &ec2.DescribeSnapshotsOutput{}
+		if err := convertShape(page, output); err != nil {
+			return err
+		}
+		if !fn(output, !paginator.HasMorePages()) {
+			break
+		}
+	}
+	return nil
+}
+
+// DescribeSpotInstanceRequestsPagesWithContext pages through the spot instance
+// requests with the v2 client
+func (c *ec2V2Client) DescribeSpotInstanceRequestsPagesWithContext(ctx aws.Context, input *ec2.DescribeSpotInstanceRequestsInput, fn func(*ec2.DescribeSpotInstanceRequestsOutput, bool) bool, _ ...request.Option) error {
+	params := &ec2v2.DescribeSpotInstanceRequestsInput{}
+	if err := convertShape(input, params); err != nil {
+		return err
+	}
+
+	paginator := ec2v2.NewDescribeSpotInstanceRequestsPaginator(c.client, params)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		output := &ec2.DescribeSpotInstanceRequestsOutput{}
+		if err := convertShape(page, output); err != nil {
+			return err
+		}
+		if !fn(output, !paginator.HasMorePages()) {
+			break
+		}
+	}
+	return nil
+}
+
+// DescribeSubnetsPagesWithContext pages through the subnets with the v2 client
+func (c *ec2V2Client) DescribeSubnetsPagesWithContext(ctx aws.Context, input *ec2.DescribeSubnetsInput, fn func(*ec2.DescribeSubnetsOutput, bool) bool, _ ...request.Option) error {
+	params := &ec2v2.DescribeSubnetsInput{}
+	if err := convertShape(input, params); err != nil {
+		return err
+	}
+
+	paginator := ec2v2.NewDescribeSubnetsPaginator(c.client, params)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		output := &ec2.DescribeSubnetsOutput{}
+		if err := convertShape(page, output); err != nil {
+			return err
+		}
+		if !fn(output, !paginator.HasMorePages()) {
+			break
+		}
+	}
+	return nil
+}
+
+// DescribeVolumesPagesWithContext pages through the volumes with the v2 client
+func (c *ec2V2Client) DescribeVolumesPagesWithContext(ctx aws.Context, input *ec2.DescribeVolumesInput, fn func(*ec2.DescribeVolumesOutput, bool) bool, _ ...request.Option) error {
+	params := &ec2v2.DescribeVolumesInput{}
+	if err := convertShape(input, params); err != nil {
+		return err
+	}
+
+	paginator := ec2v2.NewDescribeVolumesPaginator(c.client, params)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		output := &ec2.DescribeVolumesOutput{}
+		if err := convertShape(page, output); err != nil {
+			return err
+		}
+		if !fn(output, !paginator.HasMorePages()) {
+			break
+		}
+	}
+	return nil
+}
+
+// DescribeVpnGatewaysWithContext describes the virtual private gateways with
+// the v2 client
+func (c *ec2V2Client) DescribeVpnGatewaysWithContext(ctx aws.Context, input *ec2.DescribeVpnGatewaysInput, _ ...request.Option) (*ec2.DescribeVpnGatewaysOutput, error) {
+	params := &ec2v2.DescribeVpnGatewaysInput{}
+	if err := convertShape(input, params); err != nil {
+		return nil, err
+	}
+
+	result, err := c.client.DescribeVpnGateways(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	output := &ec2.DescribeVpnGatewaysOutput{}
+	if err := convertShape(result, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// DescribeCustomerGatewaysWithContext describes the customer gateways with the
+// v2 client
+func (c *ec2V2Client) DescribeCustomerGatewaysWithContext(ctx aws.Context, input *ec2.DescribeCustomerGatewaysInput, _ ...request.Option) (*ec2.DescribeCustomerGatewaysOutput, error) {
+	params := &ec2v2.DescribeCustomerGatewaysInput{}
+	if err := convertShape(input, params); err != nil {
+		return nil, err
+	}
+
+	result, err := c.client.DescribeCustomerGateways(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	output := &ec2.DescribeCustomerGatewaysOutput{}
+	if err := convertShape(result, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// GetManagedPrefixListEntriesPagesWithContext pages through the entries of a
+// managed prefix list with the v2 client
+func (c *ec2V2Client) GetManagedPrefixListEntriesPagesWithContext(ctx aws.Context, input *ec2.GetManagedPrefixListEntriesInput, fn func(*ec2.GetManagedPrefixListEntriesOutput, bool) bool, _ ...request.Option) error {
+	params := &ec2v2.GetManagedPrefixListEntriesInput{}
+	if err := convertShape(input, params); err != nil {
+		return err
+	}
+
+	paginator := ec2v2.NewGetManagedPrefixListEntriesPaginator(c.client, params)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		output := &ec2.GetManagedPrefixListEntriesOutput{}
+		if err := convertShape(page, output); err != nil {
+			return err
+		}
+		if !fn(output, !paginator.HasMorePages()) {
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockEC2V2Client) DescribeVolumes(ctx context.Context, input *ec2v2.DescribeVolumesInput, optFns ...func(*ec2v2.Options)) (*ec2v2.DescribeVolumesOutput, error) {
+	m.DescribeVolumesInputs = append(m.DescribeVolumesInputs, input)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.DescribeVolumesResponses[awsv2.ToString(input.NextToken)], nil
+}
+
+func (m *mockEC2V2Client) DescribeInstances(ctx context.Context, input *ec2v2.DescribeInstancesInput, optFns ...func(*ec2v2.Options)) (*ec2v2.DescribeInstancesOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.DescribeInstancesResponses[awsv2.ToString(input.NextToken)], nil
+}
+
+func (m *mockEC2V2Client) DescribeInstanceTypes(ctx context.Context, input *ec2v2.DescribeInstanceTypesInput, optFns ...func(*ec2v2.Options)) (*ec2v2.DescribeInstanceTypesOutput, error) {
+	return m.DescribeInstanceTypesResponse, m.err
+}
+
+func (m *mockEC2V2Client) DescribeSecurityGroups(ctx context.Context, input *ec2v2.DescribeSecurityGroupsInput, optFns ...func(*ec2v2.Options)) (*ec2v2.DescribeSecurityGroupsOutput, error) {
+	return m.DescribeSecurityGroupsResponse, m.err
+}
+
+func (m *mockEC2V2Client) DescribeVpnGateways(ctx context.Context, input *ec2v2.DescribeVpnGatewaysInput, optFns ...func(*ec2v2.Options)) (*ec2v2.DescribeVpnGatewaysOutput, error) {
+	return m.DescribeVpnGatewaysResponse, m.err
+}
+
+func TestEC2V2ClientVolumes(t *testing.T) {
+	mockClient := &mockEC2V2Client{
+		DescribeVolumesResponses: map[string]*ec2v2.DescribeVolumesOutput{
+			"": {
+				Volumes:   []types.Volume{{Size: awsv2.Int32(1024)}, {Size: awsv2.Int32(1024)}},
+				NextToken: awsv2.String("page2"),
+			},
+			"page2": {
+				Volumes: []types.Volume{{Size: awsv2.Int32(1024)}},
+			},
+		},
+	}
+
+	check := MaxGP2StoragePerRegionCheck{&ec2V2Client{client: mockClient}}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        maxGp2StoragePerRegionName,
+			Description: maxGp2StoragePerRegionDescription,
+			Usage:       3,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+	assert.Len(t, mockClient.DescribeVolumesInputs, 2)
+	assert.Equal(t, []types.Filter{{Name: awsv2.String("volume-type"), Values: []string{"gp2"}}}, mockClient.DescribeVolumesInputs[0].Filters)
+}
+
+func TestEC2V2ClientVolumesWithError(t *testing.T) {
+	mockClient := &mockEC2V2Client{err: errors.New("some err")}
+
+	check := MaxGP2StoragePerRegionCheck{&ec2V2Client{client: mockClient}}
+	usage, err := check.Usage(context.Background())
+
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestEC2V2ClientInstances(t *testing.T) {
+	mockClient := &mockEC2V2Client{
+		DescribeInstancesResponses: map[string]*ec2v2.DescribeInstancesOutput{
+			"": {
+				Reservations: []types.Reservation{
+					{
+						Instances: []types.Instance{
+							{
+								InstanceType: types.InstanceTypeM5Large,
+								CpuOptions:   &types.CpuOptions{CoreCount: awsv2.Int32(2), ThreadsPerCore: awsv2.Int32(2)},
+							},
+						},
+					},
+				},
+				NextToken: awsv2.String("page2"),
+			},
+			"page2": {
+				Reservations: []types.Reservation{
+					{
+						Instances: []types.Instance{
+							{InstanceType: types.InstanceTypeM5Xlarge},
+						},
+					},
+				},
+			},
+		},
+		DescribeInstanceTypesResponse: &ec2v2.DescribeInstanceTypesOutput{
+			InstanceTypes: []types.InstanceTypeInfo{
+				{
+					InstanceType: types.InstanceTypeM5Xlarge,
+					VCpuInfo:     &types.VCpuInfo{DefaultVCpus: awsv2.Int32(4)},
+				},
+			},
+		},
+	}
+
+	check := RunningOnDemandStandardInstancesUsageCheck{client: &ec2V2Client{client: mockClient}}
+	usage, err := check.Usage(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, usage, 1)
+	assert.Equal(t, float64(8), usage[0].Usage)
+}
+
+func TestEC2V2ClientSecurityGroups(t *testing.T) {
+	mockClient := &mockEC2V2Client{
+		DescribeSecurityGroupsResponse: &ec2v2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []types.SecurityGroup{{GroupId: awsv2.String("sg-1")}, {GroupId: awsv2.String("sg-2")}},
+		},
+	}
+
+	check := SecurityGroupsPerRegionUsageCheck{&ec2V2Client{client: mockClient}}
+	usage, err := check.Usage(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, usage, 1)
+	assert.Equal(t, float64(2), usage[0].Usage)
+}
+
+func TestEC2V2ClientVpnGateways(t *testing.T) {
+	mockClient := &mockEC2V2Client{
+		DescribeVpnGatewaysResponse: &ec2v2.DescribeVpnGatewaysOutput{
+			VpnGateways: []types.VpnGateway{
+				{VpnGatewayId: awsv2.String("vgw-1"), State: types.VpnStateAvailable},
+				{VpnGatewayId: awsv2.String("vgw-2"), State: types.VpnStateDeleted},
+			},
+		},
+	}
+
+	check := VpnGatewaysPerRegionCheck{&ec2V2Client{client: mockClient}}
+	usage, err := check.Usage(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, usage, 1)
+	assert.Equal(t, float64(1), usage[0].Usage)
+}
+
+func TestNewEC2V2Client(t *testing.T) {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Credentials: credentials.NewStaticCredentials("id", "secret", "token"),
+	}))
+
+	ec2Client := newEC2V2Client(sess, aws.NewConfig().WithRegion("eu-west-1"))
+
+	v2Client, ok := ec2Client.(*ec2V2Client)
+	assert.True(t, ok)
+	assert.IsType(t, &ec2v2.Client{}, v2Client.client)
+}
+
+func TestV1CredentialsProvider(t *testing.T) {
+	provider := v1CredentialsProvider{credentials.NewStaticCredentials("id", "secret", "token")}
+
+	creds, err := provider.Retrieve(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "id", creds.AccessKeyID)
+	assert.Equal(t, "secret", creds.SecretAccessKey)
+	assert.Equal(t, "token", creds.SessionToken)
+	assert.False(t, creds.CanExpire)
+}
+
+func TestNewUsageChecksEC2SDKV2(t *testing.T) {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	}))
+	cfg := aws.NewConfig().WithRegion("eu-west-1")
+
+	serviceQuotasChecks, _, _, _ := newUsageChecks(Options{EC2SDKV2: true}, sess, cfg, cfg, "123456789012", nil, nil)
+
+	check, ok := serviceQuotasChecks["L-E79EC296"].(*SecurityGroupsPerRegionUsageCheck)
+	assert.True(t, ok)
+	assert.IsType(t, &ec2V2Client{}, check.client)
+}
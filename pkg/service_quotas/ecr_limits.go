@@ -1,9 +1,11 @@
 package servicequotas
 
 import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ecr"
 	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
-	"github.com/pkg/errors"
 )
 
 const (
@@ -18,44 +20,65 @@ type RepositoriesPerRegionCheck struct {
 	client ecriface.ECRAPI
 }
 
-func (c *RepositoriesPerRegionCheck) Usage() ([]QuotaUsage, error) {
+// Usage returns the number of ECR repositories in the region or an
+// error
+func (c *RepositoriesPerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
 
 	var repositoryCount int
 
 	params := &ecr.DescribeRepositoriesInput{}
-	err := c.client.DescribeRepositoriesPages(params,
+	err := c.client.DescribeRepositoriesPagesWithContext(ctx, params,
 		func(page *ecr.DescribeRepositoriesOutput, lastPage bool) bool {
 			if page != nil {
 				repositoryCount += len(page.Repositories)
-				usage := QuotaUsage{
-					Name:        repositoriesPerRegionName,
-					Description: repositoriesPerRegionDescription,
-					Usage:       float64(repositoryCount),
-				}
-
-				quotaUsages = append(quotaUsages, usage)
 			}
 			return !lastPage
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := QuotaUsage{
+		Name:        repositoriesPerRegionName,
+		Description: repositoriesPerRegionDescription,
+		Usage:       float64(repositoryCount),
 	}
+	quotaUsages = append(quotaUsages, usage)
 	return quotaUsages, nil
 }
 
+// DescribeUsage implements the UsageDescriber interface
+func (c *RepositoriesPerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: repositoriesPerRegionName, Description: repositoriesPerRegionDescription}}
+}
+
 type ImagesPerRepositoryCheck struct {
-	client ecriface.ECRAPI
+	client       ecriface.ECRAPI
+	maxResources int
+	// taggedOnly only counts the tagged images of each repository
+	taggedOnly bool
+	sampler    resourceSampler
 }
 
-func (c *ImagesPerRepositoryCheck) Usage() ([]QuotaUsage, error) {
+// Usage returns the number of images for each ECR repository or an
+// error
+// All images are counted to match the AWS accounting for the quota,
+// unless `taggedOnly` is set
+// The maximum number of resources is shared between all repositories,
+// the remaining repositories are skipped once it has been reached
+// When sampling, the images of the repositories that are not sampled
+// are not listed and their previous usage is returned
+func (c *ImagesPerRepositoryCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
+	imagesCap := &resourceCap{max: c.maxResources}
+	c.sampler.start()
 
 	var listOfRepositories []*string
 
 	listOfRepositoriesParams := &ecr.DescribeRepositoriesInput{}
-	listOfRepositoriesErr := c.client.DescribeRepositoriesPages(listOfRepositoriesParams,
+	listOfRepositoriesErr := c.client.DescribeRepositoriesPagesWithContext(ctx, listOfRepositoriesParams,
 		func(page *ecr.DescribeRepositoriesOutput, lastPage bool) bool {
 			if page != nil {
 				for _, repo := range page.Repositories {
@@ -66,34 +89,53 @@ func (c *ImagesPerRepositoryCheck) Usage() ([]QuotaUsage, error) {
 		},
 	)
 	if listOfRepositoriesErr != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", listOfRepositoriesErr)
+		return nil, wrapUsageErr(listOfRepositoriesErr)
 	}
 
 	for _, repo := range listOfRepositories {
+		if usage, ok := c.sampler.skip(*repo); ok {
+			quotaUsages = append(quotaUsages, usage)
+			continue
+		}
+		if imagesCap.reached() {
+			imagesCap.truncated = true
+			break
+		}
+
 		var imageCount int
 		listOfImagesParams := &ecr.ListImagesInput{RepositoryName: repo}
-		listOfImagesErr := c.client.ListImagesPages(listOfImagesParams,
+		if c.taggedOnly {
+			listOfImagesParams.Filter = &ecr.ListImagesFilter{TagStatus: aws.String(ecr.TagStatusTagged)}
+		}
+		listOfImagesErr := c.client.ListImagesPagesWithContext(ctx, listOfImagesParams,
 			func(page *ecr.ListImagesOutput, lastPage bool) bool {
 				if page != nil {
 					imageCount += len(page.ImageIds)
+					return imagesCap.add(len(page.ImageIds), lastPage)
 				}
 				return !lastPage
 			},
 		)
 		if listOfImagesErr != nil {
-			return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", listOfImagesErr)
+			return nil, wrapUsageErr(listOfImagesErr)
 		}
 
-		usage := []QuotaUsage{
-			{
-				Name:         imagesPerRepositoryName,
-				Description:  imagesPerRepositoryDescription,
-				ResourceName: repo,
-				Usage:        float64(imageCount),
-			},
+		usage := QuotaUsage{
+			Name:         imagesPerRepositoryName,
+			Description:  imagesPerRepositoryDescription,
+			ResourceName: repo,
+			Usage:        float64(imageCount),
 		}
-		quotaUsages = append(quotaUsages, usage...)
+		c.sampler.record(*repo, usage)
+		quotaUsages = append(quotaUsages, usage)
 	}
+	c.sampler.finish()
+	imagesCap.markTruncated(quotaUsages)
 	return quotaUsages, nil
 
 }
+
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *ImagesPerRepositoryCheck) DescribeResourceUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: imagesPerRepositoryName, Description: imagesPerRepositoryDescription}}
+}
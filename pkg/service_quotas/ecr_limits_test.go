@@ -0,0 +1,177 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockECRClient) DescribeRepositoriesPagesWithContext(ctx aws.Context, input *ecr.DescribeRepositoriesInput, fn func(*ecr.DescribeRepositoriesOutput, bool) bool, opts ...request.Option) error {
+	if m.DescribeRepositoriesPagesResponses != nil {
+		for i, page := range m.DescribeRepositoriesPagesResponses {
+			if !fn(page, i == len(m.DescribeRepositoriesPagesResponses)-1) {
+				break
+			}
+		}
+		return m.err
+	}
+	fn(m.DescribeRepositoriesResponse, true)
+	return m.err
+}
+
+func (m *mockECRClient) ListImagesPagesWithContext(ctx aws.Context, input *ecr.ListImagesInput, fn func(*ecr.ListImagesOutput, bool) bool, opts ...request.Option) error {
+	m.ListImagesFilters = append(m.ListImagesFilters, input.Filter)
+	fn(m.ListImagesResponses[*input.RepositoryName], true)
+	return m.err
+}
+
+func TestImagesPerRepositoryCheckWithError(t *testing.T) {
+	mockClient := &mockECRClient{
+		err:                          errors.New("some err"),
+		DescribeRepositoriesResponse: nil,
+	}
+
+	check := ImagesPerRepositoryCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestImagesPerRepositoryCheck(t *testing.T) {
+	testCases := []struct {
+		name           string
+		taggedOnly     bool
+		expectedFilter *ecr.ListImagesFilter
+	}{
+		{
+			name:           "AllImages",
+			taggedOnly:     false,
+			expectedFilter: nil,
+		},
+		{
+			name:           "TaggedImagesOnly",
+			taggedOnly:     true,
+			expectedFilter: &ecr.ListImagesFilter{TagStatus: aws.String(ecr.TagStatusTagged)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &mockECRClient{
+				DescribeRepositoriesResponse: &ecr.DescribeRepositoriesOutput{
+					Repositories: []*ecr.Repository{
+						{RepositoryName: aws.String("repo1")},
+					},
+				},
+				ListImagesResponses: map[string]*ecr.ListImagesOutput{
+					"repo1": {
+						ImageIds: []*ecr.ImageIdentifier{
+							{ImageDigest: aws.String("sha256:1"), ImageTag: aws.String("v1")},
+							{ImageDigest: aws.String("sha256:2"), ImageTag: aws.String("v2")},
+						},
+					},
+				},
+			}
+
+			check := ImagesPerRepositoryCheck{client: mockClient, taggedOnly: tc.taggedOnly}
+			usage, err := check.Usage(context.Background())
+
+			expectedUsage := []QuotaUsage{
+				{
+					Name:         imagesPerRepositoryName,
+					Description:  imagesPerRepositoryDescription,
+					ResourceName: aws.String("repo1"),
+					Usage:        2,
+				},
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, expectedUsage, usage)
+			assert.Equal(t, []*ecr.ListImagesFilter{tc.expectedFilter}, mockClient.ListImagesFilters)
+		})
+	}
+}
+
+func TestImagesPerRepositoryCheckSampling(t *testing.T) {
+	images := func(count int) *ecr.ListImagesOutput {
+		output := &ecr.ListImagesOutput{}
+		for i := 0; i < count; i++ {
+			output.ImageIds = append(output.ImageIds, &ecr.ImageIdentifier{ImageDigest: aws.String("sha256:1")})
+		}
+		return output
+	}
+	mockClient := &mockECRClient{
+		DescribeRepositoriesResponse: &ecr.DescribeRepositoriesOutput{
+			Repositories: []*ecr.Repository{
+				{RepositoryName: aws.String("repo1")},
+				{RepositoryName: aws.String("repo2")},
+			},
+		},
+		ListImagesResponses: map[string]*ecr.ListImagesOutput{
+			"repo1": images(1),
+			"repo2": images(2),
+		},
+	}
+	check := ImagesPerRepositoryCheck{client: mockClient, sampler: resourceSampler{interval: 2}}
+
+	usageOf := func(usages []QuotaUsage) map[string]float64 {
+		usageByRepo := map[string]float64{}
+		for _, usage := range usages {
+			usageByRepo[*usage.ResourceName] = usage.Usage
+		}
+		return usageByRepo
+	}
+
+	usage, err := check.Usage(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{"repo1": 1, "repo2": 2}, usageOf(usage))
+	assert.Len(t, mockClient.ListImagesFilters, 2)
+
+	mockClient.ListImagesResponses = map[string]*ecr.ListImagesOutput{
+		"repo1": images(3),
+		"repo2": images(4),
+	}
+
+	// each of the following refreshes only lists the images of one of
+	// the repositories
+	usage, err = check.Usage(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, mockClient.ListImagesFilters, 3)
+	firstSample := usageOf(usage)
+
+	usage, err = check.Usage(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, mockClient.ListImagesFilters, 4)
+	assert.NotEqual(t, firstSample, usageOf(usage))
+	assert.Equal(t, map[string]float64{"repo1": 3, "repo2": 4}, usageOf(usage))
+}
+
+func TestRepositoriesPerRegionCheck(t *testing.T) {
+	mockClient := &mockECRClient{
+		DescribeRepositoriesPagesResponses: []*ecr.DescribeRepositoriesOutput{
+			{Repositories: []*ecr.Repository{{RepositoryName: aws.String("repo1")}, {RepositoryName: aws.String("repo2")}}},
+			{Repositories: []*ecr.Repository{{RepositoryName: aws.String("repo3")}}},
+		},
+	}
+
+	check := RepositoriesPerRegionCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        repositoriesPerRegionName,
+			Description: repositoriesPerRegionDescription,
+			Usage:       3,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
@@ -0,0 +1,249 @@
+package servicequotas
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
+)
+
+const (
+	fargateOnDemandVCPUsName        = "fargate_ondemand_vcpus"
+	fargateOnDemandVCPUsDescription = "fargate on-demand vCPUs"
+
+	fargateSpotVCPUsName        = "fargate_spot_vcpus"
+	fargateSpotVCPUsDescription = "fargate spot vCPUs"
+
+	ecsClustersPerAccountName        = "ecs_clusters_per_account"
+	ecsClustersPerAccountDescription = "ECS clusters per account"
+
+	servicesPerClusterName        = "ecs_services_per_cluster"
+	servicesPerClusterDescription = "ECS services per cluster"
+
+	fargateSpotCapacityProvider = "FARGATE_SPOT"
+
+	// describeTasksBatchSize is the maximum number of tasks that can be
+	// described with a single DescribeTasks call
+	describeTasksBatchSize = 100
+
+	// cpuUnitsPerVCPU is the number of ECS CPU units in one vCPU
+	cpuUnitsPerVCPU = 1024
+)
+
+// clusterARNs returns the ARNs of the ECS clusters, up to the cap of
+// `clustersCap`, or an error
+func clusterARNs(ctx context.Context, ecsService ecsiface.ECSAPI, clustersCap *resourceCap) ([]*string, error) {
+	var clusters []*string
+	err := ecsService.ListClustersPagesWithContext(ctx, &ecs.ListClustersInput{},
+		func(page *ecs.ListClustersOutput, lastPage bool) bool {
+			if page == nil {
+				return !lastPage
+			}
+			clusters = append(clusters, page.ClusterArns...)
+			return clustersCap.add(len(page.ClusterArns), lastPage)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return clusters, nil
+}
+
+// fargateVCPUs returns the number of vCPUs used by the running Fargate
+// tasks across all ECS clusters. Only Fargate Spot tasks are counted
+// if `spotTasks` is set, and only Fargate On-Demand tasks otherwise
+func fargateVCPUs(ctx context.Context, ecsService ecsiface.ECSAPI, spotTasks bool) (float64, error) {
+	clusters, err := clusterARNs(ctx, ecsService, &resourceCap{})
+	if err != nil {
+		return 0, err
+	}
+
+	var totalCPUUnits float64
+	for _, cluster := range clusters {
+		var taskArns []*string
+		params := &ecs.ListTasksInput{
+			Cluster:       cluster,
+			LaunchType:    aws.String(ecs.LaunchTypeFargate),
+			DesiredStatus: aws.String(ecs.DesiredStatusRunning),
+		}
+		err := ecsService.ListTasksPagesWithContext(ctx, params,
+			func(page *ecs.ListTasksOutput, lastPage bool) bool {
+				if page != nil {
+					taskArns = append(taskArns, page.TaskArns...)
+				}
+				return !lastPage
+			},
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		for start := 0; start < len(taskArns); start += describeTasksBatchSize {
+			end := start + describeTasksBatchSize
+			if end > len(taskArns) {
+				end = len(taskArns)
+			}
+
+			tasks, err := ecsService.DescribeTasksWithContext(ctx, &ecs.DescribeTasksInput{
+				Cluster: cluster,
+				Tasks:   taskArns[start:end],
+			})
+			if err != nil {
+				return 0, err
+			}
+
+			for _, task := range tasks.Tasks {
+				isSpot := aws.StringValue(task.CapacityProviderName) == fargateSpotCapacityProvider
+				if isSpot != spotTasks || task.Cpu == nil {
+					continue
+				}
+
+				cpuUnits, err := strconv.ParseFloat(*task.Cpu, 64)
+				if err != nil {
+					return 0, err
+				}
+				totalCPUUnits += cpuUnits
+			}
+		}
+	}
+
+	return totalCPUUnits / cpuUnitsPerVCPU, nil
+}
+
+// FargateOnDemandVCPUsCheck implements the UsageCheck interface for
+// Fargate On-Demand vCPUs
+type FargateOnDemandVCPUsCheck struct {
+	client ecsiface.ECSAPI
+}
+
+// Usage returns the number of vCPUs used by running Fargate On-Demand
+// tasks or an error
+func (c *FargateOnDemandVCPUsCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	vCPUs, err := fargateVCPUs(ctx, c.client, false)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        fargateOnDemandVCPUsName,
+			Description: fargateOnDemandVCPUsDescription,
+			Usage:       vCPUs,
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *FargateOnDemandVCPUsCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: fargateOnDemandVCPUsName, Description: fargateOnDemandVCPUsDescription}}
+}
+
+// FargateSpotVCPUsCheck implements the UsageCheck interface for
+// Fargate Spot vCPUs
+type FargateSpotVCPUsCheck struct {
+	client ecsiface.ECSAPI
+}
+
+// Usage returns the number of vCPUs used by running Fargate Spot tasks
+// or an error
+func (c *FargateSpotVCPUsCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	vCPUs, err := fargateVCPUs(ctx, c.client, true)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        fargateSpotVCPUsName,
+			Description: fargateSpotVCPUsDescription,
+			Usage:       vCPUs,
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *FargateSpotVCPUsCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: fargateSpotVCPUsName, Description: fargateSpotVCPUsDescription}}
+}
+
+// ClustersPerAccountCheck implements the UsageCheck interface for ECS
+// clusters per account
+type ClustersPerAccountCheck struct {
+	client ecsiface.ECSAPI
+}
+
+// Usage returns the number of ECS clusters in the region of the client
+// or an error
+func (c *ClustersPerAccountCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	clusters, err := clusterARNs(ctx, c.client, &resourceCap{})
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        ecsClustersPerAccountName,
+			Description: ecsClustersPerAccountDescription,
+			Usage:       float64(len(clusters)),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *ClustersPerAccountCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: ecsClustersPerAccountName, Description: ecsClustersPerAccountDescription}}
+}
+
+// ServicesPerClusterCheck implements the UsageCheck interface for ECS
+// services per cluster
+type ServicesPerClusterCheck struct {
+	client       ecsiface.ECSAPI
+	maxResources int
+}
+
+// Usage returns the number of services of each ECS cluster, keyed by
+// cluster ARN, or an error
+func (c *ServicesPerClusterCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	clustersCap := &resourceCap{max: c.maxResources}
+	clusters, err := clusterARNs(ctx, c.client, clustersCap)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	quotaUsages := []QuotaUsage{}
+	for _, cluster := range clusters {
+		var servicesCount int
+		params := &ecs.ListServicesInput{Cluster: cluster}
+		err := c.client.ListServicesPagesWithContext(ctx, params,
+			func(page *ecs.ListServicesOutput, lastPage bool) bool {
+				if page != nil {
+					servicesCount += len(page.ServiceArns)
+				}
+				return !lastPage
+			},
+		)
+		if err != nil {
+			return nil, wrapUsageErr(err)
+		}
+
+		quotaUsages = append(quotaUsages, QuotaUsage{
+			Name:         servicesPerClusterName,
+			ResourceName: cluster,
+			Description:  servicesPerClusterDescription,
+			Usage:        float64(servicesCount),
+		})
+	}
+
+	clustersCap.markTruncated(quotaUsages)
+	return quotaUsages, nil
+}
+
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *ServicesPerClusterCheck) DescribeResourceUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: servicesPerClusterName, Description: servicesPerClusterDescription}}
+}
@@ -0,0 +1,191 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockECSClient) ListClustersPagesWithContext(ctx aws.Context, input *ecs.ListClustersInput, fn func(*ecs.ListClustersOutput, bool) bool, opts ...request.Option) error {
+	fn(m.ListClustersResponse, true)
+	return m.err
+}
+
+func (m *mockECSClient) ListTasksPagesWithContext(ctx aws.Context, input *ecs.ListTasksInput, fn func(*ecs.ListTasksOutput, bool) bool, opts ...request.Option) error {
+	m.ListTasksFilters = append(m.ListTasksFilters, input)
+	fn(m.ListTasksResponses[*input.Cluster], true)
+	return m.err
+}
+
+func (m *mockECSClient) DescribeTasksWithContext(ctx aws.Context, input *ecs.DescribeTasksInput, opts ...request.Option) (*ecs.DescribeTasksOutput, error) {
+	return m.DescribeTasksResponse[*input.Cluster], m.err
+}
+
+func (m *mockECSClient) ListServicesPagesWithContext(ctx aws.Context, input *ecs.ListServicesInput, fn func(*ecs.ListServicesOutput, bool) bool, opts ...request.Option) error {
+	fn(m.ListServicesResponses[*input.Cluster], true)
+	return m.err
+}
+
+func fargateMockClient() *mockECSClient {
+	return &mockECSClient{
+		ListClustersResponse: &ecs.ListClustersOutput{
+			ClusterArns: []*string{aws.String("cluster1"), aws.String("cluster2")},
+		},
+		ListTasksResponses: map[string]*ecs.ListTasksOutput{
+			"cluster1": {TaskArns: []*string{aws.String("task1"), aws.String("task2"), aws.String("task3")}},
+			"cluster2": {TaskArns: []*string{aws.String("task4")}},
+		},
+		DescribeTasksResponse: map[string]*ecs.DescribeTasksOutput{
+			"cluster1": {
+				Tasks: []*ecs.Task{
+					{TaskArn: aws.String("task1"), Cpu: aws.String("512"), CapacityProviderName: aws.String("FARGATE")},
+					{TaskArn: aws.String("task2"), Cpu: aws.String("1024"), CapacityProviderName: aws.String("FARGATE_SPOT")},
+					{TaskArn: aws.String("task3"), Cpu: aws.String("256")},
+				},
+			},
+			"cluster2": {
+				Tasks: []*ecs.Task{
+					{TaskArn: aws.String("task4"), Cpu: aws.String("2048"), CapacityProviderName: aws.String("FARGATE_SPOT")},
+				},
+			},
+		},
+	}
+}
+
+func TestFargateOnDemandVCPUsCheckWithError(t *testing.T) {
+	mockClient := &mockECSClient{
+		err:                  errors.New("some err"),
+		ListClustersResponse: nil,
+	}
+
+	check := FargateOnDemandVCPUsCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestFargateOnDemandVCPUsCheck(t *testing.T) {
+	mockClient := fargateMockClient()
+
+	check := FargateOnDemandVCPUsCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        fargateOnDemandVCPUsName,
+			Description: fargateOnDemandVCPUsDescription,
+			Usage:       0.75,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+	for _, filter := range mockClient.ListTasksFilters {
+		assert.Equal(t, ecs.LaunchTypeFargate, *filter.LaunchType)
+		assert.Equal(t, ecs.DesiredStatusRunning, *filter.DesiredStatus)
+	}
+}
+
+func TestFargateSpotVCPUsCheck(t *testing.T) {
+	check := FargateSpotVCPUsCheck{fargateMockClient()}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        fargateSpotVCPUsName,
+			Description: fargateSpotVCPUsDescription,
+			Usage:       3,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestClustersPerAccountCheckWithError(t *testing.T) {
+	mockClient := &mockECSClient{
+		err: errors.New("some err"),
+	}
+
+	check := ClustersPerAccountCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestClustersPerAccountCheck(t *testing.T) {
+	mockClient := &mockECSClient{
+		ListClustersResponse: &ecs.ListClustersOutput{
+			ClusterArns: []*string{aws.String("cluster1"), aws.String("cluster2")},
+		},
+	}
+
+	check := ClustersPerAccountCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        ecsClustersPerAccountName,
+			Description: ecsClustersPerAccountDescription,
+			Usage:       2,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestServicesPerClusterCheckWithError(t *testing.T) {
+	mockClient := &mockECSClient{
+		err: errors.New("some err"),
+	}
+
+	check := ServicesPerClusterCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestServicesPerClusterCheck(t *testing.T) {
+	mockClient := &mockECSClient{
+		ListClustersResponse: &ecs.ListClustersOutput{
+			ClusterArns: []*string{aws.String("cluster1"), aws.String("cluster2")},
+		},
+		ListServicesResponses: map[string]*ecs.ListServicesOutput{
+			"cluster1": {ServiceArns: []*string{aws.String("service1"), aws.String("service2")}},
+			"cluster2": {},
+		},
+	}
+
+	check := ServicesPerClusterCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:         servicesPerClusterName,
+			ResourceName: aws.String("cluster1"),
+			Description:  servicesPerClusterDescription,
+			Usage:        2,
+		},
+		{
+			Name:         servicesPerClusterName,
+			ResourceName: aws.String("cluster2"),
+			Description:  servicesPerClusterDescription,
+			Usage:        0,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
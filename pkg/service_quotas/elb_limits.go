@@ -0,0 +1,207 @@
+package servicequotas
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+)
+
+const (
+	applicationLoadBalancersPerRegionName        = "application_load_balancers_per_region"
+	applicationLoadBalancersPerRegionDescription = "Application load balancers per region"
+
+	networkLoadBalancersPerRegionName        = "network_load_balancers_per_region"
+	networkLoadBalancersPerRegionDescription = "Network load balancers per region"
+
+	classicLoadBalancersPerRegionName        = "classic_load_balancers_per_region"
+	classicLoadBalancersPerRegionDescription = "Classic load balancers per region"
+
+	targetGroupsPerRegionName        = "target_groups_per_region"
+	targetGroupsPerRegionDescription = "Target groups per region"
+)
+
+// describeLoadBalancerTagsBatchSize is the maximum number of load
+// balancers whose tags are returned by each DescribeTags call
+const describeLoadBalancerTagsBatchSize = 20
+
+// LoadBalancersPerRegionCheck implements the UsageCheck interface for
+// the application or network load balancers per region, depending on
+// its `loadBalancerType`, one of the elbv2.LoadBalancerTypeEnum values
+type LoadBalancersPerRegionCheck struct {
+	client           elbv2iface.ELBV2API
+	loadBalancerType string
+	// includedTags are the tag keys exported as labels, the tags of
+	// the load balancers are only retrieved when it is set
+	includedTags []string
+}
+
+// Usage returns the number of load balancers of the type of the check
+// or an error. The usage is for the whole region, so the included tags
+// are only attached when every load balancer has the same value for
+// them
+func (c *LoadBalancersPerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var loadBalancerARNs []*string
+
+	err := c.client.DescribeLoadBalancersPagesWithContext(ctx, &elbv2.DescribeLoadBalancersInput{},
+		func(page *elbv2.DescribeLoadBalancersOutput, lastPage bool) bool {
+			if page != nil {
+				for _, loadBalancer := range page.LoadBalancers {
+					if aws.StringValue(loadBalancer.Type) == c.loadBalancerType {
+						loadBalancerARNs = append(loadBalancerARNs, loadBalancer.LoadBalancerArn)
+					}
+				}
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := c.describe()
+	usage.Usage = float64(len(loadBalancerARNs))
+	if len(c.includedTags) > 0 && len(loadBalancerARNs) > 0 {
+		tags, err := c.commonTags(ctx, loadBalancerARNs)
+		if err != nil {
+			return nil, wrapUsageErr(err)
+		}
+		usage.Tags = tags
+	}
+	return []QuotaUsage{usage}, nil
+}
+
+// variant implements the checkVariant interface
+func (c *LoadBalancersPerRegionCheck) variant() string {
+	return c.loadBalancerType
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *LoadBalancersPerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{c.describe()}
+}
+
+// describe returns the usage of the check with a zero value
+func (c *LoadBalancersPerRegionCheck) describe() QuotaUsage {
+	if c.loadBalancerType == elbv2.LoadBalancerTypeEnumNetwork {
+		return QuotaUsage{Name: networkLoadBalancersPerRegionName, Description: networkLoadBalancersPerRegionDescription}
+	}
+	return QuotaUsage{Name: applicationLoadBalancersPerRegionName, Description: applicationLoadBalancersPerRegionDescription}
+}
+
+// commonTags returns the included tags with the same value on all
+// `loadBalancerARNs`, keyed like the tags of the other checks, or an
+// error
+func (c *LoadBalancersPerRegionCheck) commonTags(ctx context.Context, loadBalancerARNs []*string) (map[string]string, error) {
+	var common map[string]string
+	for start := 0; start < len(loadBalancerARNs); start += describeLoadBalancerTagsBatchSize {
+		end := start + describeLoadBalancerTagsBatchSize
+		if end > len(loadBalancerARNs) {
+			end = len(loadBalancerARNs)
+		}
+
+		params := &elbv2.DescribeTagsInput{ResourceArns: loadBalancerARNs[start:end]}
+		response, err := c.client.DescribeTagsWithContext(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		for _, description := range response.TagDescriptions {
+			common = keepCommonTags(common, c.loadBalancerTags(description.Tags))
+		}
+	}
+
+	if len(common) == 0 {
+		return nil, nil
+	}
+	return common, nil
+}
+
+// loadBalancerTags returns the included tags of `tags`
+func (c *LoadBalancersPerRegionCheck) loadBalancerTags(tags []*elbv2.Tag) map[string]string {
+	included := map[string]string{}
+	for _, tag := range tags {
+		for _, key := range c.includedTags {
+			if aws.StringValue(tag.Key) == key {
+				included[ToPrometheusNamingFormat(key)] = aws.StringValue(tag.Value)
+			}
+		}
+	}
+	return included
+}
+
+// ClassicLoadBalancersPerRegionCheck implements the UsageCheck
+// interface for the classic load balancers per region
+type ClassicLoadBalancersPerRegionCheck struct {
+	client elbiface.ELBAPI
+}
+
+// Usage returns the number of classic load balancers or an error
+func (c *ClassicLoadBalancersPerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var loadBalancersCount int
+
+	err := c.client.DescribeLoadBalancersPagesWithContext(ctx, &elb.DescribeLoadBalancersInput{},
+		func(page *elb.DescribeLoadBalancersOutput, lastPage bool) bool {
+			if page != nil {
+				loadBalancersCount += len(page.LoadBalancerDescriptions)
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        classicLoadBalancersPerRegionName,
+			Description: classicLoadBalancersPerRegionDescription,
+			Usage:       float64(loadBalancersCount),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *ClassicLoadBalancersPerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: classicLoadBalancersPerRegionName, Description: classicLoadBalancersPerRegionDescription}}
+}
+
+// TargetGroupsPerRegionCheck implements the UsageCheck interface for
+// the target groups per region
+type TargetGroupsPerRegionCheck struct {
+	client elbv2iface.ELBV2API
+}
+
+// Usage returns the number of target groups or an error
+func (c *TargetGroupsPerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var targetGroupsCount int
+
+	err := c.client.DescribeTargetGroupsPagesWithContext(ctx, &elbv2.DescribeTargetGroupsInput{},
+		func(page *elbv2.DescribeTargetGroupsOutput, lastPage bool) bool {
+			if page != nil {
+				targetGroupsCount += len(page.TargetGroups)
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        targetGroupsPerRegionName,
+			Description: targetGroupsPerRegionDescription,
+			Usage:       float64(targetGroupsCount),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *TargetGroupsPerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: targetGroupsPerRegionName, Description: targetGroupsPerRegionDescription}}
+}
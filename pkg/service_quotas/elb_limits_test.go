@@ -0,0 +1,246 @@
+package servicequotas
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockELBV2Client) DescribeLoadBalancersPagesWithContext(ctx aws.Context, input *elbv2.DescribeLoadBalancersInput, fn func(*elbv2.DescribeLoadBalancersOutput, bool) bool, opts ...request.Option) error {
+	fn(m.DescribeLoadBalancersResponse, true)
+	return m.err
+}
+
+func (m *mockELBV2Client) DescribeTargetGroupsPagesWithContext(ctx aws.Context, input *elbv2.DescribeTargetGroupsInput, fn func(*elbv2.DescribeTargetGroupsOutput, bool) bool, opts ...request.Option) error {
+	fn(m.DescribeTargetGroupsResponse, true)
+	return m.err
+}
+
+func (m *mockELBV2Client) DescribeTagsWithContext(ctx aws.Context, input *elbv2.DescribeTagsInput, opts ...request.Option) (*elbv2.DescribeTagsOutput, error) {
+	m.DescribeTagsInputs = append(m.DescribeTagsInputs, input)
+	if m.err != nil {
+		return nil, m.err
+	}
+	output := &elbv2.DescribeTagsOutput{}
+	for _, arn := range input.ResourceArns {
+		output.TagDescriptions = append(output.TagDescriptions, &elbv2.TagDescription{ResourceArn: arn, Tags: m.Tags[*arn]})
+	}
+	return output, nil
+}
+
+func (m *mockELBClient) DescribeLoadBalancersPagesWithContext(ctx aws.Context, input *elb.DescribeLoadBalancersInput, fn func(*elb.DescribeLoadBalancersOutput, bool) bool, opts ...request.Option) error {
+	fn(m.DescribeLoadBalancersResponse, true)
+	return m.err
+}
+
+func testLoadBalancers() *elbv2.DescribeLoadBalancersOutput {
+	return &elbv2.DescribeLoadBalancersOutput{
+		LoadBalancers: []*elbv2.LoadBalancer{
+			{LoadBalancerArn: aws.String("arn:alb-1"), Type: aws.String(elbv2.LoadBalancerTypeEnumApplication)},
+			{LoadBalancerArn: aws.String("arn:alb-2"), Type: aws.String(elbv2.LoadBalancerTypeEnumApplication)},
+			{LoadBalancerArn: aws.String("arn:nlb-1"), Type: aws.String(elbv2.LoadBalancerTypeEnumNetwork)},
+			{LoadBalancerArn: aws.String("arn:gwlb-1"), Type: aws.String(elbv2.LoadBalancerTypeEnumGateway)},
+		},
+	}
+}
+
+func elbv2Tags(tags map[string]string) []*elbv2.Tag {
+	elbTags := []*elbv2.Tag{}
+	for key, value := range tags {
+		elbTags = append(elbTags, &elbv2.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return elbTags
+}
+
+func TestLoadBalancersPerRegionCheckWithError(t *testing.T) {
+	mockClient := &mockELBV2Client{
+		err: errors.New("some err"),
+	}
+
+	check := LoadBalancersPerRegionCheck{client: mockClient, loadBalancerType: elbv2.LoadBalancerTypeEnumApplication}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestLoadBalancersPerRegionCheck(t *testing.T) {
+	testCases := []struct {
+		name             string
+		loadBalancerType string
+		expectedUsage    []QuotaUsage
+	}{
+		{
+			name:             "Application",
+			loadBalancerType: elbv2.LoadBalancerTypeEnumApplication,
+			expectedUsage: []QuotaUsage{
+				{
+					Name:        applicationLoadBalancersPerRegionName,
+					Description: applicationLoadBalancersPerRegionDescription,
+					Usage:       2,
+				},
+			},
+		},
+		{
+			name:             "Network",
+			loadBalancerType: elbv2.LoadBalancerTypeEnumNetwork,
+			expectedUsage: []QuotaUsage{
+				{
+					Name:        networkLoadBalancersPerRegionName,
+					Description: networkLoadBalancersPerRegionDescription,
+					Usage:       1,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &mockELBV2Client{
+				DescribeLoadBalancersResponse: testLoadBalancers(),
+			}
+
+			check := LoadBalancersPerRegionCheck{client: mockClient, loadBalancerType: tc.loadBalancerType}
+			usage, err := check.Usage(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedUsage, usage)
+			assert.Empty(t, mockClient.DescribeTagsInputs)
+		})
+	}
+}
+
+func TestLoadBalancersPerRegionCheckWithTags(t *testing.T) {
+	mockClient := &mockELBV2Client{
+		DescribeLoadBalancersResponse: testLoadBalancers(),
+		Tags: map[string][]*elbv2.Tag{
+			"arn:alb-1": elbv2Tags(map[string]string{"account-owner": "platform", "team": "a"}),
+			"arn:alb-2": elbv2Tags(map[string]string{"account-owner": "platform", "team": "b"}),
+			"arn:nlb-1": elbv2Tags(map[string]string{"account-owner": "other"}),
+		},
+	}
+
+	check := LoadBalancersPerRegionCheck{mockClient, elbv2.LoadBalancerTypeEnumApplication, []string{"account-owner", "team"}}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        applicationLoadBalancersPerRegionName,
+			Description: applicationLoadBalancersPerRegionDescription,
+			Usage:       2,
+			Tags:        map[string]string{"account_owner": "platform"},
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+	assert.Len(t, mockClient.DescribeTagsInputs, 1)
+	assert.Equal(t, aws.StringSlice([]string{"arn:alb-1", "arn:alb-2"}), mockClient.DescribeTagsInputs[0].ResourceArns)
+}
+
+func TestLoadBalancersPerRegionCheckBatchesDescribeTags(t *testing.T) {
+	output := &elbv2.DescribeLoadBalancersOutput{}
+	tags := map[string][]*elbv2.Tag{}
+	for i := 0; i < describeLoadBalancerTagsBatchSize+5; i++ {
+		arn := fmt.Sprintf("arn:alb-%d", i)
+		output.LoadBalancers = append(output.LoadBalancers, &elbv2.LoadBalancer{
+			LoadBalancerArn: aws.String(arn),
+			Type:            aws.String(elbv2.LoadBalancerTypeEnumApplication),
+		})
+		tags[arn] = elbv2Tags(map[string]string{"team": "a"})
+	}
+	mockClient := &mockELBV2Client{DescribeLoadBalancersResponse: output, Tags: tags}
+
+	check := LoadBalancersPerRegionCheck{mockClient, elbv2.LoadBalancerTypeEnumApplication, []string{"team"}}
+	usage, err := check.Usage(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "a"}, usage[0].Tags)
+	assert.Len(t, mockClient.DescribeTagsInputs, 2)
+	assert.Len(t, mockClient.DescribeTagsInputs[0].ResourceArns, describeLoadBalancerTagsBatchSize)
+	assert.Len(t, mockClient.DescribeTagsInputs[1].ResourceArns, 5)
+}
+
+func TestClassicLoadBalancersPerRegionCheckWithError(t *testing.T) {
+	mockClient := &mockELBClient{
+		err: errors.New("some err"),
+	}
+
+	check := ClassicLoadBalancersPerRegionCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestClassicLoadBalancersPerRegionCheck(t *testing.T) {
+	mockClient := &mockELBClient{
+		DescribeLoadBalancersResponse: &elb.DescribeLoadBalancersOutput{
+			LoadBalancerDescriptions: []*elb.LoadBalancerDescription{
+				{LoadBalancerName: aws.String("clb-1")},
+			},
+		},
+	}
+
+	check := ClassicLoadBalancersPerRegionCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        classicLoadBalancersPerRegionName,
+			Description: classicLoadBalancersPerRegionDescription,
+			Usage:       1,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestTargetGroupsPerRegionCheckWithError(t *testing.T) {
+	mockClient := &mockELBV2Client{
+		err: errors.New("some err"),
+	}
+
+	check := TargetGroupsPerRegionCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestTargetGroupsPerRegionCheck(t *testing.T) {
+	mockClient := &mockELBV2Client{
+		DescribeTargetGroupsResponse: &elbv2.DescribeTargetGroupsOutput{
+			TargetGroups: []*elbv2.TargetGroup{
+				{TargetGroupName: aws.String("tg-1")},
+				{TargetGroupName: aws.String("tg-2")},
+				{TargetGroupName: aws.String("tg-3")},
+			},
+		},
+	}
+
+	check := TargetGroupsPerRegionCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        targetGroupsPerRegionName,
+			Description: targetGroupsPerRegionDescription,
+			Usage:       3,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
@@ -0,0 +1,125 @@
+package servicequotas
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/servicequotas/servicequotasiface"
+	"github.com/pkg/errors"
+)
+
+// FakeUsageCheck is an in-memory UsageCheck returning `Usages` or
+// `Err`, for testing integrations and new checks without AWS
+type FakeUsageCheck struct {
+	Usages []QuotaUsage
+	Err    error
+
+	mutex sync.Mutex
+	calls int
+}
+
+// Usage implements the UsageCheck interface
+func (f *FakeUsageCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.calls++
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return f.Usages, nil
+}
+
+// Calls returns the number of times Usage was called
+func (f *FakeUsageCheck) Calls() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.calls
+}
+
+// FakeQuotasInterface is an in-memory QuotasInterface returning
+// `Quotas` and `Err` from QuotasAndUsage and `DescribedQuotas` from
+// DescribeQuotas, for testing integrations without AWS
+type FakeQuotasInterface struct {
+	Quotas          []QuotaUsage
+	DescribedQuotas []QuotaUsage
+	Err             error
+
+	mutex sync.Mutex
+	calls int
+}
+
+// QuotasAndUsage implements the QuotasInterface
+func (f *FakeQuotasInterface) QuotasAndUsage() ([]QuotaUsage, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.calls++
+	return f.Quotas, f.Err
+}
+
+// DescribeQuotas implements the QuotasInterface
+func (f *FakeQuotasInterface) DescribeQuotas() []QuotaUsage {
+	return f.DescribedQuotas
+}
+
+// Calls returns the number of times QuotasAndUsage was called
+func (f *FakeQuotasInterface) Calls() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.calls
+}
+
+// UsageChecks is a pre-built set of usage checks for
+// NewServiceQuotasWithChecks
+type UsageChecks struct {
+	// ServiceQuotas are the checks of the applied quotas, by quota
+	// code
+	ServiceQuotas map[string]UsageCheck
+	// ServiceDefaults are the checks of the AWS default quotas, by
+	// quota code
+	ServiceDefaults map[string]UsageCheck
+	// Other are the checks of the quotas not listed by the service
+	// quotas API, their usages are returned as is
+	Other []UsageCheck
+}
+
+// NewServiceQuotasWithChecks creates a ServiceQuotas for `region` that
+// runs `checks` instead of the usage checks of the package, listing the
+// quotas with `quotasService`. This allows testing integrations and new
+// checks with fake AWS clients. Only the BestEffort, OnCheckError,
+// OnCheckDuration, CheckTimeout and StrictRegion options apply
+func NewServiceQuotasWithChecks(region string, quotasService servicequotasiface.ServiceQuotasAPI, checks UsageChecks, options Options) (QuotasInterface, error) {
+	validRegion, isChina := isValidRegion(region)
+	if !validRegion {
+		return nil, errors.Wrapf(ErrInvalidRegion, "failed to create ServiceQuotas")
+	}
+
+	serviceQuotasChecks := checks.ServiceQuotas
+	if serviceQuotasChecks == nil {
+		serviceQuotasChecks = map[string]UsageCheck{}
+	}
+	serviceDefaultChecks := checks.ServiceDefaults
+	if serviceDefaultChecks == nil {
+		serviceDefaultChecks = map[string]UsageCheck{}
+	}
+
+	return &ServiceQuotas{
+		region:                    region,
+		isAwsChina:                isChina,
+		quotasService:             quotasService,
+		serviceQuotasUsageChecks:  serviceQuotasChecks,
+		serviceDefaultUsageChecks: serviceDefaultChecks,
+		otherUsageChecks:          checks.Other,
+		clock:                     time.Now,
+		bestEffort:                options.BestEffort,
+		serviceRegions:            map[string]*ServiceQuotas{},
+		onCheckError:              options.OnCheckError,
+		onCheckDuration:           options.OnCheckDuration,
+		checkTimeout:              options.CheckTimeout,
+		strictRegion:              options.StrictRegion,
+	}, nil
+}
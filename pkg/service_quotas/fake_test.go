@@ -0,0 +1,175 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsservicequotas "github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewServiceQuotasWithChecks(t *testing.T) {
+	mockClient := &mockServiceQuotasClient{
+		serviceName: "ec2",
+		ListServiceQuotasResponse: &awsservicequotas.ListServiceQuotasOutput{
+			Quotas: []*awsservicequotas.ServiceQuota{
+				{QuotaCode: aws.String("L-1234"), Value: aws.Float64(15)},
+			},
+		},
+	}
+	quotaCheck := &FakeUsageCheck{Usages: []QuotaUsage{{Name: "some_quota", Usage: 5}}}
+	otherCheck := &FakeUsageCheck{Usages: []QuotaUsage{{Name: "other_quota", Usage: 2, Quota: 10}}}
+
+	serviceQuotas, err := NewServiceQuotasWithChecks("eu-west-1", mockClient, UsageChecks{
+		ServiceQuotas: map[string]UsageCheck{"L-1234": quotaCheck},
+		Other:         []UsageCheck{otherCheck},
+	}, Options{})
+	assert.NoError(t, err)
+
+	usages, err := serviceQuotas.QuotasAndUsage()
+
+	assert.NoError(t, err)
+	assert.Len(t, usages, 2)
+	assert.Equal(t, "some_quota", usages[0].Name)
+	assert.Equal(t, float64(15), usages[0].Quota)
+	assert.Equal(t, "eu-west-1", usages[0].Region)
+	assert.Equal(t, "other_quota", usages[1].Name)
+	assert.Equal(t, 1, quotaCheck.Calls())
+	assert.Equal(t, 1, otherCheck.Calls())
+}
+
+func TestNewServiceQuotasWithChecksBestEffort(t *testing.T) {
+	mockClient := &mockServiceQuotasClient{}
+	failingCheck := &FakeUsageCheck{Err: errors.Wrapf(ErrFailedToGetUsage, "some err")}
+	otherCheck := &FakeUsageCheck{Usages: []QuotaUsage{{Name: "other_quota", Usage: 2}}}
+
+	serviceQuotas, err := NewServiceQuotasWithChecks("eu-west-1", mockClient, UsageChecks{
+		Other: []UsageCheck{failingCheck, otherCheck},
+	}, Options{BestEffort: true})
+	assert.NoError(t, err)
+
+	usages, err := serviceQuotas.QuotasAndUsage()
+
+	assert.True(t, errors.Is(err, ErrPartialUsage))
+	assert.Len(t, usages, 1)
+	assert.Equal(t, "other_quota", usages[0].Name)
+}
+
+func TestNewServiceQuotasWithChecksBestEffortReportsCheckName(t *testing.T) {
+	failingCheck := &APIsPerRegionCheck{&mockAPIGatewayClient{}, &mockAPIGatewayV2Client{err: errors.New("some err")}, httpAPIType}
+
+	serviceQuotas, err := NewServiceQuotasWithChecks("eu-west-1", &mockServiceQuotasClient{}, UsageChecks{
+		Other: []UsageCheck{failingCheck},
+	}, Options{BestEffort: true})
+	assert.NoError(t, err)
+
+	_, err = serviceQuotas.QuotasAndUsage()
+
+	assert.True(t, errors.Is(err, ErrPartialUsage))
+	assert.Contains(t, err.Error(), "usage checks failed for APIsPerRegionCheck/http")
+}
+
+func TestNewServiceQuotasWithChecksWithInvalidRegion(t *testing.T) {
+	serviceQuotas, err := NewServiceQuotasWithChecks("no-region-1", &mockServiceQuotasClient{}, UsageChecks{}, Options{})
+
+	assert.True(t, errors.Is(err, ErrInvalidRegion))
+	assert.Nil(t, serviceQuotas)
+}
+
+func TestFakeQuotasInterface(t *testing.T) {
+	fake := &FakeQuotasInterface{
+		Quotas:          []QuotaUsage{{Name: "some_quota", Usage: 1}},
+		DescribedQuotas: []QuotaUsage{{Name: "some_quota"}},
+	}
+
+	usages, err := fake.QuotasAndUsage()
+
+	assert.NoError(t, err)
+	assert.Equal(t, fake.Quotas, usages)
+	assert.Equal(t, fake.DescribedQuotas, fake.DescribeQuotas())
+	assert.Equal(t, 1, fake.Calls())
+}
+
+type blockingUsageCheck struct {
+	release chan struct{}
+}
+
+func (c *blockingUsageCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	<-c.release
+	return []QuotaUsage{{Name: "slow_quota"}}, nil
+}
+
+func TestQuotasAndUsageWithContextReturnsPartialUsage(t *testing.T) {
+	slowCheck := &blockingUsageCheck{release: make(chan struct{})}
+	defer close(slowCheck.release)
+	nextCheck := &FakeUsageCheck{Usages: []QuotaUsage{{Name: "next_quota"}}}
+
+	serviceQuotas, err := NewServiceQuotasWithChecks("eu-west-1", &mockServiceQuotasClient{}, UsageChecks{
+		Other: []UsageCheck{
+			&FakeUsageCheck{Usages: []QuotaUsage{{Name: "fast_quota", Usage: 1}}},
+			slowCheck,
+			nextCheck,
+		},
+	}, Options{})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	usages, err := serviceQuotas.(ContextQuotasInterface).QuotasAndUsageWithContext(ctx)
+
+	assert.True(t, errors.Is(err, ErrPartialUsage))
+	assert.Len(t, usages, 1)
+	assert.Equal(t, "fast_quota", usages[0].Name)
+	assert.Equal(t, 0, nextCheck.Calls())
+}
+
+// contextUsageCheck returns the error of its context once it is done
+type contextUsageCheck struct {
+	returned chan struct{}
+}
+
+func (c *contextUsageCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	<-ctx.Done()
+	if c.returned != nil {
+		close(c.returned)
+	}
+	return nil, wrapUsageErr(ctx.Err())
+}
+
+func TestQuotasAndUsageWithCheckTimeout(t *testing.T) {
+	serviceQuotas, err := NewServiceQuotasWithChecks("eu-west-1", &mockServiceQuotasClient{}, UsageChecks{
+		Other: []UsageCheck{
+			&contextUsageCheck{},
+			&FakeUsageCheck{Usages: []QuotaUsage{{Name: "next_quota", Usage: 1}}},
+		},
+	}, Options{BestEffort: true, CheckTimeout: 10 * time.Millisecond})
+	assert.NoError(t, err)
+
+	usages, err := serviceQuotas.QuotasAndUsage()
+
+	assert.True(t, errors.Is(err, ErrPartialUsage))
+	assert.Len(t, usages, 1)
+	assert.Equal(t, "next_quota", usages[0].Name)
+}
+
+func TestQuotasAndUsageWithContextCancelsChecks(t *testing.T) {
+	check := &contextUsageCheck{returned: make(chan struct{})}
+	serviceQuotas, err := NewServiceQuotasWithChecks("eu-west-1", &mockServiceQuotasClient{}, UsageChecks{
+		Other: []UsageCheck{check},
+	}, Options{})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = serviceQuotas.(ContextQuotasInterface).QuotasAndUsageWithContext(ctx)
+	assert.True(t, errors.Is(err, ErrPartialUsage))
+
+	select {
+	case <-check.returned:
+	case <-time.After(time.Second):
+		t.Fatal("the check was not cancelled")
+	}
+}
@@ -0,0 +1,58 @@
+package servicequotas
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/firehose/firehoseiface"
+)
+
+const (
+	deliveryStreamsPerRegionName        = "firehose_delivery_streams_per_region"
+	deliveryStreamsPerRegionDescription = "Kinesis Data Firehose delivery streams per region"
+)
+
+// FirehoseDeliveryStreamsCheck implements the UsageCheck interface for
+// Kinesis Data Firehose delivery streams per region
+type FirehoseDeliveryStreamsCheck struct {
+	client firehoseiface.FirehoseAPI
+}
+
+// Usage returns the number of Firehose delivery streams or an error.
+// ListDeliveryStreams has no NextToken, the next page starts after the
+// last delivery stream of the previous page while
+// HasMoreDeliveryStreams is set
+func (c *FirehoseDeliveryStreamsCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var deliveryStreamsCount int
+
+	params := &firehose.ListDeliveryStreamsInput{}
+	for {
+		page, err := c.client.ListDeliveryStreamsWithContext(ctx, params)
+		if err != nil {
+			return nil, wrapUsageErr(err)
+		}
+
+		deliveryStreamsCount += len(page.DeliveryStreamNames)
+		if !aws.BoolValue(page.HasMoreDeliveryStreams) || len(page.DeliveryStreamNames) == 0 {
+			break
+		}
+		params = &firehose.ListDeliveryStreamsInput{
+			ExclusiveStartDeliveryStreamName: page.DeliveryStreamNames[len(page.DeliveryStreamNames)-1],
+		}
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        deliveryStreamsPerRegionName,
+			Description: deliveryStreamsPerRegionDescription,
+			Usage:       float64(deliveryStreamsCount),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *FirehoseDeliveryStreamsCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: deliveryStreamsPerRegionName, Description: deliveryStreamsPerRegionDescription}}
+}
@@ -0,0 +1,68 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockFirehoseClient) ListDeliveryStreamsWithContext(ctx aws.Context, input *firehose.ListDeliveryStreamsInput, opts ...request.Option) (*firehose.ListDeliveryStreamsOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	response := m.ListDeliveryStreamsResponses[len(m.ListDeliveryStreamsInputs)]
+	m.ListDeliveryStreamsInputs = append(m.ListDeliveryStreamsInputs, input)
+	return response, nil
+}
+
+func TestFirehoseDeliveryStreamsCheckWithError(t *testing.T) {
+	mockClient := &mockFirehoseClient{
+		err: errors.New("some err"),
+	}
+
+	check := FirehoseDeliveryStreamsCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestFirehoseDeliveryStreamsCheck(t *testing.T) {
+	mockClient := &mockFirehoseClient{
+		ListDeliveryStreamsResponses: []*firehose.ListDeliveryStreamsOutput{
+			{
+				DeliveryStreamNames:    []*string{aws.String("stream1"), aws.String("stream2")},
+				HasMoreDeliveryStreams: aws.Bool(true),
+			},
+			{
+				DeliveryStreamNames:    []*string{aws.String("stream3")},
+				HasMoreDeliveryStreams: aws.Bool(false),
+			},
+		},
+	}
+
+	check := FirehoseDeliveryStreamsCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        deliveryStreamsPerRegionName,
+			Description: deliveryStreamsPerRegionDescription,
+			Usage:       3,
+		},
+	}
+	expectedInputs := []*firehose.ListDeliveryStreamsInput{
+		{},
+		{ExclusiveStartDeliveryStreamName: aws.String("stream2")},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+	assert.Equal(t, expectedInputs, mockClient.ListDeliveryStreamsInputs)
+}
@@ -1,9 +1,13 @@
 package servicequotas
 
 import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/glue"
 	"github.com/aws/aws-sdk-go/service/glue/glueiface"
-	"github.com/pkg/errors"
 )
 
 const (
@@ -19,22 +23,52 @@ const (
 	dPUsName        = "dpus_per_account"
 	dPUsDescription = "DPUs per account"
 
+	activeDPUsName        = "active_dpus"
+	activeDPUsDescription = "DPUs of running glue job runs"
+
 	concurrentRunsName        = "concurrent_running_glue_jobs"
 	concurrentRunsDescription = "concurrent running glue jobs"
+
+	concurrentSessionsName        = "concurrent_glue_interactive_sessions"
+	concurrentSessionsDescription = "concurrent glue interactive sessions"
+
+	concurrentBlueprintRunsName        = "concurrent_glue_blueprint_runs"
+	concurrentBlueprintRunsDescription = "concurrent glue blueprint runs"
+
+	jobsPerSecurityConfigurationName        = "glue_jobs_per_security_configuration"
+	jobsPerSecurityConfigurationDescription = "glue jobs per security configuration"
+
+	concurrentCrawlerRunsName        = "concurrent_running_glue_crawlers"
+	concurrentCrawlerRunsDescription = "concurrent running glue crawlers"
 )
 
+// batchGetTriggersMaxNames is the maximum number of trigger names
+// accepted by a BatchGetTriggers call
+const batchGetTriggersMaxNames = 25
+
+// batchGetCrawlersMaxNames is the maximum number of crawler names
+// accepted by a BatchGetCrawlers call
+const batchGetCrawlersMaxNames = 100
+
+// JobsPerTriggerCheck implements the UsageCheck interface for glue
+// jobs per trigger
 type JobsPerTriggerCheck struct {
 	client glueiface.GlueAPI
 }
 
-func (c *JobsPerTriggerCheck) Usage() ([]QuotaUsage, error) {
+// Usage returns the number of actions for each glue trigger or an
+// error. Every action of a trigger counts towards the quota, whether it
+// starts a job or a crawler, as the quota limits the actions a trigger
+// can have. The quota of each trigger is attached by the service quotas
+// API, so the utilization of each trigger is exported
+func (c *JobsPerTriggerCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
 
 	// Need to list all the triggers then count the jobs for each trigger
 
 	var triggersList []*string
 	listParams := &glue.ListTriggersInput{}
-	listErr := c.client.ListTriggersPages(listParams,
+	listErr := c.client.ListTriggersPagesWithContext(ctx, listParams,
 		func(page *glue.ListTriggersOutput, lastPage bool) bool {
 			if page != nil {
 				triggersList = append(triggersList, page.TriggerNames...)
@@ -44,33 +78,35 @@ func (c *JobsPerTriggerCheck) Usage() ([]QuotaUsage, error) {
 	)
 	if listErr != nil {
 		log.Error("Failed to list Glue triggers")
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", listErr)
+		return nil, wrapUsageErr(listErr)
 	}
 	// do we actually have any triggers to get?
 	if len(triggersList) > 0 {
-		params := &glue.BatchGetTriggersInput{
-			TriggerNames: triggersList,
-		}
-		triggers, err := c.client.BatchGetTriggers(params)
-		if err != nil {
-			log.Error("Failed to batch get Glue triggers")
-			return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", listErr)
-		}
-		for _, trigger := range triggers.Triggers {
-			var jobsTriggered int
-			for _, action := range trigger.Actions {
-				if *action.JobName != "" {
-					jobsTriggered++
-				}
+		// BatchGetTriggers accepts up to batchGetTriggersMaxNames
+		// trigger names per call
+		for start := 0; start < len(triggersList); start += batchGetTriggersMaxNames {
+			end := start + batchGetTriggersMaxNames
+			if end > len(triggersList) {
+				end = len(triggersList)
+			}
+			params := &glue.BatchGetTriggersInput{
+				TriggerNames: triggersList[start:end],
 			}
-			usage := QuotaUsage{
-				Name:         jobsPerTriggerName,
-				Description:  jobsPerTriggerDescription,
-				ResourceName: trigger.Name,
-				Usage:        float64(jobsTriggered),
+			triggers, err := c.client.BatchGetTriggersWithContext(ctx, params)
+			if err != nil {
+				log.Error("Failed to batch get Glue triggers")
+				return nil, wrapUsageErr(err)
 			}
-			quotaUsages = append(quotaUsages, usage)
+			for _, trigger := range triggers.Triggers {
+				usage := QuotaUsage{
+					Name:         jobsPerTriggerName,
+					Description:  jobsPerTriggerDescription,
+					ResourceName: trigger.Name,
+					Usage:        float64(len(trigger.Actions)),
+				}
+				quotaUsages = append(quotaUsages, usage)
 
+			}
 		}
 
 		return quotaUsages, nil
@@ -79,17 +115,22 @@ func (c *JobsPerTriggerCheck) Usage() ([]QuotaUsage, error) {
 	return nil, nil
 }
 
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *JobsPerTriggerCheck) DescribeResourceUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: jobsPerTriggerName, Description: jobsPerTriggerDescription}}
+}
+
 type JobsPerAccountCheck struct {
 	client glueiface.GlueAPI
 }
 
-func (c *JobsPerAccountCheck) Usage() ([]QuotaUsage, error) {
+func (c *JobsPerAccountCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
 
 	var jobsCount int
 
 	params := &glue.ListJobsInput{}
-	err := c.client.ListJobsPages(params,
+	err := c.client.ListJobsPagesWithContext(ctx, params,
 		func(page *glue.ListJobsOutput, lastPage bool) bool {
 			if page != nil {
 				jobsCount += len(page.JobNames)
@@ -98,7 +139,7 @@ func (c *JobsPerAccountCheck) Usage() ([]QuotaUsage, error) {
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 	usage := QuotaUsage{
 		Name:        jobsName,
@@ -110,15 +151,20 @@ func (c *JobsPerAccountCheck) Usage() ([]QuotaUsage, error) {
 	return quotaUsages, nil
 }
 
+// DescribeUsage implements the UsageDescriber interface
+func (c *JobsPerAccountCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: jobsName, Description: jobsDescription}}
+}
+
 type ConcurrentRunsPerJobCheck struct {
 	client glueiface.GlueAPI
 }
 
-func (c *ConcurrentRunsPerJobCheck) Usage() ([]QuotaUsage, error) {
+func (c *ConcurrentRunsPerJobCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
 
 	params := &glue.GetJobsInput{}
-	err := c.client.GetJobsPages(params,
+	err := c.client.GetJobsPagesWithContext(ctx, params,
 		func(page *glue.GetJobsOutput, lastPage bool) bool {
 			if page != nil {
 				for _, job := range page.Jobs {
@@ -126,7 +172,7 @@ func (c *ConcurrentRunsPerJobCheck) Usage() ([]QuotaUsage, error) {
 						Name:         concurrentRunsPerJobName,
 						Description:  concurrentRunsPerJobDescription,
 						ResourceName: job.Name,
-						Usage:        float64(*job.ExecutionProperty.MaxConcurrentRuns),
+						Usage:        float64(jobMaxConcurrentRuns(job)),
 					}
 					quotaUsages = append(quotaUsages, usage)
 				}
@@ -135,90 +181,532 @@ func (c *ConcurrentRunsPerJobCheck) Usage() ([]QuotaUsage, error) {
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 
 	return quotaUsages, nil
 }
 
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *ConcurrentRunsPerJobCheck) DescribeResourceUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: concurrentRunsPerJobName, Description: concurrentRunsPerJobDescription}}
+}
+
 type DPUsCheck struct {
 	client glueiface.GlueAPI
 }
 
-func (c *DPUsCheck) Usage() ([]QuotaUsage, error) {
+func (c *DPUsCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
 
-	var dPUsCount int
+	var dPUsCount float64
 
 	params := &glue.GetJobsInput{}
-	err := c.client.GetJobsPages(params,
+	err := c.client.GetJobsPagesWithContext(ctx, params,
 		func(page *glue.GetJobsOutput, lastPage bool) bool {
 			if page != nil {
 				for _, job := range page.Jobs {
-					dPUsCount += int(*job.MaxCapacity)
+					dPUsCount += jobDPUs(job)
 				}
 			}
 			return !lastPage
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 	usage := QuotaUsage{
 		Name:        dPUsName,
 		Description: dPUsDescription,
-		Usage:       float64(dPUsCount),
+		Usage:       dPUsCount,
 	}
 	quotaUsages = append(quotaUsages, usage)
 
 	return quotaUsages, nil
 }
 
+// DescribeUsage implements the UsageDescriber interface
+func (c *DPUsCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: dPUsName, Description: dPUsDescription}}
+}
+
+// defaultMaxConcurrentRuns is the maximum number of concurrent runs of
+// the glue jobs that do not set it
+const defaultMaxConcurrentRuns = 1
+
+// jobMaxConcurrentRuns returns the maximum number of concurrent runs
+// of `job`
+func jobMaxConcurrentRuns(job *glue.Job) int64 {
+	if job.ExecutionProperty == nil || job.ExecutionProperty.MaxConcurrentRuns == nil {
+		return defaultMaxConcurrentRuns
+	}
+	return *job.ExecutionProperty.MaxConcurrentRuns
+}
+
+// workerTypeDPUs is the number of DPUs of each glue worker type
+var workerTypeDPUs = map[string]float64{
+	glue.WorkerTypeStandard: 1,
+	glue.WorkerTypeG1x:      1,
+	glue.WorkerTypeG2x:      2,
+	glue.WorkerTypeG025x:    0.25,
+	"G.4X":                  4,
+	"G.8X":                  8,
+}
+
+// jobDPUs returns the DPUs allocated to `job`. Glue 2.0 and later jobs
+// configured with a worker type and a number of workers have no
+// maximum capacity, their DPUs are computed from their workers instead.
+// Jobs with an unknown worker type are counted as 0 DPUs with a warning
+func jobDPUs(job *glue.Job) float64 {
+	dPUs, ok := capacityDPUs(job.MaxCapacity, job.WorkerType, job.NumberOfWorkers)
+	if !ok {
+		log.Warnf("Failed to compute the DPUs of glue job %s with worker type %q", aws.StringValue(job.Name), aws.StringValue(job.WorkerType))
+	}
+	return dPUs
+}
+
+// jobRunDPUs returns the DPUs allocated to the job run `run`, see
+// jobDPUs
+func jobRunDPUs(run *glue.JobRun) float64 {
+	dPUs, ok := capacityDPUs(run.MaxCapacity, run.WorkerType, run.NumberOfWorkers)
+	if !ok {
+		log.Warnf("Failed to compute the DPUs of run %s of glue job %s with worker type %q", aws.StringValue(run.Id), aws.StringValue(run.JobName), aws.StringValue(run.WorkerType))
+	}
+	return dPUs
+}
+
+// capacityDPUs returns the DPUs of a maximum capacity or of a number of
+// workers of a worker type, and whether they could be computed
+func capacityDPUs(maxCapacity *float64, workerType *string, numberOfWorkers *int64) (float64, bool) {
+	if maxCapacity != nil {
+		return *maxCapacity, true
+	}
+
+	dPUsPerWorker, ok := workerTypeDPUs[aws.StringValue(workerType)]
+	if !ok || numberOfWorkers == nil {
+		return 0, false
+	}
+	return dPUsPerWorker * float64(*numberOfWorkers), true
+}
+
+// jobRunsPageSize is the number of job runs requested per page when
+// looking for running glue job runs
+const jobRunsPageSize = 25
+
+// ConcurrentRunsCheck implements the UsageCheck interface for
+// concurrent running glue jobs per account
 type ConcurrentRunsCheck struct {
 	client glueiface.GlueAPI
+	// jobRuns is shared with ActiveDPUsCheck
+	jobRuns *jobRunsCache
 }
 
-func (c *ConcurrentRunsCheck) Usage() ([]QuotaUsage, error) {
+// Usage returns the number of running job runs across all glue jobs
+// or an error
+func (c *ConcurrentRunsCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
 
 	var concurrentJobsCount int
+	err := c.jobRuns.forEach(ctx, c.client, func(run *glue.JobRun) {
+		concurrentJobsCount++
+	})
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := QuotaUsage{
+		Name:        concurrentRunsName,
+		Description: concurrentRunsDescription,
+		Usage:       float64(concurrentJobsCount),
+	}
+	quotaUsages = append(quotaUsages, usage)
+
+	return quotaUsages, nil
+
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *ConcurrentRunsCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: concurrentRunsName, Description: concurrentRunsDescription}}
+}
+
+// jobRunsCache holds the running glue job runs listed during a
+// refresh, so that the concurrent runs and the active DPUs checks only
+// go through the jobs and their runs once per refresh. ServiceQuotas
+// resets it at the start of each refresh. A nil cache lists the job
+// runs on every call
+type jobRunsCache struct {
+	mutex sync.Mutex
+	// runs holds the running job runs, it is nil until they are listed
+	runs []*glue.JobRun
+}
+
+// reset empties the cache
+func (c *jobRunsCache) reset() {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.runs = nil
+}
+
+// forEach calls `fn` with each running job run across all glue jobs,
+// listing them with `client` if they are not cached, or returns an
+// error. The job runs are only cached when they were all listed
+func (c *jobRunsCache) forEach(ctx context.Context, client glueiface.GlueAPI, fn func(run *glue.JobRun)) error {
+	if c == nil {
+		return forEachRunningJobRun(ctx, client, fn)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.runs == nil {
+		runs := []*glue.JobRun{}
+		err := forEachRunningJobRun(ctx, client, func(run *glue.JobRun) {
+			runs = append(runs, run)
+		})
+		if err != nil {
+			return err
+		}
+		c.runs = runs
+	}
+	for _, run := range c.runs {
+		fn(run)
+	}
+	return nil
+}
+
+// forEachRunningJobRun calls `fn` with each running job run across all
+// glue jobs
+func forEachRunningJobRun(ctx context.Context, client glueiface.GlueAPI, fn func(run *glue.JobRun)) error {
+	var runsErr error
 
 	listParams := &glue.ListJobsInput{}
-	listErr := c.client.ListJobsPages(listParams,
+	err := client.ListJobsPagesWithContext(ctx, listParams,
 		func(page *glue.ListJobsOutput, lastPage bool) bool {
 			if page != nil {
 				for _, job := range page.JobNames {
-					params := &glue.GetJobRunsInput{JobName: job}
-					err := c.client.GetJobRunsPages(params,
-						func(page *glue.GetJobRunsOutput, lastPage bool) bool {
-							if page != nil {
-								for _, run := range page.JobRuns {
-									if *run.JobRunState == glue.JobRunStateRunning {
-										concurrentJobsCount++
-									}
-								}
-							}
-							return !lastPage
-						},
-					)
-					if err != nil {
-						panic(err)
+					if err := forEachRunningRunOfJob(ctx, client, job, fn); err != nil {
+						runsErr = err
+						// stop paging when an error is encountered
+						return false
 					}
 				}
 			}
 			return !lastPage
 		},
 	)
-	if listErr != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", listErr)
+	if err != nil {
+		return err
 	}
-	usage := QuotaUsage{
-		Name:        concurrentRunsName,
-		Description: concurrentRunsDescription,
-		Usage:       float64(concurrentJobsCount),
+	return runsErr
+}
+
+// forEachRunningRunOfJob calls `fn` with each running job run of the
+// job `jobName`
+// GetJobRuns returns the most recent job runs first, so paging stops
+// at the first page without running job runs instead of going through
+// the whole run history of the job
+func forEachRunningRunOfJob(ctx context.Context, client glueiface.GlueAPI, jobName *string, fn func(run *glue.JobRun)) error {
+	params := &glue.GetJobRunsInput{
+		JobName:    jobName,
+		MaxResults: aws.Int64(jobRunsPageSize),
+	}
+	return client.GetJobRunsPagesWithContext(ctx, params,
+		func(page *glue.GetJobRunsOutput, lastPage bool) bool {
+			if page == nil {
+				return !lastPage
+			}
+
+			var pageRunningCount int
+			for _, run := range page.JobRuns {
+				if aws.StringValue(run.JobRunState) == glue.JobRunStateRunning {
+					pageRunningCount++
+					fn(run)
+				}
+			}
+
+			return pageRunningCount > 0 && !lastPage
+		},
+	)
+}
+
+// ActiveDPUsCheck implements the UsageCheck interface for the DPUs in
+// use by the running glue job runs
+type ActiveDPUsCheck struct {
+	client glueiface.GlueAPI
+	// jobRuns is shared with ConcurrentRunsCheck
+	jobRuns *jobRunsCache
+}
+
+// Usage returns the DPUs allocated to the running job runs across all
+// glue jobs or an error. Unlike the DPUs per account, which are
+// configured on the jobs, they are the DPUs of the runs, which can
+// override the capacity of their job
+func (c *ActiveDPUsCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var activeDPUs float64
+	err := c.jobRuns.forEach(ctx, c.client, func(run *glue.JobRun) {
+		activeDPUs += jobRunDPUs(run)
+	})
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        activeDPUsName,
+			Description: activeDPUsDescription,
+			Usage:       activeDPUs,
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *ActiveDPUsCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: activeDPUsName, Description: activeDPUsDescription}}
+}
+
+// ConcurrentSessionsCheck implements the UsageCheck interface for
+// concurrent glue interactive sessions per account
+type ConcurrentSessionsCheck struct {
+	client glueiface.GlueAPI
+}
+
+// Usage returns the number of provisioning and ready interactive
+// sessions or an error. Idle sessions waiting for their idle timeout
+// are ready and count against the quota, while failed, timed out and
+// stopped sessions do not
+func (c *ConcurrentSessionsCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var concurrentSessionsCount int
+
+	params := &glue.ListSessionsInput{}
+	err := c.client.ListSessionsPagesWithContext(ctx, params,
+		func(page *glue.ListSessionsOutput, lastPage bool) bool {
+			if page != nil {
+				for _, session := range page.Sessions {
+					status := aws.StringValue(session.Status)
+					if status == glue.SessionStatusProvisioning || status == glue.SessionStatusReady {
+						concurrentSessionsCount++
+					}
+				}
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        concurrentSessionsName,
+			Description: concurrentSessionsDescription,
+			Usage:       float64(concurrentSessionsCount),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *ConcurrentSessionsCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: concurrentSessionsName, Description: concurrentSessionsDescription}}
+}
+
+// ConcurrentBlueprintRunsCheck implements the UsageCheck interface for
+// concurrent glue blueprint runs per account
+type ConcurrentBlueprintRunsCheck struct {
+	client glueiface.GlueAPI
+}
+
+// Usage returns the number of blueprint runs in progress across all
+// glue blueprints or an error. Runs rolling back after a failure are
+// still in progress and count against the quota, while succeeded and
+// failed runs do not
+func (c *ConcurrentBlueprintRunsCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var blueprints []*string
+	listParams := &glue.ListBlueprintsInput{}
+	err := c.client.ListBlueprintsPagesWithContext(ctx, listParams,
+		func(page *glue.ListBlueprintsOutput, lastPage bool) bool {
+			if page != nil {
+				blueprints = append(blueprints, page.Blueprints...)
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	var concurrentRunsCount int
+	for _, blueprint := range blueprints {
+		params := &glue.GetBlueprintRunsInput{BlueprintName: blueprint}
+		err := c.client.GetBlueprintRunsPagesWithContext(ctx, params,
+			func(page *glue.GetBlueprintRunsOutput, lastPage bool) bool {
+				if page != nil {
+					for _, run := range page.BlueprintRuns {
+						state := aws.StringValue(run.State)
+						if state == glue.BlueprintRunStateRunning || state == glue.BlueprintRunStateRollingBack {
+							concurrentRunsCount++
+						}
+					}
+				}
+				return !lastPage
+			},
+		)
+		if err != nil {
+			return nil, wrapUsageErr(err)
+		}
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        concurrentBlueprintRunsName,
+			Description: concurrentBlueprintRunsDescription,
+			Usage:       float64(concurrentRunsCount),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *ConcurrentBlueprintRunsCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: concurrentBlueprintRunsName, Description: concurrentBlueprintRunsDescription}}
+}
+
+// JobsPerSecurityConfigurationCheck implements the UsageCheck interface
+// for glue jobs per security configuration. There is no quota per
+// security configuration, the configurations without jobs count
+// against the security configurations per account quota and can be
+// deleted to stay under it
+type JobsPerSecurityConfigurationCheck struct {
+	client glueiface.GlueAPI
+}
+
+// Usage returns the number of jobs referencing each security
+// configuration, including the configurations without jobs, or an
+// error
+func (c *JobsPerSecurityConfigurationCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	jobsCounts := map[string]int{}
+	params := &glue.GetSecurityConfigurationsInput{}
+	err := c.client.GetSecurityConfigurationsPagesWithContext(ctx, params,
+		func(page *glue.GetSecurityConfigurationsOutput, lastPage bool) bool {
+			if page != nil {
+				for _, configuration := range page.SecurityConfigurations {
+					jobsCounts[aws.StringValue(configuration.Name)] = 0
+				}
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+	if len(jobsCounts) == 0 {
+		return []QuotaUsage{}, nil
 	}
-	quotaUsages = append(quotaUsages, usage)
 
+	err = c.client.GetJobsPagesWithContext(ctx, &glue.GetJobsInput{},
+		func(page *glue.GetJobsOutput, lastPage bool) bool {
+			if page != nil {
+				for _, job := range page.Jobs {
+					name := aws.StringValue(job.SecurityConfiguration)
+					if _, ok := jobsCounts[name]; ok {
+						jobsCounts[name]++
+					}
+				}
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	names := make([]string, 0, len(jobsCounts))
+	for name := range jobsCounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	quotaUsages := []QuotaUsage{}
+	for _, name := range names {
+		usage := QuotaUsage{
+			Name:         jobsPerSecurityConfigurationName,
+			Description:  jobsPerSecurityConfigurationDescription,
+			ResourceName: aws.String(name),
+			Usage:        float64(jobsCounts[name]),
+		}
+		quotaUsages = append(quotaUsages, usage)
+	}
 	return quotaUsages, nil
+}
+
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *JobsPerSecurityConfigurationCheck) DescribeResourceUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: jobsPerSecurityConfigurationName, Description: jobsPerSecurityConfigurationDescription}}
+}
+
+// ConcurrentCrawlerRunsCheck implements the UsageCheck interface for
+// concurrent running glue crawlers per account
+type ConcurrentCrawlerRunsCheck struct {
+	client glueiface.GlueAPI
+}
+
+// Usage returns the number of running glue crawlers or an error. The
+// stopping crawlers are not counted as they are about to be ready
+func (c *ConcurrentCrawlerRunsCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var crawlerNames []*string
+	err := c.client.ListCrawlersPagesWithContext(ctx, &glue.ListCrawlersInput{},
+		func(page *glue.ListCrawlersOutput, lastPage bool) bool {
+			if page != nil {
+				crawlerNames = append(crawlerNames, page.CrawlerNames...)
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		log.Error("Failed to list Glue crawlers")
+		return nil, wrapUsageErr(err)
+	}
+
+	var runningCrawlers int
+	// BatchGetCrawlers accepts up to batchGetCrawlersMaxNames crawler
+	// names per call
+	for start := 0; start < len(crawlerNames); start += batchGetCrawlersMaxNames {
+		end := start + batchGetCrawlersMaxNames
+		if end > len(crawlerNames) {
+			end = len(crawlerNames)
+		}
+		params := &glue.BatchGetCrawlersInput{
+			CrawlerNames: crawlerNames[start:end],
+		}
+		crawlers, err := c.client.BatchGetCrawlersWithContext(ctx, params)
+		if err != nil {
+			log.Error("Failed to batch get Glue crawlers")
+			return nil, wrapUsageErr(err)
+		}
+		for _, crawler := range crawlers.Crawlers {
+			if aws.StringValue(crawler.State) == glue.CrawlerStateRunning {
+				runningCrawlers++
+			}
+		}
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        concurrentCrawlerRunsName,
+			Description: concurrentCrawlerRunsDescription,
+			Usage:       float64(runningCrawlers),
+		},
+	}
+	return usage, nil
+}
 
+// DescribeUsage implements the UsageDescriber interface
+func (c *ConcurrentCrawlerRunsCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: concurrentCrawlerRunsName, Description: concurrentCrawlerRunsDescription}}
 }
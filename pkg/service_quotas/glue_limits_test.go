@@ -0,0 +1,733 @@
+package servicequotas
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/glue"
+	awsservicequotas "github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockGlueClient) ListJobsPagesWithContext(ctx aws.Context, input *glue.ListJobsInput, fn func(*glue.ListJobsOutput, bool) bool, opts ...request.Option) error {
+	fn(m.ListJobsResponse, true)
+	return m.err
+}
+
+func (m *mockGlueClient) GetJobRunsPagesWithContext(ctx aws.Context, input *glue.GetJobRunsInput, fn func(*glue.GetJobRunsOutput, bool) bool, opts ...request.Option) error {
+	m.GetJobRunsMaxResults = input.MaxResults
+	if m.GetJobRunsPagesRead == nil {
+		m.GetJobRunsPagesRead = map[string]int{}
+	}
+
+	pages := m.GetJobRunsResponses[*input.JobName]
+	for i, page := range pages {
+		m.GetJobRunsPagesRead[*input.JobName]++
+		if !fn(page, i == len(pages)-1) {
+			break
+		}
+	}
+	if m.GetJobRunsErr != nil {
+		return m.GetJobRunsErr
+	}
+	return m.err
+}
+
+func (m *mockGlueClient) GetJobsPagesWithContext(ctx aws.Context, input *glue.GetJobsInput, fn func(*glue.GetJobsOutput, bool) bool, opts ...request.Option) error {
+	fn(m.GetJobsResponse, true)
+	return m.err
+}
+
+func (m *mockGlueClient) ListTriggersPagesWithContext(ctx aws.Context, input *glue.ListTriggersInput, fn func(*glue.ListTriggersOutput, bool) bool, opts ...request.Option) error {
+	fn(m.ListTriggersResponse, true)
+	return m.err
+}
+
+func (m *mockGlueClient) BatchGetTriggersWithContext(ctx aws.Context, input *glue.BatchGetTriggersInput, opts ...request.Option) (*glue.BatchGetTriggersOutput, error) {
+	m.BatchGetTriggersInputs = append(m.BatchGetTriggersInputs, input)
+	if m.BatchGetTriggersResponse != nil || m.err != nil {
+		return m.BatchGetTriggersResponse, m.err
+	}
+
+	output := &glue.BatchGetTriggersOutput{}
+	for _, name := range input.TriggerNames {
+		output.Triggers = append(output.Triggers, m.Triggers[*name])
+	}
+	return output, nil
+}
+
+func (m *mockGlueClient) ListCrawlersPagesWithContext(ctx aws.Context, input *glue.ListCrawlersInput, fn func(*glue.ListCrawlersOutput, bool) bool, opts ...request.Option) error {
+	fn(m.ListCrawlersResponse, true)
+	return m.err
+}
+
+func (m *mockGlueClient) BatchGetCrawlersWithContext(ctx aws.Context, input *glue.BatchGetCrawlersInput, opts ...request.Option) (*glue.BatchGetCrawlersOutput, error) {
+	m.BatchGetCrawlersInputs = append(m.BatchGetCrawlersInputs, input)
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	output := &glue.BatchGetCrawlersOutput{}
+	for _, name := range input.CrawlerNames {
+		output.Crawlers = append(output.Crawlers, &glue.Crawler{
+			Name:  name,
+			State: aws.String(m.CrawlerStates[*name]),
+		})
+	}
+	return output, nil
+}
+
+func (m *mockGlueClient) ListSessionsPagesWithContext(ctx aws.Context, input *glue.ListSessionsInput, fn func(*glue.ListSessionsOutput, bool) bool, opts ...request.Option) error {
+	fn(m.ListSessionsResponse, true)
+	return m.err
+}
+
+func (m *mockGlueClient) ListBlueprintsPagesWithContext(ctx aws.Context, input *glue.ListBlueprintsInput, fn func(*glue.ListBlueprintsOutput, bool) bool, opts ...request.Option) error {
+	fn(m.ListBlueprintsResponse, true)
+	return m.err
+}
+
+func (m *mockGlueClient) GetBlueprintRunsPagesWithContext(ctx aws.Context, input *glue.GetBlueprintRunsInput, fn func(*glue.GetBlueprintRunsOutput, bool) bool, opts ...request.Option) error {
+	fn(m.GetBlueprintRunsResponses[*input.BlueprintName], true)
+	return m.err
+}
+
+func (m *mockGlueClient) GetSecurityConfigurationsPagesWithContext(ctx aws.Context, input *glue.GetSecurityConfigurationsInput, fn func(*glue.GetSecurityConfigurationsOutput, bool) bool, opts ...request.Option) error {
+	fn(m.GetSecurityConfigurationsResponse, true)
+	return m.err
+}
+
+func jobRuns(states ...string) *glue.GetJobRunsOutput {
+	runs := []*glue.JobRun{}
+	for _, state := range states {
+		runs = append(runs, &glue.JobRun{JobRunState: aws.String(state)})
+	}
+	return &glue.GetJobRunsOutput{JobRuns: runs}
+}
+
+func TestConcurrentRunsCheckWithError(t *testing.T) {
+	mockClient := &mockGlueClient{
+		err:              errors.New("some err"),
+		ListJobsResponse: nil,
+	}
+
+	check := ConcurrentRunsCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestConcurrentRunsCheck(t *testing.T) {
+	mockClient := &mockGlueClient{
+		ListJobsResponse: &glue.ListJobsOutput{
+			JobNames: []*string{aws.String("job1"), aws.String("job2"), aws.String("job3")},
+		},
+		GetJobRunsResponses: map[string][]*glue.GetJobRunsOutput{
+			"job1": {
+				jobRuns(glue.JobRunStateRunning, glue.JobRunStateRunning),
+				jobRuns(glue.JobRunStateRunning, glue.JobRunStateSucceeded),
+				jobRuns(glue.JobRunStateSucceeded, glue.JobRunStateFailed),
+				jobRuns(glue.JobRunStateSucceeded),
+			},
+			"job2": {
+				jobRuns(glue.JobRunStateSucceeded, glue.JobRunStateTimeout),
+				jobRuns(glue.JobRunStateSucceeded),
+			},
+			"job3": {
+				jobRuns(glue.JobRunStateRunning),
+			},
+		},
+	}
+
+	check := ConcurrentRunsCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        concurrentRunsName,
+			Description: concurrentRunsDescription,
+			Usage:       4,
+		},
+	}
+	expectedPagesRead := map[string]int{
+		"job1": 3,
+		"job2": 1,
+		"job3": 1,
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+	assert.Equal(t, expectedPagesRead, mockClient.GetJobRunsPagesRead)
+	assert.Equal(t, aws.Int64(jobRunsPageSize), mockClient.GetJobRunsMaxResults)
+}
+
+func TestConcurrentRunsCheckWithJobRunsError(t *testing.T) {
+	mockClient := &mockGlueClient{
+		ListJobsResponse: &glue.ListJobsOutput{
+			JobNames: []*string{aws.String("job1"), aws.String("job2")},
+		},
+		GetJobRunsErr: errors.New("some err"),
+	}
+
+	check := ConcurrentRunsCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestConcurrentRunsCheckWithoutJobRunState(t *testing.T) {
+	mockClient := &mockGlueClient{
+		ListJobsResponse: &glue.ListJobsOutput{
+			JobNames: []*string{aws.String("job1"), aws.String("job2")},
+		},
+		GetJobRunsResponses: map[string][]*glue.GetJobRunsOutput{
+			"job1": {{JobRuns: []*glue.JobRun{{}, {JobRunState: aws.String(glue.JobRunStateRunning)}}}},
+			"job2": {jobRuns(glue.JobRunStateRunning, glue.JobRunStateRunning)},
+		},
+	}
+
+	check := ConcurrentRunsCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(3), usage[0].Usage)
+}
+
+func TestActiveDPUsCheckWithError(t *testing.T) {
+	mockClient := &mockGlueClient{
+		err: errors.New("some err"),
+	}
+
+	check := ActiveDPUsCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestActiveDPUsCheck(t *testing.T) {
+	mockClient := &mockGlueClient{
+		ListJobsResponse: &glue.ListJobsOutput{
+			JobNames: []*string{aws.String("job1"), aws.String("job2")},
+		},
+		GetJobRunsResponses: map[string][]*glue.GetJobRunsOutput{
+			"job1": {
+				{JobRuns: []*glue.JobRun{
+					{JobRunState: aws.String(glue.JobRunStateRunning), MaxCapacity: aws.Float64(10)},
+					{JobRunState: aws.String(glue.JobRunStateRunning), WorkerType: aws.String(glue.WorkerTypeG2x), NumberOfWorkers: aws.Int64(5)},
+					{JobRunState: aws.String(glue.JobRunStateSucceeded), MaxCapacity: aws.Float64(100)},
+				}},
+			},
+			"job2": {
+				{JobRuns: []*glue.JobRun{
+					{JobRunState: aws.String(glue.JobRunStateRunning), WorkerType: aws.String(glue.WorkerTypeG025x), NumberOfWorkers: aws.Int64(2)},
+					{JobRunState: aws.String(glue.JobRunStateRunning), WorkerType: aws.String("unknown"), NumberOfWorkers: aws.Int64(2)},
+				}},
+			},
+		},
+	}
+
+	check := ActiveDPUsCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        activeDPUsName,
+			Description: activeDPUsDescription,
+			Usage:       20.5,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestActiveDPUsCheckWithJobRunsError(t *testing.T) {
+	mockClient := &mockGlueClient{
+		ListJobsResponse: &glue.ListJobsOutput{
+			JobNames: []*string{aws.String("job1")},
+		},
+		GetJobRunsErr: errors.New("some err"),
+	}
+
+	check := ActiveDPUsCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestJobRunsCacheSharedByChecks(t *testing.T) {
+	mockClient := &mockGlueClient{
+		ListJobsResponse: &glue.ListJobsOutput{
+			JobNames: []*string{aws.String("job1")},
+		},
+		GetJobRunsResponses: map[string][]*glue.GetJobRunsOutput{
+			"job1": {
+				{JobRuns: []*glue.JobRun{
+					{JobRunState: aws.String(glue.JobRunStateRunning), MaxCapacity: aws.Float64(10)},
+					{JobRunState: aws.String(glue.JobRunStateRunning), MaxCapacity: aws.Float64(5)},
+				}},
+			},
+		},
+	}
+	cache := &jobRunsCache{}
+	concurrentRunsCheck := ConcurrentRunsCheck{mockClient, cache}
+	activeDPUsCheck := ActiveDPUsCheck{mockClient, cache}
+
+	concurrentRuns, err := concurrentRunsCheck.Usage(context.Background())
+	assert.NoError(t, err)
+	activeDPUs, err := activeDPUsCheck.Usage(context.Background())
+	assert.NoError(t, err)
+
+	assert.Equal(t, float64(2), concurrentRuns[0].Usage)
+	assert.Equal(t, float64(15), activeDPUs[0].Usage)
+	// the job runs are only listed once for both checks
+	assert.Equal(t, map[string]int{"job1": 1}, mockClient.GetJobRunsPagesRead)
+
+	// they are listed again after a reset, at the next refresh
+	cache.reset()
+	_, err = activeDPUsCheck.Usage(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"job1": 2}, mockClient.GetJobRunsPagesRead)
+}
+
+func TestJobRunsCacheWithError(t *testing.T) {
+	mockClient := &mockGlueClient{
+		ListJobsResponse: &glue.ListJobsOutput{
+			JobNames: []*string{aws.String("job1")},
+		},
+		GetJobRunsErr: errors.New("some err"),
+	}
+	cache := &jobRunsCache{}
+
+	_, err := (&ConcurrentRunsCheck{mockClient, cache}).Usage(context.Background())
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+
+	// the failed listing is not cached
+	mockClient.GetJobRunsErr = nil
+	mockClient.GetJobRunsResponses = map[string][]*glue.GetJobRunsOutput{"job1": {jobRuns(glue.JobRunStateRunning)}}
+	usage, err := (&ActiveDPUsCheck{mockClient, cache}).Usage(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, usage, 1)
+}
+
+func TestJobsPerTriggerCheckWithError(t *testing.T) {
+	mockClient := &mockGlueClient{
+		err:                  errors.New("some err"),
+		ListTriggersResponse: nil,
+	}
+
+	check := JobsPerTriggerCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestJobsPerTriggerCheck(t *testing.T) {
+	mockClient := &mockGlueClient{
+		ListTriggersResponse: &glue.ListTriggersOutput{
+			TriggerNames: []*string{aws.String("trigger1"), aws.String("trigger2")},
+		},
+		BatchGetTriggersResponse: &glue.BatchGetTriggersOutput{
+			Triggers: []*glue.Trigger{
+				{
+					Name: aws.String("trigger1"),
+					Actions: []*glue.Action{
+						{JobName: aws.String("job1")},
+						{CrawlerName: aws.String("crawler1")},
+						{JobName: aws.String("")},
+						{},
+					},
+				},
+				{
+					Name:    aws.String("trigger2"),
+					Actions: []*glue.Action{},
+				},
+			},
+		},
+	}
+
+	check := JobsPerTriggerCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:         jobsPerTriggerName,
+			Description:  jobsPerTriggerDescription,
+			ResourceName: aws.String("trigger1"),
+			Usage:        4,
+		},
+		{
+			Name:         jobsPerTriggerName,
+			Description:  jobsPerTriggerDescription,
+			ResourceName: aws.String("trigger2"),
+			Usage:        0,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestJobsPerTriggerCheckBatchesTriggerNames(t *testing.T) {
+	mockClient := &mockGlueClient{
+		ListTriggersResponse: &glue.ListTriggersOutput{},
+		Triggers:             map[string]*glue.Trigger{},
+	}
+	for i := 0; i < 60; i++ {
+		name := fmt.Sprintf("trigger%d", i)
+		mockClient.ListTriggersResponse.TriggerNames = append(mockClient.ListTriggersResponse.TriggerNames, aws.String(name))
+		mockClient.Triggers[name] = &glue.Trigger{
+			Name:    aws.String(name),
+			Actions: []*glue.Action{{JobName: aws.String("job")}},
+		}
+	}
+
+	check := JobsPerTriggerCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, usage, 60)
+	assert.Len(t, mockClient.BatchGetTriggersInputs, 3)
+	assert.Len(t, mockClient.BatchGetTriggersInputs[0].TriggerNames, 25)
+	assert.Len(t, mockClient.BatchGetTriggersInputs[1].TriggerNames, 25)
+	assert.Len(t, mockClient.BatchGetTriggersInputs[2].TriggerNames, 10)
+	for i, quotaUsage := range usage {
+		assert.Equal(t, fmt.Sprintf("trigger%d", i), *quotaUsage.ResourceName)
+		assert.Equal(t, float64(1), quotaUsage.Usage)
+	}
+}
+
+func TestJobsPerTriggerCheckNearLimit(t *testing.T) {
+	actions := []*glue.Action{}
+	for i := 0; i < 49; i++ {
+		if i%2 == 0 {
+			actions = append(actions, &glue.Action{JobName: aws.String(fmt.Sprintf("job%d", i))})
+		} else {
+			actions = append(actions, &glue.Action{CrawlerName: aws.String(fmt.Sprintf("crawler%d", i))})
+		}
+	}
+	glueClient := &mockGlueClient{
+		ListTriggersResponse: &glue.ListTriggersOutput{
+			TriggerNames: []*string{aws.String("trigger1")},
+		},
+		BatchGetTriggersResponse: &glue.BatchGetTriggersOutput{
+			Triggers: []*glue.Trigger{{Name: aws.String("trigger1"), Actions: actions}},
+		},
+	}
+	quotasClient := &mockServiceQuotasClient{
+		serviceName: "glue",
+		ListServiceQuotasResponse: &awsservicequotas.ListServiceQuotasOutput{
+			Quotas: []*awsservicequotas.ServiceQuota{
+				{QuotaCode: aws.String("L-EEC98450"), Value: aws.Float64(50)},
+			},
+		},
+	}
+
+	serviceQuotas := ServiceQuotas{
+		quotasService:            quotasClient,
+		serviceQuotasUsageChecks: map[string]UsageCheck{"L-EEC98450": &JobsPerTriggerCheck{glueClient}},
+	}
+	usage, err := serviceQuotas.QuotasAndUsage()
+
+	assert.NoError(t, err)
+	assert.Len(t, usage, 1)
+	assert.Equal(t, "trigger1", aws.StringValue(usage[0].ResourceName))
+	assert.Equal(t, float64(49), usage[0].Usage)
+	assert.Equal(t, float64(50), usage[0].Quota)
+}
+
+func TestConcurrentSessionsCheckWithError(t *testing.T) {
+	mockClient := &mockGlueClient{
+		err:                  errors.New("some err"),
+		ListSessionsResponse: nil,
+	}
+
+	check := ConcurrentSessionsCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestConcurrentSessionsCheck(t *testing.T) {
+	mockClient := &mockGlueClient{
+		ListSessionsResponse: &glue.ListSessionsOutput{
+			Sessions: []*glue.Session{
+				{Id: aws.String("session1"), Status: aws.String(glue.SessionStatusProvisioning)},
+				{Id: aws.String("session2"), Status: aws.String(glue.SessionStatusReady)},
+				{Id: aws.String("session3"), Status: aws.String(glue.SessionStatusTimeout)},
+				{Id: aws.String("session4"), Status: aws.String(glue.SessionStatusStopped)},
+				{Id: aws.String("session5"), Status: aws.String(glue.SessionStatusFailed)},
+			},
+		},
+	}
+
+	check := ConcurrentSessionsCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        concurrentSessionsName,
+			Description: concurrentSessionsDescription,
+			Usage:       2,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func blueprintRuns(states ...string) *glue.GetBlueprintRunsOutput {
+	runs := []*glue.BlueprintRun{}
+	for _, state := range states {
+		runs = append(runs, &glue.BlueprintRun{State: aws.String(state)})
+	}
+	return &glue.GetBlueprintRunsOutput{BlueprintRuns: runs}
+}
+
+func TestConcurrentBlueprintRunsCheckWithError(t *testing.T) {
+	mockClient := &mockGlueClient{
+		err:                    errors.New("some err"),
+		ListBlueprintsResponse: nil,
+	}
+
+	check := ConcurrentBlueprintRunsCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestConcurrentBlueprintRunsCheck(t *testing.T) {
+	mockClient := &mockGlueClient{
+		ListBlueprintsResponse: &glue.ListBlueprintsOutput{
+			Blueprints: []*string{aws.String("blueprint1"), aws.String("blueprint2"), aws.String("blueprint3")},
+		},
+		GetBlueprintRunsResponses: map[string]*glue.GetBlueprintRunsOutput{
+			"blueprint1": blueprintRuns(glue.BlueprintRunStateRunning, glue.BlueprintRunStateSucceeded),
+			"blueprint2": blueprintRuns(glue.BlueprintRunStateRollingBack, glue.BlueprintRunStateFailed, glue.BlueprintRunStateRunning),
+			"blueprint3": {},
+		},
+	}
+
+	check := ConcurrentBlueprintRunsCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        concurrentBlueprintRunsName,
+			Description: concurrentBlueprintRunsDescription,
+			Usage:       3,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func testGlueJobs() *glue.GetJobsOutput {
+	return &glue.GetJobsOutput{
+		Jobs: []*glue.Job{
+			{
+				Name:              aws.String("legacy"),
+				MaxCapacity:       aws.Float64(10),
+				ExecutionProperty: &glue.ExecutionProperty{MaxConcurrentRuns: aws.Int64(3)},
+			},
+			{
+				Name:            aws.String("g1x"),
+				WorkerType:      aws.String(glue.WorkerTypeG1x),
+				NumberOfWorkers: aws.Int64(4),
+			},
+			{
+				Name:              aws.String("g2x"),
+				WorkerType:        aws.String(glue.WorkerTypeG2x),
+				NumberOfWorkers:   aws.Int64(5),
+				ExecutionProperty: &glue.ExecutionProperty{},
+			},
+			{
+				Name:            aws.String("g025x"),
+				WorkerType:      aws.String(glue.WorkerTypeG025x),
+				NumberOfWorkers: aws.Int64(2),
+			},
+			{
+				Name:       aws.String("unknown"),
+				WorkerType: aws.String("Z.99X"),
+			},
+		},
+	}
+}
+
+func TestDPUsCheckWithError(t *testing.T) {
+	mockClient := &mockGlueClient{
+		err:             errors.New("some err"),
+		GetJobsResponse: nil,
+	}
+
+	check := DPUsCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestDPUsCheckWithWorkerTypeJobs(t *testing.T) {
+	mockClient := &mockGlueClient{
+		GetJobsResponse: testGlueJobs(),
+	}
+
+	check := DPUsCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        dPUsName,
+			Description: dPUsDescription,
+			Usage:       24.5,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestConcurrentRunsPerJobCheckWithoutExecutionProperty(t *testing.T) {
+	mockClient := &mockGlueClient{
+		GetJobsResponse: testGlueJobs(),
+	}
+
+	check := ConcurrentRunsPerJobCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, usage, 5)
+	usages := map[string]float64{}
+	for _, u := range usage {
+		usages[*u.ResourceName] = u.Usage
+	}
+	assert.Equal(t, map[string]float64{"legacy": 3, "g1x": 1, "g2x": 1, "g025x": 1, "unknown": 1}, usages)
+}
+
+func TestJobsPerSecurityConfigurationCheckWithError(t *testing.T) {
+	mockClient := &mockGlueClient{
+		err:                               errors.New("some err"),
+		GetSecurityConfigurationsResponse: &glue.GetSecurityConfigurationsOutput{},
+	}
+
+	check := JobsPerSecurityConfigurationCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestJobsPerSecurityConfigurationCheck(t *testing.T) {
+	mockClient := &mockGlueClient{
+		GetSecurityConfigurationsResponse: &glue.GetSecurityConfigurationsOutput{
+			SecurityConfigurations: []*glue.SecurityConfiguration{
+				{Name: aws.String("used")},
+				{Name: aws.String("unused")},
+			},
+		},
+		GetJobsResponse: &glue.GetJobsOutput{
+			Jobs: []*glue.Job{
+				{Name: aws.String("job1"), SecurityConfiguration: aws.String("used")},
+				{Name: aws.String("job2"), SecurityConfiguration: aws.String("used")},
+				{Name: aws.String("job3")},
+			},
+		},
+	}
+
+	check := JobsPerSecurityConfigurationCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:         jobsPerSecurityConfigurationName,
+			Description:  jobsPerSecurityConfigurationDescription,
+			ResourceName: aws.String("unused"),
+			Usage:        0,
+		},
+		{
+			Name:         jobsPerSecurityConfigurationName,
+			Description:  jobsPerSecurityConfigurationDescription,
+			ResourceName: aws.String("used"),
+			Usage:        2,
+		},
+	}
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestConcurrentCrawlerRunsCheckWithError(t *testing.T) {
+	mockClient := &mockGlueClient{
+		err:                  errors.New("some err"),
+		ListCrawlersResponse: nil,
+	}
+
+	check := ConcurrentCrawlerRunsCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestConcurrentCrawlerRunsCheck(t *testing.T) {
+	mockClient := &mockGlueClient{
+		ListCrawlersResponse: &glue.ListCrawlersOutput{},
+		CrawlerStates:        map[string]string{},
+	}
+	states := []string{glue.CrawlerStateRunning, glue.CrawlerStateReady, glue.CrawlerStateStopping}
+	for i := 0; i < 150; i++ {
+		name := fmt.Sprintf("crawler%d", i)
+		mockClient.ListCrawlersResponse.CrawlerNames = append(mockClient.ListCrawlersResponse.CrawlerNames, aws.String(name))
+		mockClient.CrawlerStates[name] = states[i%len(states)]
+	}
+
+	check := ConcurrentCrawlerRunsCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        concurrentCrawlerRunsName,
+			Description: concurrentCrawlerRunsDescription,
+			Usage:       50,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+	assert.Len(t, mockClient.BatchGetCrawlersInputs, 2)
+	assert.Len(t, mockClient.BatchGetCrawlersInputs[0].CrawlerNames, 100)
+	assert.Len(t, mockClient.BatchGetCrawlersInputs[1].CrawlerNames, 50)
+}
+
+func TestConcurrentCrawlerRunsCheckWithoutCrawlers(t *testing.T) {
+	mockClient := &mockGlueClient{
+		ListCrawlersResponse: &glue.ListCrawlersOutput{},
+	}
+
+	check := ConcurrentCrawlerRunsCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(0), usage[0].Usage)
+	assert.Empty(t, mockClient.BatchGetCrawlersInputs)
+}
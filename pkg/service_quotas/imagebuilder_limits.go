@@ -0,0 +1,94 @@
+package servicequotas
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/imagebuilder"
+	"github.com/aws/aws-sdk-go/service/imagebuilder/imagebuilderiface"
+)
+
+const (
+	imagePipelinesName        = "imagebuilder_image_pipelines_per_region"
+	imagePipelinesDescription = "EC2 Image Builder image pipelines per region"
+
+	imageRecipesName        = "imagebuilder_image_recipes_per_region"
+	imageRecipesDescription = "EC2 Image Builder image recipes per region"
+)
+
+// ImagePipelinesCheck implements the UsageCheck interface for EC2 Image
+// Builder image pipelines per region
+type ImagePipelinesCheck struct {
+	client imagebuilderiface.ImagebuilderAPI
+}
+
+// Usage returns the number of image pipelines in the region or an
+// error
+func (c *ImagePipelinesCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var pipelinesCount int
+
+	err := c.client.ListImagePipelinesPagesWithContext(ctx, &imagebuilder.ListImagePipelinesInput{},
+		func(page *imagebuilder.ListImagePipelinesOutput, lastPage bool) bool {
+			if page != nil {
+				pipelinesCount += len(page.ImagePipelineList)
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        imagePipelinesName,
+			Description: imagePipelinesDescription,
+			Usage:       float64(pipelinesCount),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *ImagePipelinesCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: imagePipelinesName, Description: imagePipelinesDescription}}
+}
+
+// ImageRecipesCheck implements the UsageCheck interface for EC2 Image
+// Builder image recipes per region
+type ImageRecipesCheck struct {
+	client imagebuilderiface.ImagebuilderAPI
+}
+
+// Usage returns the number of image recipes owned by the account in
+// the region or an error. ListImageRecipes only lists the recipes of
+// the account by default, the shared and AWS managed recipes do not
+// count against the quota
+func (c *ImageRecipesCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var recipesCount int
+
+	err := c.client.ListImageRecipesPagesWithContext(ctx, &imagebuilder.ListImageRecipesInput{},
+		func(page *imagebuilder.ListImageRecipesOutput, lastPage bool) bool {
+			if page != nil {
+				recipesCount += len(page.ImageRecipeSummaryList)
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        imageRecipesName,
+			Description: imageRecipesDescription,
+			Usage:       float64(recipesCount),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *ImageRecipesCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: imageRecipesName, Description: imageRecipesDescription}}
+}
@@ -0,0 +1,102 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/imagebuilder"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockImagebuilderClient) ListImagePipelinesPagesWithContext(ctx aws.Context, input *imagebuilder.ListImagePipelinesInput, fn func(*imagebuilder.ListImagePipelinesOutput, bool) bool, opts ...request.Option) error {
+	for i, page := range m.ListImagePipelinesResponses {
+		if !fn(page, i == len(m.ListImagePipelinesResponses)-1) {
+			break
+		}
+	}
+	return m.err
+}
+
+func (m *mockImagebuilderClient) ListImageRecipesPagesWithContext(ctx aws.Context, input *imagebuilder.ListImageRecipesInput, fn func(*imagebuilder.ListImageRecipesOutput, bool) bool, opts ...request.Option) error {
+	for i, page := range m.ListImageRecipesResponses {
+		if !fn(page, i == len(m.ListImageRecipesResponses)-1) {
+			break
+		}
+	}
+	return m.err
+}
+
+func TestImagePipelinesCheckWithError(t *testing.T) {
+	mockClient := &mockImagebuilderClient{
+		err: errors.New("some err"),
+	}
+
+	check := ImagePipelinesCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestImagePipelinesCheck(t *testing.T) {
+	mockClient := &mockImagebuilderClient{
+		ListImagePipelinesResponses: []*imagebuilder.ListImagePipelinesOutput{
+			{ImagePipelineList: []*imagebuilder.ImagePipeline{{Name: aws.String("pipeline1")}, {Name: aws.String("pipeline2")}}},
+			{ImagePipelineList: []*imagebuilder.ImagePipeline{{Name: aws.String("pipeline3")}}},
+		},
+	}
+
+	check := ImagePipelinesCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        imagePipelinesName,
+			Description: imagePipelinesDescription,
+			Usage:       3,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestImageRecipesCheckWithError(t *testing.T) {
+	mockClient := &mockImagebuilderClient{
+		err: errors.New("some err"),
+	}
+
+	check := ImageRecipesCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestImageRecipesCheck(t *testing.T) {
+	mockClient := &mockImagebuilderClient{
+		ListImageRecipesResponses: []*imagebuilder.ListImageRecipesOutput{
+			{ImageRecipeSummaryList: []*imagebuilder.ImageRecipeSummary{{Name: aws.String("recipe1")}}},
+			{ImageRecipeSummaryList: []*imagebuilder.ImageRecipeSummary{{Name: aws.String("recipe2")}}},
+		},
+	}
+
+	check := ImageRecipesCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        imageRecipesName,
+			Description: imageRecipesDescription,
+			Usage:       2,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
@@ -0,0 +1,86 @@
+package servicequotas
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+)
+
+// instanceTypesCache holds the information of the instance types
+// described during a refresh, so that the EC2 checks needing it, such
+// as the vCPUs or the network interfaces of the instance types, only
+// describe each instance type once per refresh. ServiceQuotas resets it
+// at the start of each refresh. A nil cache describes the instance
+// types on every call
+type instanceTypesCache struct {
+	mutex sync.Mutex
+	// instanceTypes holds the information of each described instance
+	// type, it is nil for the instance types AWS did not return
+	instanceTypes map[string]*ec2.InstanceTypeInfo
+}
+
+// reset empties the cache
+func (c *instanceTypesCache) reset() {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.instanceTypes = nil
+}
+
+// get returns the information of each of `instanceTypes` by instance
+// type or an error. The instance types missing from the cache are
+// described with `ec2Service`, those AWS does not return are missing
+// from the result
+func (c *instanceTypesCache) get(ctx context.Context, ec2Service ec2iface.EC2API, instanceTypes []string) (map[string]*ec2.InstanceTypeInfo, error) {
+	if c == nil {
+		return describeInstanceTypesInfo(ctx, ec2Service, instanceTypes)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.instanceTypes == nil {
+		c.instanceTypes = map[string]*ec2.InstanceTypeInfo{}
+	}
+	missing := []string{}
+	for _, instanceType := range instanceTypes {
+		if _, ok := c.instanceTypes[instanceType]; !ok {
+			missing = append(missing, instanceType)
+		}
+	}
+
+	described, err := describeInstanceTypesInfo(ctx, ec2Service, missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, instanceType := range missing {
+		c.instanceTypes[instanceType] = described[instanceType]
+	}
+
+	infos := map[string]*ec2.InstanceTypeInfo{}
+	for _, instanceType := range instanceTypes {
+		if info := c.instanceTypes[instanceType]; info != nil {
+			infos[instanceType] = info
+		}
+	}
+	return infos, nil
+}
+
+// describeInstanceTypesInfo returns the information of each of
+// `instanceTypes` returned by AWS by instance type or an error
+func describeInstanceTypesInfo(ctx context.Context, ec2Service ec2iface.EC2API, instanceTypes []string) (map[string]*ec2.InstanceTypeInfo, error) {
+	infos := map[string]*ec2.InstanceTypeInfo{}
+	err := describeInstanceTypes(ctx, ec2Service, instanceTypes, func(instanceType *ec2.InstanceTypeInfo) {
+		infos[aws.StringValue(instanceType.InstanceType)] = instanceType
+	})
+	if err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
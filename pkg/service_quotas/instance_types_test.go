@@ -0,0 +1,120 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func instanceTypeInfos(names ...string) *ec2.DescribeInstanceTypesOutput {
+	output := &ec2.DescribeInstanceTypesOutput{}
+	for _, name := range names {
+		output.InstanceTypes = append(output.InstanceTypes, &ec2.InstanceTypeInfo{
+			InstanceType: aws.String(name),
+			VCpuInfo:     &ec2.VCpuInfo{DefaultVCpus: aws.Int64(2)},
+		})
+	}
+	return output
+}
+
+func TestInstanceTypesCache(t *testing.T) {
+	mockClient := &mockEC2Client{
+		DescribeInstanceTypesResponse: instanceTypeInfos("m5.large", "c5.large"),
+	}
+	cache := &instanceTypesCache{}
+
+	infos, err := cache.get(context.Background(), mockClient, []string{"m5.large", "c5.large"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(infos))
+	assert.Equal(t, aws.StringSlice([]string{"m5.large", "c5.large"}), mockClient.InstanceTypes)
+
+	infos, err = cache.get(context.Background(), mockClient, []string{"c5.large"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*ec2.InstanceTypeInfo{"c5.large": mockClient.DescribeInstanceTypesResponse.InstanceTypes[1]}, infos)
+	assert.Equal(t, aws.StringSlice([]string{"m5.large", "c5.large"}), mockClient.InstanceTypes)
+}
+
+func TestInstanceTypesCacheDescribesMissingTypes(t *testing.T) {
+	mockClient := &mockEC2Client{
+		DescribeInstanceTypesResponse: instanceTypeInfos("m5.large"),
+	}
+	cache := &instanceTypesCache{}
+
+	_, err := cache.get(context.Background(), mockClient, []string{"m5.large"})
+	assert.NoError(t, err)
+
+	// t3.unknown is not returned by AWS, it is not described again
+	infos, err := cache.get(context.Background(), mockClient, []string{"m5.large", "t3.unknown"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(infos))
+	infos, err = cache.get(context.Background(), mockClient, []string{"t3.unknown"})
+	assert.NoError(t, err)
+	assert.Empty(t, infos)
+	assert.Equal(t, aws.StringSlice([]string{"m5.large", "t3.unknown"}), mockClient.InstanceTypes)
+}
+
+func TestInstanceTypesCacheReset(t *testing.T) {
+	mockClient := &mockEC2Client{
+		DescribeInstanceTypesResponse: instanceTypeInfos("m5.large"),
+	}
+	cache := &instanceTypesCache{}
+
+	_, err := cache.get(context.Background(), mockClient, []string{"m5.large"})
+	assert.NoError(t, err)
+	cache.reset()
+	_, err = cache.get(context.Background(), mockClient, []string{"m5.large"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, aws.StringSlice([]string{"m5.large", "m5.large"}), mockClient.InstanceTypes)
+}
+
+func TestInstanceTypesCacheWithError(t *testing.T) {
+	mockClient := &mockEC2Client{
+		DescribeInstanceTypesErr: errors.New("some err"),
+	}
+	cache := &instanceTypesCache{}
+
+	infos, err := cache.get(context.Background(), mockClient, []string{"m5.large"})
+
+	assert.Error(t, err)
+	assert.Nil(t, infos)
+	assert.Empty(t, cache.instanceTypes)
+}
+
+func TestInstanceTypesCacheSharedByChecks(t *testing.T) {
+	mockClient := &mockEC2Client{
+		DescribeInstancesResponse: &ec2.DescribeInstancesOutput{
+			Reservations: []*ec2.Reservation{
+				{
+					Instances: []*ec2.Instance{
+						{InstanceId: aws.String("i-1"), InstanceType: aws.String("m5.large")},
+					},
+				},
+			},
+		},
+		DescribeInstanceTypesResponse: instanceTypeInfos("m5.large"),
+	}
+	cache := &instanceTypesCache{}
+
+	serviceQuotas := ServiceQuotas{
+		quotasService: &mockServiceQuotasClient{},
+		instanceTypes: cache,
+		otherUsageChecks: []UsageCheck{
+			&ENIsPerInstanceCheck{client: mockClient, instanceTypes: cache},
+			&ENIsPerInstanceCheck{client: mockClient, instanceTypes: cache},
+		},
+	}
+
+	_, err := serviceQuotas.QuotasAndUsage()
+	assert.NoError(t, err)
+	assert.Equal(t, aws.StringSlice([]string{"m5.large"}), mockClient.InstanceTypes)
+
+	// the next refresh describes the instance types again
+	_, err = serviceQuotas.QuotasAndUsage()
+	assert.NoError(t, err)
+	assert.Equal(t, aws.StringSlice([]string{"m5.large", "m5.large"}), mockClient.InstanceTypes)
+}
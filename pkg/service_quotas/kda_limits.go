@@ -1,6 +1,8 @@
 package servicequotas
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/service/kinesisanalyticsv2"
 	"github.com/aws/aws-sdk-go/service/kinesisanalyticsv2/kinesisanalyticsv2iface"
 	"github.com/pkg/errors"
@@ -14,74 +16,138 @@ const (
 	appsPerRegionDescription = "apps per region"
 )
 
+// listApplicationsGuard protects the hand-rolled pagination of
+// ListApplications against AWS returning the same NextToken again,
+// which would otherwise page forever, and stops paging after
+// `maxPages` pages. No page limit is applied when `maxPages` is 0
+type listApplicationsGuard struct {
+	maxPages  int
+	pages     int
+	seen      map[string]bool
+	truncated bool
+}
+
+// next returns whether the page after the one with `nextToken` should
+// be requested or an error if `nextToken` was already returned
+func (g *listApplicationsGuard) next(nextToken *string) (bool, error) {
+	g.pages++
+	if nextToken == nil {
+		return false, nil
+	}
+
+	if g.seen == nil {
+		g.seen = map[string]bool{}
+	}
+	if g.seen[*nextToken] {
+		return false, errors.Wrapf(ErrFailedToGetUsage, "ListApplications returned NextToken %s more than once", *nextToken)
+	}
+	g.seen[*nextToken] = true
+
+	if g.maxPages > 0 && g.pages >= g.maxPages {
+		log.Warnf("Stopped paging KDA applications after %d pages", g.pages)
+		g.truncated = true
+		return false, nil
+	}
+	return true, nil
+}
+
 type AppKPUUsageCheck struct {
-	client kinesisanalyticsv2iface.KinesisAnalyticsV2API
+	client   kinesisanalyticsv2iface.KinesisAnalyticsV2API
+	sampler  resourceSampler
+	maxPages int
+	// parallelismOffset is added to the current parallelism of each
+	// application, see Options.KDAParallelismOffset
+	parallelismOffset int64
 }
 
-func (c *AppKPUUsageCheck) Usage() ([]QuotaUsage, error) {
+// Usage returns the KPUs of each flink application or an error. When
+// sampling, the applications that are not sampled are not described
+// and their previous usage is returned
+func (c *AppKPUUsageCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
+	c.sampler.start()
 
 	listParams := &kinesisanalyticsv2.ListApplicationsInput{}
-	apps, err := c.client.ListApplications(listParams)
+	apps, err := c.client.ListApplicationsWithContext(ctx, listParams)
 	if err != nil {
 		log.Error("Failed to get KPUs Usage")
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
+	guard := &listApplicationsGuard{maxPages: c.maxPages}
 	// Go doesn't support while loops, so let's make our own
 	// First let's get the first page of apps
 	repeat := true
 	for repeat != false {
 		// Then we iterate over each app from that page
 		for _, app := range apps.ApplicationSummaries {
+			if usage, ok := c.sampler.skip(*app.ApplicationName); ok {
+				quotaUsages = append(quotaUsages, usage)
+				continue
+			}
 			descParams := &kinesisanalyticsv2.DescribeApplicationInput{ApplicationName: app.ApplicationName}
-			response, err := c.client.DescribeApplication(descParams)
+			response, err := c.client.DescribeApplicationWithContext(ctx, descParams)
 			if err != nil {
 				log.Error("Failed to describe KDA applications")
-				return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+				return nil, wrapUsageErr(err)
 			} else {
 				usage := QuotaUsage{
 					Name:         flinkKPUsPerAppName,
 					Description:  flinkKPUsPerAppDescription,
 					ResourceName: response.ApplicationDetail.ApplicationName,
-					// we have to add 1 here because what the AWS API reports is off by 1 compared to billing, confirmed with AWS support
-					Usage: float64(*response.ApplicationDetail.ApplicationConfigurationDescription.FlinkApplicationConfigurationDescription.ParallelismConfigurationDescription.CurrentParallelism + 1),
+					// the offset is 1 by default because what the AWS API reports is off by 1 compared to billing, confirmed with AWS support
+					Usage: float64(*response.ApplicationDetail.ApplicationConfigurationDescription.FlinkApplicationConfigurationDescription.ParallelismConfigurationDescription.CurrentParallelism + c.parallelismOffset),
 				}
+				c.sampler.record(*app.ApplicationName, usage)
 				quotaUsages = append(quotaUsages, usage)
 			}
 		}
 		// Once we have finished with that page
 		// We need to check if we need to get another one
-		if apps.NextToken == nil {
-			// If it doesn't have a next token, we know to stop here
-			repeat = false
-		} else {
+		repeat, err = guard.next(apps.NextToken)
+		if err != nil {
+			return nil, err
+		}
+		if repeat {
 			// If it does have a NextToken, we need to get the next page of apps
 			listParams = &kinesisanalyticsv2.ListApplicationsInput{NextToken: apps.NextToken}
-			apps, err = c.client.ListApplications(listParams)
+			apps, err = c.client.ListApplicationsWithContext(ctx, listParams)
 			if err != nil {
-				return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+				return nil, wrapUsageErr(err)
 			}
 
 		}
 
 	}
+	c.sampler.finish()
+	if guard.truncated {
+		for i := range quotaUsages {
+			quotaUsages[i].Truncated = true
+		}
+	}
 
 	return quotaUsages, nil
 }
 
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *AppKPUUsageCheck) DescribeResourceUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: flinkKPUsPerAppName, Description: flinkKPUsPerAppDescription}}
+}
+
 type AppsPerRegionCheck struct {
-	client kinesisanalyticsv2iface.KinesisAnalyticsV2API
+	client   kinesisanalyticsv2iface.KinesisAnalyticsV2API
+	maxPages int
 }
 
-func (c *AppsPerRegionCheck) Usage() ([]QuotaUsage, error) {
+func (c *AppsPerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
 
 	var totalAppsCount int
 	listParams := &kinesisanalyticsv2.ListApplicationsInput{}
-	apps, err := c.client.ListApplications(listParams)
+	apps, err := c.client.ListApplicationsWithContext(ctx, listParams)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
+	guard := &listApplicationsGuard{maxPages: c.maxPages}
 	// Go doesn't support while loops, so let's make our own
 	// First let's get the first page of apps
 	repeat := true
@@ -91,15 +157,16 @@ func (c *AppsPerRegionCheck) Usage() ([]QuotaUsage, error) {
 
 		// Once we have finished with that page
 		// We need to check if we need to get another one
-		if apps.NextToken == nil {
-			// If it doesn't have a next token, we know to stop here
-			repeat = false
-		} else {
+		repeat, err = guard.next(apps.NextToken)
+		if err != nil {
+			return nil, err
+		}
+		if repeat {
 			// If it does have a NextToken, we need to get the next page of apps
 			listParams = &kinesisanalyticsv2.ListApplicationsInput{NextToken: apps.NextToken}
-			apps, err = c.client.ListApplications(listParams)
+			apps, err = c.client.ListApplicationsWithContext(ctx, listParams)
 			if err != nil {
-				return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+				return nil, wrapUsageErr(err)
 			}
 		}
 	}
@@ -107,8 +174,14 @@ func (c *AppsPerRegionCheck) Usage() ([]QuotaUsage, error) {
 		Name:        appsPerRegionName,
 		Description: appsPerRegionDescription,
 		Usage:       float64(totalAppsCount),
+		Truncated:   guard.truncated,
 	}
 	quotaUsages = append(quotaUsages, quota)
 
 	return quotaUsages, nil
 }
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *AppsPerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: appsPerRegionName, Description: appsPerRegionDescription}}
+}
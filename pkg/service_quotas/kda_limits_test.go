@@ -0,0 +1,205 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/kinesisanalyticsv2"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockKDAClient) ListApplicationsWithContext(ctx aws.Context, input *kinesisanalyticsv2.ListApplicationsInput, opts ...request.Option) (*kinesisanalyticsv2.ListApplicationsOutput, error) {
+	m.ListApplicationsCalls++
+	return m.ListApplicationsResponses[aws.StringValue(input.NextToken)], m.err
+}
+
+func (m *mockKDAClient) DescribeApplicationWithContext(ctx aws.Context, input *kinesisanalyticsv2.DescribeApplicationInput, opts ...request.Option) (*kinesisanalyticsv2.DescribeApplicationOutput, error) {
+	return m.DescribeApplicationResponses[aws.StringValue(input.ApplicationName)], m.err
+}
+
+// flinkApplication returns the description of the flink application
+// `name` with `parallelism`
+func flinkApplication(name string, parallelism int64) *kinesisanalyticsv2.DescribeApplicationOutput {
+	return &kinesisanalyticsv2.DescribeApplicationOutput{
+		ApplicationDetail: &kinesisanalyticsv2.ApplicationDetail{
+			ApplicationName: aws.String(name),
+			ApplicationConfigurationDescription: &kinesisanalyticsv2.ApplicationConfigurationDescription{
+				FlinkApplicationConfigurationDescription: &kinesisanalyticsv2.FlinkApplicationConfigurationDescription{
+					ParallelismConfigurationDescription: &kinesisanalyticsv2.ParallelismConfigurationDescription{
+						CurrentParallelism: aws.Int64(parallelism),
+					},
+				},
+			},
+		},
+	}
+}
+
+func applicationSummaries(names ...string) []*kinesisanalyticsv2.ApplicationSummary {
+	summaries := []*kinesisanalyticsv2.ApplicationSummary{}
+	for _, name := range names {
+		summaries = append(summaries, &kinesisanalyticsv2.ApplicationSummary{ApplicationName: aws.String(name)})
+	}
+	return summaries
+}
+
+func TestAppsPerRegionCheckWithError(t *testing.T) {
+	mockClient := &mockKDAClient{
+		err:                       errors.New("some err"),
+		ListApplicationsResponses: nil,
+	}
+
+	check := AppsPerRegionCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestAppsPerRegionCheck(t *testing.T) {
+	mockClient := &mockKDAClient{
+		ListApplicationsResponses: map[string]*kinesisanalyticsv2.ListApplicationsOutput{
+			"": {
+				ApplicationSummaries: applicationSummaries("app1", "app2"),
+				NextToken:            aws.String("token1"),
+			},
+			"token1": {
+				ApplicationSummaries: applicationSummaries("app3"),
+			},
+		},
+	}
+
+	check := AppsPerRegionCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        appsPerRegionName,
+			Description: appsPerRegionDescription,
+			Usage:       3,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestAppsPerRegionCheckWithRepeatedNextToken(t *testing.T) {
+	mockClient := &mockKDAClient{
+		ListApplicationsResponses: map[string]*kinesisanalyticsv2.ListApplicationsOutput{
+			"": {
+				ApplicationSummaries: applicationSummaries("app1"),
+				NextToken:            aws.String("token1"),
+			},
+			"token1": {
+				ApplicationSummaries: applicationSummaries("app2"),
+				NextToken:            aws.String("token1"),
+			},
+		},
+	}
+
+	check := AppsPerRegionCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+	assert.Equal(t, 2, mockClient.ListApplicationsCalls)
+}
+
+func TestAppsPerRegionCheckWithMaxPages(t *testing.T) {
+	mockClient := &mockKDAClient{
+		ListApplicationsResponses: map[string]*kinesisanalyticsv2.ListApplicationsOutput{
+			"": {
+				ApplicationSummaries: applicationSummaries("app1", "app2"),
+				NextToken:            aws.String("token1"),
+			},
+			"token1": {
+				ApplicationSummaries: applicationSummaries("app3"),
+				NextToken:            aws.String("token2"),
+			},
+			"token2": {
+				ApplicationSummaries: applicationSummaries("app4"),
+			},
+		},
+	}
+
+	check := AppsPerRegionCheck{client: mockClient, maxPages: 2}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        appsPerRegionName,
+			Description: appsPerRegionDescription,
+			Usage:       3,
+			Truncated:   true,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+	assert.Equal(t, 2, mockClient.ListApplicationsCalls)
+}
+
+func TestAppKPUUsageCheckWithRepeatedNextToken(t *testing.T) {
+	mockClient := &mockKDAClient{
+		ListApplicationsResponses: map[string]*kinesisanalyticsv2.ListApplicationsOutput{
+			"":       {NextToken: aws.String("token1")},
+			"token1": {NextToken: aws.String("token1")},
+		},
+	}
+
+	check := AppKPUUsageCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestAppKPUUsageCheck(t *testing.T) {
+	mockClient := &mockKDAClient{
+		ListApplicationsResponses: map[string]*kinesisanalyticsv2.ListApplicationsOutput{
+			"": {ApplicationSummaries: applicationSummaries("app1")},
+		},
+		DescribeApplicationResponses: map[string]*kinesisanalyticsv2.DescribeApplicationOutput{
+			"app1": flinkApplication("app1", 4),
+		},
+	}
+
+	check := AppKPUUsageCheck{client: mockClient, parallelismOffset: 1}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:         flinkKPUsPerAppName,
+			Description:  flinkKPUsPerAppDescription,
+			ResourceName: aws.String("app1"),
+			Usage:        5,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestAppKPUUsageCheckWithoutParallelismOffset(t *testing.T) {
+	mockClient := &mockKDAClient{
+		ListApplicationsResponses: map[string]*kinesisanalyticsv2.ListApplicationsOutput{
+			"": {ApplicationSummaries: applicationSummaries("app1")},
+		},
+		DescribeApplicationResponses: map[string]*kinesisanalyticsv2.DescribeApplicationOutput{
+			"app1": flinkApplication("app1", 4),
+		},
+	}
+
+	check := AppKPUUsageCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(usage))
+	assert.Equal(t, float64(4), usage[0].Usage)
+}
@@ -0,0 +1,69 @@
+package servicequotas
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kinesis/kinesisiface"
+)
+
+const (
+	shardsPerRegionName        = "kinesis_shards_per_region"
+	shardsPerRegionDescription = "Kinesis Data Streams shards per region"
+)
+
+// ShardsPerRegionCheck implements the UsageCheck interface for Kinesis
+// Data Streams shards per region
+type ShardsPerRegionCheck struct {
+	client kinesisiface.KinesisAPI
+}
+
+// Usage returns the number of open shards of the Kinesis data streams
+// or an error. ListStreams only returns the names of the streams, so
+// the shards are counted with one DescribeStreamSummary call per
+// stream. The streams deleted after they were listed are left out
+func (c *ShardsPerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var streamNames []*string
+
+	err := c.client.ListStreamsPagesWithContext(ctx, &kinesis.ListStreamsInput{},
+		func(page *kinesis.ListStreamsOutput, lastPage bool) bool {
+			if page != nil {
+				streamNames = append(streamNames, page.StreamNames...)
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	var shardsCount int64
+	for _, streamName := range streamNames {
+		response, err := c.client.DescribeStreamSummaryWithContext(ctx, &kinesis.DescribeStreamSummaryInput{StreamName: streamName})
+		if err != nil {
+			if aerr, ok := err.(awserr.Error); ok && aerr.Code() == kinesis.ErrCodeResourceNotFoundException {
+				continue
+			}
+			return nil, wrapUsageErr(err)
+		}
+		if response.StreamDescriptionSummary != nil {
+			shardsCount += aws.Int64Value(response.StreamDescriptionSummary.OpenShardCount)
+		}
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        shardsPerRegionName,
+			Description: shardsPerRegionDescription,
+			Usage:       float64(shardsCount),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *ShardsPerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: shardsPerRegionName, Description: shardsPerRegionDescription}}
+}
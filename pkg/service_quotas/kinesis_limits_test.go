@@ -0,0 +1,93 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockKinesisClient) ListStreamsPagesWithContext(ctx aws.Context, input *kinesis.ListStreamsInput, fn func(*kinesis.ListStreamsOutput, bool) bool, opts ...request.Option) error {
+	for i, page := range m.ListStreamsResponses {
+		if !fn(page, i == len(m.ListStreamsResponses)-1) {
+			break
+		}
+	}
+	return m.err
+}
+
+func (m *mockKinesisClient) DescribeStreamSummaryWithContext(ctx aws.Context, input *kinesis.DescribeStreamSummaryInput, opts ...request.Option) (*kinesis.DescribeStreamSummaryOutput, error) {
+	if m.DescribeStreamSummaryErr != nil {
+		return nil, m.DescribeStreamSummaryErr
+	}
+	openShardCount, ok := m.OpenShardCounts[aws.StringValue(input.StreamName)]
+	if !ok {
+		return nil, awserr.New(kinesis.ErrCodeResourceNotFoundException, "not found", nil)
+	}
+	return &kinesis.DescribeStreamSummaryOutput{
+		StreamDescriptionSummary: &kinesis.StreamDescriptionSummary{
+			StreamName:     input.StreamName,
+			OpenShardCount: aws.Int64(openShardCount),
+		},
+	}, nil
+}
+
+func TestShardsPerRegionCheckWithError(t *testing.T) {
+	mockClient := &mockKinesisClient{
+		err: errors.New("some err"),
+	}
+
+	check := ShardsPerRegionCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestShardsPerRegionCheckWithDescribeError(t *testing.T) {
+	mockClient := &mockKinesisClient{
+		ListStreamsResponses:     []*kinesis.ListStreamsOutput{{StreamNames: aws.StringSlice([]string{"stream1"})}},
+		DescribeStreamSummaryErr: errors.New("some err"),
+	}
+
+	check := ShardsPerRegionCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestShardsPerRegionCheck(t *testing.T) {
+	mockClient := &mockKinesisClient{
+		ListStreamsResponses: []*kinesis.ListStreamsOutput{
+			{StreamNames: aws.StringSlice([]string{"stream1", "stream2"}), HasMoreStreams: aws.Bool(true)},
+			{StreamNames: aws.StringSlice([]string{"stream3", "deleted"}), HasMoreStreams: aws.Bool(false)},
+		},
+		OpenShardCounts: map[string]int64{
+			"stream1": 4,
+			"stream2": 1,
+			"stream3": 10,
+		},
+	}
+
+	check := ShardsPerRegionCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        shardsPerRegionName,
+			Description: shardsPerRegionDescription,
+			Usage:       15,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
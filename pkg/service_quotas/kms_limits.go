@@ -0,0 +1,73 @@
+package servicequotas
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+)
+
+const (
+	customerManagedKeysName        = "kms_customer_managed_keys"
+	customerManagedKeysDescription = "KMS customer managed keys per region"
+)
+
+// CustomerManagedKeysCheck implements the UsageCheck interface for KMS
+// customer managed keys per region
+type CustomerManagedKeysCheck struct {
+	client kmsiface.KMSAPI
+	// customerManagedOnly describes every key to leave out the AWS
+	// managed keys, see Options.KMSCustomerManagedKeysOnly
+	customerManagedOnly bool
+}
+
+// Usage returns the number of KMS keys in the region of the client or
+// an error. ListKeys also returns the AWS managed keys, which do not
+// count against the quota, so they are only left out when
+// `customerManagedOnly` is set, with one DescribeKey call per key
+func (c *CustomerManagedKeysCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var keyIDs []*string
+
+	err := c.client.ListKeysPagesWithContext(ctx, &kms.ListKeysInput{},
+		func(page *kms.ListKeysOutput, lastPage bool) bool {
+			if page != nil {
+				for _, key := range page.Keys {
+					keyIDs = append(keyIDs, key.KeyId)
+				}
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	keysCount := len(keyIDs)
+	if c.customerManagedOnly {
+		keysCount = 0
+		for _, keyID := range keyIDs {
+			response, err := c.client.DescribeKeyWithContext(ctx, &kms.DescribeKeyInput{KeyId: keyID})
+			if err != nil {
+				return nil, wrapUsageErr(err)
+			}
+			if response.KeyMetadata != nil && aws.StringValue(response.KeyMetadata.KeyManager) == kms.KeyManagerTypeCustomer {
+				keysCount++
+			}
+		}
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        customerManagedKeysName,
+			Description: customerManagedKeysDescription,
+			Usage:       float64(keysCount),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *CustomerManagedKeysCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: customerManagedKeysName, Description: customerManagedKeysDescription}}
+}
@@ -0,0 +1,91 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockKMSClient) ListKeysPagesWithContext(ctx aws.Context, input *kms.ListKeysInput, fn func(*kms.ListKeysOutput, bool) bool, opts ...request.Option) error {
+	for i, page := range m.ListKeysResponses {
+		if !fn(page, i == len(m.ListKeysResponses)-1) {
+			break
+		}
+	}
+	return m.err
+}
+
+func (m *mockKMSClient) DescribeKeyWithContext(ctx aws.Context, input *kms.DescribeKeyInput, opts ...request.Option) (*kms.DescribeKeyOutput, error) {
+	m.DescribeKeyCalls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	metadata := &kms.KeyMetadata{KeyId: input.KeyId, KeyManager: aws.String(m.KeyManagers[*input.KeyId])}
+	return &kms.DescribeKeyOutput{KeyMetadata: metadata}, nil
+}
+
+func keys(ids ...string) *kms.ListKeysOutput {
+	output := &kms.ListKeysOutput{}
+	for _, id := range ids {
+		output.Keys = append(output.Keys, &kms.KeyListEntry{KeyId: aws.String(id)})
+	}
+	return output
+}
+
+func TestCustomerManagedKeysCheckWithError(t *testing.T) {
+	mockClient := &mockKMSClient{
+		err: errors.New("some err"),
+	}
+
+	check := CustomerManagedKeysCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestCustomerManagedKeysCheck(t *testing.T) {
+	mockClient := &mockKMSClient{
+		ListKeysResponses: []*kms.ListKeysOutput{keys("key1", "key2"), keys("key3")},
+	}
+
+	check := CustomerManagedKeysCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        customerManagedKeysName,
+			Description: customerManagedKeysDescription,
+			Usage:       3,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+	assert.Equal(t, 0, mockClient.DescribeKeyCalls)
+}
+
+func TestCustomerManagedKeysCheckCustomerManagedOnly(t *testing.T) {
+	mockClient := &mockKMSClient{
+		ListKeysResponses: []*kms.ListKeysOutput{keys("key1", "key2", "key3")},
+		KeyManagers: map[string]string{
+			"key1": kms.KeyManagerTypeCustomer,
+			"key2": kms.KeyManagerTypeAws,
+			"key3": kms.KeyManagerTypeCustomer,
+		},
+	}
+
+	check := CustomerManagedKeysCheck{client: mockClient, customerManagedOnly: true}
+	usage, err := check.Usage(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(usage))
+	assert.Equal(t, float64(2), usage[0].Usage)
+	assert.Equal(t, 3, mockClient.DescribeKeyCalls)
+}
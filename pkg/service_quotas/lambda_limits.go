@@ -0,0 +1,188 @@
+package servicequotas
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+)
+
+const (
+	provisionedConcurrencyAllocatedName        = "provisioned_concurrency_allocated"
+	provisionedConcurrencyAllocatedDescription = "provisioned concurrency allocated"
+
+	eventSourceMappingsName        = "lambda_event_source_mappings_per_region"
+	eventSourceMappingsDescription = "Lambda event source mappings per region"
+
+	functionUrlsName        = "lambda_function_urls_per_region"
+	functionUrlsDescription = "Lambda function URLs per region"
+)
+
+// functionNames returns the names of the lambda functions or an error
+func functionNames(ctx context.Context, client lambdaiface.LambdaAPI) ([]*string, error) {
+	var names []*string
+	err := client.ListFunctionsPagesWithContext(ctx, &lambda.ListFunctionsInput{},
+		func(page *lambda.ListFunctionsOutput, lastPage bool) bool {
+			if page != nil {
+				for _, function := range page.Functions {
+					names = append(names, function.FunctionName)
+				}
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// ProvisionedConcurrencyCheck implements the UsageCheck interface for
+// the provisioned concurrency allocated to lambda functions
+type ProvisionedConcurrencyCheck struct {
+	client lambdaiface.LambdaAPI
+}
+
+// Usage returns the provisioned concurrency allocated across all
+// lambda functions with the account concurrent executions limit as the
+// quota or an error
+// Provisioned concurrency is taken from the unreserved concurrency of
+// the account, so it is reported against the account limit
+func (c *ProvisionedConcurrencyCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	settings, err := c.client.GetAccountSettingsWithContext(ctx, &lambda.GetAccountSettingsInput{})
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	functions, err := functionNames(ctx, c.client)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	var allocatedConcurrency int64
+	for _, functionName := range functions {
+		params := &lambda.ListProvisionedConcurrencyConfigsInput{FunctionName: functionName}
+		err := c.client.ListProvisionedConcurrencyConfigsPagesWithContext(ctx, params,
+			func(page *lambda.ListProvisionedConcurrencyConfigsOutput, lastPage bool) bool {
+				if page != nil {
+					for _, config := range page.ProvisionedConcurrencyConfigs {
+						allocatedConcurrency += aws.Int64Value(config.AllocatedProvisionedConcurrentExecutions)
+					}
+				}
+				return !lastPage
+			},
+		)
+		if err != nil {
+			return nil, wrapUsageErr(err)
+		}
+	}
+
+	// the usage is reported without a quota when the account limit is
+	// missing from the settings
+	var concurrentExecutions int64
+	if settings.AccountLimit != nil {
+		concurrentExecutions = aws.Int64Value(settings.AccountLimit.ConcurrentExecutions)
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        provisionedConcurrencyAllocatedName,
+			Description: provisionedConcurrencyAllocatedDescription,
+			Usage:       float64(allocatedConcurrency),
+			Quota:       float64(concurrentExecutions),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *ProvisionedConcurrencyCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: provisionedConcurrencyAllocatedName, Description: provisionedConcurrencyAllocatedDescription}}
+}
+
+// EventSourceMappingsCheck implements the UsageCheck interface for the
+// lambda event source mappings per region
+type EventSourceMappingsCheck struct {
+	client lambdaiface.LambdaAPI
+}
+
+// Usage returns the number of lambda event source mappings or an error.
+// Lambda publishes no quota for them in service quotas, so only the
+// usage is reported
+func (c *EventSourceMappingsCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var mappingsCount int
+
+	err := c.client.ListEventSourceMappingsPagesWithContext(ctx, &lambda.ListEventSourceMappingsInput{},
+		func(page *lambda.ListEventSourceMappingsOutput, lastPage bool) bool {
+			if page != nil {
+				mappingsCount += len(page.EventSourceMappings)
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        eventSourceMappingsName,
+			Description: eventSourceMappingsDescription,
+			Usage:       float64(mappingsCount),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *EventSourceMappingsCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: eventSourceMappingsName, Description: eventSourceMappingsDescription}}
+}
+
+// FunctionUrlsCheck implements the UsageCheck interface for the lambda
+// function URLs per region
+type FunctionUrlsCheck struct {
+	client lambdaiface.LambdaAPI
+}
+
+// Usage returns the number of lambda function URLs or an error. The
+// URLs are listed per function, including the URLs of their aliases.
+// Lambda publishes no quota for them in service quotas, so only the
+// usage is reported
+func (c *FunctionUrlsCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	functions, err := functionNames(ctx, c.client)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	var urlsCount int
+	for _, functionName := range functions {
+		params := &lambda.ListFunctionUrlConfigsInput{FunctionName: functionName}
+		err := c.client.ListFunctionUrlConfigsPagesWithContext(ctx, params,
+			func(page *lambda.ListFunctionUrlConfigsOutput, lastPage bool) bool {
+				if page != nil {
+					urlsCount += len(page.FunctionUrlConfigs)
+				}
+				return !lastPage
+			},
+		)
+		if err != nil {
+			return nil, wrapUsageErr(err)
+		}
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        functionUrlsName,
+			Description: functionUrlsDescription,
+			Usage:       float64(urlsCount),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *FunctionUrlsCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: functionUrlsName, Description: functionUrlsDescription}}
+}
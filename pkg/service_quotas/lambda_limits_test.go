@@ -0,0 +1,203 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockLambdaClient) GetAccountSettingsWithContext(ctx aws.Context, input *lambda.GetAccountSettingsInput, opts ...request.Option) (*lambda.GetAccountSettingsOutput, error) {
+	return m.GetAccountSettingsResponse, m.err
+}
+
+func (m *mockLambdaClient) ListFunctionsPagesWithContext(ctx aws.Context, input *lambda.ListFunctionsInput, fn func(*lambda.ListFunctionsOutput, bool) bool, opts ...request.Option) error {
+	fn(m.ListFunctionsResponse, true)
+	return m.err
+}
+
+func (m *mockLambdaClient) ListProvisionedConcurrencyConfigsPagesWithContext(ctx aws.Context, input *lambda.ListProvisionedConcurrencyConfigsInput, fn func(*lambda.ListProvisionedConcurrencyConfigsOutput, bool) bool, opts ...request.Option) error {
+	fn(m.ListProvisionedConcurrencyConfigsResponses[*input.FunctionName], true)
+	return m.err
+}
+
+func (m *mockLambdaClient) ListEventSourceMappingsPagesWithContext(ctx aws.Context, input *lambda.ListEventSourceMappingsInput, fn func(*lambda.ListEventSourceMappingsOutput, bool) bool, opts ...request.Option) error {
+	fn(m.ListEventSourceMappingsResponse, true)
+	return m.err
+}
+
+func (m *mockLambdaClient) ListFunctionUrlConfigsPagesWithContext(ctx aws.Context, input *lambda.ListFunctionUrlConfigsInput, fn func(*lambda.ListFunctionUrlConfigsOutput, bool) bool, opts ...request.Option) error {
+	fn(m.ListFunctionUrlConfigsResponses[*input.FunctionName], true)
+	return m.err
+}
+
+func TestProvisionedConcurrencyCheckWithError(t *testing.T) {
+	mockClient := &mockLambdaClient{
+		err:                        errors.New("some err"),
+		GetAccountSettingsResponse: nil,
+	}
+
+	check := ProvisionedConcurrencyCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestProvisionedConcurrencyCheck(t *testing.T) {
+	mockClient := &mockLambdaClient{
+		GetAccountSettingsResponse: &lambda.GetAccountSettingsOutput{
+			AccountLimit: &lambda.AccountLimit{ConcurrentExecutions: aws.Int64(1000)},
+		},
+		ListFunctionsResponse: &lambda.ListFunctionsOutput{
+			Functions: []*lambda.FunctionConfiguration{
+				{FunctionName: aws.String("function1")},
+				{FunctionName: aws.String("function2")},
+			},
+		},
+		ListProvisionedConcurrencyConfigsResponses: map[string]*lambda.ListProvisionedConcurrencyConfigsOutput{
+			"function1": {
+				ProvisionedConcurrencyConfigs: []*lambda.ProvisionedConcurrencyConfigListItem{
+					{AllocatedProvisionedConcurrentExecutions: aws.Int64(50)},
+					{AllocatedProvisionedConcurrentExecutions: aws.Int64(25)},
+				},
+			},
+		},
+	}
+
+	check := ProvisionedConcurrencyCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        provisionedConcurrencyAllocatedName,
+			Description: provisionedConcurrencyAllocatedDescription,
+			Usage:       75,
+			Quota:       1000,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestProvisionedConcurrencyCheckWithoutAccountLimit(t *testing.T) {
+	mockClient := &mockLambdaClient{
+		GetAccountSettingsResponse: &lambda.GetAccountSettingsOutput{},
+		ListFunctionsResponse: &lambda.ListFunctionsOutput{
+			Functions: []*lambda.FunctionConfiguration{{FunctionName: aws.String("function1")}},
+		},
+		ListProvisionedConcurrencyConfigsResponses: map[string]*lambda.ListProvisionedConcurrencyConfigsOutput{
+			"function1": {
+				ProvisionedConcurrencyConfigs: []*lambda.ProvisionedConcurrencyConfigListItem{
+					{AllocatedProvisionedConcurrentExecutions: aws.Int64(50)},
+					{},
+				},
+			},
+		},
+	}
+
+	check := ProvisionedConcurrencyCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        provisionedConcurrencyAllocatedName,
+			Description: provisionedConcurrencyAllocatedDescription,
+			Usage:       50,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestEventSourceMappingsCheckWithError(t *testing.T) {
+	mockClient := &mockLambdaClient{
+		err: errors.New("some err"),
+	}
+
+	check := EventSourceMappingsCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestEventSourceMappingsCheck(t *testing.T) {
+	mockClient := &mockLambdaClient{
+		ListEventSourceMappingsResponse: &lambda.ListEventSourceMappingsOutput{
+			EventSourceMappings: []*lambda.EventSourceMappingConfiguration{
+				{UUID: aws.String("mapping-1")},
+				{UUID: aws.String("mapping-2")},
+			},
+		},
+	}
+
+	check := EventSourceMappingsCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        eventSourceMappingsName,
+			Description: eventSourceMappingsDescription,
+			Usage:       2,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestFunctionUrlsCheckWithError(t *testing.T) {
+	mockClient := &mockLambdaClient{
+		err: errors.New("some err"),
+	}
+
+	check := FunctionUrlsCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestFunctionUrlsCheck(t *testing.T) {
+	mockClient := &mockLambdaClient{
+		ListFunctionsResponse: &lambda.ListFunctionsOutput{
+			Functions: []*lambda.FunctionConfiguration{
+				{FunctionName: aws.String("some-function")},
+				{FunctionName: aws.String("other-function")},
+			},
+		},
+		ListFunctionUrlConfigsResponses: map[string]*lambda.ListFunctionUrlConfigsOutput{
+			"some-function": {
+				FunctionUrlConfigs: []*lambda.FunctionUrlConfig{
+					{FunctionUrl: aws.String("https://some.lambda-url.eu-west-1.on.aws/")},
+					{FunctionUrl: aws.String("https://alias.lambda-url.eu-west-1.on.aws/")},
+				},
+			},
+			"other-function": {},
+		},
+	}
+
+	check := FunctionUrlsCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        functionUrlsName,
+			Description: functionUrlsDescription,
+			Usage:       2,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
@@ -1,36 +1,44 @@
 package servicequotas
 
 import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
-	"github.com/pkg/errors"
 )
 
 const (
 	logGroupsPerRegionName        = "log_groups_per_region"
 	logGroupsPerRegionDescription = "log groups per region"
+
+	logStreamsPerLogGroupName        = "log_streams_per_log_group"
+	logStreamsPerLogGroupDescription = "log streams per log group"
 )
 
 type LogGroupsPerRegionCheck struct {
-	client cloudwatchlogsiface.CloudWatchLogsAPI
+	client       cloudwatchlogsiface.CloudWatchLogsAPI
+	maxResources int
 }
 
-func (c *LogGroupsPerRegionCheck) Usage() ([]QuotaUsage, error) {
+func (c *LogGroupsPerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
+	logGroupsCap := &resourceCap{max: c.maxResources}
 
 	var totalLogGroupsCount int
 	params := &cloudwatchlogs.DescribeLogGroupsInput{}
-	err := c.client.DescribeLogGroupsPages(params,
+	err := c.client.DescribeLogGroupsPagesWithContext(ctx, params,
 		func(page *cloudwatchlogs.DescribeLogGroupsOutput, lastPage bool) bool {
 			if page != nil {
 				pageLogGroupsCount := len(page.LogGroups)
 				totalLogGroupsCount += pageLogGroupsCount
+				return logGroupsCap.add(pageLogGroupsCount, lastPage)
 			}
 			return !lastPage
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 	usage := QuotaUsage{
 		Name:        logGroupsPerRegionName,
@@ -38,5 +46,87 @@ func (c *LogGroupsPerRegionCheck) Usage() ([]QuotaUsage, error) {
 		Usage:       float64(totalLogGroupsCount),
 	}
 	quotaUsages = append(quotaUsages, usage)
+	logGroupsCap.markTruncated(quotaUsages)
 	return quotaUsages, nil
 }
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *LogGroupsPerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: logGroupsPerRegionName, Description: logGroupsPerRegionDescription}}
+}
+
+// LogStreamsPerLogGroupCheck implements the UsageCheck interface for
+// the log streams of each CloudWatch Logs log group. Only the log
+// groups whose names start with `logGroupPrefix` are checked, as the
+// log streams of every group have to be paged through
+type LogStreamsPerLogGroupCheck struct {
+	client         cloudwatchlogsiface.CloudWatchLogsAPI
+	logGroupPrefix string
+	maxResources   int
+}
+
+// Usage returns the number of log streams for each log group or an
+// error. The quota is not published by Service Quotas so no limit is
+// reported
+// The maximum number of resources is shared between all log groups,
+// the remaining log groups are skipped once it has been reached
+func (c *LogStreamsPerLogGroupCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	quotaUsages := []QuotaUsage{}
+	logStreamsCap := &resourceCap{max: c.maxResources}
+
+	var logGroupNames []*string
+	params := &cloudwatchlogs.DescribeLogGroupsInput{}
+	if c.logGroupPrefix != "" {
+		params.LogGroupNamePrefix = aws.String(c.logGroupPrefix)
+	}
+	err := c.client.DescribeLogGroupsPagesWithContext(ctx, params,
+		func(page *cloudwatchlogs.DescribeLogGroupsOutput, lastPage bool) bool {
+			if page != nil {
+				for _, logGroup := range page.LogGroups {
+					logGroupNames = append(logGroupNames, logGroup.LogGroupName)
+				}
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	for _, logGroupName := range logGroupNames {
+		if logStreamsCap.reached() {
+			logStreamsCap.truncated = true
+			break
+		}
+
+		var logStreamsCount int
+		streamsParams := &cloudwatchlogs.DescribeLogStreamsInput{LogGroupName: logGroupName}
+		err := c.client.DescribeLogStreamsPagesWithContext(ctx, streamsParams,
+			func(page *cloudwatchlogs.DescribeLogStreamsOutput, lastPage bool) bool {
+				if page != nil {
+					logStreamsCount += len(page.LogStreams)
+					return logStreamsCap.add(len(page.LogStreams), lastPage)
+				}
+				return !lastPage
+			},
+		)
+		if err != nil {
+			return nil, wrapUsageErr(err)
+		}
+
+		usage := QuotaUsage{
+			Name:         logStreamsPerLogGroupName,
+			Description:  logStreamsPerLogGroupDescription,
+			ResourceName: logGroupName,
+			Usage:        float64(logStreamsCount),
+		}
+		quotaUsages = append(quotaUsages, usage)
+	}
+	logStreamsCap.markTruncated(quotaUsages)
+	return quotaUsages, nil
+}
+
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *LogStreamsPerLogGroupCheck) DescribeResourceUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: logStreamsPerLogGroupName, Description: logStreamsPerLogGroupDescription}}
+}
@@ -0,0 +1,79 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockCloudWatchLogsClient) DescribeLogGroupsPagesWithContext(ctx aws.Context, input *cloudwatchlogs.DescribeLogGroupsInput, fn func(*cloudwatchlogs.DescribeLogGroupsOutput, bool) bool, opts ...request.Option) error {
+	m.DescribeLogGroupsInput = input
+	fn(m.DescribeLogGroupsResponse, true)
+	return m.err
+}
+
+func (m *mockCloudWatchLogsClient) DescribeLogStreamsPagesWithContext(ctx aws.Context, input *cloudwatchlogs.DescribeLogStreamsInput, fn func(*cloudwatchlogs.DescribeLogStreamsOutput, bool) bool, opts ...request.Option) error {
+	fn(m.DescribeLogStreamsResponses[*input.LogGroupName], true)
+	return m.err
+}
+
+func TestLogStreamsPerLogGroupCheckWithError(t *testing.T) {
+	mockClient := &mockCloudWatchLogsClient{
+		err:                       errors.New("some err"),
+		DescribeLogGroupsResponse: nil,
+	}
+
+	check := LogStreamsPerLogGroupCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestLogStreamsPerLogGroupCheck(t *testing.T) {
+	mockClient := &mockCloudWatchLogsClient{
+		DescribeLogGroupsResponse: &cloudwatchlogs.DescribeLogGroupsOutput{
+			LogGroups: []*cloudwatchlogs.LogGroup{
+				{LogGroupName: aws.String("/app/group1")},
+				{LogGroupName: aws.String("/app/group2")},
+			},
+		},
+		DescribeLogStreamsResponses: map[string]*cloudwatchlogs.DescribeLogStreamsOutput{
+			"/app/group1": {
+				LogStreams: []*cloudwatchlogs.LogStream{
+					{LogStreamName: aws.String("stream1")},
+					{LogStreamName: aws.String("stream2")},
+				},
+			},
+			"/app/group2": {},
+		},
+	}
+
+	check := LogStreamsPerLogGroupCheck{client: mockClient, logGroupPrefix: "/app/"}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:         logStreamsPerLogGroupName,
+			Description:  logStreamsPerLogGroupDescription,
+			ResourceName: aws.String("/app/group1"),
+			Usage:        2,
+		},
+		{
+			Name:         logStreamsPerLogGroupName,
+			Description:  logStreamsPerLogGroupDescription,
+			ResourceName: aws.String("/app/group2"),
+			Usage:        0,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+	assert.Equal(t, aws.String("/app/"), mockClient.DescribeLogGroupsInput.LogGroupNamePrefix)
+}
@@ -0,0 +1,13 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/acmpca"
+	"github.com/aws/aws-sdk-go/service/acmpca/acmpcaiface"
+)
+
+type mockACMPCAClient struct {
+	acmpcaiface.ACMPCAAPI
+
+	err                                error
+	ListCertificateAuthoritiesResponse *acmpca.ListCertificateAuthoritiesOutput
+}
@@ -0,0 +1,26 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/aws/aws-sdk-go/service/apigateway/apigatewayiface"
+	"github.com/aws/aws-sdk-go/service/apigatewayv2"
+	"github.com/aws/aws-sdk-go/service/apigatewayv2/apigatewayv2iface"
+)
+
+type mockAPIGatewayClient struct {
+	apigatewayiface.APIGatewayAPI
+
+	err                  error
+	GetRestApisResponses []*apigateway.GetRestApisOutput
+	// GetStagesResponses holds the stages of each REST API ID
+	GetStagesResponses map[string]*apigateway.GetStagesOutput
+}
+
+type mockAPIGatewayV2Client struct {
+	apigatewayv2iface.ApiGatewayV2API
+
+	err error
+	// GetApisResponses holds the response for each NextToken, the
+	// first page is stored under the empty token
+	GetApisResponses map[string]*apigatewayv2.GetApisOutput
+}
@@ -0,0 +1,14 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/aws/aws-sdk-go/service/appmesh/appmeshiface"
+)
+
+type mockAppMeshClient struct {
+	appmeshiface.AppMeshAPI
+
+	err                       error
+	ListMeshesResponse        *appmesh.ListMeshesOutput
+	ListVirtualNodesResponses map[string]*appmesh.ListVirtualNodesOutput
+}
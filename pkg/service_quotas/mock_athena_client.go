@@ -0,0 +1,25 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/athena"
+	"github.com/aws/aws-sdk-go/service/athena/athenaiface"
+)
+
+type mockAthenaClient struct {
+	athenaiface.AthenaAPI
+
+	err                    error
+	ListWorkGroupsResponse *athena.ListWorkGroupsOutput
+	// ListQueryExecutionsResponses holds the pages of query executions
+	// of each workgroup
+	ListQueryExecutionsResponses map[string][]*athena.ListQueryExecutionsOutput
+	// QueryExecutions holds the query executions returned by
+	// BatchGetQueryExecution by ID
+	QueryExecutions map[string]*athena.QueryExecution
+	// BatchGetQueryExecutionInputs records the input of each
+	// BatchGetQueryExecution call
+	BatchGetQueryExecutionInputs []*athena.BatchGetQueryExecutionInput
+	// BatchGetQueryExecutionErr is returned by BatchGetQueryExecution
+	// only
+	BatchGetQueryExecutionErr error
+}
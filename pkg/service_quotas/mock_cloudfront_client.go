@@ -0,0 +1,13 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go/service/cloudfront/cloudfrontiface"
+)
+
+type mockCloudFrontClient struct {
+	cloudfrontiface.CloudFrontAPI
+
+	err                        error
+	ListDistributionsResponses []*cloudfront.ListDistributionsOutput
+}
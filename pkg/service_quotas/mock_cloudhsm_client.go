@@ -0,0 +1,13 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/cloudhsmv2"
+	"github.com/aws/aws-sdk-go/service/cloudhsmv2/cloudhsmv2iface"
+)
+
+type mockCloudHSMClient struct {
+	cloudhsmv2iface.CloudHSMV2API
+
+	err                      error
+	DescribeClustersResponse *cloudhsmv2.DescribeClustersOutput
+}
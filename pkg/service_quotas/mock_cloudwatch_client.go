@@ -0,0 +1,14 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+)
+
+type mockCloudWatchClient struct {
+	cloudwatchiface.CloudWatchAPI
+
+	err                         error
+	GetMetricStatisticsResponse *cloudwatch.GetMetricStatisticsOutput
+	GetMetricStatisticsInput    *cloudwatch.GetMetricStatisticsInput
+}
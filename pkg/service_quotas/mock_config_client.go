@@ -0,0 +1,13 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/configservice"
+	"github.com/aws/aws-sdk-go/service/configservice/configserviceiface"
+)
+
+type mockConfigClient struct {
+	configserviceiface.ConfigServiceAPI
+
+	err                          error
+	DescribeConfigRulesResponses []*configservice.DescribeConfigRulesOutput
+}
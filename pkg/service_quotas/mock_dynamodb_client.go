@@ -0,0 +1,16 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+type mockDynamoDBClient struct {
+	dynamodbiface.DynamoDBAPI
+
+	err                error
+	ListTablesResponse *dynamodb.ListTablesOutput
+	// DescribeTableResponses holds the describe response for each
+	// table name
+	DescribeTableResponses map[string]*dynamodb.DescribeTableOutput
+}
@@ -8,10 +8,26 @@ import (
 type mockEC2Client struct {
 	ec2iface.EC2API
 
-	err                               error
-	DescribeSecurityGroupsResponse    *ec2.DescribeSecurityGroupsOutput
-	DescribeNetworkInterfacesResponse *ec2.DescribeNetworkInterfacesOutput
-	InstancesFilters                  []*ec2.Filter
-	DescribeInstancesResponse         *ec2.DescribeInstancesOutput
-	DescribeSubnetsResponse           *ec2.DescribeSubnetsOutput
+	err                                  error
+	DescribeSecurityGroupsResponse       *ec2.DescribeSecurityGroupsOutput
+	DescribeNetworkInterfacesResponse    *ec2.DescribeNetworkInterfacesOutput
+	InstancesFilters                     []*ec2.Filter
+	DescribeInstancesResponse            *ec2.DescribeInstancesOutput
+	DescribeSubnetsResponse              *ec2.DescribeSubnetsOutput
+	DescribeSnapshotsResponses           []*ec2.DescribeSnapshotsOutput
+	DescribeInstanceTypesResponse        *ec2.DescribeInstanceTypesOutput
+	DescribeInstanceTypesErr             error
+	InstanceTypes                        []*string
+	SpotInstanceRequestsFilters          []*ec2.Filter
+	DescribeSpotInstanceRequestsResponse *ec2.DescribeSpotInstanceRequestsOutput
+	DescribeManagedPrefixListsResponse   *ec2.DescribeManagedPrefixListsOutput
+	FastSnapshotRestoresFilters          []*ec2.Filter
+	DescribeFastSnapshotRestoresResponse *ec2.DescribeFastSnapshotRestoresOutput
+	VolumesFilters                       []*ec2.Filter
+	DescribeVolumesResponse              *ec2.DescribeVolumesOutput
+	DescribeVpnGatewaysResponse          *ec2.DescribeVpnGatewaysOutput
+	DescribeCustomerGatewaysResponse     *ec2.DescribeCustomerGatewaysOutput
+	// GetManagedPrefixListEntriesResponses holds the entries response
+	// for each prefix list ID
+	GetManagedPrefixListEntriesResponses map[string]*ec2.GetManagedPrefixListEntriesOutput
 }
@@ -0,0 +1,21 @@
+package servicequotas
+
+import (
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+type mockEC2V2Client struct {
+	ec2V2API
+
+	err error
+	// DescribeVolumesResponses holds the response for each NextToken,
+	// the first page is stored under the empty token
+	DescribeVolumesResponses map[string]*ec2v2.DescribeVolumesOutput
+	DescribeVolumesInputs    []*ec2v2.DescribeVolumesInput
+	// DescribeInstancesResponses holds the response for each
+	// NextToken, the first page is stored under the empty token
+	DescribeInstancesResponses     map[string]*ec2v2.DescribeInstancesOutput
+	DescribeInstanceTypesResponse  *ec2v2.DescribeInstanceTypesOutput
+	DescribeSecurityGroupsResponse *ec2v2.DescribeSecurityGroupsOutput
+	DescribeVpnGatewaysResponse    *ec2v2.DescribeVpnGatewaysOutput
+}
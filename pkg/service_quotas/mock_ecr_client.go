@@ -0,0 +1,18 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
+)
+
+type mockECRClient struct {
+	ecriface.ECRAPI
+
+	err                          error
+	DescribeRepositoriesResponse *ecr.DescribeRepositoriesOutput
+	// DescribeRepositoriesPagesResponses are returned as separate pages
+	// instead of DescribeRepositoriesResponse when set
+	DescribeRepositoriesPagesResponses []*ecr.DescribeRepositoriesOutput
+	ListImagesResponses                map[string]*ecr.ListImagesOutput
+	ListImagesFilters                  []*ecr.ListImagesFilter
+}
@@ -0,0 +1,17 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
+)
+
+type mockECSClient struct {
+	ecsiface.ECSAPI
+
+	err                   error
+	ListClustersResponse  *ecs.ListClustersOutput
+	ListTasksResponses    map[string]*ecs.ListTasksOutput
+	DescribeTasksResponse map[string]*ecs.DescribeTasksOutput
+	ListTasksFilters      []*ecs.ListTasksInput
+	ListServicesResponses map[string]*ecs.ListServicesOutput
+}
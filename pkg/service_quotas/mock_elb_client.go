@@ -0,0 +1,27 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+)
+
+type mockELBV2Client struct {
+	elbv2iface.ELBV2API
+
+	err                           error
+	DescribeLoadBalancersResponse *elbv2.DescribeLoadBalancersOutput
+	DescribeTargetGroupsResponse  *elbv2.DescribeTargetGroupsOutput
+	// Tags holds the tags of each load balancer ARN
+	Tags map[string][]*elbv2.Tag
+	// DescribeTagsInputs records the input of each call
+	DescribeTagsInputs []*elbv2.DescribeTagsInput
+}
+
+type mockELBClient struct {
+	elbiface.ELBAPI
+
+	err                           error
+	DescribeLoadBalancersResponse *elb.DescribeLoadBalancersOutput
+}
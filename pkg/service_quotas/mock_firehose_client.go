@@ -0,0 +1,16 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/firehose/firehoseiface"
+)
+
+type mockFirehoseClient struct {
+	firehoseiface.FirehoseAPI
+
+	err error
+	// ListDeliveryStreamsResponses are returned in order, one per call
+	ListDeliveryStreamsResponses []*firehose.ListDeliveryStreamsOutput
+	// ListDeliveryStreamsInputs records the input of each call
+	ListDeliveryStreamsInputs []*firehose.ListDeliveryStreamsInput
+}
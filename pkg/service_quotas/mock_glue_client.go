@@ -0,0 +1,42 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/glue/glueiface"
+)
+
+type mockGlueClient struct {
+	glueiface.GlueAPI
+
+	err                  error
+	ListJobsResponse     *glue.ListJobsOutput
+	GetJobsResponse      *glue.GetJobsOutput
+	GetJobRunsResponses  map[string][]*glue.GetJobRunsOutput
+	GetJobRunsMaxResults *int64
+	GetJobRunsPagesRead  map[string]int
+	// GetJobRunsErr is returned by GetJobRunsPages only
+	GetJobRunsErr            error
+	ListTriggersResponse     *glue.ListTriggersOutput
+	BatchGetTriggersResponse *glue.BatchGetTriggersOutput
+	// Triggers holds the triggers returned by BatchGetTriggers by
+	// name when BatchGetTriggersResponse is not set
+	Triggers map[string]*glue.Trigger
+	// BatchGetTriggersInputs records the input of each BatchGetTriggers
+	// call
+	BatchGetTriggersInputs []*glue.BatchGetTriggersInput
+	ListSessionsResponse   *glue.ListSessionsOutput
+	ListBlueprintsResponse *glue.ListBlueprintsOutput
+	// GetBlueprintRunsResponses holds the runs response for each
+	// blueprint name
+	GetBlueprintRunsResponses map[string]*glue.GetBlueprintRunsOutput
+	// GetSecurityConfigurationsResponse is the security configurations
+	// response
+	GetSecurityConfigurationsResponse *glue.GetSecurityConfigurationsOutput
+	ListCrawlersResponse              *glue.ListCrawlersOutput
+	// CrawlerStates holds the state of the crawlers returned by
+	// BatchGetCrawlers by name
+	CrawlerStates map[string]string
+	// BatchGetCrawlersInputs records the input of each BatchGetCrawlers
+	// call
+	BatchGetCrawlersInputs []*glue.BatchGetCrawlersInput
+}
@@ -0,0 +1,14 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/imagebuilder"
+	"github.com/aws/aws-sdk-go/service/imagebuilder/imagebuilderiface"
+)
+
+type mockImagebuilderClient struct {
+	imagebuilderiface.ImagebuilderAPI
+
+	err                         error
+	ListImagePipelinesResponses []*imagebuilder.ListImagePipelinesOutput
+	ListImageRecipesResponses   []*imagebuilder.ListImageRecipesOutput
+}
@@ -0,0 +1,19 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/kinesisanalyticsv2"
+	"github.com/aws/aws-sdk-go/service/kinesisanalyticsv2/kinesisanalyticsv2iface"
+)
+
+type mockKDAClient struct {
+	kinesisanalyticsv2iface.KinesisAnalyticsV2API
+
+	err error
+	// ListApplicationsResponses holds the response for each NextToken,
+	// the first page is stored under the empty token
+	ListApplicationsResponses map[string]*kinesisanalyticsv2.ListApplicationsOutput
+	ListApplicationsCalls     int
+	// DescribeApplicationResponses holds the response for each
+	// application name
+	DescribeApplicationResponses map[string]*kinesisanalyticsv2.DescribeApplicationOutput
+}
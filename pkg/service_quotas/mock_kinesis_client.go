@@ -0,0 +1,18 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kinesis/kinesisiface"
+)
+
+type mockKinesisClient struct {
+	kinesisiface.KinesisAPI
+
+	err                  error
+	ListStreamsResponses []*kinesis.ListStreamsOutput
+	// OpenShardCounts holds the open shards of each stream name, the
+	// streams missing from it are not found
+	OpenShardCounts map[string]int64
+	// DescribeStreamSummaryErr is returned by DescribeStreamSummary
+	DescribeStreamSummaryErr error
+}
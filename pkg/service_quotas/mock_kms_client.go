@@ -0,0 +1,16 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+)
+
+type mockKMSClient struct {
+	kmsiface.KMSAPI
+
+	err               error
+	ListKeysResponses []*kms.ListKeysOutput
+	// KeyManagers holds the key manager of each key ID
+	KeyManagers      map[string]string
+	DescribeKeyCalls int
+}
@@ -0,0 +1,17 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+)
+
+type mockLambdaClient struct {
+	lambdaiface.LambdaAPI
+
+	err                                        error
+	GetAccountSettingsResponse                 *lambda.GetAccountSettingsOutput
+	ListFunctionsResponse                      *lambda.ListFunctionsOutput
+	ListProvisionedConcurrencyConfigsResponses map[string]*lambda.ListProvisionedConcurrencyConfigsOutput
+	ListEventSourceMappingsResponse            *lambda.ListEventSourceMappingsOutput
+	ListFunctionUrlConfigsResponses            map[string]*lambda.ListFunctionUrlConfigsOutput
+}
@@ -0,0 +1,15 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+)
+
+type mockCloudWatchLogsClient struct {
+	cloudwatchlogsiface.CloudWatchLogsAPI
+
+	err                         error
+	DescribeLogGroupsResponse   *cloudwatchlogs.DescribeLogGroupsOutput
+	DescribeLogGroupsInput      *cloudwatchlogs.DescribeLogGroupsInput
+	DescribeLogStreamsResponses map[string]*cloudwatchlogs.DescribeLogStreamsOutput
+}
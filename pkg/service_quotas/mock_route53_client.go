@@ -0,0 +1,28 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+)
+
+type mockRoute53Client struct {
+	route53iface.Route53API
+
+	err                      error
+	ListHealthChecksResponse *route53.ListHealthChecksOutput
+	// ListTrafficPoliciesResponses are returned in order, one per call
+	ListTrafficPoliciesResponses []*route53.ListTrafficPoliciesOutput
+	// ListTrafficPoliciesInputs records the input of each call
+	ListTrafficPoliciesInputs []*route53.ListTrafficPoliciesInput
+	// AccountLimits holds the value of each account limit type
+	AccountLimits map[string]int64
+	// AccountLimitErr is returned by GetAccountLimit only
+	AccountLimitErr         error
+	ListHostedZonesResponse *route53.ListHostedZonesOutput
+	// HostedZoneLimits holds the record limit of each hosted zone ID
+	HostedZoneLimits map[string]int64
+	// HostedZoneLimitInputs records the input of each call
+	HostedZoneLimitInputs []*route53.GetHostedZoneLimitInput
+	// HostedZoneLimitErr is returned by GetHostedZoneLimit only
+	HostedZoneLimitErr error
+}
@@ -0,0 +1,17 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+type mockS3Client struct {
+	s3iface.S3API
+
+	err                 error
+	ListBucketsResponse *s3.ListBucketsOutput
+	// GetBucketTaggingResponses holds the tagging response for each
+	// bucket name, the buckets without a response have no tags
+	GetBucketTaggingResponses map[string]*s3.GetBucketTaggingOutput
+	GetBucketTaggingCalls     int
+}
@@ -0,0 +1,25 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/s3control"
+	"github.com/aws/aws-sdk-go/service/s3control/s3controliface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+)
+
+type mockS3ControlClient struct {
+	s3controliface.S3ControlAPI
+
+	err                                 error
+	accountID                           *string
+	ListAccessPointsResponse            *s3control.ListAccessPointsOutput
+	ListMultiRegionAccessPointsResponse *s3control.ListMultiRegionAccessPointsOutput
+}
+
+type mockSTSClient struct {
+	stsiface.STSAPI
+
+	err                       error
+	GetCallerIdentityResponse *sts.GetCallerIdentityOutput
+	GetCallerIdentityCalls    int
+}
@@ -0,0 +1,18 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+)
+
+type mockSNSClient struct {
+	snsiface.SNSAPI
+
+	err                 error
+	ListTopicsResponses []*sns.ListTopicsOutput
+	// ListSubscriptionsByTopicResponses holds the subscriptions of each
+	// topic ARN
+	ListSubscriptionsByTopicResponses map[string]*sns.ListSubscriptionsByTopicOutput
+	// ListSubscriptionsByTopicInputs records the input of each call
+	ListSubscriptionsByTopicInputs []*sns.ListSubscriptionsByTopicInput
+}
@@ -0,0 +1,18 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+)
+
+type mockSQSClient struct {
+	sqsiface.SQSAPI
+
+	err                 error
+	ListQueuesResponses []*sqs.ListQueuesOutput
+	ListQueuesInput     *sqs.ListQueuesInput
+	// ListQueueTagsResponses holds the tags of each queue URL, the
+	// other queues have no tags
+	ListQueueTagsResponses map[string]*sqs.ListQueueTagsOutput
+	ListQueueTagsCalls     int
+}
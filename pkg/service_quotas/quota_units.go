@@ -0,0 +1,53 @@
+package servicequotas
+
+// Units of the usages and limits of the quotas
+const (
+	// UnitCount is the unit of the quotas on a number of resources or
+	// operations, which is the case of most quotas. Their usages have
+	// no unit
+	UnitCount = "count"
+	UnitTiB   = "TiB"
+	UnitIOPS  = "IOPS"
+	UnitVCPUs = "vCPUs"
+	UnitDPUs  = "DPUs"
+	UnitKPUs  = "KPUs"
+	// UnitCapacityUnits is the unit of the DynamoDB read and write
+	// capacity quotas
+	UnitCapacityUnits = "capacity_units"
+)
+
+// quotaUnits holds the unit of the usage and limit of the quotas by
+// quota name. The quotas missing from it are counts, see UnitCount. The
+// usage of each quota is converted by its check to the unit AWS uses
+// for its limit, e.g. the EBS volume sizes in GiB to TiB
+var quotaUnits = map[string]string{
+	spotInstanceRequestsName:        UnitVCPUs,
+	onDemandInstanceRequestsName:    UnitVCPUs,
+	maxGp2StoragePerRegionName:      UnitTiB,
+	maxGp3StoragePerRegionName:      UnitTiB,
+	maxIo1StoragePerRegionName:      UnitTiB,
+	maxIo2StoragePerRegionName:      UnitTiB,
+	maxSt1StoragePerRegionName:      UnitTiB,
+	maxSc1StoragePerRegionName:      UnitTiB,
+	maxStandardStoragePerRegionName: UnitTiB,
+	maxIo1IopsPerRegionName:         UnitIOPS,
+	maxIo2IopsPerRegionName:         UnitIOPS,
+	fargateOnDemandVCPUsName:        UnitVCPUs,
+	fargateSpotVCPUsName:            UnitVCPUs,
+	dPUsName:                        UnitDPUs,
+	activeDPUsName:                  UnitDPUs,
+	flinkKPUsPerAppName:             UnitKPUs,
+	readCapacityPerTableName:        UnitCapacityUnits,
+	writeCapacityPerTableName:       UnitCapacityUnits,
+}
+
+// withUnits sets the unit of the usages of the quotas in quotaUnits
+// that have none. The counts are left without a unit
+func withUnits(usages []QuotaUsage) []QuotaUsage {
+	for i, usage := range usages {
+		if unit, ok := quotaUnits[usage.Name]; ok && usage.Unit == "" {
+			usages[i].Unit = unit
+		}
+	}
+	return usages
+}
@@ -0,0 +1,85 @@
+package servicequotas
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithUnits(t *testing.T) {
+	usages := []QuotaUsage{
+		{Name: maxGp3StoragePerRegionName},
+		{Name: maxIo1IopsPerRegionName},
+		{Name: spotInstanceRequestsName},
+		{Name: dPUsName},
+		{Name: readCapacityPerTableName},
+		{Name: vpnGatewaysPerRegionName},
+		{Name: "some_quota", Unit: UnitTiB},
+	}
+
+	expectedUnits := []string{UnitTiB, UnitIOPS, UnitVCPUs, UnitDPUs, UnitCapacityUnits, "", UnitTiB}
+
+	units := []string{}
+	for _, usage := range withUnits(usages) {
+		units = append(units, usage.Unit)
+	}
+	assert.Equal(t, expectedUnits, units)
+}
+
+func TestQuotasAndUsageUnits(t *testing.T) {
+	ec2Client := &mockEC2Client{
+		DescribeVolumesResponse: &ec2.DescribeVolumesOutput{
+			Volumes: []*ec2.Volume{{Size: aws.Int64(2048)}},
+		},
+		DescribeVpnGatewaysResponse: &ec2.DescribeVpnGatewaysOutput{
+			VpnGateways: []*ec2.VpnGateway{{VpnGatewayId: aws.String("vgw-1")}},
+		},
+	}
+	glueClient := &mockGlueClient{
+		ListJobsResponse: &glue.ListJobsOutput{JobNames: []*string{aws.String("job1")}},
+		GetJobRunsResponses: map[string][]*glue.GetJobRunsOutput{
+			"job1": {{JobRuns: []*glue.JobRun{{JobRunState: aws.String(glue.JobRunStateRunning), MaxCapacity: aws.Float64(10)}}}},
+		},
+	}
+
+	serviceQuotas := ServiceQuotas{
+		quotasService: &mockServiceQuotasClient{},
+		otherUsageChecks: []UsageCheck{
+			&MaxGP3StoragePerRegionCheck{ec2Client},
+			&ActiveDPUsCheck{client: glueClient},
+			&VpnGatewaysPerRegionCheck{ec2Client},
+		},
+	}
+
+	quotas, err := serviceQuotas.QuotasAndUsage()
+	assert.NoError(t, err)
+
+	usages := map[string]QuotaUsage{}
+	for _, quota := range quotas {
+		usages[quota.Name] = quota
+	}
+	// the usage of the storage is in the unit of its limit
+	assert.Equal(t, float64(2), usages[maxGp3StoragePerRegionName].Usage)
+	assert.Equal(t, UnitTiB, usages[maxGp3StoragePerRegionName].Unit)
+	assert.Equal(t, float64(10), usages[activeDPUsName].Usage)
+	assert.Equal(t, UnitDPUs, usages[activeDPUsName].Unit)
+	assert.Equal(t, "", usages[vpnGatewaysPerRegionName].Unit)
+}
+
+func TestDescribeQuotasUnits(t *testing.T) {
+	serviceQuotas := ServiceQuotas{
+		otherUsageChecks: []UsageCheck{
+			&MaxGP3StoragePerRegionCheck{},
+			&VpnGatewaysPerRegionCheck{},
+		},
+	}
+
+	expectedQuotas := []QuotaUsage{
+		{Name: maxGp3StoragePerRegionName, Description: maxGp3StoragePerRegionDescription, Unit: UnitTiB},
+		{Name: vpnGatewaysPerRegionName, Description: vpnGatewaysPerRegionDescription},
+	}
+	assert.Equal(t, expectedQuotas, serviceQuotas.DescribeQuotas())
+}
@@ -1,9 +1,10 @@
 package servicequotas
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/service/rds"
 	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
-	"github.com/pkg/errors"
 )
 
 const (
@@ -18,11 +19,11 @@ type ReadReplicasPerMasterCheck struct {
 	client rdsiface.RDSAPI
 }
 
-func (c *ReadReplicasPerMasterCheck) Usage() ([]QuotaUsage, error) {
+func (c *ReadReplicasPerMasterCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
 
 	params := &rds.DescribeDBClustersInput{}
-	err := c.client.DescribeDBClustersPages(params,
+	err := c.client.DescribeDBClustersPagesWithContext(ctx, params,
 		func(page *rds.DescribeDBClustersOutput, lastPage bool) bool {
 			if page != nil {
 				for _, group := range page.DBClusters {
@@ -49,23 +50,28 @@ func (c *ReadReplicasPerMasterCheck) Usage() ([]QuotaUsage, error) {
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 
 	return quotaUsages, nil
 }
 
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *ReadReplicasPerMasterCheck) DescribeResourceUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: numReadReplicasPerMasterName, Description: numReadReplicasPerMasterDescription}}
+}
+
 type MaxTotalStorageCheck struct {
 	client rdsiface.RDSAPI
 }
 
-func (c *MaxTotalStorageCheck) Usage() ([]QuotaUsage, error) {
+func (c *MaxTotalStorageCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotasUsage := []QuotaUsage{}
 
 	var totalStorageCount int64
 
 	params := &rds.DescribeDBInstancesInput{}
-	err := c.client.DescribeDBInstancesPages(params,
+	err := c.client.DescribeDBInstancesPagesWithContext(ctx, params,
 		func(page *rds.DescribeDBInstancesOutput, lastPage bool) bool {
 			if page != nil {
 				for _, instance := range page.DBInstances {
@@ -76,7 +82,7 @@ func (c *MaxTotalStorageCheck) Usage() ([]QuotaUsage, error) {
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 
 	usage := QuotaUsage{
@@ -89,3 +95,8 @@ func (c *MaxTotalStorageCheck) Usage() ([]QuotaUsage, error) {
 
 	return quotasUsage, nil
 }
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *MaxTotalStorageCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: MaxTotalStorageCheckName, Description: MaxTotalStorageCheckDescription}}
+}
@@ -0,0 +1,114 @@
+package servicequotas
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+	awsservicequotas "github.com/aws/aws-sdk-go/service/servicequotas"
+)
+
+// reportedUsageWindow is how far back the datapoints of the usage
+// metrics are looked up. AWS publishes most of them every minute, some
+// only every few minutes
+const reportedUsageWindow = 15 * time.Minute
+
+// reportedUsagePeriod is the period of the datapoints of the usage
+// metrics
+const reportedUsagePeriod = 60
+
+// defaultUsageMetricStatistic is used for the usage metrics without a
+// recommended statistic
+const defaultUsageMetricStatistic = cloudwatch.StatisticMaximum
+
+// reportedUsage returns the latest value of the CloudWatch usage metric
+// `metric` of a quota or nil if it has no datapoint in the last
+// `reportedUsageWindow`, or an error
+func reportedUsage(ctx context.Context, client cloudwatchiface.CloudWatchAPI, metric *awsservicequotas.MetricInfo, now time.Time) (*float64, error) {
+	statistic := aws.StringValue(metric.MetricStatisticRecommendation)
+	if statistic == "" {
+		statistic = defaultUsageMetricStatistic
+	}
+
+	dimensions := []*cloudwatch.Dimension{}
+	for name, value := range metric.MetricDimensions {
+		dimensions = append(dimensions, &cloudwatch.Dimension{Name: aws.String(name), Value: value})
+	}
+
+	params := &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  metric.MetricNamespace,
+		MetricName: metric.MetricName,
+		Dimensions: dimensions,
+		StartTime:  aws.Time(now.Add(-reportedUsageWindow)),
+		EndTime:    aws.Time(now),
+		Period:     aws.Int64(reportedUsagePeriod),
+		Statistics: []*string{aws.String(statistic)},
+	}
+	response, err := client.GetMetricStatisticsWithContext(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *cloudwatch.Datapoint
+	for _, datapoint := range response.Datapoints {
+		if latest == nil || aws.TimeValue(datapoint.Timestamp).After(aws.TimeValue(latest.Timestamp)) {
+			latest = datapoint
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+
+	var value *float64
+	switch statistic {
+	case cloudwatch.StatisticAverage:
+		value = latest.Average
+	case cloudwatch.StatisticMinimum:
+		value = latest.Minimum
+	case cloudwatch.StatisticSum:
+		value = latest.Sum
+	case cloudwatch.StatisticSampleCount:
+		value = latest.SampleCount
+	default:
+		value = latest.Maximum
+	}
+	return value, nil
+}
+
+// withReportedUsage sets the usage reported by AWS for `quota` on the
+// usages of the whole quota in `usages`. The per-resource usages are
+// left unchanged, as the usage metrics are for the whole quota. A
+// failure to retrieve the reported usage is logged and does not fail
+// the check
+func (s *ServiceQuotas) withReportedUsage(ctx context.Context, quota *awsservicequotas.ServiceQuota, usages []QuotaUsage) []QuotaUsage {
+	if s.cloudwatchClient == nil || quota.UsageMetric == nil {
+		return usages
+	}
+
+	value, err := reportedUsage(ctx, s.cloudwatchClient, quota.UsageMetric, s.clockOrNow())
+	if err != nil {
+		log.Warnf("Failed to get the usage reported by AWS for quota %s: %s", aws.StringValue(quota.QuotaCode), err)
+		return usages
+	}
+	if value == nil {
+		return usages
+	}
+
+	for i := range usages {
+		if usages[i].ResourceName == nil {
+			usages[i].ReportedUsage = aws.Float64(*value)
+		}
+	}
+	return usages
+}
+
+// clockOrNow returns the time of the clock of `s` or the current time
+// if no clock is set
+func (s *ServiceQuotas) clockOrNow() time.Time {
+	if s.clock == nil {
+		return time.Now()
+	}
+	return s.clock()
+}
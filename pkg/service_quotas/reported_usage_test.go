@@ -0,0 +1,126 @@
+package servicequotas
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	awsservicequotas "github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockCloudWatchClient) GetMetricStatisticsWithContext(ctx aws.Context, input *cloudwatch.GetMetricStatisticsInput, opts ...request.Option) (*cloudwatch.GetMetricStatisticsOutput, error) {
+	m.GetMetricStatisticsInput = input
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.GetMetricStatisticsResponse, nil
+}
+
+func usageMetricQuota() *awsservicequotas.ServiceQuota {
+	return &awsservicequotas.ServiceQuota{
+		QuotaCode: aws.String("L-1234"),
+		Value:     aws.Float64(15),
+		UsageMetric: &awsservicequotas.MetricInfo{
+			MetricNamespace:               aws.String("AWS/Usage"),
+			MetricName:                    aws.String("ResourceCount"),
+			MetricDimensions:              map[string]*string{"Resource": aws.String("vCPU")},
+			MetricStatisticRecommendation: aws.String(cloudwatch.StatisticMaximum),
+		},
+	}
+}
+
+func TestQuotasAndUsageWithReportedUsage(t *testing.T) {
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	mockClient := &mockServiceQuotasClient{
+		serviceName: "ec2",
+		ListServiceQuotasResponse: &awsservicequotas.ListServiceQuotasOutput{
+			Quotas: []*awsservicequotas.ServiceQuota{usageMetricQuota()},
+		},
+	}
+	mockCloudWatch := &mockCloudWatchClient{
+		GetMetricStatisticsResponse: &cloudwatch.GetMetricStatisticsOutput{
+			Datapoints: []*cloudwatch.Datapoint{
+				{Timestamp: aws.Time(now.Add(-2 * time.Minute)), Maximum: aws.Float64(3)},
+				{Timestamp: aws.Time(now.Add(-time.Minute)), Maximum: aws.Float64(4)},
+			},
+		},
+	}
+	usageCheckMock := &UsageCheckMock{
+		usages: []QuotaUsage{
+			{Name: "quota", Usage: 5},
+			{Name: "quota", ResourceName: aws.String("i-123"), Usage: 1},
+		},
+	}
+
+	serviceQuotas := ServiceQuotas{
+		quotasService:            mockClient,
+		cloudwatchClient:         mockCloudWatch,
+		clock:                    func() time.Time { return now },
+		serviceQuotasUsageChecks: map[string]UsageCheck{"L-1234": usageCheckMock},
+	}
+	quotasAndUsage, err := serviceQuotas.QuotasAndUsage()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(quotasAndUsage))
+	assert.Equal(t, aws.Float64(4), quotasAndUsage[0].ReportedUsage)
+	assert.Nil(t, quotasAndUsage[1].ReportedUsage)
+
+	input := mockCloudWatch.GetMetricStatisticsInput
+	assert.Equal(t, "AWS/Usage", aws.StringValue(input.Namespace))
+	assert.Equal(t, "ResourceCount", aws.StringValue(input.MetricName))
+	assert.Equal(t, []*cloudwatch.Dimension{{Name: aws.String("Resource"), Value: aws.String("vCPU")}}, input.Dimensions)
+	assert.Equal(t, []*string{aws.String(cloudwatch.StatisticMaximum)}, input.Statistics)
+	assert.Equal(t, now.Add(-reportedUsageWindow), aws.TimeValue(input.StartTime))
+	assert.Equal(t, now, aws.TimeValue(input.EndTime))
+}
+
+func TestQuotasAndUsageWithReportedUsageError(t *testing.T) {
+	mockClient := &mockServiceQuotasClient{
+		serviceName: "ec2",
+		ListServiceQuotasResponse: &awsservicequotas.ListServiceQuotasOutput{
+			Quotas: []*awsservicequotas.ServiceQuota{usageMetricQuota()},
+		},
+	}
+
+	serviceQuotas := ServiceQuotas{
+		quotasService:            mockClient,
+		cloudwatchClient:         &mockCloudWatchClient{err: errors.New("some err")},
+		serviceQuotasUsageChecks: map[string]UsageCheck{"L-1234": &UsageCheckMock{usages: []QuotaUsage{{Name: "quota", Usage: 5}}}},
+	}
+	quotasAndUsage, err := serviceQuotas.QuotasAndUsage()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(quotasAndUsage))
+	assert.Equal(t, float64(5), quotasAndUsage[0].Usage)
+	assert.Nil(t, quotasAndUsage[0].ReportedUsage)
+}
+
+func TestReportedUsageWithoutDatapoints(t *testing.T) {
+	mockCloudWatch := &mockCloudWatchClient{GetMetricStatisticsResponse: &cloudwatch.GetMetricStatisticsOutput{}}
+
+	value, err := reportedUsage(aws.BackgroundContext(), mockCloudWatch, usageMetricQuota().UsageMetric, time.Now())
+
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestReportedUsageDefaultStatistic(t *testing.T) {
+	now := time.Now()
+	mockCloudWatch := &mockCloudWatchClient{
+		GetMetricStatisticsResponse: &cloudwatch.GetMetricStatisticsOutput{
+			Datapoints: []*cloudwatch.Datapoint{{Timestamp: aws.Time(now), Maximum: aws.Float64(7)}},
+		},
+	}
+	metric := usageMetricQuota().UsageMetric
+	metric.MetricStatisticRecommendation = nil
+
+	value, err := reportedUsage(aws.BackgroundContext(), mockCloudWatch, metric, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, aws.Float64(7), value)
+	assert.Equal(t, []*string{aws.String(defaultUsageMetricStatistic)}, mockCloudWatch.GetMetricStatisticsInput.Statistics)
+}
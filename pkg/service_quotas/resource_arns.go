@@ -0,0 +1,79 @@
+package servicequotas
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+)
+
+// Resource identifiers of the per-resource usages
+const (
+	// ResourceIdentifierID identifies the resources by the ID or name
+	// returned by their service
+	ResourceIdentifierID = "id"
+	// ResourceIdentifierARN identifies the resources by their ARN where
+	// it can be built from their ID or name
+	ResourceIdentifierARN = "arn"
+)
+
+// resourceARNFormat is how the ARN of the resources of a quota is
+// built from their ID or name
+type resourceARNFormat struct {
+	// service is the service namespace of the ARN
+	service string
+	// prefix is prepended to the ID or name of the resource to build
+	// the resource part of the ARN
+	prefix string
+}
+
+// resourceARNFormats holds the ARN format of the resources of the per
+// resource quotas by quota name. The ARNs of the quotas missing from it
+// cannot be built from the resource name, such as the auto scaling
+// groups whose ARN includes an ID that is not returned by the check
+var resourceARNFormats = map[string]resourceARNFormat{
+	inboundRulesPerSecGrpName:               {"ec2", "security-group/"},
+	outboundRulesPerSecGrpName:              {"ec2", "security-group/"},
+	totalRulesPerSecGrpName:                 {"ec2", "security-group/"},
+	secGroupsPerENIName:                     {"ec2", "network-interface/"},
+	eNIsPerInstanceName:                     {"ec2", "instance/"},
+	availableIPsPerSubnetName:               {"ec2", "subnet/"},
+	entriesPerPrefixListName:                {"ec2", "prefix-list/"},
+	numReadReplicasPerMasterName:            {"rds", "cluster:"},
+	jobsPerTriggerName:                      {"glue", "trigger/"},
+	concurrentRunsPerJobName:                {"glue", "job/"},
+	virtualNodesPerMeshName:                 {"appmesh", "mesh/"},
+	hsmsPerClusterName:                      {"cloudhsm", "cluster/"},
+	imagesPerRepositoryName:                 {"ecr", "repository/"},
+	flinkKPUsPerAppName:                     {"kinesisanalytics", "application/"},
+	logStreamsPerLogGroupName:               {"logs", "log-group:"},
+	readCapacityPerTableName:                {"dynamodb", "table/"},
+	writeCapacityPerTableName:               {"dynamodb", "table/"},
+	concurrentAthenaQueriesPerWorkGroupName: {"athena", "workgroup/"},
+}
+
+// resourceARNs replaces the resource names of the per-resource usages
+// with the ARNs of the resources in `accountID`. The resource names of
+// the usages whose ARN cannot be built are kept
+func resourceARNs(usages []QuotaUsage, accountID string) []QuotaUsage {
+	for i, usage := range usages {
+		if usage.ResourceName == nil {
+			continue
+		}
+		format, ok := resourceARNFormats[usage.Name]
+		if !ok {
+			log.Debugf("Keeping the resource name of %s for resource (%s), its ARN cannot be built", usage.Name, *usage.ResourceName)
+			continue
+		}
+
+		partition := endpoints.AwsPartitionID
+		if p, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), usage.Region); ok {
+			partition = p.ID()
+		}
+		arn := fmt.Sprintf("arn:%s:%s:%s:%s:%s%s", partition, format.service, usage.Region, accountID, format.prefix, *usage.ResourceName)
+		// the usages can share their resource name with the state of
+		// the checks, so it is replaced instead of being updated
+		usages[i].ResourceName = aws.String(arn)
+	}
+	return usages
+}
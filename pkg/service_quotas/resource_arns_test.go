@@ -0,0 +1,77 @@
+package servicequotas
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceARNs(t *testing.T) {
+	testCases := []struct {
+		name     string
+		usage    QuotaUsage
+		expected *string
+	}{
+		{
+			name:     "SecurityGroup",
+			usage:    QuotaUsage{Name: inboundRulesPerSecGrpName, ResourceName: aws.String("sg-123"), Region: "eu-west-1"},
+			expected: aws.String("arn:aws:ec2:eu-west-1:123456789012:security-group/sg-123"),
+		},
+		{
+			name:     "LogGroup",
+			usage:    QuotaUsage{Name: logStreamsPerLogGroupName, ResourceName: aws.String("/aws/lambda/fn"), Region: "eu-west-1"},
+			expected: aws.String("arn:aws:logs:eu-west-1:123456789012:log-group:/aws/lambda/fn"),
+		},
+		{
+			name:     "ChinaPartition",
+			usage:    QuotaUsage{Name: readCapacityPerTableName, ResourceName: aws.String("table"), Region: "cn-north-1"},
+			expected: aws.String("arn:aws-cn:dynamodb:cn-north-1:123456789012:table/table"),
+		},
+		{
+			name:     "UnknownARNKeepsName",
+			usage:    QuotaUsage{Name: numInstancesPerASGName, ResourceName: aws.String("asg"), Region: "eu-west-1"},
+			expected: aws.String("asg"),
+		},
+		{
+			name:     "AggregateUsage",
+			usage:    QuotaUsage{Name: inboundRulesPerSecGrpName, Region: "eu-west-1"},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			usages := resourceARNs([]QuotaUsage{tc.usage}, "123456789012")
+
+			assert.Equal(t, tc.expected, usages[0].ResourceName)
+		})
+	}
+}
+
+func TestResourceARNsDoesNotUpdateSharedName(t *testing.T) {
+	name := aws.String("sg-123")
+	usages := []QuotaUsage{{Name: inboundRulesPerSecGrpName, ResourceName: name, Region: "eu-west-1"}}
+
+	resourceARNs(usages, "123456789012")
+
+	assert.Equal(t, "sg-123", *name)
+}
+
+func TestQuotasAndUsageWithResourceARNs(t *testing.T) {
+	check := &FakeUsageCheck{Usages: []QuotaUsage{
+		{Name: availableIPsPerSubnetName, ResourceName: aws.String("subnet-123"), Usage: 5},
+	}}
+	serviceQuotas, err := NewServiceQuotasWithChecks("eu-west-1", &mockServiceQuotasClient{}, UsageChecks{
+		Other: []UsageCheck{check},
+	}, Options{})
+	assert.NoError(t, err)
+	serviceQuotas.(*ServiceQuotas).resourceAccountID = "123456789012"
+
+	usages, err := serviceQuotas.QuotasAndUsage()
+
+	assert.NoError(t, err)
+	assert.Len(t, usages, 1)
+	assert.Equal(t, "arn:aws:ec2:eu-west-1:123456789012:subnet/subnet-123", *usages[0].ResourceName)
+	assert.Equal(t, "subnet-123", *check.Usages[0].ResourceName)
+}
@@ -0,0 +1,168 @@
+package servicequotas
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsservicequotas "github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/pkg/errors"
+)
+
+// UsageForResource implements the ResourceQuotasInterface interface.
+// Only the quotas whose usage check returns one usage per resource are
+// supported, see ResourceUsageDescriber. `resourceID` identifies the
+// resource as in the resource label of its metrics, by ARN when the
+// resources are identified by ARN. The quota is looked up in the quotas
+// listed by AWS for the service its usage check is registered under,
+// and only its usage check is run. The quotas of the other usage
+// checks, which have no quota code, are looked up by their name
+// instead, such as available_ips_per_subnet. The lookup does not wait
+// for a refresh in progress
+func (s *ServiceQuotas) UsageForResource(ctx context.Context, quotaCode, resourceID string) (*QuotaUsage, error) {
+	check, isDefault := s.serviceQuotasUsageChecks[quotaCode], false
+	if check == nil {
+		check, isDefault = s.serviceDefaultUsageChecks[quotaCode], true
+	}
+	if check == nil {
+		if check = s.otherResourceCheck(quotaCode); check != nil {
+			return s.otherUsageForResource(ctx, check, quotaCode, resourceID)
+		}
+		return nil, errors.Wrapf(ErrInvalidQuota, "no usage check for quota %s", quotaCode)
+	}
+	if _, ok := check.(ResourceUsageDescriber); !ok {
+		return nil, errors.Wrapf(ErrInvalidQuota, "quota %s is not a per-resource quota", quotaCode)
+	}
+	service := s.quotaServices[quotaCode]
+	if service == "" {
+		return nil, errors.Wrapf(ErrInvalidQuota, "no service for quota %s", quotaCode)
+	}
+
+	serviceQuotas := s.forService(service)
+	if serviceQuotas.isAwsChina {
+		return nil, errors.Wrapf(ErrInvalidQuota, "service quotas are not available in %s", serviceQuotas.region)
+	}
+	if !serviceQuotas.serviceAvailable(service) {
+		return nil, errors.Wrapf(ErrInvalidQuota, "service %s is not available in %s", service, serviceQuotas.region)
+	}
+	quota, err := serviceQuotas.findQuota(ctx, service, quotaCode, isDefault)
+	if err != nil {
+		return nil, err
+	}
+	if quota == nil {
+		return nil, errors.Wrapf(ErrInvalidQuota, "quota %s is not listed by AWS in %s", quotaCode, serviceQuotas.region)
+	}
+
+	// The services retrieved in another region have their own checks
+	check = serviceQuotas.serviceQuotasUsageChecks[quotaCode]
+	if isDefault {
+		check = serviceQuotas.serviceDefaultUsageChecks[quotaCode]
+	}
+	usages, err := serviceQuotas.runCheck(ctx, check, service, quotaCode)
+	if err != nil {
+		return nil, err
+	}
+	collectedAt := serviceQuotas.now()
+	for _, usage := range s.identifyResources(usages) {
+		if usage.ResourceName == nil || *usage.ResourceName != resourceID {
+			continue
+		}
+		usage.CollectedAt = collectedAt
+		usage.Region = serviceQuotas.region
+		usage.Quota = usageLimit(check, usage, aws.Float64Value(quota.Value))
+		usage.Adjustable = aws.BoolValue(quota.Adjustable)
+		return &usage, nil
+	}
+	return nil, errors.Wrapf(ErrResourceNotFound, "no usage of quota %s for resource %s", quotaCode, resourceID)
+}
+
+// otherResourceCheck returns the other usage check that returns one
+// usage per resource for the quota `name`, or nil if there is none
+func (s *ServiceQuotas) otherResourceCheck(name string) UsageCheck {
+	for _, check := range s.otherUsageChecks {
+		describer, ok := check.(ResourceUsageDescriber)
+		if !ok {
+			continue
+		}
+		for _, usage := range describer.DescribeResourceUsage() {
+			if usage.Name == name {
+				return check
+			}
+		}
+	}
+	return nil
+}
+
+// otherUsageForResource returns the usage of the resource `resourceID`
+// for the quota `name` of the other usage check `check`. The limit is
+// the one returned by the check, as the quota is not listed by AWS
+func (s *ServiceQuotas) otherUsageForResource(ctx context.Context, check UsageCheck, name, resourceID string) (*QuotaUsage, error) {
+	service := otherUsageCheckService(check)
+	serviceQuotas := s.forService(service)
+	if !serviceQuotas.serviceAvailable(service) {
+		return nil, errors.Wrapf(ErrInvalidQuota, "service %s is not available in %s", service, serviceQuotas.region)
+	}
+
+	check = s.otherCheckFor(serviceQuotas, check)
+	usages, err := serviceQuotas.runCheck(ctx, check, service, "")
+	if err != nil {
+		return nil, err
+	}
+	collectedAt := serviceQuotas.now()
+	for _, usage := range s.identifyResources(usages) {
+		if usage.Name != name || usage.ResourceName == nil || *usage.ResourceName != resourceID {
+			continue
+		}
+		usage.CollectedAt = collectedAt
+		usage.Region = serviceQuotas.region
+		return &usage, nil
+	}
+	return nil, errors.Wrapf(ErrResourceNotFound, "no usage of quota %s for resource %s", name, resourceID)
+}
+
+// findQuota returns the quota `quotaCode` of `service` listed by AWS,
+// or nil if it is not listed. The default value of the quota is
+// returned when `isDefault` is set, as for the default usage checks
+func (s *ServiceQuotas) findQuota(ctx context.Context, service, quotaCode string, isDefault bool) (*awsservicequotas.ServiceQuota, error) {
+	var found *awsservicequotas.ServiceQuota
+	// find returns whether `quotas` include the quota, so that paging
+	// stops once it is found
+	find := func(quotas []*awsservicequotas.ServiceQuota) bool {
+		for _, quota := range quotas {
+			if aws.StringValue(quota.QuotaCode) == quotaCode {
+				found = quota
+				return true
+			}
+		}
+		return false
+	}
+
+	var err error
+	if isDefault {
+		params := &awsservicequotas.ListAWSDefaultServiceQuotasInput{ServiceCode: aws.String(service)}
+		err = s.quotasService.ListAWSDefaultServiceQuotasPagesWithContext(ctx, params,
+			func(page *awsservicequotas.ListAWSDefaultServiceQuotasOutput, lastPage bool) bool {
+				if page != nil && find(page.Quotas) {
+					return false
+				}
+				return !lastPage
+			},
+		)
+	} else {
+		params := &awsservicequotas.ListServiceQuotasInput{ServiceCode: aws.String(service)}
+		err = s.quotasService.ListServiceQuotasPagesWithContext(ctx, params,
+			func(page *awsservicequotas.ListServiceQuotasOutput, lastPage bool) bool {
+				if page != nil && find(page.Quotas) {
+					return false
+				}
+				return !lastPage
+			},
+		)
+	}
+	if err != nil {
+		if isRegionNotEnabled(err) {
+			return nil, errors.Wrapf(ErrRegionNotEnabled, "failed to list the quotas of %s in %s: %s", service, s.region, err)
+		}
+		return nil, errors.Wrapf(ErrFailedToListQuotas, "failed to list the quotas of %s: %s", service, err)
+	}
+	return found, nil
+}
@@ -0,0 +1,277 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/glue"
+	awsservicequotas "github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTriggersClient() *mockGlueClient {
+	return &mockGlueClient{
+		ListTriggersResponse: &glue.ListTriggersOutput{
+			TriggerNames: aws.StringSlice([]string{"trigger1", "trigger2"}),
+		},
+		Triggers: map[string]*glue.Trigger{
+			"trigger1": {Name: aws.String("trigger1"), Actions: []*glue.Action{{JobName: aws.String("job1")}}},
+			"trigger2": {Name: aws.String("trigger2"), Actions: []*glue.Action{{JobName: aws.String("job1")}, {JobName: aws.String("job2")}}},
+		},
+	}
+}
+
+func TestUsageForResource(t *testing.T) {
+	mockClient := &mockServiceQuotasClient{
+		serviceName: "glue",
+		ListServiceQuotasResponse: &awsservicequotas.ListServiceQuotasOutput{
+			Quotas: []*awsservicequotas.ServiceQuota{
+				{QuotaCode: aws.String("L-EEC98450"), Value: aws.Float64(50), Adjustable: aws.Bool(true)},
+			},
+		},
+	}
+
+	serviceQuotas := ServiceQuotas{
+		region:                   "eu-west-1",
+		quotasService:            mockClient,
+		serviceQuotasUsageChecks: map[string]UsageCheck{"L-EEC98450": &JobsPerTriggerCheck{newTriggersClient()}},
+		quotaServices:            map[string]string{"L-EEC98450": "glue"},
+	}
+	usage, err := serviceQuotas.UsageForResource(context.Background(), "L-EEC98450", "trigger2")
+
+	expectedUsage := &QuotaUsage{
+		Name:         jobsPerTriggerName,
+		Description:  jobsPerTriggerDescription,
+		ResourceName: aws.String("trigger2"),
+		Usage:        2,
+		Quota:        50,
+		Adjustable:   true,
+		Region:       "eu-west-1",
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+	// only the quotas of the service of the check are listed
+	assert.Equal(t, 1, mockClient.timesCalled)
+}
+
+func TestUsageForResourceDuringRefresh(t *testing.T) {
+	mockClient := &mockServiceQuotasClient{
+		serviceName: "glue",
+		ListServiceQuotasResponse: &awsservicequotas.ListServiceQuotasOutput{
+			Quotas: []*awsservicequotas.ServiceQuota{{QuotaCode: aws.String("L-EEC98450"), Value: aws.Float64(50)}},
+		},
+	}
+	serviceQuotas := ServiceQuotas{
+		region:                   "eu-west-1",
+		quotasService:            mockClient,
+		serviceQuotasUsageChecks: map[string]UsageCheck{"L-EEC98450": &JobsPerTriggerCheck{newTriggersClient()}},
+		quotaServices:            map[string]string{"L-EEC98450": "glue"},
+	}
+	// a refresh in progress holds the mutex
+	serviceQuotas.mutex.Lock()
+	defer serviceQuotas.mutex.Unlock()
+
+	usage, err := serviceQuotas.UsageForResource(context.Background(), "L-EEC98450", "trigger2")
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2), usage.Usage)
+}
+
+func TestUsageForResourceWithServiceRegion(t *testing.T) {
+	glueServiceQuotas := &ServiceQuotas{
+		region: "us-east-1",
+		quotasService: &mockServiceQuotasClient{
+			serviceName: "glue",
+			ListServiceQuotasResponse: &awsservicequotas.ListServiceQuotasOutput{
+				Quotas: []*awsservicequotas.ServiceQuota{{QuotaCode: aws.String("L-EEC98450"), Value: aws.Float64(50)}},
+			},
+		},
+		serviceQuotasUsageChecks: map[string]UsageCheck{"L-EEC98450": &JobsPerTriggerCheck{newTriggersClient()}},
+	}
+	mockClient := &mockServiceQuotasClient{err: errors.New("some err")}
+	serviceQuotas := ServiceQuotas{
+		region:                   "eu-west-1",
+		quotasService:            mockClient,
+		serviceRegions:           map[string]*ServiceQuotas{"glue": glueServiceQuotas},
+		serviceQuotasUsageChecks: map[string]UsageCheck{"L-EEC98450": &JobsPerTriggerCheck{&mockGlueClient{err: errors.New("some err")}}},
+		quotaServices:            map[string]string{"L-EEC98450": "glue"},
+	}
+	usage, err := serviceQuotas.UsageForResource(context.Background(), "L-EEC98450", "trigger1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), usage.Usage)
+	assert.Equal(t, "us-east-1", usage.Region)
+	assert.Equal(t, 0, mockClient.timesCalled)
+}
+
+func TestUsageForResourceWithDefaultQuota(t *testing.T) {
+	mockClient := &mockServiceQuotasClient{
+		serviceName: "glue",
+		ListAWSDefaultServiceQuotasResponse: &awsservicequotas.ListAWSDefaultServiceQuotasOutput{
+			Quotas: []*awsservicequotas.ServiceQuota{
+				{QuotaCode: aws.String("L-EEC98450"), Value: aws.Float64(50)},
+			},
+		},
+	}
+
+	serviceQuotas := ServiceQuotas{
+		region:                    "eu-west-1",
+		quotasService:             mockClient,
+		serviceDefaultUsageChecks: map[string]UsageCheck{"L-EEC98450": &JobsPerTriggerCheck{newTriggersClient()}},
+		quotaServices:             map[string]string{"L-EEC98450": "glue"},
+	}
+	usage, err := serviceQuotas.UsageForResource(context.Background(), "L-EEC98450", "trigger1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), usage.Usage)
+	assert.Equal(t, float64(50), usage.Quota)
+}
+
+func TestUsageForResourceWithUnknownResource(t *testing.T) {
+	mockClient := &mockServiceQuotasClient{
+		serviceName: "glue",
+		ListServiceQuotasResponse: &awsservicequotas.ListServiceQuotasOutput{
+			Quotas: []*awsservicequotas.ServiceQuota{
+				{QuotaCode: aws.String("L-EEC98450"), Value: aws.Float64(50)},
+			},
+		},
+	}
+
+	serviceQuotas := ServiceQuotas{
+		region:                   "eu-west-1",
+		quotasService:            mockClient,
+		serviceQuotasUsageChecks: map[string]UsageCheck{"L-EEC98450": &JobsPerTriggerCheck{newTriggersClient()}},
+		quotaServices:            map[string]string{"L-EEC98450": "glue"},
+	}
+	usage, err := serviceQuotas.UsageForResource(context.Background(), "L-EEC98450", "trigger3")
+
+	assert.True(t, errors.Is(err, ErrResourceNotFound))
+	assert.Nil(t, usage)
+}
+
+func TestUsageForResourceWithInvalidQuota(t *testing.T) {
+	mockClient := &mockServiceQuotasClient{}
+	serviceQuotas := ServiceQuotas{
+		region:        "eu-west-1",
+		quotasService: mockClient,
+		serviceQuotasUsageChecks: map[string]UsageCheck{
+			"L-1234": &UsageCheckMock{usages: []QuotaUsage{{Name: "some_quota", Usage: 5}}},
+		},
+	}
+
+	for _, quotaCode := range []string{"L-1234", "L-5678"} {
+		usage, err := serviceQuotas.UsageForResource(context.Background(), quotaCode, "some-resource")
+
+		assert.True(t, errors.Is(err, ErrInvalidQuota), quotaCode)
+		assert.Nil(t, usage)
+	}
+	// the quotas are not listed for the checks that are not per resource
+	assert.Equal(t, 0, mockClient.timesCalled)
+}
+
+func TestUsageForResourceWithUnlistedQuota(t *testing.T) {
+	mockClient := &mockServiceQuotasClient{}
+	serviceQuotas := ServiceQuotas{
+		region:                   "eu-west-1",
+		quotasService:            mockClient,
+		serviceQuotasUsageChecks: map[string]UsageCheck{"L-EEC98450": &JobsPerTriggerCheck{newTriggersClient()}},
+		quotaServices:            map[string]string{"L-EEC98450": "glue"},
+	}
+	usage, err := serviceQuotas.UsageForResource(context.Background(), "L-EEC98450", "trigger1")
+
+	assert.True(t, errors.Is(err, ErrInvalidQuota))
+	assert.Nil(t, usage)
+}
+
+func TestUsageForResourceWithError(t *testing.T) {
+	mockClient := &mockServiceQuotasClient{err: errors.New("some err")}
+	serviceQuotas := ServiceQuotas{
+		region:                   "eu-west-1",
+		quotasService:            mockClient,
+		serviceQuotasUsageChecks: map[string]UsageCheck{"L-EEC98450": &JobsPerTriggerCheck{newTriggersClient()}},
+		quotaServices:            map[string]string{"L-EEC98450": "glue"},
+	}
+	usage, err := serviceQuotas.UsageForResource(context.Background(), "L-EEC98450", "trigger1")
+
+	assert.True(t, errors.Is(err, ErrFailedToListQuotas))
+	assert.Nil(t, usage)
+}
+
+func TestUsageForResourceWithoutService(t *testing.T) {
+	mockClient := &mockServiceQuotasClient{}
+	serviceQuotas := ServiceQuotas{
+		region:                   "eu-west-1",
+		quotasService:            mockClient,
+		serviceQuotasUsageChecks: map[string]UsageCheck{"L-EEC98450": &JobsPerTriggerCheck{newTriggersClient()}},
+	}
+	usage, err := serviceQuotas.UsageForResource(context.Background(), "L-EEC98450", "trigger1")
+
+	assert.True(t, errors.Is(err, ErrInvalidQuota))
+	assert.Nil(t, usage)
+	assert.Equal(t, 0, mockClient.timesCalled)
+}
+
+func newSubnetsClient() *mockEC2Client {
+	return &mockEC2Client{
+		DescribeSubnetsResponse: &ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{
+				{AvailableIpAddressCount: aws.Int64(200), CidrBlock: aws.String("10.0.0.0/24"), SubnetId: aws.String("subnet-1")},
+				{AvailableIpAddressCount: aws.Int64(100), CidrBlock: aws.String("10.0.1.0/24"), SubnetId: aws.String("subnet-2")},
+			},
+		},
+	}
+}
+
+func TestUsageForResourceWithOtherCheck(t *testing.T) {
+	mockClient := &mockServiceQuotasClient{}
+	serviceQuotas := ServiceQuotas{
+		region:           "eu-west-1",
+		quotasService:    mockClient,
+		otherUsageChecks: []UsageCheck{&AvailableIpsPerSubnetUsageCheck{newSubnetsClient()}},
+	}
+	usage, err := serviceQuotas.UsageForResource(context.Background(), availableIPsPerSubnetName, "subnet-2")
+
+	expectedUsage := &QuotaUsage{
+		Name:         availableIPsPerSubnetName,
+		Description:  availableIPsPerSubnetDesc,
+		ResourceName: aws.String("subnet-2"),
+		Usage:        151,
+		Quota:        251,
+		Region:       "eu-west-1",
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+	// the quotas of the other checks are not listed by AWS
+	assert.Equal(t, 0, mockClient.timesCalled)
+}
+
+func TestUsageForResourceWithOtherCheckAndUnknownResource(t *testing.T) {
+	serviceQuotas := ServiceQuotas{
+		region:           "eu-west-1",
+		quotasService:    &mockServiceQuotasClient{},
+		otherUsageChecks: []UsageCheck{&AvailableIpsPerSubnetUsageCheck{newSubnetsClient()}},
+	}
+	usage, err := serviceQuotas.UsageForResource(context.Background(), availableIPsPerSubnetName, "subnet-3")
+
+	assert.True(t, errors.Is(err, ErrResourceNotFound))
+	assert.Nil(t, usage)
+}
+
+func TestUsageForResourceWithOtherCheckNotPerResource(t *testing.T) {
+	serviceQuotas := ServiceQuotas{
+		region:        "eu-west-1",
+		quotasService: &mockServiceQuotasClient{},
+		otherUsageChecks: []UsageCheck{
+			&UsageCheckMock{usages: []QuotaUsage{{Name: "some_quota", ResourceName: aws.String("some-resource"), Usage: 5}}},
+		},
+	}
+	usage, err := serviceQuotas.UsageForResource(context.Background(), "some_quota", "some-resource")
+
+	assert.True(t, errors.Is(err, ErrInvalidQuota))
+	assert.Nil(t, usage)
+}
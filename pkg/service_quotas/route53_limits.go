@@ -0,0 +1,252 @@
+package servicequotas
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+)
+
+const (
+	healthChecksPerAccountName        = "route53_health_checks_per_account"
+	healthChecksPerAccountDescription = "Route53 health checks per account"
+
+	trafficPoliciesName        = "route53_traffic_policies_per_account"
+	trafficPoliciesDescription = "Route53 traffic policies per account"
+
+	hostedZonesName        = "route53_hosted_zones_per_account"
+	hostedZonesDescription = "Route53 hosted zones per account"
+
+	recordsPerZoneName        = "route53_records_per_hosted_zone"
+	recordsPerZoneDescription = "Route53 records per hosted zone"
+)
+
+// hostedZoneIDPrefix prefixes the IDs of the hosted zones returned by
+// ListHostedZones
+const hostedZoneIDPrefix = "/hostedzone/"
+
+// accountLimit returns the value of the Route53 account limit of
+// `limitType`, one of the route53.AccountLimitType values
+func accountLimit(ctx context.Context, client route53iface.Route53API, limitType string) (float64, error) {
+	params := &route53.GetAccountLimitInput{Type: aws.String(limitType)}
+	response, err := client.GetAccountLimitWithContext(ctx, params)
+	if err != nil {
+		return 0, err
+	}
+	if response.Limit == nil {
+		return 0, nil
+	}
+	return float64(aws.Int64Value(response.Limit.Value)), nil
+}
+
+// HealthChecksPerAccountCheck implements the UsageCheck interface for
+// Route53 health checks per account. Route53 is a global service, so
+// its client must be in the global region
+type HealthChecksPerAccountCheck struct {
+	client route53iface.Route53API
+}
+
+// Usage returns the number of Route53 health checks and their account
+// limit or an error
+func (c *HealthChecksPerAccountCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var healthChecksCount int
+
+	params := &route53.ListHealthChecksInput{}
+	err := c.client.ListHealthChecksPagesWithContext(ctx, params,
+		func(page *route53.ListHealthChecksOutput, lastPage bool) bool {
+			if page != nil {
+				healthChecksCount += len(page.HealthChecks)
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	limit, err := accountLimit(ctx, c.client, route53.AccountLimitTypeMaxHealthChecksByOwner)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        healthChecksPerAccountName,
+			Description: healthChecksPerAccountDescription,
+			Usage:       float64(healthChecksCount),
+			Quota:       limit,
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *HealthChecksPerAccountCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: healthChecksPerAccountName, Description: healthChecksPerAccountDescription}}
+}
+
+// TrafficPoliciesCheck implements the UsageCheck interface for Route53
+// traffic policies per account. Route53 is a global service, so its
+// client must be in the global region
+type TrafficPoliciesCheck struct {
+	client route53iface.Route53API
+}
+
+// Usage returns the number of Route53 traffic policies and their
+// account limit or an error. ListTrafficPolicies has no paginator, the
+// next page starts at TrafficPolicyIdMarker while IsTruncated is set
+func (c *TrafficPoliciesCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var trafficPoliciesCount int
+
+	params := &route53.ListTrafficPoliciesInput{}
+	for {
+		page, err := c.client.ListTrafficPoliciesWithContext(ctx, params)
+		if err != nil {
+			return nil, wrapUsageErr(err)
+		}
+
+		trafficPoliciesCount += len(page.TrafficPolicySummaries)
+		if !aws.BoolValue(page.IsTruncated) {
+			break
+		}
+		params = &route53.ListTrafficPoliciesInput{TrafficPolicyIdMarker: page.TrafficPolicyIdMarker}
+	}
+
+	limit, err := accountLimit(ctx, c.client, route53.AccountLimitTypeMaxTrafficPoliciesByOwner)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        trafficPoliciesName,
+			Description: trafficPoliciesDescription,
+			Usage:       float64(trafficPoliciesCount),
+			Quota:       limit,
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *TrafficPoliciesCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: trafficPoliciesName, Description: trafficPoliciesDescription}}
+}
+
+// hostedZones returns the Route53 hosted zones, public and private, or
+// an error
+func hostedZones(ctx context.Context, client route53iface.Route53API) ([]*route53.HostedZone, error) {
+	zones := []*route53.HostedZone{}
+
+	params := &route53.ListHostedZonesInput{}
+	err := client.ListHostedZonesPagesWithContext(ctx, params,
+		func(page *route53.ListHostedZonesOutput, lastPage bool) bool {
+			if page != nil {
+				zones = append(zones, page.HostedZones...)
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return zones, nil
+}
+
+// HostedZonesCheck implements the UsageCheck interface for Route53
+// hosted zones per account. Route53 is a global service, so its client
+// must be in the global region
+type HostedZonesCheck struct {
+	client route53iface.Route53API
+}
+
+// Usage returns the number of Route53 hosted zones and their account
+// limit or an error
+func (c *HostedZonesCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	zones, err := hostedZones(ctx, c.client)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	limit, err := accountLimit(ctx, c.client, route53.AccountLimitTypeMaxHostedZonesByOwner)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        hostedZonesName,
+			Description: hostedZonesDescription,
+			Usage:       float64(len(zones)),
+			Quota:       limit,
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *HostedZonesCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: hostedZonesName, Description: hostedZonesDescription}}
+}
+
+// RecordsPerZoneCheck implements the UsageCheck interface for Route53
+// records per hosted zone. Route53 is a global service, so its client
+// must be in the global region
+type RecordsPerZoneCheck struct {
+	client  route53iface.Route53API
+	sampler resourceSampler
+}
+
+// Usage returns the number of records of each Route53 hosted zone,
+// keyed by hosted zone ID, with the record limit of the zone or an
+// error. The records are counted by ListHostedZones, the limit of each
+// zone is retrieved with one GetHostedZoneLimit call per zone. When
+// sampling, the limit of the zones that are not sampled is the one of
+// their previous refresh
+func (c *RecordsPerZoneCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	zones, err := hostedZones(ctx, c.client)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	quotaUsages := []QuotaUsage{}
+	c.sampler.start()
+	for _, zone := range zones {
+		id := strings.TrimPrefix(aws.StringValue(zone.Id), hostedZoneIDPrefix)
+		usage := QuotaUsage{
+			Name:         recordsPerZoneName,
+			ResourceName: aws.String(id),
+			Description:  recordsPerZoneDescription,
+			Usage:        float64(aws.Int64Value(zone.ResourceRecordSetCount)),
+		}
+
+		if previous, ok := c.sampler.skip(id); ok {
+			usage.Quota = previous.Quota
+		} else {
+			params := &route53.GetHostedZoneLimitInput{
+				HostedZoneId: zone.Id,
+				Type:         aws.String(route53.HostedZoneLimitTypeMaxRrsetsByZone),
+			}
+			response, err := c.client.GetHostedZoneLimitWithContext(ctx, params)
+			if err != nil {
+				return nil, wrapUsageErr(err)
+			}
+			if response.Limit != nil {
+				usage.Quota = float64(aws.Int64Value(response.Limit.Value))
+			}
+		}
+
+		c.sampler.record(id, usage)
+		quotaUsages = append(quotaUsages, usage)
+	}
+	c.sampler.finish()
+
+	return quotaUsages, nil
+}
+
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *RecordsPerZoneCheck) DescribeResourceUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: recordsPerZoneName, Description: recordsPerZoneDescription}}
+}
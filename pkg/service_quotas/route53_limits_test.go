@@ -0,0 +1,265 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockRoute53Client) ListHealthChecksPagesWithContext(ctx aws.Context, input *route53.ListHealthChecksInput, fn func(*route53.ListHealthChecksOutput, bool) bool, opts ...request.Option) error {
+	fn(m.ListHealthChecksResponse, true)
+	return m.err
+}
+
+func (m *mockRoute53Client) ListTrafficPoliciesWithContext(ctx aws.Context, input *route53.ListTrafficPoliciesInput, opts ...request.Option) (*route53.ListTrafficPoliciesOutput, error) {
+	m.ListTrafficPoliciesInputs = append(m.ListTrafficPoliciesInputs, input)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.ListTrafficPoliciesResponses[len(m.ListTrafficPoliciesInputs)-1], nil
+}
+
+func (m *mockRoute53Client) GetAccountLimitWithContext(ctx aws.Context, input *route53.GetAccountLimitInput, opts ...request.Option) (*route53.GetAccountLimitOutput, error) {
+	if m.AccountLimitErr != nil {
+		return nil, m.AccountLimitErr
+	}
+	value := m.AccountLimits[*input.Type]
+	return &route53.GetAccountLimitOutput{
+		Limit: &route53.AccountLimit{Type: input.Type, Value: aws.Int64(value)},
+	}, nil
+}
+
+func (m *mockRoute53Client) ListHostedZonesPagesWithContext(ctx aws.Context, input *route53.ListHostedZonesInput, fn func(*route53.ListHostedZonesOutput, bool) bool, opts ...request.Option) error {
+	fn(m.ListHostedZonesResponse, true)
+	return m.err
+}
+
+func (m *mockRoute53Client) GetHostedZoneLimitWithContext(ctx aws.Context, input *route53.GetHostedZoneLimitInput, opts ...request.Option) (*route53.GetHostedZoneLimitOutput, error) {
+	m.HostedZoneLimitInputs = append(m.HostedZoneLimitInputs, input)
+	if m.HostedZoneLimitErr != nil {
+		return nil, m.HostedZoneLimitErr
+	}
+	value := m.HostedZoneLimits[*input.HostedZoneId]
+	return &route53.GetHostedZoneLimitOutput{
+		Limit: &route53.HostedZoneLimit{Type: input.Type, Value: aws.Int64(value)},
+	}, nil
+}
+
+func TestHealthChecksPerAccountCheckWithError(t *testing.T) {
+	mockClient := &mockRoute53Client{
+		err: errors.New("some err"),
+	}
+
+	check := HealthChecksPerAccountCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestHealthChecksPerAccountCheckWithAccountLimitError(t *testing.T) {
+	mockClient := &mockRoute53Client{
+		ListHealthChecksResponse: &route53.ListHealthChecksOutput{},
+		AccountLimitErr:          errors.New("some err"),
+	}
+
+	check := HealthChecksPerAccountCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestHealthChecksPerAccountCheck(t *testing.T) {
+	mockClient := &mockRoute53Client{
+		ListHealthChecksResponse: &route53.ListHealthChecksOutput{
+			HealthChecks: []*route53.HealthCheck{
+				{Id: aws.String("hc-1")},
+				{Id: aws.String("hc-2")},
+			},
+		},
+		AccountLimits: map[string]int64{route53.AccountLimitTypeMaxHealthChecksByOwner: 200},
+	}
+
+	check := HealthChecksPerAccountCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        healthChecksPerAccountName,
+			Description: healthChecksPerAccountDescription,
+			Usage:       2,
+			Quota:       200,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestTrafficPoliciesCheckWithError(t *testing.T) {
+	mockClient := &mockRoute53Client{
+		err: errors.New("some err"),
+	}
+
+	check := TrafficPoliciesCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestTrafficPoliciesCheck(t *testing.T) {
+	mockClient := &mockRoute53Client{
+		ListTrafficPoliciesResponses: []*route53.ListTrafficPoliciesOutput{
+			{
+				TrafficPolicySummaries: []*route53.TrafficPolicySummary{{Id: aws.String("tp-1")}, {Id: aws.String("tp-2")}},
+				IsTruncated:            aws.Bool(true),
+				TrafficPolicyIdMarker:  aws.String("tp-3"),
+			},
+			{
+				TrafficPolicySummaries: []*route53.TrafficPolicySummary{{Id: aws.String("tp-3")}},
+				IsTruncated:            aws.Bool(false),
+			},
+		},
+		AccountLimits: map[string]int64{route53.AccountLimitTypeMaxTrafficPoliciesByOwner: 50},
+	}
+
+	check := TrafficPoliciesCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        trafficPoliciesName,
+			Description: trafficPoliciesDescription,
+			Usage:       3,
+			Quota:       50,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+	assert.Len(t, mockClient.ListTrafficPoliciesInputs, 2)
+	assert.Nil(t, mockClient.ListTrafficPoliciesInputs[0].TrafficPolicyIdMarker)
+	assert.Equal(t, aws.String("tp-3"), mockClient.ListTrafficPoliciesInputs[1].TrafficPolicyIdMarker)
+}
+
+func TestHostedZonesCheckWithError(t *testing.T) {
+	mockClient := &mockRoute53Client{
+		err: errors.New("some err"),
+	}
+
+	check := HostedZonesCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestHostedZonesCheck(t *testing.T) {
+	mockClient := &mockRoute53Client{
+		ListHostedZonesResponse: &route53.ListHostedZonesOutput{
+			HostedZones: []*route53.HostedZone{
+				{Id: aws.String("/hostedzone/Z1")},
+				{Id: aws.String("/hostedzone/Z2")},
+				{Id: aws.String("/hostedzone/Z3")},
+			},
+		},
+		AccountLimits: map[string]int64{route53.AccountLimitTypeMaxHostedZonesByOwner: 500},
+	}
+
+	check := HostedZonesCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        hostedZonesName,
+			Description: hostedZonesDescription,
+			Usage:       3,
+			Quota:       500,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestRecordsPerZoneCheckWithError(t *testing.T) {
+	mockClient := &mockRoute53Client{
+		err: errors.New("some err"),
+	}
+
+	check := RecordsPerZoneCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestRecordsPerZoneCheckWithHostedZoneLimitError(t *testing.T) {
+	mockClient := &mockRoute53Client{
+		ListHostedZonesResponse: &route53.ListHostedZonesOutput{
+			HostedZones: []*route53.HostedZone{{Id: aws.String("/hostedzone/Z1")}},
+		},
+		HostedZoneLimitErr: errors.New("some err"),
+	}
+
+	check := RecordsPerZoneCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestRecordsPerZoneCheck(t *testing.T) {
+	mockClient := &mockRoute53Client{
+		ListHostedZonesResponse: &route53.ListHostedZonesOutput{
+			HostedZones: []*route53.HostedZone{
+				{Id: aws.String("/hostedzone/Z1"), ResourceRecordSetCount: aws.Int64(42)},
+				{Id: aws.String("/hostedzone/Z2"), ResourceRecordSetCount: aws.Int64(2)},
+			},
+		},
+		HostedZoneLimits: map[string]int64{
+			"/hostedzone/Z1": 10000,
+			"/hostedzone/Z2": 20000,
+		},
+	}
+
+	check := RecordsPerZoneCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:         recordsPerZoneName,
+			ResourceName: aws.String("Z1"),
+			Description:  recordsPerZoneDescription,
+			Usage:        42,
+			Quota:        10000,
+		},
+		{
+			Name:         recordsPerZoneName,
+			ResourceName: aws.String("Z2"),
+			Description:  recordsPerZoneDescription,
+			Usage:        2,
+			Quota:        20000,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+	assert.Len(t, mockClient.HostedZoneLimitInputs, 2)
+	for _, input := range mockClient.HostedZoneLimitInputs {
+		assert.Equal(t, route53.HostedZoneLimitTypeMaxRrsetsByZone, *input.Type)
+	}
+}
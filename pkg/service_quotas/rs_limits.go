@@ -1,10 +1,11 @@
 package servicequotas
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/redshift"
 	"github.com/aws/aws-sdk-go/service/redshift/redshiftiface"
-	"github.com/pkg/errors"
 )
 
 const (
@@ -13,26 +14,29 @@ const (
 )
 
 type UserSnapshotsPerRegionCheck struct {
-	client redshiftiface.RedshiftAPI
+	client       redshiftiface.RedshiftAPI
+	maxResources int
 }
 
-func (c *UserSnapshotsPerRegionCheck) Usage() ([]QuotaUsage, error) {
+func (c *UserSnapshotsPerRegionCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
+	snapshotsCap := &resourceCap{max: c.maxResources}
 
 	var userSnapshotsCount int
 
 	params := &redshift.DescribeClusterSnapshotsInput{SnapshotType: aws.String("manual")}
-	err := c.client.DescribeClusterSnapshotsPages(params,
+	err := c.client.DescribeClusterSnapshotsPagesWithContext(ctx, params,
 		func(page *redshift.DescribeClusterSnapshotsOutput, lastPage bool) bool {
 			if page != nil {
 				userSnapshotsCount += len(page.Snapshots)
+				return snapshotsCap.add(len(page.Snapshots), lastPage)
 			}
 			return !lastPage
 		},
 	)
 	if err != nil {
 		log.Error("Failed to get Redshift Snapshots Usage Check")
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	}
 	usage := QuotaUsage{
 		Name:        userSnapshotsPerRegionName,
@@ -40,7 +44,13 @@ func (c *UserSnapshotsPerRegionCheck) Usage() ([]QuotaUsage, error) {
 		Usage:       float64(userSnapshotsCount),
 	}
 	quotaUsages = append(quotaUsages, usage)
+	snapshotsCap.markTruncated(quotaUsages)
 
 	return quotaUsages, nil
 
 }
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *UserSnapshotsPerRegionCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: userSnapshotsPerRegionName, Description: userSnapshotsPerRegionDescription}}
+}
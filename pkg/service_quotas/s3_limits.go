@@ -0,0 +1,236 @@
+package servicequotas
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3control"
+	"github.com/aws/aws-sdk-go/service/s3control/s3controliface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+)
+
+const (
+	bucketsPerAccountName        = "buckets_per_account"
+	bucketsPerAccountDescription = "S3 buckets per account"
+
+	accessPointsPerAccountName        = "s3_access_points_per_account"
+	accessPointsPerAccountDescription = "S3 access points per account"
+
+	multiRegionAccessPointsName        = "s3_multi_region_access_points_per_account"
+	multiRegionAccessPointsDescription = "S3 multi-region access points per account"
+)
+
+// multiRegionAccessPointsRegion is the only region serving the S3
+// Control API of the multi-region access points
+const multiRegionAccessPointsRegion = "us-west-2"
+
+// noSuchTagSetErrorCode is returned by GetBucketTagging for buckets
+// without tags
+const noSuchTagSetErrorCode = "NoSuchTagSet"
+
+// BucketsPerAccountCheck implements the UsageCheck interface for S3
+// buckets per account
+type BucketsPerAccountCheck struct {
+	client s3iface.S3API
+	// includedTags are the tag keys exported as labels, the tags of
+	// the buckets are only retrieved when it is set
+	includedTags []string
+}
+
+// Usage returns the number of S3 buckets in the account or an error.
+// The usage is for the whole account, so the included tags are only
+// attached when every bucket has the same value for them. The tags of
+// a bucket that cannot be read, for instance because it is in another
+// region than the client, are treated as missing
+func (c *BucketsPerAccountCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	buckets, err := c.client.ListBucketsWithContext(ctx, &s3.ListBucketsInput{})
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := QuotaUsage{
+		Name:        bucketsPerAccountName,
+		Description: bucketsPerAccountDescription,
+		Usage:       float64(len(buckets.Buckets)),
+	}
+	if len(c.includedTags) > 0 && len(buckets.Buckets) > 0 {
+		usage.Tags = c.commonTags(ctx, buckets.Buckets)
+	}
+	return []QuotaUsage{usage}, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *BucketsPerAccountCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: bucketsPerAccountName, Description: bucketsPerAccountDescription}}
+}
+
+// commonTags returns the included tags with the same value on all
+// `buckets`, keyed like the tags of the other checks
+func (c *BucketsPerAccountCheck) commonTags(ctx context.Context, buckets []*s3.Bucket) map[string]string {
+	var common map[string]string
+	for _, bucket := range buckets {
+		common = keepCommonTags(common, c.bucketTags(ctx, bucket.Name))
+	}
+
+	if len(common) == 0 {
+		return nil
+	}
+	return common
+}
+
+// keepCommonTags removes the tags of `common` that do not have the same
+// value in `tags` and returns it, `tags` is returned when `common` is
+// nil
+func keepCommonTags(common, tags map[string]string) map[string]string {
+	if common == nil {
+		return tags
+	}
+	for key, value := range common {
+		if tags[key] != value {
+			delete(common, key)
+		}
+	}
+	return common
+}
+
+// bucketTags returns the included tags of the bucket `name`
+func (c *BucketsPerAccountCheck) bucketTags(ctx context.Context, name *string) map[string]string {
+	tags := map[string]string{}
+
+	output, err := c.client.GetBucketTaggingWithContext(ctx, &s3.GetBucketTaggingInput{Bucket: name})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != noSuchTagSetErrorCode {
+			log.Warnf("Failed to get the tags of S3 bucket %s: %s", aws.StringValue(name), err)
+		}
+		return tags
+	}
+
+	for _, tag := range output.TagSet {
+		for _, included := range c.includedTags {
+			if aws.StringValue(tag.Key) == included {
+				tags[ToPrometheusNamingFormat(included)] = aws.StringValue(tag.Value)
+			}
+		}
+	}
+	return tags
+}
+
+// accountID resolves the ID of the account of the credentials through
+// STS once and caches it, as the S3 Control API requires it on every
+// request
+type accountID struct {
+	client stsiface.STSAPI
+
+	mutex sync.Mutex
+	id    string
+}
+
+// get returns the account ID or an error
+func (a *accountID) get(ctx context.Context) (string, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.id != "" {
+		return a.id, nil
+	}
+	identity, err := a.client.GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", err
+	}
+	a.id = aws.StringValue(identity.Account)
+	return a.id, nil
+}
+
+// AccessPointsPerAccountCheck implements the UsageCheck interface for
+// S3 access points per account
+type AccessPointsPerAccountCheck struct {
+	client    s3controliface.S3ControlAPI
+	accountID *accountID
+}
+
+// Usage returns the number of S3 access points in the region of the
+// client or an error
+func (c *AccessPointsPerAccountCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	accountID, err := c.accountID.get(ctx)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	var accessPointsCount int
+	params := &s3control.ListAccessPointsInput{AccountId: aws.String(accountID)}
+	err = c.client.ListAccessPointsPagesWithContext(ctx, params,
+		func(page *s3control.ListAccessPointsOutput, lastPage bool) bool {
+			if page != nil {
+				accessPointsCount += len(page.AccessPointList)
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        accessPointsPerAccountName,
+			Description: accessPointsPerAccountDescription,
+			Usage:       float64(accessPointsCount),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *AccessPointsPerAccountCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: accessPointsPerAccountName, Description: accessPointsPerAccountDescription}}
+}
+
+// MultiRegionAccessPointsCheck implements the UsageCheck interface for
+// S3 multi-region access points per account. Its client must be in
+// `multiRegionAccessPointsRegion`
+type MultiRegionAccessPointsCheck struct {
+	client    s3controliface.S3ControlAPI
+	accountID *accountID
+}
+
+// Usage returns the number of S3 multi-region access points or an
+// error
+func (c *MultiRegionAccessPointsCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	accountID, err := c.accountID.get(ctx)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	var accessPointsCount int
+	params := &s3control.ListMultiRegionAccessPointsInput{AccountId: aws.String(accountID)}
+	err = c.client.ListMultiRegionAccessPointsPagesWithContext(ctx, params,
+		func(page *s3control.ListMultiRegionAccessPointsOutput, lastPage bool) bool {
+			if page != nil {
+				accessPointsCount += len(page.AccessPoints)
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        multiRegionAccessPointsName,
+			Description: multiRegionAccessPointsDescription,
+			Usage:       float64(accessPointsCount),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *MultiRegionAccessPointsCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: multiRegionAccessPointsName, Description: multiRegionAccessPointsDescription}}
+}
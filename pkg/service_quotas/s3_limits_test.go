@@ -0,0 +1,245 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3control"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockS3Client) ListBucketsWithContext(ctx aws.Context, input *s3.ListBucketsInput, opts ...request.Option) (*s3.ListBucketsOutput, error) {
+	return m.ListBucketsResponse, m.err
+}
+
+func (m *mockS3Client) GetBucketTaggingWithContext(ctx aws.Context, input *s3.GetBucketTaggingInput, opts ...request.Option) (*s3.GetBucketTaggingOutput, error) {
+	m.GetBucketTaggingCalls++
+	if response, ok := m.GetBucketTaggingResponses[*input.Bucket]; ok {
+		return response, nil
+	}
+	return nil, awserr.New(noSuchTagSetErrorCode, "The TagSet does not exist", nil)
+}
+
+func testBuckets(names ...string) *s3.ListBucketsOutput {
+	buckets := []*s3.Bucket{}
+	for _, name := range names {
+		buckets = append(buckets, &s3.Bucket{Name: aws.String(name)})
+	}
+	return &s3.ListBucketsOutput{Buckets: buckets}
+}
+
+func bucketTagging(tags map[string]string) *s3.GetBucketTaggingOutput {
+	tagSet := []*s3.Tag{}
+	for key, value := range tags {
+		tagSet = append(tagSet, &s3.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return &s3.GetBucketTaggingOutput{TagSet: tagSet}
+}
+
+func TestBucketsPerAccountCheckWithError(t *testing.T) {
+	mockClient := &mockS3Client{
+		err:                 errors.New("some err"),
+		ListBucketsResponse: nil,
+	}
+
+	check := BucketsPerAccountCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestBucketsPerAccountCheck(t *testing.T) {
+	mockClient := &mockS3Client{
+		ListBucketsResponse: testBuckets("bucket1", "bucket2", "bucket3"),
+	}
+
+	check := BucketsPerAccountCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        bucketsPerAccountName,
+			Description: bucketsPerAccountDescription,
+			Usage:       3,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+	assert.Equal(t, 0, mockClient.GetBucketTaggingCalls)
+}
+
+func TestBucketsPerAccountCheckWithTags(t *testing.T) {
+	mockClient := &mockS3Client{
+		ListBucketsResponse: testBuckets("bucket1", "bucket2"),
+		GetBucketTaggingResponses: map[string]*s3.GetBucketTaggingOutput{
+			"bucket1": bucketTagging(map[string]string{"account-owner": "platform", "team": "a", "other": "x"}),
+			"bucket2": bucketTagging(map[string]string{"account-owner": "platform", "team": "b"}),
+		},
+	}
+
+	check := BucketsPerAccountCheck{mockClient, []string{"account-owner", "team"}}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        bucketsPerAccountName,
+			Description: bucketsPerAccountDescription,
+			Usage:       2,
+			Tags:        map[string]string{"account_owner": "platform"},
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+	assert.Equal(t, 2, mockClient.GetBucketTaggingCalls)
+}
+
+func TestBucketsPerAccountCheckWithUntaggedBucket(t *testing.T) {
+	mockClient := &mockS3Client{
+		ListBucketsResponse: testBuckets("bucket1", "bucket2"),
+		GetBucketTaggingResponses: map[string]*s3.GetBucketTaggingOutput{
+			"bucket1": bucketTagging(map[string]string{"team": "a"}),
+		},
+	}
+
+	check := BucketsPerAccountCheck{mockClient, []string{"team"}}
+	usage, err := check.Usage(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, usage, 1)
+	assert.Nil(t, usage[0].Tags)
+}
+
+func (m *mockS3ControlClient) ListAccessPointsPagesWithContext(ctx aws.Context, input *s3control.ListAccessPointsInput, fn func(*s3control.ListAccessPointsOutput, bool) bool, opts ...request.Option) error {
+	m.accountID = input.AccountId
+	fn(m.ListAccessPointsResponse, true)
+	return m.err
+}
+
+func (m *mockS3ControlClient) ListMultiRegionAccessPointsPagesWithContext(ctx aws.Context, input *s3control.ListMultiRegionAccessPointsInput, fn func(*s3control.ListMultiRegionAccessPointsOutput, bool) bool, opts ...request.Option) error {
+	m.accountID = input.AccountId
+	fn(m.ListMultiRegionAccessPointsResponse, true)
+	return m.err
+}
+
+func (m *mockSTSClient) GetCallerIdentityWithContext(ctx aws.Context, input *sts.GetCallerIdentityInput, opts ...request.Option) (*sts.GetCallerIdentityOutput, error) {
+	m.GetCallerIdentityCalls++
+	return m.GetCallerIdentityResponse, m.err
+}
+
+func testAccountID() *accountID {
+	return &accountID{client: &mockSTSClient{
+		GetCallerIdentityResponse: &sts.GetCallerIdentityOutput{Account: aws.String("123456789012")},
+	}}
+}
+
+func TestAccountIDIsCached(t *testing.T) {
+	stsClient := &mockSTSClient{
+		GetCallerIdentityResponse: &sts.GetCallerIdentityOutput{Account: aws.String("123456789012")},
+	}
+	resolver := &accountID{client: stsClient}
+
+	for i := 0; i < 2; i++ {
+		id, err := resolver.get(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "123456789012", id)
+	}
+	assert.Equal(t, 1, stsClient.GetCallerIdentityCalls)
+}
+
+func TestAccessPointsPerAccountCheckWithAccountIDError(t *testing.T) {
+	check := AccessPointsPerAccountCheck{&mockS3ControlClient{}, &accountID{client: &mockSTSClient{err: errors.New("some err")}}}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestAccessPointsPerAccountCheckWithError(t *testing.T) {
+	mockClient := &mockS3ControlClient{
+		err:                      errors.New("some err"),
+		ListAccessPointsResponse: nil,
+	}
+
+	check := AccessPointsPerAccountCheck{mockClient, testAccountID()}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestAccessPointsPerAccountCheck(t *testing.T) {
+	mockClient := &mockS3ControlClient{
+		ListAccessPointsResponse: &s3control.ListAccessPointsOutput{
+			AccessPointList: []*s3control.AccessPoint{
+				{Name: aws.String("access-point1")},
+				{Name: aws.String("access-point2")},
+			},
+		},
+	}
+
+	check := AccessPointsPerAccountCheck{mockClient, testAccountID()}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        accessPointsPerAccountName,
+			Description: accessPointsPerAccountDescription,
+			Usage:       2,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+	assert.Equal(t, "123456789012", aws.StringValue(mockClient.accountID))
+}
+
+func TestMultiRegionAccessPointsCheckWithError(t *testing.T) {
+	mockClient := &mockS3ControlClient{
+		err:                                 errors.New("some err"),
+		ListMultiRegionAccessPointsResponse: nil,
+	}
+
+	check := MultiRegionAccessPointsCheck{mockClient, testAccountID()}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestMultiRegionAccessPointsCheck(t *testing.T) {
+	mockClient := &mockS3ControlClient{
+		ListMultiRegionAccessPointsResponse: &s3control.ListMultiRegionAccessPointsOutput{
+			AccessPoints: []*s3control.MultiRegionAccessPointReport{
+				{Name: aws.String("mrap1")},
+			},
+		},
+	}
+
+	check := MultiRegionAccessPointsCheck{mockClient, testAccountID()}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        multiRegionAccessPointsName,
+			Description: multiRegionAccessPointsDescription,
+			Usage:       1,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+	assert.Equal(t, "123456789012", aws.StringValue(mockClient.accountID))
+}
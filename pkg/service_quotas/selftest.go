@@ -0,0 +1,80 @@
+package servicequotas
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// selfTestAccountID is the account ID of the checks created by
+// SelfTest, so that the checks depending on it are validated too
+const selfTestAccountID = "000000000000"
+
+// SelfTest creates the usage checks registered with `options`, without
+// calling AWS, and returns an error for each usage whose name is not a
+// valid Prometheus metric name or whose description is empty, as well
+// as for each check that does not describe its usages. No errors are
+// returned when every registered check is valid
+func SelfTest(options Options) ([]error, error) {
+	awsSession, err := session.NewSession(&aws.Config{
+		Region:      aws.String(defaultGlobalRegion),
+		Credentials: credentials.NewStaticCredentials("selftest", "selftest", ""),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := aws.NewConfig().WithRegion(defaultGlobalRegion)
+	serviceQuotasChecks, serviceDefaultChecks, otherChecks, _ := newUsageChecks(options, awsSession, cfg, cfg, selfTestAccountID, &instanceTypesCache{}, &jobRunsCache{})
+
+	checks := map[string]UsageCheck{}
+	for code, check := range serviceQuotasChecks {
+		checks[code] = check
+	}
+	for code, check := range serviceDefaultChecks {
+		checks[code] = check
+	}
+	for _, check := range otherChecks {
+		checks[checkName(check)] = check
+	}
+
+	keys := make([]string, 0, len(checks))
+	for key := range checks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var failures []error
+	for _, key := range keys {
+		failures = append(failures, validateUsageCheck(key, checks[key])...)
+	}
+	return failures, nil
+}
+
+// validateUsageCheck returns the errors of the usages described by
+// `check`, registered as `key`
+func validateUsageCheck(key string, check UsageCheck) []error {
+	var usages []QuotaUsage
+	switch describer := check.(type) {
+	case UsageDescriber:
+		usages = describer.DescribeUsage()
+	case ResourceUsageDescriber:
+		usages = describer.DescribeResourceUsage()
+	default:
+		return []error{fmt.Errorf("%s (%T) does not describe its usages", key, check)}
+	}
+
+	var failures []error
+	for _, usage := range usages {
+		if formatted := ToPrometheusNamingFormat(usage.Name); usage.Name == "" || formatted != usage.Name {
+			failures = append(failures, fmt.Errorf("%s (%T) reports invalid metric name %q, expected %q", key, check, usage.Name, formatted))
+		}
+		if usage.Description == "" {
+			failures = append(failures, fmt.Errorf("%s (%T) reports %s without a description", key, check, usage.Name))
+		}
+	}
+	return failures
+}
@@ -0,0 +1,68 @@
+package servicequotas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type describedUsageCheck struct {
+	FakeUsageCheck
+	usages []QuotaUsage
+}
+
+func (c *describedUsageCheck) DescribeUsage() []QuotaUsage {
+	return c.usages
+}
+
+func TestSelfTest(t *testing.T) {
+	failures, err := SelfTest(Options{
+		TotalRulesPerSecurityGroup: true,
+		LogStreamsPerLogGroup:      true,
+	})
+
+	assert.NoError(t, err)
+	assert.Empty(t, failures)
+}
+
+func TestValidateUsageCheck(t *testing.T) {
+	testCases := []struct {
+		name     string
+		check    UsageCheck
+		failures int
+	}{
+		{
+			name:     "Valid",
+			check:    &describedUsageCheck{usages: []QuotaUsage{{Name: "some_quota", Description: "some quota"}}},
+			failures: 0,
+		},
+		{
+			name:     "InvalidName",
+			check:    &describedUsageCheck{usages: []QuotaUsage{{Name: "some-Quota", Description: "some quota"}}},
+			failures: 1,
+		},
+		{
+			name:     "EmptyName",
+			check:    &describedUsageCheck{usages: []QuotaUsage{{Description: "some quota"}}},
+			failures: 1,
+		},
+		{
+			name:     "EmptyDescription",
+			check:    &describedUsageCheck{usages: []QuotaUsage{{Name: "some_quota"}, {Name: "other_quota", Description: "other quota"}}},
+			failures: 1,
+		},
+		{
+			name:     "NotDescribed",
+			check:    &FakeUsageCheck{},
+			failures: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			failures := validateUsageCheck("L-1234", tc.check)
+
+			assert.Len(t, failures, tc.failures)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package servicequotas
+
+import (
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+)
+
+// serviceEndpointIDs holds the ID of the endpoints of the services
+// whose service quotas code differs from it
+var serviceEndpointIDs = map[string]string{
+	"vpc":      "ec2",
+	"ebs":      "ec2",
+	"ecr":      "api.ecr",
+	"fargate":  "ecs",
+	"cloudhsm": "cloudhsmv2",
+	"ses":      "email",
+}
+
+// isServiceAvailable returns whether the endpoints of `partitions`, the
+// partitions of the AWS SDK, list `service` in `region`. The regions
+// and services missing from the endpoints, as well as the global
+// services, are assumed to be available
+func isServiceAvailable(partitions []endpoints.Partition, service, region string) bool {
+	endpointID, ok := serviceEndpointIDs[service]
+	if !ok {
+		endpointID = service
+	}
+
+	for _, partition := range partitions {
+		if _, ok := partition.Regions()[region]; !ok {
+			continue
+		}
+		partitionService, ok := partition.Services()[endpointID]
+		if !ok {
+			return true
+		}
+		regions := partitionService.Regions()
+		if len(regions) == 0 {
+			// the global services have a single partition endpoint
+			return true
+		}
+		_, ok = regions[region]
+		return ok
+	}
+	return true
+}
+
+// serviceAvailable returns whether the usage checks of `service` run in
+// the region of `s`, logging the services that are skipped. The forced
+// services always run, see Options.ForcedServices
+func (s *ServiceQuotas) serviceAvailable(service string) bool {
+	partitions := s.partitions
+	if partitions == nil {
+		partitions = endpoints.DefaultPartitions()
+	}
+	if service == "" || s.forcedServices[service] || isServiceAvailable(partitions, service, s.region) {
+		return true
+	}
+	log.Debugf("Skipping the usage checks of %s, it is not available in %s", service, s.region)
+	return false
+}
@@ -0,0 +1,103 @@
+package servicequotas
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// testEndpointsModel is an endpoints model where glue is available in
+// eu-west-1 only and route53 is a global service, so that the tests do
+// not depend on the endpoints of the AWS SDK
+const testEndpointsModel = `{
+  "version": 3,
+  "partitions": [{
+    "partition": "aws",
+    "partitionName": "AWS Standard",
+    "dnsSuffix": "amazonaws.com",
+    "regionRegex": "^(eu|xx)\\-\\w+\\-\\d+$",
+    "defaults": {"hostname": "{service}.{region}.{dnsSuffix}", "protocols": ["https"], "signatureVersions": ["v4"]},
+    "regions": {"eu-west-1": {"description": "Europe (Ireland)"}, "xx-new-1": {"description": "New region"}},
+    "services": {
+      "glue": {"endpoints": {"eu-west-1": {}}},
+      "api.ecr": {"endpoints": {"eu-west-1": {}}},
+      "route53": {"isRegionalized": false, "partitionEndpoint": "aws-global", "endpoints": {"aws-global": {}}}
+    }
+  }]
+}`
+
+// testPartitions returns the partitions of testEndpointsModel
+func testPartitions(t *testing.T) []endpoints.Partition {
+	resolver, err := endpoints.DecodeModel(strings.NewReader(testEndpointsModel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resolver.(endpoints.EnumPartitions).Partitions()
+}
+
+func TestIsServiceAvailable(t *testing.T) {
+	testCases := []struct {
+		name     string
+		service  string
+		region   string
+		expected bool
+	}{
+		{"Available", "glue", "eu-west-1", true},
+		{"NotAvailable", "glue", "xx-new-1", false},
+		{"DifferentEndpointID", "ecr", "eu-west-1", true},
+		{"GlobalService", "route53", "xx-new-1", true},
+		{"UnlistedService", "logs", "xx-new-1", true},
+		{"UnknownService", "someservice", "eu-west-1", true},
+		{"UnknownRegion", "glue", "xx-west-1", true},
+	}
+
+	partitions := testPartitions(t)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isServiceAvailable(partitions, tc.service, tc.region))
+		})
+	}
+}
+
+func TestQuotasAndUsageSkipsUnavailableServices(t *testing.T) {
+	glueCheck := &UsageCheckMock{usages: []QuotaUsage{{Name: "glue_quota", Usage: 1}}}
+	serviceQuotas := ServiceQuotas{
+		region:           "xx-new-1",
+		quotasService:    &mockServiceQuotasClient{},
+		otherUsageChecks: []UsageCheck{&ActiveDPUsCheck{}, glueCheck},
+		partitions:       testPartitions(t),
+	}
+
+	usages, err := serviceQuotas.QuotasAndUsage()
+
+	assert.NoError(t, err)
+	assert.Len(t, usages, 1)
+	assert.Equal(t, "glue_quota", usages[0].Name)
+}
+
+func TestQuotasAndUsageRunsForcedServices(t *testing.T) {
+	serviceQuotas := ServiceQuotas{
+		region:           "xx-new-1",
+		quotasService:    &mockServiceQuotasClient{},
+		otherUsageChecks: []UsageCheck{&ActiveDPUsCheck{client: &mockGlueClient{}}},
+		forcedServices:   map[string]bool{"glue": true},
+		partitions:       testPartitions(t),
+	}
+
+	usages, err := serviceQuotas.QuotasAndUsage()
+
+	assert.NoError(t, err)
+	assert.Len(t, usages, 1)
+	assert.Equal(t, activeDPUsName, usages[0].Name)
+}
+
+func TestNewServiceQuotasWithInvalidForcedService(t *testing.T) {
+	svcQuotas, err := NewServiceQuotas("eu-west-1", "", Options{ForcedServices: []string{"asdasd"}})
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidService))
+	assert.Nil(t, svcQuotas)
+}
@@ -1,22 +1,55 @@
 package servicequotas
 
 import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/acmpca"
+	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/aws/aws-sdk-go/service/apigatewayv2"
+	"github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/aws/aws-sdk-go/service/athena"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go/service/cloudhsmv2"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/configservice"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/firehose"
 	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/imagebuilder"
+	"github.com/aws/aws-sdk-go/service/kinesis"
 	"github.com/aws/aws-sdk-go/service/kinesisanalyticsv2"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/lambda"
 	"github.com/aws/aws-sdk-go/service/rds"
 	"github.com/aws/aws-sdk-go/service/redshift"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3control"
 	awsservicequotas "github.com/aws/aws-sdk-go/service/servicequotas"
 	"github.com/aws/aws-sdk-go/service/servicequotas/servicequotasiface"
 	"github.com/aws/aws-sdk-go/service/sesv2"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/pkg/errors"
 	logging "github.com/sirupsen/logrus"
 )
@@ -26,23 +59,306 @@ var (
 	ErrInvalidRegion       = errors.New("invalid region")
 	ErrFailedToListQuotas  = errors.New("failed to list quotas")
 	ErrFailedToGetUsage    = errors.New("failed to get usage")
-	ErrFailedToConvertCidr = errors.New("failed to convert CIDR block from string to int")
+	ErrFailedToConvertCidr = errors.New("failed to parse CIDR block")
+	ErrInvalidService      = errors.New("invalid service")
+	ErrPartialUsage        = errors.New("some usage checks failed")
+	ErrFailedToGetAccount  = errors.New("failed to get the account ID")
+	ErrRegionNotEnabled    = errors.New("region not enabled for the account")
+	ErrInvalidQuota        = errors.New("invalid quota")
+	ErrResourceNotFound    = errors.New("resource not found")
 )
 
 func allServices() []string {
-	return []string{"ec2", "vpc", "rds", "ecr", "ecs", "logs", "kinesisanalytics", "redshift", "ebs", "glue"}
+	return []string{"ec2", "vpc", "rds", "ecr", "ecs", "logs", "kinesisanalytics", "redshift", "ebs", "glue", "fargate", "config", "acm-pca", "appmesh", "cloudhsm", "s3", "dynamodb", "firehose", "athena", "sns", "sqs", "elasticloadbalancing", "kms", "cloudfront", "apigateway", "kinesis", "imagebuilder"}
 }
 
 // UsageCheck is an interface for retrieving service quota usage
 type UsageCheck interface {
 	// Usage returns slice of QuotaUsage or an error
-	Usage() ([]QuotaUsage, error)
+	Usage(ctx context.Context) ([]QuotaUsage, error)
+}
+
+// UsageDescriber is implemented by the usage checks that always return
+// the same usages, regardless of the resources in the account
+type UsageDescriber interface {
+	// DescribeUsage returns the usages returned by the check with
+	// zero values, without calling AWS
+	DescribeUsage() []QuotaUsage
+}
+
+// ResourceUsageDescriber is implemented by the usage checks that return
+// one usage per resource
+type ResourceUsageDescriber interface {
+	// DescribeResourceUsage returns the usages returned by the check
+	// for each resource with zero values and no resource name, without
+	// calling AWS
+	DescribeResourceUsage() []QuotaUsage
+}
+
+// limitScaler is implemented by the usage checks returning usages
+// whose limit is a multiple of the quota of the check, such as the
+// combined inbound and outbound rules of a security group
+type limitScaler interface {
+	// limitScale returns the multiple of the quota that limits `usage`
+	limitScale(usage QuotaUsage) float64
+}
+
+// usageLimit returns the limit of `usage` of `check` whose quota has
+// the value `quota`
+func usageLimit(check UsageCheck, usage QuotaUsage, quota float64) float64 {
+	if scaler, ok := check.(limitScaler); ok {
+		return quota * scaler.limitScale(usage)
+	}
+	return quota
+}
+
+// resourceCap limits the number of resources a usage check pages
+// through. No limit is applied when `max` is 0
+type resourceCap struct {
+	max       int
+	count     int
+	truncated bool
+}
+
+// add counts `n` more resources and returns whether paging should
+// continue
+func (r *resourceCap) add(n int, lastPage bool) bool {
+	r.count += n
+	if r.max > 0 && r.count >= r.max && !lastPage {
+		r.truncated = true
+		return false
+	}
+	return !lastPage
+}
+
+// reached returns whether `max` resources have already been counted
+func (r *resourceCap) reached() bool {
+	return r.max > 0 && r.count >= r.max
+}
+
+// markTruncated flags `quotaUsages` as truncated and logs a warning if
+// paging was stopped by the cap
+func (r *resourceCap) markTruncated(quotaUsages []QuotaUsage) {
+	if !r.truncated {
+		return
+	}
+
+	for i := range quotaUsages {
+		quotaUsages[i].Truncated = true
+	}
+	if len(quotaUsages) > 0 {
+		log.Warnf("Stopped paging %s after %d resources, usage is incomplete", quotaUsages[0].Name, r.count)
+	}
+}
+
+// resourceSampler spreads the per-resource calls of a fan-out check
+// over several refreshes. Every resource is refreshed the first time it
+// is seen, after that each refresh only refreshes one of `interval`
+// groups of resources and reuses the previous usage of the others, so
+// that all resources are refreshed every `interval` refreshes. No
+// sampling is applied when `interval` is 1 or less
+type resourceSampler struct {
+	interval int
+	refresh  int
+	previous map[string]QuotaUsage
+	current  map[string]QuotaUsage
+}
+
+// start begins a new refresh of the check
+func (r *resourceSampler) start() {
+	r.refresh++
+	r.current = map[string]QuotaUsage{}
+}
+
+// skip returns the previous usage of `resource` and true if it is
+// not sampled in the current refresh
+func (r *resourceSampler) skip(resource string) (QuotaUsage, bool) {
+	if r.interval <= 1 {
+		return QuotaUsage{}, false
+	}
+
+	usage, ok := r.previous[resource]
+	if !ok || resourceGroup(resource, r.interval) == r.refresh%r.interval {
+		return QuotaUsage{}, false
+	}
+	r.current[resource] = usage
+	return usage, true
+}
+
+// record stores `usage` as the latest usage of `resource`
+func (r *resourceSampler) record(resource string, usage QuotaUsage) {
+	if r.interval <= 1 {
+		return
+	}
+	r.current[resource] = usage
 }
 
-func newUsageChecks(c client.ConfigProvider, cfgs ...*aws.Config) (map[string]UsageCheck, map[string]UsageCheck, []UsageCheck) {
+// finish completes the refresh, forgetting the resources that no
+// longer exist
+func (r *resourceSampler) finish() {
+	r.previous = r.current
+}
+
+// resourceGroup returns the sampling group of `resource` out of
+// `groups`, which stays the same across refreshes
+func resourceGroup(resource string, groups int) int {
+	h := fnv.New32a()
+	h.Write([]byte(resource))
+	return int(h.Sum32() % uint32(groups))
+}
+
+// Options configures the usage checks of ServiceQuotas
+type Options struct {
+	// TotalRulesPerSecurityGroup additionally reports the combined
+	// inbound and outbound rules per security group
+	TotalRulesPerSecurityGroup bool
+	// MaxResourcesPerCheck stops the usage checks that page through
+	// large numbers of resources after that many resources. No limit
+	// is applied when it is 0
+	MaxResourcesPerCheck int
+	// GlobalRegion is the region used by the clients of global
+	// services such as IAM, Route53 and CloudFront. Defaults to
+	// `defaultGlobalRegion`
+	GlobalRegion string
+	// ECRTaggedImagesOnly only counts the tagged images of each ECR
+	// repository
+	ECRTaggedImagesOnly bool
+	// SampleInterval is the number of refreshes over which the checks
+	// calling AWS once per resource spread those calls. Every resource
+	// is refreshed on each refresh when it is 0 or 1
+	SampleInterval int
+	// LogStreamsPerLogGroup enables the log streams per log group
+	// check, which pages through the log streams of every log group
+	LogStreamsPerLogGroup bool
+	// LogStreamsLogGroupPrefix limits the log streams per log group
+	// check to the log groups whose names start with it
+	LogStreamsLogGroupPrefix string
+	// KDAMaxPages stops paging the KDA applications after that many
+	// pages. No limit is applied when it is 0
+	KDAMaxPages int
+	// KDAParallelismOffset is added to the current parallelism reported
+	// by AWS for each KDA application to get its KPUs. AWS support
+	// confirmed that the reported parallelism is one less than the
+	// billed KPUs, so it defaults to defaultKDAParallelismOffset when
+	// nil. Set it to 0 to export the parallelism as reported
+	KDAParallelismOffset *int
+	// ServiceRegions maps service codes to the region in which their
+	// quotas and usage are retrieved instead of the default region
+	ServiceRegions map[string]string
+	// BestEffort logs and skips the usage checks that fail instead of
+	// failing QuotasAndUsage. The usages of the other checks are
+	// returned with an error wrapping ErrPartialUsage
+	BestEffort bool
+	// EC2SDKV2 backs the EC2 usage checks with an aws-sdk-go-v2 client
+	// instead of the aws-sdk-go one
+	EC2SDKV2 bool
+	// IncludeAWSTags are the tag keys exported as labels. The checks
+	// that need additional calls to read the tags of their resources
+	// only do so when it is set
+	IncludeAWSTags []string
+	// OnCheckError is called with the name of each usage check that
+	// fails and its error, a *CheckError, including the checks that
+	// are skipped
+	OnCheckError func(check string, err error)
+	// OnCheckDuration is called with the name of each usage check that
+	// ran and how long it took, whether it failed or not
+	OnCheckDuration func(check string, duration time.Duration)
+	// ResourceIdentifier is how the resources of the per-resource
+	// usages are identified, either ResourceIdentifierID (the default)
+	// or ResourceIdentifierARN
+	ResourceIdentifier string
+	// CheckTimeout bounds the AWS calls of each usage check, 0 means
+	// no timeout
+	CheckTimeout time.Duration
+	// SkipGlobalChecks disables the usage checks of global services
+	// such as Route53 and CloudFront, whose usage is the same in every region. It is
+	// set for all but one of the regions exported together
+	SkipGlobalChecks bool
+	// ForcedServices are the service codes whose usage checks run even
+	// in the regions where the AWS SDK does not list them as available,
+	// such as newly launched regions. The checks of the other services
+	// are skipped in those regions
+	ForcedServices []string
+	// ReportedUsage additionally retrieves the usage reported by AWS
+	// through the CloudWatch usage metric of the quotas that have one,
+	// alongside the usage computed by their checks
+	ReportedUsage bool
+	// KMSCustomerManagedKeysOnly describes every KMS key to only count
+	// the customer managed keys, which are the ones counted against the
+	// quota. The AWS managed keys are counted too when it is not set
+	KMSCustomerManagedKeysOnly bool
+	// APIGatewayStagesPerAPI additionally exports the stages of each
+	// API Gateway REST API, which needs one call per API
+	APIGatewayStagesPerAPI bool
+	// StrictRegion fails QuotasAndUsage when a region is not enabled
+	// for the account. Otherwise the opt-in regions that are not
+	// enabled are skipped and the usage is partial, see
+	// ErrRegionNotEnabled
+	StrictRegion bool
+	// AccountID is the account ID of the credentials when it is already
+	// known, see ResolveAccountID, so that it is not resolved again for
+	// each region. An empty ID means that it could not be resolved. It
+	// is resolved by NewServiceQuotasWithRole when nil
+	AccountID *string
+}
+
+// globalQuotaServices are the global services whose quotas are only
+// published by service quotas in the global region
+func globalQuotaServices() []string {
+	return []string{"cloudfront"}
+}
+
+// serviceRegions returns the region overrides of `options` with the
+// global services retrieved in `globalRegion` unless overridden. The
+// global services are left out when their checks are skipped
+func serviceRegions(options Options, globalRegion string) map[string]string {
+	regions := map[string]string{}
+	for service, serviceRegion := range options.ServiceRegions {
+		regions[service] = serviceRegion
+	}
+	if options.SkipGlobalChecks {
+		return regions
+	}
+	for _, service := range globalQuotaServices() {
+		if _, ok := regions[service]; !ok {
+			regions[service] = globalRegion
+		}
+	}
+	return regions
+}
+
+// defaultGlobalRegion is the region hosting the endpoints of the
+// global services in the standard AWS partition
+const defaultGlobalRegion = "us-east-1"
+
+// defaultKDAParallelismOffset is the difference between the KPUs billed
+// by AWS and the parallelism it reports, see Options.KDAParallelismOffset
+const defaultKDAParallelismOffset = 1
+
+// kdaParallelismOffset returns the KDA parallelism offset of `options`
+func kdaParallelismOffset(options Options) int64 {
+	if options.KDAParallelismOffset == nil {
+		return defaultKDAParallelismOffset
+	}
+	return int64(*options.KDAParallelismOffset)
+}
+
+// newUsageChecks creates the usage checks with clients for the region
+// set in `cfg`. Clients for global services are created with
+// `globalCfg` instead. The checks of the S3 Control API, which needs
+// the account ID of the credentials, are only created when
+// `callerAccountID` is set. The EC2 checks share `instanceTypes` and
+// the glue job runs checks share `jobRuns`. The
+// checks of the applied and default quotas are returned by quota code,
+// along with the service each quota code is registered under
+func newUsageChecks(options Options, c client.ConfigProvider, cfg, globalCfg *aws.Config, callerAccountID string, instanceTypes *instanceTypesCache, jobRuns *jobRunsCache) (map[string]UsageCheck, map[string]UsageCheck, []UsageCheck, map[string]string) {
+	cfgs := []*aws.Config{cfg}
 
 	// all clients that will be used by the usage checks
-	ec2Client := ec2.New(c, cfgs...)
+	var ec2Client ec2iface.EC2API = ec2.New(c, cfgs...)
+	if options.EC2SDKV2 {
+		ec2Client = newEC2V2Client(c, cfgs...)
+	}
 	autoscalingClient := autoscaling.New(c, cfgs...)
 	rdsClient := rds.New(c, cfgs...)
 	ecrClient := ecr.New(c, cfgs...)
@@ -51,49 +367,205 @@ func newUsageChecks(c client.ConfigProvider, cfgs ...*aws.Config) (map[string]Us
 	kdaClient := kinesisanalyticsv2.New(c, cfgs...)
 	rsClient := redshift.New(c, cfgs...)
 	glueClient := glue.New(c, cfgs...)
+	ecsClient := ecs.New(c, cfgs...)
+	configClient := configservice.New(c, cfgs...)
+	lambdaClient := lambda.New(c, cfgs...)
+	snsClient := sns.New(c, cfgs...)
+	sqsClient := sqs.New(c, cfgs...)
+	elbClient := elb.New(c, cfgs...)
+	elbv2Client := elbv2.New(c, cfgs...)
+	acmpcaClient := acmpca.New(c, cfgs...)
+	appmeshClient := appmesh.New(c, cfgs...)
+	cloudhsmClient := cloudhsmv2.New(c, cfgs...)
+	s3Client := s3.New(c, cfgs...)
+	s3controlClient := s3control.New(c, cfgs...)
+	mrapClient := s3control.New(c, aws.NewConfig().WithRegion(multiRegionAccessPointsRegion))
+	s3AccountID := &accountID{client: sts.New(c, cfgs...), id: callerAccountID}
+	dynamodbClient := dynamodb.New(c, cfgs...)
+	firehoseClient := firehose.New(c, cfgs...)
+	athenaClient := athena.New(c, cfgs...)
+	kmsClient := kms.New(c, cfgs...)
+	apigatewayClient := apigateway.New(c, cfgs...)
+	apigatewayv2Client := apigatewayv2.New(c, cfgs...)
+	kinesisClient := kinesis.New(c, cfgs...)
+	imagebuilderClient := imagebuilder.New(c, cfgs...)
+	route53Client := route53.New(c, globalCfg)
+	cloudfrontClient := cloudfront.New(c, globalCfg)
 
-	serviceQuotasUsageChecks := map[string]UsageCheck{
-		"L-0EA8095F": &RulesPerSecurityGroupUsageCheck{ec2Client},
-		"L-2AFB9258": &SecurityGroupsPerENIUsageCheck{ec2Client},
-		"L-E79EC296": &SecurityGroupsPerRegionUsageCheck{ec2Client},
-		"L-34B43A08": &StandardSpotInstanceRequestsUsageCheck{ec2Client},
-		"L-1216C47A": &RunningOnDemandStandardInstancesUsageCheck{ec2Client},
-		"L-5BC124EF": &ReadReplicasPerMasterCheck{rdsClient},
-		"L-DF5E4CA3": &ENIsPerRegionCheck{ec2Client},
-		"L-C7B9AAAB": &LogGroupsPerRegionCheck{logsClient},
-		"L-7A658B76": &MaxGP3StoragePerRegionCheck{ec2Client},
-		"L-D18FCD1D": &MaxGP2StoragePerRegionCheck{ec2Client},
-		"L-FD252861": &MaxIo1StoragePerRegionCheck{ec2Client},
-		"L-09BD8365": &MaxIo2StoragePerRegionCheck{ec2Client},
-		"L-82ACEF56": &MaxSt1StoragePerRegionCheck{ec2Client},
-		"L-9CF3C2EB": &MaxStandardStoragePerRegionCheck{ec2Client},
-		"L-17AF77E8": &MaxSc1StoragePerRegionCheck{ec2Client},
-		"L-309BACF6": &EbsSnapshotsPerRegionCheck{ec2Client},
-		"L-8D977E7E": &MaxIo2IopsPerRegionCheck{ec2Client},
-		"L-B3A130E6": &MaxIo1IopsPerRegionCheck{ec2Client},
-		"L-EEC98450": &JobsPerTriggerCheck{glueClient},
-		"L-611FDDE4": &JobsPerAccountCheck{glueClient},
-		"L-F574AED9": &ConcurrentRunsPerJobCheck{glueClient},
-		"L-08F3B322": &DPUsCheck{glueClient},
-		"L-5E4153CA": &ConcurrentRunsCheck{glueClient},
-	}
-
-	serviceDefaultUsageChecks := map[string]UsageCheck{
-		"L-CFEB8E8D": &RepositoriesPerRegionCheck{ecrClient},
-		"L-03A36CE1": &ImagesPerRepositoryCheck{ecrClient},
-		"L-3A88E041": &AppKPUUsageCheck{kdaClient},
-		"L-3729A2EF": &AppsPerRegionCheck{kdaClient},
-		"L-2E428669": &UserSnapshotsPerRegionCheck{rsClient},
+	serviceQuotasUsageChecks := serviceUsageChecks{
+		"vpc": {
+			"L-0EA8095F": &RulesPerSecurityGroupUsageCheck{ec2Client, options.TotalRulesPerSecurityGroup},
+			"L-2AFB9258": &SecurityGroupsPerENIUsageCheck{ec2Client, options.MaxResourcesPerCheck},
+			"L-E79EC296": &SecurityGroupsPerRegionUsageCheck{ec2Client},
+			"L-DF5E4CA3": &ENIsPerRegionCheck{ec2Client},
+			"L-2DB1F0D8": &ManagedPrefixListsPerRegionCheck{ec2Client},
+			"L-7A8A7D4E": &EntriesPerPrefixListCheck{ec2Client},
+		},
+		"ec2": {
+			"L-34B43A08": &StandardSpotInstanceRequestsUsageCheck{ec2Client, instanceTypes},
+			"L-1216C47A": &RunningOnDemandStandardInstancesUsageCheck{ec2Client, instanceTypes},
+			"L-3E6EC3A3": &VpnGatewaysPerRegionCheck{ec2Client},
+			"L-4FB7FF5D": &CustomerGatewaysPerRegionCheck{ec2Client},
+		},
+		"rds": {
+			"L-5BC124EF": &ReadReplicasPerMasterCheck{rdsClient},
+		},
+		"logs": {
+			"L-C7B9AAAB": &LogGroupsPerRegionCheck{logsClient, options.MaxResourcesPerCheck},
+		},
+		"ebs": {
+			"L-7A658B76": &MaxGP3StoragePerRegionCheck{ec2Client},
+			"L-D18FCD1D": &MaxGP2StoragePerRegionCheck{ec2Client},
+			"L-FD252861": &MaxIo1StoragePerRegionCheck{ec2Client},
+			"L-09BD8365": &MaxIo2StoragePerRegionCheck{ec2Client},
+			"L-82ACEF56": &MaxSt1StoragePerRegionCheck{ec2Client},
+			"L-9CF3C2EB": &MaxStandardStoragePerRegionCheck{ec2Client},
+			"L-17AF77E8": &MaxSc1StoragePerRegionCheck{ec2Client},
+			"L-309BACF6": &EbsSnapshotsPerRegionCheck{ec2Client, options.MaxResourcesPerCheck},
+			"L-0B3D5F88": &FastSnapshotRestoresPerRegionCheck{ec2Client},
+			"L-8D977E7E": &MaxIo2IopsPerRegionCheck{ec2Client},
+			"L-B3A130E6": &MaxIo1IopsPerRegionCheck{ec2Client},
+		},
+		"glue": {
+			"L-EEC98450": &JobsPerTriggerCheck{glueClient},
+			"L-611FDDE4": &JobsPerAccountCheck{glueClient},
+			"L-F574AED9": &ConcurrentRunsPerJobCheck{glueClient},
+			"L-08F3B322": &DPUsCheck{glueClient},
+			"L-5E4153CA": &ConcurrentRunsCheck{glueClient, jobRuns},
+			"L-F7B7A1D2": &ConcurrentSessionsCheck{glueClient},
+			"L-C8D3F2F1": &ConcurrentBlueprintRunsCheck{glueClient},
+		},
+		"fargate": {
+			"L-3032A538": &FargateOnDemandVCPUsCheck{ecsClient},
+			"L-36FBB829": &FargateSpotVCPUsCheck{ecsClient},
+		},
+		"ecs": {
+			"L-21C621EB": &ClustersPerAccountCheck{ecsClient},
+			"L-9EF96962": &ServicesPerClusterCheck{ecsClient, options.MaxResourcesPerCheck},
+		},
+		"acm-pca": {
+			"L-3CF5E486": &AcmPcaCertificateAuthoritiesCheck{acmpcaClient},
+		},
+		"appmesh": {
+			"L-AC861A39": &MeshesPerAccountCheck{appmeshClient},
+			"L-A59F6E50": &VirtualNodesPerMeshCheck{appmeshClient},
+		},
+		"cloudhsm": {
+			"L-87E3E8EB": &ClustersPerRegionCheck{cloudhsmClient},
+			"L-A3FD4C1E": &HsmsPerClusterCheck{cloudhsmClient},
+		},
+		"dynamodb": {
+			"L-F98FE922": &TablesPerRegionCheck{dynamodbClient},
+			"L-CF0CBE56": &ReadCapacityPerTableCheck{dynamodbClient},
+			"L-AB614373": &WriteCapacityPerTableCheck{dynamodbClient},
+		},
+		"firehose": {
+			"L-D8E6B9A2": &FirehoseDeliveryStreamsCheck{firehoseClient},
+		},
+		"athena": {
+			"L-FC5F6546": &ActiveQueriesCheck{athenaClient, options.MaxResourcesPerCheck, time.Now},
+		},
+		"sns": {
+			"L-61103206": &TopicsPerAccountCheck{snsClient},
+		},
+		"elasticloadbalancing": {
+			"L-53DA6B97": &LoadBalancersPerRegionCheck{elbv2Client, elbv2.LoadBalancerTypeEnumApplication, options.IncludeAWSTags},
+			"L-69A177A2": &LoadBalancersPerRegionCheck{elbv2Client, elbv2.LoadBalancerTypeEnumNetwork, options.IncludeAWSTags},
+			"L-E9E9831D": &ClassicLoadBalancersPerRegionCheck{elbClient},
+			"L-B22855CB": &TargetGroupsPerRegionCheck{elbv2Client},
+		},
+		"kms": {
+			"L-C2F1777E": &CustomerManagedKeysCheck{kmsClient, options.KMSCustomerManagedKeysOnly},
+		},
+		"apigateway": {
+			"L-8A5B8E43": &APIsPerRegionCheck{apigatewayClient, apigatewayv2Client, restAPIType},
+		},
+		"kinesis": {
+			"L-8102E1DA": &ShardsPerRegionCheck{kinesisClient},
+		},
+	}
+	if options.APIGatewayStagesPerAPI {
+		serviceQuotasUsageChecks["apigateway"]["L-379E48B0"] = &StagesPerAPICheck{apigatewayClient}
+	}
+
+	serviceDefaultUsageChecks := serviceUsageChecks{
+		"ecr": {
+			"L-CFEB8E8D": &RepositoriesPerRegionCheck{ecrClient},
+			"L-03A36CE1": &ImagesPerRepositoryCheck{ecrClient, options.MaxResourcesPerCheck, options.ECRTaggedImagesOnly, resourceSampler{interval: options.SampleInterval}},
+		},
+		"kinesisanalytics": {
+			"L-3A88E041": &AppKPUUsageCheck{kdaClient, resourceSampler{interval: options.SampleInterval}, options.KDAMaxPages, kdaParallelismOffset(options)},
+			"L-3729A2EF": &AppsPerRegionCheck{kdaClient, options.KDAMaxPages},
+		},
+		"redshift": {
+			"L-2E428669": &UserSnapshotsPerRegionCheck{rsClient, options.MaxResourcesPerCheck},
+		},
+		"config": {
+			"L-36F1A3DE": &ConfigRulesPerRegionCheck{configClient},
+		},
+		"s3": {
+			"L-DC2B2D3D": &BucketsPerAccountCheck{s3Client, options.IncludeAWSTags},
+		},
+	}
+	if !options.SkipGlobalChecks {
+		serviceDefaultUsageChecks["cloudfront"] = map[string]UsageCheck{"L-24B04930": &DistributionsPerAccountCheck{cloudfrontClient}}
+	}
+	if callerAccountID != "" {
+		serviceDefaultUsageChecks["s3"]["L-FAABEEBA"] = &AccessPointsPerAccountCheck{s3controlClient, s3AccountID}
+		serviceDefaultUsageChecks["s3"]["L-5F5D3C8F"] = &MultiRegionAccessPointsCheck{mrapClient, s3AccountID}
 	}
 
 	otherUsageChecks := []UsageCheck{
 		&AvailableIpsPerSubnetUsageCheck{ec2Client},
+		&ENIsPerAZCheck{ec2Client},
+		&ENIsPerInstanceCheck{ec2Client, options.MaxResourcesPerCheck, instanceTypes},
+		&SpotInstanceRequestsCountCheck{ec2Client},
 		&ASGUsageCheck{autoscalingClient},
 		&MaxSendIn24HoursCheck{sesv2Client},
+		&ProvisionedConcurrencyCheck{lambdaClient},
+		&EventSourceMappingsCheck{lambdaClient},
+		&FunctionUrlsCheck{lambdaClient},
+		&SubscriptionsPerTopicCheck{snsClient, options.MaxResourcesPerCheck, resourceSampler{interval: options.SampleInterval}},
+		&QueuesPerAccountCheck{sqsClient, options.IncludeAWSTags},
+		&JobsPerSecurityConfigurationCheck{glueClient},
+		&ActiveDPUsCheck{glueClient, jobRuns},
+		&ConcurrentCrawlerRunsCheck{glueClient},
+		&APIsPerRegionCheck{apigatewayClient, apigatewayv2Client, httpAPIType},
+		&ImagePipelinesCheck{imagebuilderClient},
+		&ImageRecipesCheck{imagebuilderClient},
 		// &MaxTotalStorageCheck{rdsClient}, //Need to review this check
 	}
+	if !options.SkipGlobalChecks {
+		otherUsageChecks = append(otherUsageChecks,
+			&HealthChecksPerAccountCheck{route53Client},
+			&TrafficPoliciesCheck{route53Client},
+			&HostedZonesCheck{route53Client},
+			&RecordsPerZoneCheck{route53Client, resourceSampler{interval: options.SampleInterval}},
+		)
+	}
+	if options.LogStreamsPerLogGroup {
+		otherUsageChecks = append(otherUsageChecks, &LogStreamsPerLogGroupCheck{logsClient, options.LogStreamsLogGroupPrefix, options.MaxResourcesPerCheck})
+	}
+
+	quotaServices := map[string]string{}
+	return serviceQuotasUsageChecks.byQuota(quotaServices), serviceDefaultUsageChecks.byQuota(quotaServices), otherUsageChecks, quotaServices
+}
+
+// serviceUsageChecks holds the usage checks of the quotas of each
+// service, by service quotas service code and quota code
+type serviceUsageChecks map[string]map[string]UsageCheck
 
-	return serviceQuotasUsageChecks, serviceDefaultUsageChecks, otherUsageChecks
+// byQuota returns the checks of c by quota code, adding the service of
+// each quota code to `quotaServices`
+func (c serviceUsageChecks) byQuota(quotaServices map[string]string) map[string]UsageCheck {
+	checks := map[string]UsageCheck{}
+	for service, serviceChecks := range c {
+		for quotaCode, check := range serviceChecks {
+			checks[quotaCode] = check
+			quotaServices[quotaCode] = service
+		}
+	}
+	return checks
 }
 
 // QuotaUsage represents service quota usage
@@ -114,6 +586,29 @@ type QuotaUsage struct {
 	Usage float64
 	// Quota is the current quota
 	Quota float64
+	// Unit is the unit of the usage and the quota, e.g. UnitTiB for
+	// the storage quotas. It is empty for the counts, see UnitCount
+	Unit string
+	// Adjustable is whether AWS allows the quota to be increased.
+	// Only quotas retrieved through the service quotas API can be
+	// adjustable
+	Adjustable bool
+	// Truncated is set when the usage check stopped paging after the
+	// maximum number of resources and the usage is incomplete
+	Truncated bool
+	// Region is the region in which the quota and usage were
+	// retrieved
+	Region string
+	// CollectedAt is the time at which the usage check ran. Sinks that
+	// support explicit timestamps can use it instead of the time the
+	// metrics are sent
+	CollectedAt time.Time
+
+	// ReportedUsage is the usage AWS reports through the CloudWatch
+	// usage metric of the quota. It is only set on the usages of the
+	// whole quota when Options.ReportedUsage is set and the metric has
+	// a recent datapoint
+	ReportedUsage *float64
 
 	// Tags are the metadata associated with the resource in form of key, value pairs
 	Tags map[string]string
@@ -138,23 +633,210 @@ type ServiceQuotas struct {
 	serviceQuotasUsageChecks  map[string]UsageCheck
 	serviceDefaultUsageChecks map[string]UsageCheck
 	otherUsageChecks          []UsageCheck
+	// quotaServices holds the service the checks of the applied and
+	// default quotas are registered under, by quota code
+	quotaServices map[string]string
+	clock         func() time.Time
+	// bestEffort skips the usage checks that fail, see
+	// Options.BestEffort
+	bestEffort bool
+	// serviceRegions holds the ServiceQuotas used for the services
+	// retrieved in a different region
+	serviceRegions map[string]*ServiceQuotas
+	// onCheckError is called when a usage check fails, see
+	// Options.OnCheckError
+	onCheckError func(check string, err error)
+	// onCheckDuration is called after each usage check, see
+	// Options.OnCheckDuration
+	onCheckDuration func(check string, duration time.Duration)
+	// checkTimeout bounds each usage check, see Options.CheckTimeout
+	checkTimeout time.Duration
+	// mutex serializes the runs of the usage checks, which can outlive
+	// a QuotasAndUsageWithContext call whose context is done
+	mutex sync.Mutex
+	// checkMutex serializes the calls to the usage checks, which keep
+	// state between refreshes, with those of UsageForResource that run
+	// while a refresh is in progress
+	checkMutex sync.Mutex
+	// resourceAccountID is the account in the ARNs of the resources,
+	// they are identified by their ID or name when it is empty
+	resourceAccountID string
+	// forcedServices run in every region, see Options.ForcedServices
+	forcedServices map[string]bool
+	// partitions are the endpoints partitions listing the services
+	// available in each region, those of the AWS SDK when nil
+	partitions []endpoints.Partition
+	// cloudwatchClient retrieves the usage reported by AWS, it is only
+	// set when Options.ReportedUsage is
+	cloudwatchClient cloudwatchiface.CloudWatchAPI
+	// instanceTypes is the instance types cache of the EC2 checks,
+	// reset at the start of each refresh
+	instanceTypes *instanceTypesCache
+	// jobRuns is the running job runs cache of the glue checks, reset
+	// at the start of each refresh
+	jobRuns *jobRunsCache
+	// strictRegion fails the refreshes of a region that is not enabled,
+	// see Options.StrictRegion
+	strictRegion bool
+	// regionNotEnabledLog logs that the region is skipped once
+	regionNotEnabledLog sync.Once
+	// availableQuotas holds the number of quotas listed by AWS by
+	// service, guarded by availableQuotasMutex as it is read while the
+	// checks run
+	availableQuotas      map[string]int
+	availableQuotasMutex sync.Mutex
 }
 
 // QuotasInterface is an interface for retrieving AWS service
 // quotas and usage
 type QuotasInterface interface {
 	QuotasAndUsage() ([]QuotaUsage, error)
+	DescribeQuotas() []QuotaUsage
+}
+
+// ContextQuotasInterface is implemented by the QuotasInterface
+// implementations that can bound the retrieval of the quotas and usage
+// with a context
+type ContextQuotasInterface interface {
+	QuotasAndUsageWithContext(ctx context.Context) ([]QuotaUsage, error)
+}
+
+// CoverageQuotasInterface is implemented by the QuotasInterface
+// implementations that report how many of the quotas listed by AWS
+// their usage checks cover
+type CoverageQuotasInterface interface {
+	// RegisteredChecks returns the number of usage checks of the
+	// quotas counted by AvailableQuotas
+	RegisteredChecks() int
+	// AvailableQuotas returns the number of quotas listed by AWS by
+	// service during the last refresh that listed them
+	AvailableQuotas() map[string]int
+}
+
+// ResourceQuotasInterface is implemented by the QuotasInterface
+// implementations that can retrieve the usage of a single resource
+type ResourceQuotasInterface interface {
+	// UsageForResource returns the usage of the resource `resourceID`
+	// for the quota `quotaCode` or an error, bounded by `ctx`
+	UsageForResource(ctx context.Context, quotaCode, resourceID string) (*QuotaUsage, error)
 }
 
 // NewServiceQuotas creates a ServiceQuotas for `region` and `profile`
-// or returns an error. Note that the ServiceQuotas will only return
-// usage and quotas for the service quotas with implemented usage checks
-func NewServiceQuotas(region, profile string) (QuotasInterface, error) {
+// with the usage checks configured by `options` or returns an error.
+// Note that the ServiceQuotas will only return usage and quotas for
+// the service quotas with implemented usage checks
+func NewServiceQuotas(region, profile string, options Options) (QuotasInterface, error) {
+	return NewServiceQuotasWithRole(region, profile, "", "", options)
+}
+
+// assumeRoleExpiryWindow is how long before they expire the credentials
+// of an assumed role are renewed, so that they do not expire during a
+// refresh
+const assumeRoleExpiryWindow = 5 * time.Minute
+
+// NewServiceQuotasWithRole is NewServiceQuotas with the credentials of
+// `roleARN`, assumed with `externalID` using the credentials of
+// `profile`. The role is assumed again whenever its credentials are
+// about to expire. The credentials of `profile` are used as they are
+// when `roleARN` is empty, and no external ID is passed when
+// `externalID` is empty
+func NewServiceQuotasWithRole(region, profile, roleARN, externalID string, options Options) (QuotasInterface, error) {
 	validRegion, isChina := isValidRegion(region)
 	if !validRegion {
 		return nil, errors.Wrapf(ErrInvalidRegion, "failed to create ServiceQuotas")
 	}
+	globalRegion, err := optionsGlobalRegion(options)
+	if err != nil {
+		return nil, err
+	}
+	awsSession, err := newSession(region, profile, roleARN, externalID)
+	if err != nil {
+		return nil, err
+	}
+
+	globalCfg := aws.NewConfig().WithRegion(globalRegion)
+	if isChina {
+		logging.Warn("AWS china currently doesn't support service quotas, disabling...")
+	}
+	// the account ID is only resolved once, the features depending on
+	// it are disabled when it cannot be resolved
+	var callerAccountID string
+	if options.AccountID != nil {
+		callerAccountID = *options.AccountID
+	} else {
+		callerAccountID = resolveAccountID(&accountID{client: sts.New(awsSession, globalCfg)}, options.ResourceIdentifier)
+	}
+
+	quotas := newServiceQuotasForRegion(awsSession, region, isChina, options, globalCfg, callerAccountID)
+
+	for _, service := range options.ForcedServices {
+		if !isKnownService(service) {
+			return nil, errors.Wrapf(ErrInvalidService, "failed to force service %s", service)
+		}
+	}
+
+	regionalQuotas := map[string]*ServiceQuotas{region: quotas}
+	for service, serviceRegion := range serviceRegions(options, globalRegion) {
+		if !isKnownService(service) {
+			return nil, errors.Wrapf(ErrInvalidService, "failed to override the region of service %s", service)
+		}
+		validServiceRegion, serviceIsChina := isValidRegion(serviceRegion)
+		if !validServiceRegion {
+			return nil, errors.Wrapf(ErrInvalidRegion, "failed to create ServiceQuotas with region %s for service %s", serviceRegion, service)
+		}
+
+		if _, ok := regionalQuotas[serviceRegion]; !ok {
+			regionalQuotas[serviceRegion] = newServiceQuotasForRegion(awsSession, serviceRegion, serviceIsChina, options, globalCfg, callerAccountID)
+		}
+		quotas.serviceRegions[service] = regionalQuotas[serviceRegion]
+	}
+
+	switch options.ResourceIdentifier {
+	case "", ResourceIdentifierID:
+	case ResourceIdentifierARN:
+		quotas.resourceAccountID = callerAccountID
+	default:
+		return nil, errors.Errorf("failed to create ServiceQuotas with invalid resource identifier %s", options.ResourceIdentifier)
+	}
+	return quotas, nil
+}
 
+// ResolveAccountID returns the account ID of the credentials that
+// NewServiceQuotasWithRole uses with the same arguments, to be passed
+// through Options.AccountID by the callers creating a ServiceQuotas for
+// several regions of the same account. An empty ID is returned, and a
+// warning logged, when it cannot be resolved
+func ResolveAccountID(region, profile, roleARN, externalID string, options Options) (string, error) {
+	if validRegion, _ := isValidRegion(region); !validRegion {
+		return "", errors.Wrapf(ErrInvalidRegion, "failed to resolve the account ID")
+	}
+	globalRegion, err := optionsGlobalRegion(options)
+	if err != nil {
+		return "", err
+	}
+	awsSession, err := newSession(region, profile, roleARN, externalID)
+	if err != nil {
+		return "", err
+	}
+	return resolveAccountID(&accountID{client: sts.New(awsSession, aws.NewConfig().WithRegion(globalRegion))}, options.ResourceIdentifier), nil
+}
+
+// optionsGlobalRegion returns the global region of `options` or an
+// error if it is invalid
+func optionsGlobalRegion(options Options) (string, error) {
+	globalRegion := options.GlobalRegion
+	if globalRegion == "" {
+		globalRegion = defaultGlobalRegion
+	}
+	if validGlobalRegion, _ := isValidRegion(globalRegion); !validGlobalRegion {
+		return "", errors.Wrapf(ErrInvalidRegion, "failed to create ServiceQuotas with global region %s", globalRegion)
+	}
+	return globalRegion, nil
+}
+
+// newSession creates the session with the credentials of `profile`, or
+// of `roleARN` assumed with them, see NewServiceQuotasWithRole
+func newSession(region, profile, roleARN, externalID string) (*session.Session, error) {
 	opts := session.Options{}
 	if profile != "" {
 		opts = session.Options{
@@ -168,15 +850,40 @@ func NewServiceQuotas(region, profile string) (QuotasInterface, error) {
 	if err != nil {
 		return nil, err
 	}
+	if roleARN != "" {
+		// the role is assumed through the STS endpoint of `region`, as
+		// the session may not have a region
+		stsSession := awsSession.Copy(aws.NewConfig().WithRegion(region))
+		credentials := stscreds.NewCredentials(stsSession, roleARN, func(p *stscreds.AssumeRoleProvider) {
+			if externalID != "" {
+				p.ExternalID = aws.String(externalID)
+			}
+			p.ExpiryWindow = assumeRoleExpiryWindow
+		})
+		awsSession = awsSession.Copy(aws.NewConfig().WithCredentials(credentials))
+	}
+	return awsSession, nil
+}
 
+// newServiceQuotasForRegion creates a ServiceQuotas with the service
+// quotas client and the usage checks for `region` in the account
+// `callerAccountID`, which is empty when it is unknown
+func newServiceQuotasForRegion(awsSession *session.Session, region string, isChina bool, options Options, globalCfg *aws.Config, callerAccountID string) *ServiceQuotas {
 	quotasService := awsservicequotas.New(awsSession, aws.NewConfig().WithRegion(region))
-	serviceQuotasChecks, serviceDefaultUsageChecks, otherChecks := newUsageChecks(awsSession, aws.NewConfig().WithRegion(region))
+	instanceTypes := &instanceTypesCache{}
+	jobRuns := &jobRunsCache{}
+	serviceQuotasChecks, serviceDefaultUsageChecks, otherChecks, quotaServices := newUsageChecks(options, awsSession, aws.NewConfig().WithRegion(region), globalCfg, callerAccountID, instanceTypes, jobRuns)
+	forcedServices := map[string]bool{}
+	for _, service := range options.ForcedServices {
+		forcedServices[service] = true
+	}
 
-	if isChina {
-		logging.Warn("AWS china currently doesn't support service quotas, disabling...")
+	var cloudwatchClient cloudwatchiface.CloudWatchAPI
+	if options.ReportedUsage {
+		cloudwatchClient = cloudwatch.New(awsSession, aws.NewConfig().WithRegion(region))
 	}
 
-	quotas := &ServiceQuotas{
+	return &ServiceQuotas{
 		session:                   awsSession,
 		region:                    region,
 		quotasService:             quotasService,
@@ -184,8 +891,53 @@ func NewServiceQuotas(region, profile string) (QuotasInterface, error) {
 		serviceDefaultUsageChecks: serviceDefaultUsageChecks,
 		isAwsChina:                isChina,
 		otherUsageChecks:          otherChecks,
+		quotaServices:             quotaServices,
+		clock:                     time.Now,
+		bestEffort:                options.BestEffort,
+		serviceRegions:            map[string]*ServiceQuotas{},
+		onCheckError:              options.OnCheckError,
+		onCheckDuration:           options.OnCheckDuration,
+		checkTimeout:              options.CheckTimeout,
+		forcedServices:            forcedServices,
+		cloudwatchClient:          cloudwatchClient,
+		instanceTypes:             instanceTypes,
+		jobRuns:                   jobRuns,
+		strictRegion:              options.StrictRegion,
 	}
-	return quotas, nil
+}
+
+// accountIDTimeout bounds the call resolving the account ID of the
+// credentials when ServiceQuotas is created
+const accountIDTimeout = 30 * time.Second
+
+// resolveAccountID returns the account ID of the credentials of
+// `account` or an empty string if STS fails to return it, for instance
+// because it is unavailable or not authorized. The failure is logged
+// once, with the features disabled by it depending on
+// `resourceIdentifier`, instead of failing the exporter
+func resolveAccountID(account *accountID, resourceIdentifier string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), accountIDTimeout)
+	defer cancel()
+
+	id, err := account.get(ctx)
+	if err != nil {
+		disabled := "the S3 access points checks are disabled"
+		if resourceIdentifier == ResourceIdentifierARN {
+			disabled += " and the resources are identified by ID instead of ARN"
+		}
+		log.Warnf("%s, %s: %s", ErrFailedToGetAccount, disabled, err)
+		return ""
+	}
+	return id
+}
+
+func isKnownService(service string) bool {
+	for _, knownService := range allServices() {
+		if service == knownService {
+			return true
+		}
+	}
+	return false
 }
 
 func isValidRegion(region string) (bool, bool) {
@@ -198,23 +950,113 @@ func isValidRegion(region string) (bool, bool) {
 	return false, false
 }
 
-func (s *ServiceQuotas) defaultsForService(service string) ([]QuotaUsage, error) {
+// forService returns the ServiceQuotas retrieving the quotas and usage
+// of `service`
+func (s *ServiceQuotas) forService(service string) *ServiceQuotas {
+	if serviceQuotas, ok := s.serviceRegions[service]; ok {
+		return serviceQuotas
+	}
+	return s
+}
+
+// setAvailableQuotas records that AWS listed `count` quotas of
+// `service`
+func (s *ServiceQuotas) setAvailableQuotas(service string, count int) {
+	s.availableQuotasMutex.Lock()
+	defer s.availableQuotasMutex.Unlock()
+
+	if s.availableQuotas == nil {
+		s.availableQuotas = map[string]int{}
+	}
+	s.availableQuotas[service] = count
+}
+
+// RegisteredChecks implements the CoverageQuotasInterface interface.
+// Only the checks of the quotas listed by ListServiceQuotas are counted,
+// the same quotas as AvailableQuotas, and not those of the default
+// quotas or the other checks. The checks of the services retrieved in
+// another region are the same as those of the region of `s`
+func (s *ServiceQuotas) RegisteredChecks() int {
+	return len(s.serviceQuotasUsageChecks)
+}
+
+// AvailableQuotas implements the CoverageQuotasInterface interface.
+// The quotas of the services retrieved in another region are those
+// listed in that region
+func (s *ServiceQuotas) AvailableQuotas() map[string]int {
+	available := map[string]int{}
+	for _, service := range allServices() {
+		serviceQuotas := s.forService(service)
+		serviceQuotas.availableQuotasMutex.Lock()
+		if count, ok := serviceQuotas.availableQuotas[service]; ok {
+			available[service] = count
+		}
+		serviceQuotas.availableQuotasMutex.Unlock()
+	}
+	return available
+}
+
+// now returns the time used to stamp the usages of the checks or the
+// zero time if no clock is set
+func (s *ServiceQuotas) now() time.Time {
+	if s.clock == nil {
+		return time.Time{}
+	}
+	return s.clock()
+}
+
+// runCheck returns the usage of `check` of the quota `quotaCode` of
+// `service` bounded by `ctx` and by the check timeout, or a *CheckError
+func (s *ServiceQuotas) runCheck(ctx context.Context, check UsageCheck, service, quotaCode string) ([]QuotaUsage, error) {
+	s.checkMutex.Lock()
+	defer s.checkMutex.Unlock()
+
+	if s.checkTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.checkTimeout)
+		defer cancel()
+	}
+	start := time.Now()
+	usages, err := check.Usage(ctx)
+	if s.onCheckDuration != nil {
+		s.onCheckDuration(checkName(check), time.Since(start))
+	}
+	if err != nil {
+		return nil, &CheckError{Check: checkName(check), Service: service, QuotaCode: quotaCode, Err: err}
+	}
+	return usages, nil
+}
+
+func (s *ServiceQuotas) defaultsForService(ctx context.Context, service string) ([]QuotaUsage, error) {
 	defaultQuotaUsages := []QuotaUsage{}
 	var defaultUsageErr error
+	var failedChecks int
 
 	params := &awsservicequotas.ListAWSDefaultServiceQuotasInput{ServiceCode: aws.String(service)}
-	err := s.quotasService.ListAWSDefaultServiceQuotasPages(params,
+	err := s.quotasService.ListAWSDefaultServiceQuotasPagesWithContext(ctx, params,
 		func(page *awsservicequotas.ListAWSDefaultServiceQuotasOutput, lastPage bool) bool {
 			if page != nil {
 				for _, quota := range page.Quotas {
 					if check, ok := s.serviceDefaultUsageChecks[*quota.QuotaCode]; ok {
-						defaultUsages, err := check.Usage()
+						defaultUsages, err := s.runCheck(ctx, check, service, *quota.QuotaCode)
 						if err != nil {
+							skip, partial := s.checkFailed(check, *quota.QuotaCode, err)
+							if partial {
+								failedChecks++
+							}
+							if skip {
+								continue
+							}
 							defaultUsageErr = err
 							return true
 						}
+						defaultUsages = s.withReportedUsage(ctx, quota, defaultUsages)
+						collectedAt := s.now()
 						for _, defaultUsage := range defaultUsages {
-							defaultUsage.Quota = *quota.Value
+							defaultUsage.CollectedAt = collectedAt
+							defaultUsage.Region = s.region
+							defaultUsage.Quota = usageLimit(check, defaultUsage, *quota.Value)
+							defaultUsage.Adjustable = aws.BoolValue(quota.Adjustable)
 							defaultQuotaUsages = append(defaultQuotaUsages, defaultUsage)
 						}
 					}
@@ -224,34 +1066,55 @@ func (s *ServiceQuotas) defaultsForService(service string) ([]QuotaUsage, error)
 		},
 	)
 	if err != nil {
+		if isRegionNotEnabled(err) {
+			return nil, errors.Wrapf(ErrRegionNotEnabled, "failed to list the quotas of %s in %s: %s", service, s.region, err)
+		}
 		return nil, errors.Wrapf(ErrFailedToListQuotas, "%w", err)
 	}
 
 	if defaultUsageErr != nil {
 		return nil, defaultUsageErr
 	}
+	if failedChecks > 0 {
+		return defaultQuotaUsages, errors.Wrapf(ErrPartialUsage, "%d default usage checks of %s failed", failedChecks, service)
+	}
 	return defaultQuotaUsages, nil
 }
 
-func (s *ServiceQuotas) quotasForService(service string) ([]QuotaUsage, error) {
+func (s *ServiceQuotas) quotasForService(ctx context.Context, service string) ([]QuotaUsage, error) {
 	serviceQuotaUsages := []QuotaUsage{}
 	var usageErr error
+	var failedChecks int
+	var listedQuotas int
 
 	params := &awsservicequotas.ListServiceQuotasInput{ServiceCode: aws.String(service)}
-	err := s.quotasService.ListServiceQuotasPages(params,
+	err := s.quotasService.ListServiceQuotasPagesWithContext(ctx, params,
 		func(page *awsservicequotas.ListServiceQuotasOutput, lastPage bool) bool {
 			if page != nil {
+				listedQuotas += len(page.Quotas)
 				for _, quota := range page.Quotas {
 					if check, ok := s.serviceQuotasUsageChecks[*quota.QuotaCode]; ok { // this only gets the non default quotas
-						quotaUsages, err := check.Usage()
+						quotaUsages, err := s.runCheck(ctx, check, service, *quota.QuotaCode)
 						if err != nil {
+							skip, partial := s.checkFailed(check, *quota.QuotaCode, err)
+							if partial {
+								failedChecks++
+							}
+							if skip {
+								continue
+							}
 							usageErr = err
 							// stop paging when an error is encountered
 							return true
 						}
 
+						quotaUsages = s.withReportedUsage(ctx, quota, quotaUsages)
+						collectedAt := s.now()
 						for _, quotaUsage := range quotaUsages {
-							quotaUsage.Quota = *quota.Value
+							quotaUsage.CollectedAt = collectedAt
+							quotaUsage.Region = s.region
+							quotaUsage.Quota = usageLimit(check, quotaUsage, *quota.Value)
+							quotaUsage.Adjustable = aws.BoolValue(quota.Adjustable)
 							serviceQuotaUsages = append(serviceQuotaUsages, quotaUsage)
 						}
 					}
@@ -261,53 +1124,336 @@ func (s *ServiceQuotas) quotasForService(service string) ([]QuotaUsage, error) {
 		},
 	)
 	if err != nil {
+		if isRegionNotEnabled(err) {
+			return nil, errors.Wrapf(ErrRegionNotEnabled, "failed to list the quotas of %s in %s: %s", service, s.region, err)
+		}
 		return nil, errors.Wrapf(ErrFailedToListQuotas, "%w", err)
 	}
+	s.setAvailableQuotas(service, listedQuotas)
 
 	if usageErr != nil {
 		return nil, usageErr
 	}
+	if failedChecks > 0 {
+		return serviceQuotaUsages, errors.Wrapf(ErrPartialUsage, "%d usage checks of %s failed", failedChecks, service)
+	}
 
 	return serviceQuotaUsages, nil
 }
 
-// QuotasAndUsage returns a slice of `QuotaUsage` or an error
+// QuotasAndUsage returns a slice of `QuotaUsage` or an error. The
+// checks that are not authorized to call AWS are skipped, as are the
+// regions that are not enabled unless Options.StrictRegion is set, the
+// usages of the other regions being returned along with an error
+// wrapping ErrPartialUsage. In best effort mode the usages of the checks
+// that did not fail are returned along with an error wrapping
+// ErrPartialUsage
 func (s *ServiceQuotas) QuotasAndUsage() ([]QuotaUsage, error) {
-	allQuotaUsages := []QuotaUsage{}
+	return s.QuotasAndUsageWithContext(context.Background())
+}
 
-	if !s.isAwsChina {
-		for _, service := range allServices() {
-			serviceQuotas, err := s.quotasForService(service)
-			if err != nil {
-				return nil, err
-			}
+// QuotasAndUsageWithContext is QuotasAndUsage bounded by `ctx`. When
+// `ctx` is done before all the checks have returned, the usages of the
+// services and checks that completed are returned along with an error
+// wrapping ErrPartialUsage. The AWS calls of the checks in progress are
+// cancelled and no further checks are started
+func (s *ServiceQuotas) QuotasAndUsageWithContext(ctx context.Context) ([]QuotaUsage, error) {
+	collected := &collectedUsages{usages: []QuotaUsage{}}
+	done := make(chan error, 1)
+	go func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		done <- s.collectQuotasAndUsage(ctx, allServices(), s.otherUsageChecks, collected)
+	}()
 
-			for _, quota := range serviceQuotas {
-				allQuotaUsages = append(allQuotaUsages, quota)
-			}
+	select {
+	case err := <-done:
+		if err != nil && !errors.Is(err, ErrPartialUsage) {
+			return nil, err
 		}
-		for _, service := range allServices() {
-			defaultQuotas, err := s.defaultsForService(service)
-			if err != nil {
-				return nil, err
-			}
+		return collected.snapshot(), err
+	case <-ctx.Done():
+		return collected.snapshot(), errors.Wrapf(ErrPartialUsage, "stopped waiting for the usage checks: %s", ctx.Err())
+	}
+}
 
-			for _, quota := range defaultQuotas {
-				allQuotaUsages = append(allQuotaUsages, quota)
-			}
+// collectedUsages holds the usages collected by QuotasAndUsage, it is
+// read while the checks are still running when the context is done
+type collectedUsages struct {
+	mutex  sync.Mutex
+	usages []QuotaUsage
+}
+
+func (c *collectedUsages) add(usages []QuotaUsage) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.usages = append(c.usages, usages...)
+}
+
+func (c *collectedUsages) snapshot() []QuotaUsage {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]QuotaUsage{}, c.usages...)
+}
+
+// QuotasAndUsageForService returns the quotas and usage of the
+// applied and default quotas of `service` and of the other usage checks
+// belonging to it, or an error. `service` is a service quotas service
+// code, it must have registered usage checks
+func (s *ServiceQuotas) QuotasAndUsageForService(service string) ([]QuotaUsage, error) {
+	var services []string
+	if isKnownService(service) {
+		services = []string{service}
+	}
+	otherChecks := []UsageCheck{}
+	for _, check := range s.otherUsageChecks {
+		if otherUsageCheckService(check) == service {
+			otherChecks = append(otherChecks, check)
 		}
 	}
+	if len(services) == 0 && len(otherChecks) == 0 {
+		return nil, errors.Wrapf(ErrInvalidService, "no usage checks for service %s", service)
+	}
 
-	for _, check := range s.otherUsageChecks {
-		quotas, err := check.Usage()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	collected := &collectedUsages{usages: []QuotaUsage{}}
+	err := s.collectQuotasAndUsage(context.Background(), services, otherChecks, collected)
+	if err != nil && !errors.Is(err, ErrPartialUsage) {
+		return nil, err
+	}
+	return collected.snapshot(), err
+}
+
+// otherUsageCheckService returns the service quotas service code of the
+// service an other usage check belongs to, or an empty string for the
+// checks that are not part of the package
+func otherUsageCheckService(check UsageCheck) string {
+	switch check.(type) {
+	case *AvailableIpsPerSubnetUsageCheck:
+		return "vpc"
+	case *ENIsPerAZCheck, *ENIsPerInstanceCheck, *SpotInstanceRequestsCountCheck:
+		return "ec2"
+	case *ASGUsageCheck:
+		return "autoscaling"
+	case *MaxSendIn24HoursCheck:
+		return "ses"
+	case *ProvisionedConcurrencyCheck, *EventSourceMappingsCheck, *FunctionUrlsCheck:
+		return "lambda"
+	case *LogStreamsPerLogGroupCheck:
+		return "logs"
+	case *JobsPerSecurityConfigurationCheck, *ActiveDPUsCheck, *ConcurrentCrawlerRunsCheck:
+		return "glue"
+	case *HealthChecksPerAccountCheck, *TrafficPoliciesCheck, *HostedZonesCheck, *RecordsPerZoneCheck:
+		return "route53"
+	case *SubscriptionsPerTopicCheck:
+		return "sns"
+	case *QueuesPerAccountCheck:
+		return "sqs"
+	case *APIsPerRegionCheck:
+		return "apigateway"
+	case *ImagePipelinesCheck, *ImageRecipesCheck:
+		return "imagebuilder"
+	}
+	return ""
+}
+
+// collectQuotasAndUsage runs the usage checks of `services` and
+// `otherChecks`, adding their usages to `collected`. It stops before the
+// next service or check once `ctx` is done. The instance types cache of
+// the EC2 checks and the job runs cache of the glue checks are reset
+// first, so each refresh describes them again
+func (s *ServiceQuotas) collectQuotasAndUsage(ctx context.Context, services []string, otherChecks []UsageCheck, collected *collectedUsages) error {
+	s.instanceTypes.reset()
+	s.jobRuns.reset()
+	for _, serviceQuotas := range s.serviceRegions {
+		serviceQuotas.instanceTypes.reset()
+		serviceQuotas.jobRuns.reset()
+	}
+	var failures []string
+	// skipped holds the regions that are not enabled for the account,
+	// whose remaining checks are skipped
+	skipped := map[*ServiceQuotas]bool{}
+
+	for _, service := range services {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if s.forService(service).isAwsChina || !s.forService(service).serviceAvailable(service) || skipped[s.forService(service)] {
+			continue
+		}
+		serviceQuotas, err := s.forService(service).quotasForService(ctx, service)
+		if s.forService(service).skipRegion(err) {
+			failures = append(failures, skippedRegion(skipped, s.forService(service))...)
+			continue
+		}
+		if errors.Is(err, ErrPartialUsage) {
+			failures = append(failures, service)
+		} else if err != nil {
+			return err
+		}
+
+		collected.add(s.identifyResources(serviceQuotas))
+	}
+	for _, service := range services {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if s.forService(service).isAwsChina || !s.forService(service).serviceAvailable(service) || skipped[s.forService(service)] {
+			continue
+		}
+		defaultQuotas, err := s.forService(service).defaultsForService(ctx, service)
+		if s.forService(service).skipRegion(err) {
+			failures = append(failures, skippedRegion(skipped, s.forService(service))...)
+			continue
+		}
+		if errors.Is(err, ErrPartialUsage) {
+			failures = append(failures, service)
+		} else if err != nil {
+			return err
+		}
+
+		collected.add(s.identifyResources(defaultQuotas))
+	}
+
+	for _, check := range otherChecks {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		service := otherUsageCheckService(check)
+		serviceQuotas := s.forService(service)
+		if !serviceQuotas.serviceAvailable(service) || skipped[serviceQuotas] {
+			continue
+		}
+		check = s.otherCheckFor(serviceQuotas, check)
+		quotas, err := serviceQuotas.runCheck(ctx, check, service, "")
+		if serviceQuotas.skipRegion(err) {
+			failures = append(failures, skippedRegion(skipped, serviceQuotas)...)
+			continue
+		}
 		if err != nil {
-			return nil, err
+			skip, partial := serviceQuotas.checkFailed(check, checkName(check), err)
+			if partial {
+				failures = append(failures, checkName(check))
+			}
+			if skip {
+				continue
+			}
+			return err
 		}
 
+		collectedAt := serviceQuotas.now()
+		checkQuotas := make([]QuotaUsage, 0, len(quotas))
 		for _, quota := range quotas {
-			allQuotaUsages = append(allQuotaUsages, quota)
+			quota.CollectedAt = collectedAt
+			quota.Region = serviceQuotas.region
+			checkQuotas = append(checkQuotas, quota)
+		}
+		collected.add(s.identifyResources(checkQuotas))
+	}
+
+	if len(failures) > 0 {
+		return errors.Wrapf(ErrPartialUsage, "usage checks failed for %s", strings.Join(failures, ", "))
+	}
+	return nil
+}
+
+// otherCheckFor returns the other usage check of `serviceQuotas`
+// matching `check`, one of the other usage checks of s. The ServiceQuotas
+// of a service region override has its own checks, created with the
+// clients of its region, which are matched by their check name as it
+// may not register the same checks as s, such as the global checks
+func (s *ServiceQuotas) otherCheckFor(serviceQuotas *ServiceQuotas, check UsageCheck) UsageCheck {
+	if serviceQuotas == s {
+		return check
+	}
+	name := checkName(check)
+	for _, otherCheck := range serviceQuotas.otherUsageChecks {
+		if checkName(otherCheck) == name {
+			return otherCheck
+		}
+	}
+	return check
+}
+
+// skippedRegion marks the region of `s` as skipped in `skipped` and
+// returns the failure reported for it, or nothing when it was already
+// skipped
+func skippedRegion(skipped map[*ServiceQuotas]bool, s *ServiceQuotas) []string {
+	if skipped[s] {
+		return nil
+	}
+	skipped[s] = true
+	return []string{fmt.Sprintf("region %s", s.region)}
+}
+
+// skipRegion returns whether the remaining usage checks of the region
+// of `s` are skipped after one of them failed with `err`, which is the
+// case when the region is not enabled for the account unless strict
+// region is set. The region is checked again on the next refresh, in
+// case it has been enabled since, but it is only logged once
+func (s *ServiceQuotas) skipRegion(err error) bool {
+	if err == nil || s.strictRegion || !isRegionNotEnabled(err) {
+		return false
+	}
+	s.regionNotEnabledLog.Do(func() {
+		log.Warnf("Skipping the usage checks of region %s, which is not enabled for the account: %s", s.region, err)
+	})
+	return true
+}
+
+// checkFailed reports the failure of `check`, identified by `id` in
+// the logs, and returns whether it is skipped instead of failing
+// QuotasAndUsage and whether skipping it makes the usage partial. The
+// checks that are not authorized by the IAM policy of the exporter are
+// always skipped without making the usage partial, as they would fail
+// on every refresh. The checks failing with other errors are only
+// skipped in best effort mode
+func (s *ServiceQuotas) checkFailed(check UsageCheck, id string, err error) (bool, bool) {
+	if s.onCheckError != nil {
+		s.onCheckError(checkName(check), err)
+	}
+
+	if isAccessDenied(err) {
+		log.Warnf("Skipping unauthorized usage check %s: %s", id, err)
+		return true, false
+	}
+	if s.bestEffort {
+		log.Warnf("Skipping usage check %s: %s", id, err)
+		return true, true
+	}
+	return false, false
+}
+
+// identifyResources sets the unit of `usages` and replaces their
+// resource names with the ARNs of the resources when they are
+// identified by ARN
+func (s *ServiceQuotas) identifyResources(usages []QuotaUsage) []QuotaUsage {
+	usages = withUnits(usages)
+	if s.resourceAccountID == "" {
+		return usages
+	}
+	return resourceARNs(usages, s.resourceAccountID)
+}
+
+func (s *ServiceQuotas) DescribeQuotas() []QuotaUsage {
+	checks := []UsageCheck{}
+	if !s.isAwsChina {
+		for _, check := range s.serviceQuotasUsageChecks {
+			checks = append(checks, check)
+		}
+		for _, check := range s.serviceDefaultUsageChecks {
+			checks = append(checks, check)
 		}
 	}
+	checks = append(checks, s.otherUsageChecks...)
 
-	return allQuotaUsages, nil
+	quotaUsages := []QuotaUsage{}
+	for _, check := range checks {
+		if describer, ok := check.(UsageDescriber); ok {
+			quotaUsages = append(quotaUsages, describer.DescribeUsage()...)
+		}
+	}
+	return withUnits(quotaUsages)
 }
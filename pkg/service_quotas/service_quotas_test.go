@@ -1,11 +1,22 @@
 package servicequotas
 
 import (
+	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/glue"
 	awsservicequotas "github.com/aws/aws-sdk-go/service/servicequotas"
 	"github.com/aws/aws-sdk-go/service/servicequotas/servicequotasiface"
+	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
@@ -13,13 +24,14 @@ import (
 type mockServiceQuotasClient struct {
 	servicequotasiface.ServiceQuotasAPI
 
-	err                       error
-	serviceName               string
-	ListServiceQuotasResponse *awsservicequotas.ListServiceQuotasOutput
-	timesCalled               int
+	err                                 error
+	serviceName                         string
+	ListServiceQuotasResponse           *awsservicequotas.ListServiceQuotasOutput
+	ListAWSDefaultServiceQuotasResponse *awsservicequotas.ListAWSDefaultServiceQuotasOutput
+	timesCalled                         int
 }
 
-func (m *mockServiceQuotasClient) ListServiceQuotasPages(input *awsservicequotas.ListServiceQuotasInput, fn func(*awsservicequotas.ListServiceQuotasOutput, bool) bool) error {
+func (m *mockServiceQuotasClient) ListServiceQuotasPagesWithContext(ctx aws.Context, input *awsservicequotas.ListServiceQuotasInput, fn func(*awsservicequotas.ListServiceQuotasOutput, bool) bool, opts ...request.Option) error {
 	m.timesCalled++
 
 	if *input.ServiceCode == m.serviceName {
@@ -30,12 +42,21 @@ func (m *mockServiceQuotasClient) ListServiceQuotasPages(input *awsservicequotas
 	return m.err
 }
 
+func (m *mockServiceQuotasClient) ListAWSDefaultServiceQuotasPagesWithContext(ctx aws.Context, input *awsservicequotas.ListAWSDefaultServiceQuotasInput, fn func(*awsservicequotas.ListAWSDefaultServiceQuotasOutput, bool) bool, opts ...request.Option) error {
+	if *input.ServiceCode == m.serviceName {
+		fn(m.ListAWSDefaultServiceQuotasResponse, true)
+	} else {
+		fn(nil, true)
+	}
+	return m.err
+}
+
 type UsageCheckMock struct {
 	err    error
 	usages []QuotaUsage
 }
 
-func (m *UsageCheckMock) Usage() ([]QuotaUsage, error) {
+func (m *UsageCheckMock) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	return m.usages, m.err
 }
 
@@ -80,10 +101,51 @@ func TestQuotasAndUsageWithUsageError(t *testing.T) {
 	}
 	quotasAndUsage, err := serviceQuotas.QuotasAndUsage()
 
-	assert.Equal(t, expectedErr, err)
+	assert.Equal(t, &CheckError{Check: "UsageCheckMock", Service: "ec2", QuotaCode: "L-1234", Err: expectedErr}, err)
+	assert.True(t, errors.Is(err, expectedErr))
 	assert.Nil(t, quotasAndUsage)
 }
 
+func TestQuotasAndUsageBestEffort(t *testing.T) {
+	mockClient := &mockServiceQuotasClient{
+		serviceName: "ec2",
+		ListServiceQuotasResponse: &awsservicequotas.ListServiceQuotasOutput{
+			Quotas: []*awsservicequotas.ServiceQuota{
+				{
+					QuotaCode: aws.String("L-1234"),
+					Value:     aws.Float64(15),
+				},
+				{
+					QuotaCode: aws.String("L-5678"),
+					Value:     aws.Float64(10),
+				},
+			},
+		},
+	}
+
+	serviceQuotas := ServiceQuotas{
+		quotasService: mockClient,
+		bestEffort:    true,
+		serviceQuotasUsageChecks: map[string]UsageCheck{
+			"L-1234": &UsageCheckMock{err: errors.New("some err")},
+			"L-5678": &UsageCheckMock{usages: []QuotaUsage{{Name: "quota", Usage: 2}}},
+		},
+		otherUsageChecks: []UsageCheck{
+			&UsageCheckMock{err: errors.New("some err")},
+			&UsageCheckMock{usages: []QuotaUsage{{Name: "other", Usage: 3}}},
+		},
+	}
+	quotasAndUsage, err := serviceQuotas.QuotasAndUsage()
+
+	expectedQuotasAndUsage := []QuotaUsage{
+		{Name: "quota", Usage: 2, Quota: 10},
+		{Name: "other", Usage: 3},
+	}
+
+	assert.True(t, errors.Is(err, ErrPartialUsage))
+	assert.Equal(t, expectedQuotasAndUsage, quotasAndUsage)
+}
+
 func TestQuotasAndUsage(t *testing.T) {
 	mockClient := &mockServiceQuotasClient{
 		serviceName: "ec2",
@@ -98,8 +160,9 @@ func TestQuotasAndUsage(t *testing.T) {
 					Value:     aws.Float64(6),
 				},
 				{
-					QuotaCode: aws.String("L-5678"),
-					Value:     aws.Float64(2),
+					QuotaCode:  aws.String("L-5678"),
+					Value:      aws.Float64(2),
+					Adjustable: aws.Bool(true),
 				},
 			},
 		},
@@ -160,10 +223,11 @@ func TestQuotasAndUsage(t *testing.T) {
 			Description: "some check",
 			Usage:       1,
 			Quota:       2,
+			Adjustable:  true,
 		},
 	}
 
-	expectedServiceQuotasAPICalls := 2
+	expectedServiceQuotasAPICalls := len(allServices())
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedServiceQuotasAPICalls, mockClient.timesCalled)
@@ -277,9 +341,662 @@ func TestQuotaUsageIdentifier(t *testing.T) {
 }
 
 func TestNewServiceQuotasWithInvalidRegion(t *testing.T) {
-	svcQuotas, err := NewServiceQuotas("asdasd", "someprofile")
+	svcQuotas, err := NewServiceQuotas("asdasd", "someprofile", Options{})
 
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, ErrInvalidRegion))
 	assert.Nil(t, svcQuotas)
 }
+
+func TestQuotasAndUsageRulesPerSecurityGroup(t *testing.T) {
+	mockClient := &mockServiceQuotasClient{
+		serviceName: "vpc",
+		ListServiceQuotasResponse: &awsservicequotas.ListServiceQuotasOutput{
+			Quotas: []*awsservicequotas.ServiceQuota{
+				{
+					QuotaCode: aws.String("L-0EA8095F"),
+					Value:     aws.Float64(60),
+				},
+			},
+		},
+	}
+
+	ec2Client := &mockEC2Client{
+		DescribeSecurityGroupsResponse: &ec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []*ec2.SecurityGroup{
+				{
+					GroupId: aws.String("sg-1"),
+					IpPermissions: []*ec2.IpPermission{
+						{IpRanges: []*ec2.IpRange{{CidrIp: aws.String("10.0.0.0/16")}}},
+					},
+				},
+				{
+					GroupId: aws.String("sg-2"),
+					IpPermissionsEgress: []*ec2.IpPermission{
+						{IpRanges: []*ec2.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}},
+					},
+				},
+			},
+		},
+	}
+
+	serviceQuotas := ServiceQuotas{
+		quotasService: mockClient,
+		serviceQuotasUsageChecks: map[string]UsageCheck{
+			"L-0EA8095F": &RulesPerSecurityGroupUsageCheck{client: ec2Client},
+		},
+	}
+	actualQuotasAndUsage, err := serviceQuotas.QuotasAndUsage()
+
+	expectedQuotasAndUsage := []QuotaUsage{
+		{
+			Name:         inboundRulesPerSecGrpName,
+			ResourceName: aws.String("sg-1"),
+			Description:  inboundRulesPerSecGrpDesc,
+			Usage:        1,
+			Quota:        60,
+		},
+		{
+			Name:         outboundRulesPerSecGrpName,
+			ResourceName: aws.String("sg-1"),
+			Description:  outboundRulesPerSecGrpDesc,
+			Usage:        0,
+			Quota:        60,
+		},
+		{
+			Name:         inboundRulesPerSecGrpName,
+			ResourceName: aws.String("sg-2"),
+			Description:  inboundRulesPerSecGrpDesc,
+			Usage:        0,
+			Quota:        60,
+		},
+		{
+			Name:         outboundRulesPerSecGrpName,
+			ResourceName: aws.String("sg-2"),
+			Description:  outboundRulesPerSecGrpDesc,
+			Usage:        1,
+			Quota:        60,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedQuotasAndUsage, actualQuotasAndUsage)
+}
+
+func TestQuotasAndUsageCollectedAt(t *testing.T) {
+	collectedAt := time.Date(2021, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	serviceQuotas := ServiceQuotas{
+		isAwsChina: true,
+		otherUsageChecks: []UsageCheck{
+			&UsageCheckMock{
+				usages: []QuotaUsage{
+					{
+						Name:        "some_check",
+						Description: "some check",
+						Usage:       1,
+						Quota:       2,
+					},
+				},
+			},
+		},
+		clock: func() time.Time { return collectedAt },
+	}
+	actualQuotasAndUsage, err := serviceQuotas.QuotasAndUsage()
+
+	expectedQuotasAndUsage := []QuotaUsage{
+		{
+			Name:        "some_check",
+			Description: "some check",
+			Usage:       1,
+			Quota:       2,
+			CollectedAt: collectedAt,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedQuotasAndUsage, actualQuotasAndUsage)
+}
+
+func TestDescribeQuotas(t *testing.T) {
+	serviceQuotas := ServiceQuotas{
+		isAwsChina: true,
+		otherUsageChecks: []UsageCheck{
+			&UsageCheckMock{},
+			&LogGroupsPerRegionCheck{},
+		},
+	}
+	actualQuotas := serviceQuotas.DescribeQuotas()
+
+	expectedQuotas := []QuotaUsage{
+		{Name: logGroupsPerRegionName, Description: logGroupsPerRegionDescription},
+	}
+	assert.Equal(t, expectedQuotas, actualQuotas)
+}
+
+func TestQuotasAndUsageServiceRegions(t *testing.T) {
+	mockClient := &mockServiceQuotasClient{serviceName: "ec2"}
+	logsMockClient := &mockServiceQuotasClient{
+		serviceName: "logs",
+		ListServiceQuotasResponse: &awsservicequotas.ListServiceQuotasOutput{
+			Quotas: []*awsservicequotas.ServiceQuota{
+				{
+					QuotaCode: aws.String("L-1234"),
+					Value:     aws.Float64(15),
+				},
+			},
+		},
+	}
+
+	logsServiceQuotas := &ServiceQuotas{
+		region:        "us-east-1",
+		quotasService: logsMockClient,
+		serviceQuotasUsageChecks: map[string]UsageCheck{
+			"L-1234": &UsageCheckMock{
+				usages: []QuotaUsage{{Name: "some_check", Description: "some check", Usage: 1}},
+			},
+		},
+	}
+	serviceQuotas := ServiceQuotas{
+		region:         "eu-central-1",
+		quotasService:  mockClient,
+		serviceRegions: map[string]*ServiceQuotas{"logs": logsServiceQuotas},
+		otherUsageChecks: []UsageCheck{
+			&UsageCheckMock{
+				usages: []QuotaUsage{{Name: "other_check", Description: "other check", Usage: 2, Quota: 3}},
+			},
+		},
+	}
+	actualQuotasAndUsage, err := serviceQuotas.QuotasAndUsage()
+
+	expectedQuotasAndUsage := []QuotaUsage{
+		{Name: "some_check", Description: "some check", Usage: 1, Quota: 15, Region: "us-east-1"},
+		{Name: "other_check", Description: "other check", Usage: 2, Quota: 3, Region: "eu-central-1"},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedQuotasAndUsage, actualQuotasAndUsage)
+	assert.Equal(t, len(allServices())-1, mockClient.timesCalled)
+	assert.Equal(t, 1, logsMockClient.timesCalled)
+}
+
+func TestQuotasAndUsageServiceRegionsOtherChecks(t *testing.T) {
+	glueClient := &mockGlueClient{
+		ListCrawlersResponse: &glue.ListCrawlersOutput{CrawlerNames: aws.StringSlice([]string{"crawler1", "crawler2"})},
+		CrawlerStates:        map[string]string{"crawler1": glue.CrawlerStateRunning, "crawler2": glue.CrawlerStateReady},
+	}
+	glueServiceQuotas := &ServiceQuotas{
+		region:           "us-east-1",
+		quotasService:    &mockServiceQuotasClient{serviceName: "glue"},
+		otherUsageChecks: []UsageCheck{&ConcurrentCrawlerRunsCheck{glueClient}},
+	}
+	// the check of the exporter region fails if it is run instead of
+	// the check of the service region
+	serviceQuotas := ServiceQuotas{
+		region:           "eu-central-1",
+		quotasService:    &mockServiceQuotasClient{serviceName: "ec2"},
+		serviceRegions:   map[string]*ServiceQuotas{"glue": glueServiceQuotas},
+		otherUsageChecks: []UsageCheck{&ConcurrentCrawlerRunsCheck{&mockGlueClient{err: errors.New("some err")}}},
+	}
+	actualQuotasAndUsage, err := serviceQuotas.QuotasAndUsage()
+
+	expectedQuotasAndUsage := []QuotaUsage{
+		{Name: concurrentCrawlerRunsName, Description: concurrentCrawlerRunsDescription, Usage: 1, Region: "us-east-1"},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedQuotasAndUsage, actualQuotasAndUsage)
+}
+
+func TestQuotasAndUsageServiceRegionsOtherChecksByName(t *testing.T) {
+	glueClient := &mockGlueClient{
+		ListCrawlersResponse: &glue.ListCrawlersOutput{CrawlerNames: aws.StringSlice([]string{"crawler1", "crawler2"})},
+		CrawlerStates:        map[string]string{"crawler1": glue.CrawlerStateRunning, "crawler2": glue.CrawlerStateRunning},
+	}
+	// the service region registers other checks before the one of the
+	// exporter region
+	glueServiceQuotas := &ServiceQuotas{
+		region:        "us-east-1",
+		quotasService: &mockServiceQuotasClient{serviceName: "glue"},
+		otherUsageChecks: []UsageCheck{
+			&JobsPerSecurityConfigurationCheck{&mockGlueClient{err: errors.New("some err")}},
+			&ConcurrentCrawlerRunsCheck{glueClient},
+		},
+	}
+	serviceQuotas := ServiceQuotas{
+		region:           "eu-central-1",
+		quotasService:    &mockServiceQuotasClient{serviceName: "ec2"},
+		serviceRegions:   map[string]*ServiceQuotas{"glue": glueServiceQuotas},
+		otherUsageChecks: []UsageCheck{&ConcurrentCrawlerRunsCheck{&mockGlueClient{err: errors.New("some err")}}},
+	}
+	actualQuotasAndUsage, err := serviceQuotas.QuotasAndUsage()
+
+	expectedQuotasAndUsage := []QuotaUsage{
+		{Name: concurrentCrawlerRunsName, Description: concurrentCrawlerRunsDescription, Usage: 2, Region: "us-east-1"},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedQuotasAndUsage, actualQuotasAndUsage)
+}
+
+func TestAvailableQuotas(t *testing.T) {
+	mockClient := &mockServiceQuotasClient{
+		serviceName: "ec2",
+		ListServiceQuotasResponse: &awsservicequotas.ListServiceQuotasOutput{
+			Quotas: []*awsservicequotas.ServiceQuota{
+				{QuotaCode: aws.String("L-1234"), Value: aws.Float64(15)},
+				{QuotaCode: aws.String("L-NOTIMPLEMENTED"), Value: aws.Float64(6)},
+			},
+		},
+	}
+	logsMockClient := &mockServiceQuotasClient{
+		serviceName: "logs",
+		ListServiceQuotasResponse: &awsservicequotas.ListServiceQuotasOutput{
+			Quotas: []*awsservicequotas.ServiceQuota{{QuotaCode: aws.String("L-5678"), Value: aws.Float64(1)}},
+		},
+	}
+
+	serviceQuotas := ServiceQuotas{
+		quotasService: mockClient,
+		serviceQuotasUsageChecks: map[string]UsageCheck{
+			"L-1234": &UsageCheckMock{},
+		},
+		serviceDefaultUsageChecks: map[string]UsageCheck{
+			"L-5678": &UsageCheckMock{},
+		},
+		otherUsageChecks: []UsageCheck{&UsageCheckMock{}},
+		serviceRegions: map[string]*ServiceQuotas{
+			"logs": {region: "us-east-1", quotasService: logsMockClient},
+		},
+	}
+	assert.Empty(t, serviceQuotas.AvailableQuotas())
+
+	_, err := serviceQuotas.QuotasAndUsage()
+	assert.NoError(t, err)
+
+	available := serviceQuotas.AvailableQuotas()
+	assert.Equal(t, 2, available["ec2"])
+	assert.Equal(t, 1, available["logs"])
+	assert.Equal(t, 0, available["vpc"])
+	assert.Len(t, available, len(allServices()))
+	// only the check of the quotas listed by ListServiceQuotas is counted
+	assert.Equal(t, 1, serviceQuotas.RegisteredChecks())
+}
+
+func TestQuotasAndUsageReportsCheckDurations(t *testing.T) {
+	failingCheck := &UsageCheckMock{err: errors.New("some err")}
+	otherCheck := &FakeUsageCheck{Usages: []QuotaUsage{{Name: "other_quota", Usage: 2}}}
+	durations := map[string]int{}
+
+	serviceQuotas, err := NewServiceQuotasWithChecks("eu-west-1", &mockServiceQuotasClient{}, UsageChecks{
+		Other: []UsageCheck{failingCheck, otherCheck},
+	}, Options{
+		BestEffort: true,
+		OnCheckDuration: func(check string, duration time.Duration) {
+			assert.True(t, duration >= 0)
+			durations[check]++
+		},
+	})
+	assert.NoError(t, err)
+
+	_, err = serviceQuotas.QuotasAndUsage()
+
+	assert.True(t, errors.Is(err, ErrPartialUsage))
+	assert.Equal(t, map[string]int{"UsageCheckMock": 1, "FakeUsageCheck": 1}, durations)
+}
+
+func TestNewServiceQuotasWithInvalidServiceRegion(t *testing.T) {
+	svcQuotas, err := NewServiceQuotas("eu-west-1", "", Options{ServiceRegions: map[string]string{"logs": "asdasd"}})
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidRegion))
+	assert.Nil(t, svcQuotas)
+}
+
+func TestNewServiceQuotasWithUnknownServiceRegion(t *testing.T) {
+	svcQuotas, err := NewServiceQuotas("eu-west-1", "", Options{ServiceRegions: map[string]string{"asdasd": "us-east-1"}})
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidService))
+	assert.Nil(t, svcQuotas)
+}
+
+func TestNewServiceQuotasWithInvalidGlobalRegion(t *testing.T) {
+	svcQuotas, err := NewServiceQuotas("eu-west-1", "", Options{GlobalRegion: "asdasd"})
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidRegion))
+	assert.Nil(t, svcQuotas)
+}
+
+func TestNewServiceQuotasWithAccountID(t *testing.T) {
+	svcQuotas, err := NewServiceQuotas("eu-west-1", "", Options{AccountID: aws.String("123456789012"), ResourceIdentifier: ResourceIdentifierARN})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "123456789012", svcQuotas.(*ServiceQuotas).resourceAccountID)
+	// the S3 Control checks are created with the account ID
+	assert.Contains(t, svcQuotas.(*ServiceQuotas).serviceDefaultUsageChecks, "L-FAABEEBA")
+}
+
+func TestNewServiceQuotasWithUnresolvedAccountID(t *testing.T) {
+	svcQuotas, err := NewServiceQuotas("eu-west-1", "", Options{AccountID: aws.String(""), ResourceIdentifier: ResourceIdentifierARN})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", svcQuotas.(*ServiceQuotas).resourceAccountID)
+	assert.NotContains(t, svcQuotas.(*ServiceQuotas).serviceDefaultUsageChecks, "L-FAABEEBA")
+}
+
+func TestResolveAccountIDWithInvalidRegion(t *testing.T) {
+	id, err := ResolveAccountID("asdasd", "", "", "", Options{})
+
+	assert.True(t, errors.Is(err, ErrInvalidRegion))
+	assert.Equal(t, "", id)
+
+	id, err = ResolveAccountID("eu-west-1", "", "", "", Options{GlobalRegion: "asdasd"})
+
+	assert.True(t, errors.Is(err, ErrInvalidRegion))
+	assert.Equal(t, "", id)
+}
+
+// perResourceChecks are the registered checks that report one usage
+// per resource. Every other check must implement UsageDescriber
+var perResourceChecks = map[string]bool{
+	"*servicequotas.RulesPerSecurityGroupUsageCheck":   true,
+	"*servicequotas.SecurityGroupsPerENIUsageCheck":    true,
+	"*servicequotas.ReadReplicasPerMasterCheck":        true,
+	"*servicequotas.JobsPerTriggerCheck":               true,
+	"*servicequotas.ConcurrentRunsPerJobCheck":         true,
+	"*servicequotas.VirtualNodesPerMeshCheck":          true,
+	"*servicequotas.EntriesPerPrefixListCheck":         true,
+	"*servicequotas.HsmsPerClusterCheck":               true,
+	"*servicequotas.ReadCapacityPerTableCheck":         true,
+	"*servicequotas.WriteCapacityPerTableCheck":        true,
+	"*servicequotas.ImagesPerRepositoryCheck":          true,
+	"*servicequotas.AppKPUUsageCheck":                  true,
+	"*servicequotas.AvailableIpsPerSubnetUsageCheck":   true,
+	"*servicequotas.ENIsPerAZCheck":                    true,
+	"*servicequotas.ENIsPerInstanceCheck":              true,
+	"*servicequotas.ASGUsageCheck":                     true,
+	"*servicequotas.LogStreamsPerLogGroupCheck":        true,
+	"*servicequotas.JobsPerSecurityConfigurationCheck": true,
+	"*servicequotas.RecordsPerZoneCheck":               true,
+	"*servicequotas.SubscriptionsPerTopicCheck":        true,
+	"*servicequotas.ServicesPerClusterCheck":           true,
+	"*servicequotas.StagesPerAPICheck":                 true,
+	"*servicequotas.ActiveQueriesCheck":                true,
+}
+
+func TestUsageChecksCardinality(t *testing.T) {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("eu-west-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	}))
+	cfg := aws.NewConfig()
+	serviceQuotasChecks, serviceDefaultChecks, otherChecks, _ := newUsageChecks(Options{LogStreamsPerLogGroup: true, APIGatewayStagesPerAPI: true}, sess, cfg, cfg, "123456789012", nil, nil)
+
+	checks := otherChecks
+	for _, check := range serviceQuotasChecks {
+		checks = append(checks, check)
+	}
+	for _, check := range serviceDefaultChecks {
+		checks = append(checks, check)
+	}
+
+	for _, check := range checks {
+		checkType := fmt.Sprintf("%T", check)
+		describer, ok := check.(UsageDescriber)
+		if perResourceChecks[checkType] {
+			assert.False(t, ok, "%s reports per resource usage and should not describe aggregate usage", checkType)
+			_, ok = check.(ResourceUsageDescriber)
+			assert.True(t, ok, "%s reports per resource usage and must implement ResourceUsageDescriber", checkType)
+			continue
+		}
+		if !assert.True(t, ok, "%s must implement UsageDescriber or be listed as a per resource check", checkType) {
+			continue
+		}
+
+		usages := describer.DescribeUsage()
+		assert.NotEmpty(t, usages, checkType)
+		names := map[string]bool{}
+		for _, usage := range usages {
+			assert.Nil(t, usage.ResourceName, "%s should not set a resource name on aggregate usage", checkType)
+			assert.False(t, names[usage.Name], "%s describes %s more than once", checkType, usage.Name)
+			names[usage.Name] = true
+		}
+	}
+}
+
+func TestNewUsageChecksKDAParallelismOffset(t *testing.T) {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("eu-west-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	}))
+	cfg := aws.NewConfig()
+	testCases := []struct {
+		name           string
+		offset         *int
+		expectedOffset int64
+	}{
+		{"default", nil, 1},
+		{"disabled", aws.Int(0), 0},
+		{"custom", aws.Int(2), 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, serviceDefaultChecks, _, _ := newUsageChecks(Options{KDAParallelismOffset: tc.offset}, sess, cfg, cfg, "123456789012", nil, nil)
+
+			check, ok := serviceDefaultChecks["L-3A88E041"].(*AppKPUUsageCheck)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expectedOffset, check.parallelismOffset)
+		})
+	}
+}
+
+func TestUsageCheckNamesAreUnique(t *testing.T) {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("eu-west-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	}))
+	cfg := aws.NewConfig()
+	serviceQuotasChecks, serviceDefaultChecks, otherChecks, _ := newUsageChecks(Options{LogStreamsPerLogGroup: true, APIGatewayStagesPerAPI: true}, sess, cfg, cfg, "123456789012", nil, nil)
+
+	checks := otherChecks
+	for _, check := range serviceQuotasChecks {
+		checks = append(checks, check)
+	}
+	for _, check := range serviceDefaultChecks {
+		checks = append(checks, check)
+	}
+	// the names label the metrics of the checks
+	names := map[string]bool{}
+	for _, check := range checks {
+		name := checkName(check)
+		assert.False(t, names[name], "%s is the name of more than one check", name)
+		names[name] = true
+	}
+	assert.True(t, names["LoadBalancersPerRegionCheck/application"])
+	assert.True(t, names["APIsPerRegionCheck/http"])
+}
+
+func TestOtherUsageChecksHaveService(t *testing.T) {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("eu-west-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	}))
+	cfg := aws.NewConfig()
+	_, _, otherChecks, _ := newUsageChecks(Options{LogStreamsPerLogGroup: true}, sess, cfg, cfg, "123456789012", nil, nil)
+
+	for _, check := range otherChecks {
+		assert.NotEmpty(t, otherUsageCheckService(check), "%T must be mapped to its service in otherUsageCheckService", check)
+	}
+}
+
+func TestUsageChecksHaveService(t *testing.T) {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("eu-west-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	}))
+	cfg := aws.NewConfig()
+	serviceQuotasChecks, serviceDefaultChecks, _, quotaServices := newUsageChecks(Options{APIGatewayStagesPerAPI: true}, sess, cfg, cfg, "123456789012", nil, nil)
+
+	for _, checks := range []map[string]UsageCheck{serviceQuotasChecks, serviceDefaultChecks} {
+		for quotaCode := range checks {
+			assert.True(t, isKnownService(quotaServices[quotaCode]), "quota %s must be registered under a known service", quotaCode)
+		}
+	}
+	assert.Equal(t, "glue", quotaServices["L-EEC98450"])
+	assert.Equal(t, "ecr", quotaServices["L-03A36CE1"])
+}
+
+func TestNewUsageChecksWithSkipGlobalChecks(t *testing.T) {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("eu-west-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	}))
+	cfg := aws.NewConfig()
+
+	_, serviceDefaultChecks, otherChecks, _ := newUsageChecks(Options{}, sess, cfg, cfg, "123456789012", nil, nil)
+	assert.Contains(t, serviceDefaultChecks, "L-24B04930")
+	globalChecks := 0
+	for _, check := range otherChecks {
+		if otherUsageCheckService(check) == "route53" {
+			globalChecks++
+		}
+	}
+	assert.NotZero(t, globalChecks)
+
+	_, serviceDefaultChecks, otherChecks, _ = newUsageChecks(Options{SkipGlobalChecks: true}, sess, cfg, cfg, "123456789012", nil, nil)
+	assert.NotContains(t, serviceDefaultChecks, "L-24B04930")
+	for _, check := range otherChecks {
+		assert.NotEqual(t, "route53", otherUsageCheckService(check), "%T checks a global service", check)
+	}
+}
+
+func TestNewUsageChecksWithAPIGatewayStagesPerAPI(t *testing.T) {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("eu-west-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	}))
+	cfg := aws.NewConfig()
+
+	serviceQuotasChecks, _, _, _ := newUsageChecks(Options{}, sess, cfg, cfg, "123456789012", nil, nil)
+	assert.Contains(t, serviceQuotasChecks, "L-8A5B8E43")
+	assert.NotContains(t, serviceQuotasChecks, "L-379E48B0")
+
+	serviceQuotasChecks, _, _, _ = newUsageChecks(Options{APIGatewayStagesPerAPI: true}, sess, cfg, cfg, "123456789012", nil, nil)
+	assert.Contains(t, serviceQuotasChecks, "L-379E48B0")
+}
+
+func TestServiceRegions(t *testing.T) {
+	regions := serviceRegions(Options{ServiceRegions: map[string]string{"logs": "us-west-2"}}, "us-east-1")
+	assert.Equal(t, map[string]string{"logs": "us-west-2", "cloudfront": "us-east-1"}, regions)
+
+	regions = serviceRegions(Options{ServiceRegions: map[string]string{"cloudfront": "us-west-2"}}, "us-east-1")
+	assert.Equal(t, map[string]string{"cloudfront": "us-west-2"}, regions)
+
+	regions = serviceRegions(Options{SkipGlobalChecks: true}, "us-east-1")
+	assert.Empty(t, regions)
+}
+
+func TestResolveAccountID(t *testing.T) {
+	account := &accountID{client: &mockSTSClient{
+		GetCallerIdentityResponse: &sts.GetCallerIdentityOutput{Account: aws.String("123456789012")},
+	}}
+
+	assert.Equal(t, "123456789012", resolveAccountID(account, ResourceIdentifierARN))
+}
+
+func TestResolveAccountIDWithDeniedSTS(t *testing.T) {
+	stsClient := &mockSTSClient{err: awserr.New("AccessDenied", "not authorized to perform sts:GetCallerIdentity", nil)}
+
+	accountID := resolveAccountID(&accountID{client: stsClient}, ResourceIdentifierARN)
+
+	assert.Empty(t, accountID)
+	assert.Equal(t, 1, stsClient.GetCallerIdentityCalls)
+}
+
+func TestNewUsageChecksWithoutAccountID(t *testing.T) {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("eu-west-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	}))
+	cfg := aws.NewConfig()
+
+	_, serviceDefaultChecks, _, _ := newUsageChecks(Options{}, sess, cfg, cfg, "", nil, nil)
+
+	for code, check := range serviceDefaultChecks {
+		switch check.(type) {
+		case *AccessPointsPerAccountCheck, *MultiRegionAccessPointsCheck:
+			assert.Fail(t, "S3 Control check created without account ID", "%s: %T", code, check)
+		}
+	}
+	_, ok := serviceDefaultChecks["L-DC2B2D3D"]
+	assert.True(t, ok, "the S3 buckets check does not need the account ID")
+}
+
+func TestIdentifyResourcesWithoutAccountID(t *testing.T) {
+	serviceQuotas := &ServiceQuotas{}
+	usages := []QuotaUsage{{Name: availableIPsPerSubnetName, ResourceName: aws.String("subnet-1"), Region: "eu-west-1"}}
+
+	identified := serviceQuotas.identifyResources(usages)
+
+	assert.Equal(t, "subnet-1", *identified[0].ResourceName)
+}
+
+func TestQuotasAndUsageForService(t *testing.T) {
+	mockClient := &mockServiceQuotasClient{
+		serviceName: "ec2",
+		ListServiceQuotasResponse: &awsservicequotas.ListServiceQuotasOutput{
+			Quotas: []*awsservicequotas.ServiceQuota{
+				{QuotaCode: aws.String("L-1234"), Value: aws.Float64(15)},
+			},
+		},
+	}
+	spotCheck := &SpotInstanceRequestsCountCheck{&mockEC2Client{
+		DescribeSpotInstanceRequestsResponse: &ec2.DescribeSpotInstanceRequestsOutput{},
+	}}
+	asgCheck := &FakeUsageCheck{Usages: []QuotaUsage{{Name: "instances_per_asg"}}}
+
+	serviceQuotas := ServiceQuotas{
+		region:                    "eu-west-1",
+		quotasService:             mockClient,
+		serviceQuotasUsageChecks:  map[string]UsageCheck{"L-1234": &UsageCheckMock{usages: []QuotaUsage{{Name: "some_quota", Usage: 5}}}},
+		serviceDefaultUsageChecks: map[string]UsageCheck{},
+		otherUsageChecks:          []UsageCheck{spotCheck, asgCheck},
+	}
+	usages, err := serviceQuotas.QuotasAndUsageForService("ec2")
+
+	assert.NoError(t, err)
+	assert.Len(t, usages, 2)
+	assert.Equal(t, "some_quota", usages[0].Name)
+	assert.Equal(t, float64(15), usages[0].Quota)
+	assert.Equal(t, spotInstanceRequestsCountName, usages[1].Name)
+	assert.Equal(t, 0, asgCheck.Calls())
+	// the applied and default quotas are only listed for ec2
+	assert.Equal(t, 1, mockClient.timesCalled)
+}
+
+func TestQuotasAndUsageForServiceWithOtherChecksOnly(t *testing.T) {
+	mockClient := &mockServiceQuotasClient{}
+	asgCheck := &ASGUsageCheck{&mockAutoScalingClient{
+		DescribeAutoScalingGroupsResponse: &autoscaling.DescribeAutoScalingGroupsOutput{},
+	}}
+
+	serviceQuotas := ServiceQuotas{
+		quotasService:    mockClient,
+		otherUsageChecks: []UsageCheck{asgCheck},
+	}
+	usages, err := serviceQuotas.QuotasAndUsageForService("autoscaling")
+
+	assert.NoError(t, err)
+	assert.Empty(t, usages)
+	assert.Equal(t, 0, mockClient.timesCalled)
+}
+
+func TestQuotasAndUsageForServiceWithInvalidService(t *testing.T) {
+	serviceQuotas := ServiceQuotas{quotasService: &mockServiceQuotasClient{}}
+	usages, err := serviceQuotas.QuotasAndUsageForService("unknown")
+
+	assert.True(t, errors.Is(err, ErrInvalidService))
+	assert.Nil(t, usages)
+}
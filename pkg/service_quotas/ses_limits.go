@@ -1,9 +1,10 @@
 package servicequotas
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/service/sesv2"
 	"github.com/aws/aws-sdk-go/service/sesv2/sesv2iface"
-	"github.com/pkg/errors"
 )
 
 const (
@@ -15,14 +16,14 @@ type MaxSendIn24HoursCheck struct {
 	client sesv2iface.SESV2API
 }
 
-func (c *MaxSendIn24HoursCheck) Usage() ([]QuotaUsage, error) {
+func (c *MaxSendIn24HoursCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
 	quotaUsages := []QuotaUsage{}
 
 	params := &sesv2.GetAccountInput{}
-	response, err := c.client.GetAccount(params)
+	response, err := c.client.GetAccountWithContext(ctx, params)
 	if err != nil {
 		log.Error("Failed to get SES Account")
-		return nil, errors.Wrapf(ErrFailedToGetUsage, "%w", err)
+		return nil, wrapUsageErr(err)
 	} else {
 		usage := QuotaUsage{
 			Name:        maxSendIn24HoursName,
@@ -34,3 +35,8 @@ func (c *MaxSendIn24HoursCheck) Usage() ([]QuotaUsage, error) {
 	}
 	return quotaUsages, nil
 }
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *MaxSendIn24HoursCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: maxSendIn24HoursName, Description: maxSendIn24HoursDescription}}
+}
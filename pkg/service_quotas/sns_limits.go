@@ -0,0 +1,133 @@
+package servicequotas
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+)
+
+const (
+	topicsPerAccountName        = "sns_topics_per_account"
+	topicsPerAccountDescription = "SNS topics per account"
+
+	subscriptionsPerTopicName        = "sns_subscriptions_per_topic"
+	subscriptionsPerTopicDescription = "SNS subscriptions per topic"
+
+	// SNS documents a limit of 12,500,000 subscriptions per topic that
+	// is not published in service quotas
+	maxSubscriptionsPerTopic = 12500000
+)
+
+// TopicsPerAccountCheck implements the UsageCheck interface for SNS
+// topics per account
+type TopicsPerAccountCheck struct {
+	client snsiface.SNSAPI
+}
+
+// Usage returns the number of SNS topics in the region of the client
+// or an error
+func (c *TopicsPerAccountCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var topicsCount int
+
+	err := c.client.ListTopicsPagesWithContext(ctx, &sns.ListTopicsInput{},
+		func(page *sns.ListTopicsOutput, lastPage bool) bool {
+			if page != nil {
+				topicsCount += len(page.Topics)
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := []QuotaUsage{
+		{
+			Name:        topicsPerAccountName,
+			Description: topicsPerAccountDescription,
+			Usage:       float64(topicsCount),
+		},
+	}
+	return usage, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *TopicsPerAccountCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: topicsPerAccountName, Description: topicsPerAccountDescription}}
+}
+
+// SubscriptionsPerTopicCheck implements the UsageCheck interface for
+// SNS subscriptions per topic
+type SubscriptionsPerTopicCheck struct {
+	client       snsiface.SNSAPI
+	maxResources int
+	sampler      resourceSampler
+}
+
+// Usage returns the number of subscriptions of each SNS topic, keyed
+// by topic ARN, with the documented limit as the quota or an error. The subscriptions are listed with one
+// ListSubscriptionsByTopic call per topic, which can be spread over
+// several refreshes when sampling
+func (c *SubscriptionsPerTopicCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var topicARNs []string
+	topicsCap := &resourceCap{max: c.maxResources}
+
+	err := c.client.ListTopicsPagesWithContext(ctx, &sns.ListTopicsInput{},
+		func(page *sns.ListTopicsOutput, lastPage bool) bool {
+			if page == nil {
+				return !lastPage
+			}
+			for _, topic := range page.Topics {
+				topicARNs = append(topicARNs, aws.StringValue(topic.TopicArn))
+			}
+			return topicsCap.add(len(page.Topics), lastPage)
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	quotaUsages := []QuotaUsage{}
+	c.sampler.start()
+	for _, topicARN := range topicARNs {
+		if previous, ok := c.sampler.skip(topicARN); ok {
+			quotaUsages = append(quotaUsages, previous)
+			continue
+		}
+
+		var subscriptionsCount int
+		params := &sns.ListSubscriptionsByTopicInput{TopicArn: aws.String(topicARN)}
+		err := c.client.ListSubscriptionsByTopicPagesWithContext(ctx, params,
+			func(page *sns.ListSubscriptionsByTopicOutput, lastPage bool) bool {
+				if page != nil {
+					subscriptionsCount += len(page.Subscriptions)
+				}
+				return !lastPage
+			},
+		)
+		if err != nil {
+			return nil, wrapUsageErr(err)
+		}
+
+		usage := QuotaUsage{
+			Name:         subscriptionsPerTopicName,
+			ResourceName: aws.String(topicARN),
+			Description:  subscriptionsPerTopicDescription,
+			Usage:        float64(subscriptionsCount),
+			Quota:        maxSubscriptionsPerTopic,
+		}
+		c.sampler.record(topicARN, usage)
+		quotaUsages = append(quotaUsages, usage)
+	}
+	c.sampler.finish()
+
+	topicsCap.markTruncated(quotaUsages)
+	return quotaUsages, nil
+}
+
+// DescribeResourceUsage implements the ResourceUsageDescriber interface
+func (c *SubscriptionsPerTopicCheck) DescribeResourceUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: subscriptionsPerTopicName, Description: subscriptionsPerTopicDescription, Quota: maxSubscriptionsPerTopic}}
+}
@@ -0,0 +1,173 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockSNSClient) ListTopicsPagesWithContext(ctx aws.Context, input *sns.ListTopicsInput, fn func(*sns.ListTopicsOutput, bool) bool, opts ...request.Option) error {
+	for i, page := range m.ListTopicsResponses {
+		if !fn(page, i == len(m.ListTopicsResponses)-1) {
+			break
+		}
+	}
+	return m.err
+}
+
+func (m *mockSNSClient) ListSubscriptionsByTopicPagesWithContext(ctx aws.Context, input *sns.ListSubscriptionsByTopicInput, fn func(*sns.ListSubscriptionsByTopicOutput, bool) bool, opts ...request.Option) error {
+	m.ListSubscriptionsByTopicInputs = append(m.ListSubscriptionsByTopicInputs, input)
+	fn(m.ListSubscriptionsByTopicResponses[*input.TopicArn], true)
+	return m.err
+}
+
+func topics(arns ...string) *sns.ListTopicsOutput {
+	output := &sns.ListTopicsOutput{}
+	for _, arn := range arns {
+		output.Topics = append(output.Topics, &sns.Topic{TopicArn: aws.String(arn)})
+	}
+	return output
+}
+
+func subscriptions(count int) *sns.ListSubscriptionsByTopicOutput {
+	output := &sns.ListSubscriptionsByTopicOutput{}
+	for i := 0; i < count; i++ {
+		output.Subscriptions = append(output.Subscriptions, &sns.Subscription{Protocol: aws.String("sqs")})
+	}
+	return output
+}
+
+func TestTopicsPerAccountCheckWithError(t *testing.T) {
+	mockClient := &mockSNSClient{
+		err: errors.New("some err"),
+	}
+
+	check := TopicsPerAccountCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestTopicsPerAccountCheck(t *testing.T) {
+	mockClient := &mockSNSClient{
+		ListTopicsResponses: []*sns.ListTopicsOutput{
+			topics("arn:aws:sns:eu-west-1:123456789012:topic-1", "arn:aws:sns:eu-west-1:123456789012:topic-2"),
+			topics("arn:aws:sns:eu-west-1:123456789012:topic-3"),
+		},
+	}
+
+	check := TopicsPerAccountCheck{mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        topicsPerAccountName,
+			Description: topicsPerAccountDescription,
+			Usage:       3,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestSubscriptionsPerTopicCheckWithError(t *testing.T) {
+	mockClient := &mockSNSClient{
+		err: errors.New("some err"),
+	}
+
+	check := SubscriptionsPerTopicCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestSubscriptionsPerTopicCheck(t *testing.T) {
+	mockClient := &mockSNSClient{
+		ListTopicsResponses: []*sns.ListTopicsOutput{
+			topics("arn:aws:sns:eu-west-1:123456789012:topic-1", "arn:aws:sns:eu-west-1:123456789012:topic-2"),
+		},
+		ListSubscriptionsByTopicResponses: map[string]*sns.ListSubscriptionsByTopicOutput{
+			"arn:aws:sns:eu-west-1:123456789012:topic-1": subscriptions(3),
+			"arn:aws:sns:eu-west-1:123456789012:topic-2": subscriptions(0),
+		},
+	}
+
+	check := SubscriptionsPerTopicCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:         subscriptionsPerTopicName,
+			ResourceName: aws.String("arn:aws:sns:eu-west-1:123456789012:topic-1"),
+			Description:  subscriptionsPerTopicDescription,
+			Usage:        3,
+			Quota:        maxSubscriptionsPerTopic,
+		},
+		{
+			Name:         subscriptionsPerTopicName,
+			ResourceName: aws.String("arn:aws:sns:eu-west-1:123456789012:topic-2"),
+			Description:  subscriptionsPerTopicDescription,
+			Usage:        0,
+			Quota:        maxSubscriptionsPerTopic,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+}
+
+func TestSubscriptionsPerTopicCheckWithMaxResources(t *testing.T) {
+	mockClient := &mockSNSClient{
+		ListTopicsResponses: []*sns.ListTopicsOutput{
+			topics("arn:aws:sns:eu-west-1:123456789012:topic-1", "arn:aws:sns:eu-west-1:123456789012:topic-2"),
+			topics("arn:aws:sns:eu-west-1:123456789012:topic-3"),
+		},
+		ListSubscriptionsByTopicResponses: map[string]*sns.ListSubscriptionsByTopicOutput{
+			"arn:aws:sns:eu-west-1:123456789012:topic-1": subscriptions(1),
+			"arn:aws:sns:eu-west-1:123456789012:topic-2": subscriptions(2),
+		},
+	}
+
+	check := SubscriptionsPerTopicCheck{client: mockClient, maxResources: 2}
+	usage, err := check.Usage(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, usage, 2)
+	for _, u := range usage {
+		assert.True(t, u.Truncated)
+	}
+	assert.Len(t, mockClient.ListSubscriptionsByTopicInputs, 2)
+}
+
+func TestSubscriptionsPerTopicCheckSampling(t *testing.T) {
+	mockClient := &mockSNSClient{
+		ListTopicsResponses: []*sns.ListTopicsOutput{
+			topics("arn:aws:sns:eu-west-1:123456789012:topic-1", "arn:aws:sns:eu-west-1:123456789012:topic-2"),
+		},
+		ListSubscriptionsByTopicResponses: map[string]*sns.ListSubscriptionsByTopicOutput{
+			"arn:aws:sns:eu-west-1:123456789012:topic-1": subscriptions(1),
+			"arn:aws:sns:eu-west-1:123456789012:topic-2": subscriptions(2),
+		},
+	}
+	check := SubscriptionsPerTopicCheck{client: mockClient, sampler: resourceSampler{interval: 2}}
+
+	usage, err := check.Usage(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, usage, 2)
+	assert.Len(t, mockClient.ListSubscriptionsByTopicInputs, 2)
+
+	usage, err = check.Usage(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, usage, 2)
+	assert.Len(t, mockClient.ListSubscriptionsByTopicInputs, 3)
+}
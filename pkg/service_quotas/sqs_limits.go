@@ -0,0 +1,98 @@
+package servicequotas
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+)
+
+const (
+	queuesPerRegionName        = "sqs_queues_per_region"
+	queuesPerRegionDescription = "SQS queues per region"
+)
+
+// listQueuesPageSize is the maximum number of queues returned by each
+// ListQueues call. ListQueues only paginates when MaxResults is set,
+// otherwise it returns the first 1000 queues
+const listQueuesPageSize = 1000
+
+// QueuesPerAccountCheck implements the UsageCheck interface for SQS
+// queues per region
+type QueuesPerAccountCheck struct {
+	client sqsiface.SQSAPI
+	// includedTags are the tag keys exported as labels, the tags of
+	// the queues are only retrieved when it is set
+	includedTags []string
+}
+
+// Usage returns the number of SQS queues in the region of the client
+// or an error. SQS publishes no quota for the number of queues, so only
+// the usage is reported. The usage is for the whole region, so the
+// included tags are only attached when every queue has the same value
+// for them
+func (c *QueuesPerAccountCheck) Usage(ctx context.Context) ([]QuotaUsage, error) {
+	var queueURLs []*string
+
+	params := &sqs.ListQueuesInput{MaxResults: aws.Int64(listQueuesPageSize)}
+	err := c.client.ListQueuesPagesWithContext(ctx, params,
+		func(page *sqs.ListQueuesOutput, lastPage bool) bool {
+			if page != nil {
+				queueURLs = append(queueURLs, page.QueueUrls...)
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapUsageErr(err)
+	}
+
+	usage := QuotaUsage{
+		Name:        queuesPerRegionName,
+		Description: queuesPerRegionDescription,
+		Usage:       float64(len(queueURLs)),
+	}
+	if len(c.includedTags) > 0 && len(queueURLs) > 0 {
+		usage.Tags = c.commonTags(ctx, queueURLs)
+	}
+	return []QuotaUsage{usage}, nil
+}
+
+// DescribeUsage implements the UsageDescriber interface
+func (c *QueuesPerAccountCheck) DescribeUsage() []QuotaUsage {
+	return []QuotaUsage{{Name: queuesPerRegionName, Description: queuesPerRegionDescription}}
+}
+
+// commonTags returns the included tags with the same value on all
+// `queueURLs`, keyed like the tags of the other checks
+func (c *QueuesPerAccountCheck) commonTags(ctx context.Context, queueURLs []*string) map[string]string {
+	var common map[string]string
+	for _, queueURL := range queueURLs {
+		common = keepCommonTags(common, c.queueTags(ctx, queueURL))
+	}
+
+	if len(common) == 0 {
+		return nil
+	}
+	return common
+}
+
+// queueTags returns the included tags of the queue `queueURL`. The
+// tags of a queue that cannot be read are treated as missing
+func (c *QueuesPerAccountCheck) queueTags(ctx context.Context, queueURL *string) map[string]string {
+	tags := map[string]string{}
+
+	output, err := c.client.ListQueueTagsWithContext(ctx, &sqs.ListQueueTagsInput{QueueUrl: queueURL})
+	if err != nil {
+		log.Warnf("Failed to get the tags of SQS queue %s: %s", aws.StringValue(queueURL), err)
+		return tags
+	}
+
+	for _, included := range c.includedTags {
+		if value, ok := output.Tags[included]; ok {
+			tags[ToPrometheusNamingFormat(included)] = aws.StringValue(value)
+		}
+	}
+	return tags
+}
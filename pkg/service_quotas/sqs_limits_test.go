@@ -0,0 +1,122 @@
+package servicequotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *mockSQSClient) ListQueuesPagesWithContext(ctx aws.Context, input *sqs.ListQueuesInput, fn func(*sqs.ListQueuesOutput, bool) bool, opts ...request.Option) error {
+	m.ListQueuesInput = input
+	for i, page := range m.ListQueuesResponses {
+		if !fn(page, i == len(m.ListQueuesResponses)-1) {
+			break
+		}
+	}
+	return m.err
+}
+
+func (m *mockSQSClient) ListQueueTagsWithContext(ctx aws.Context, input *sqs.ListQueueTagsInput, opts ...request.Option) (*sqs.ListQueueTagsOutput, error) {
+	m.ListQueueTagsCalls++
+	if response, ok := m.ListQueueTagsResponses[*input.QueueUrl]; ok {
+		return response, nil
+	}
+	return &sqs.ListQueueTagsOutput{}, nil
+}
+
+func testQueues(urls ...string) *sqs.ListQueuesOutput {
+	return &sqs.ListQueuesOutput{QueueUrls: aws.StringSlice(urls)}
+}
+
+func TestQueuesPerAccountCheckWithError(t *testing.T) {
+	mockClient := &mockSQSClient{
+		err: errors.New("some err"),
+	}
+
+	check := QueuesPerAccountCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToGetUsage))
+	assert.Nil(t, usage)
+}
+
+func TestQueuesPerAccountCheck(t *testing.T) {
+	mockClient := &mockSQSClient{
+		ListQueuesResponses: []*sqs.ListQueuesOutput{
+			testQueues("https://sqs.eu-west-1.amazonaws.com/123456789012/queue1", "https://sqs.eu-west-1.amazonaws.com/123456789012/queue2"),
+			testQueues("https://sqs.eu-west-1.amazonaws.com/123456789012/queue3"),
+		},
+	}
+
+	check := QueuesPerAccountCheck{client: mockClient}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        queuesPerRegionName,
+			Description: queuesPerRegionDescription,
+			Usage:       3,
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+	assert.Equal(t, aws.Int64(listQueuesPageSize), mockClient.ListQueuesInput.MaxResults)
+	assert.Equal(t, 0, mockClient.ListQueueTagsCalls)
+}
+
+func TestQueuesPerAccountCheckWithTags(t *testing.T) {
+	mockClient := &mockSQSClient{
+		ListQueuesResponses: []*sqs.ListQueuesOutput{
+			testQueues("https://sqs.eu-west-1.amazonaws.com/123456789012/queue1", "https://sqs.eu-west-1.amazonaws.com/123456789012/queue2"),
+		},
+		ListQueueTagsResponses: map[string]*sqs.ListQueueTagsOutput{
+			"https://sqs.eu-west-1.amazonaws.com/123456789012/queue1": {
+				Tags: aws.StringMap(map[string]string{"account-owner": "platform", "team": "a", "other": "x"}),
+			},
+			"https://sqs.eu-west-1.amazonaws.com/123456789012/queue2": {
+				Tags: aws.StringMap(map[string]string{"account-owner": "platform", "team": "b"}),
+			},
+		},
+	}
+
+	check := QueuesPerAccountCheck{mockClient, []string{"account-owner", "team"}}
+	usage, err := check.Usage(context.Background())
+
+	expectedUsage := []QuotaUsage{
+		{
+			Name:        queuesPerRegionName,
+			Description: queuesPerRegionDescription,
+			Usage:       2,
+			Tags:        map[string]string{"account_owner": "platform"},
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUsage, usage)
+	assert.Equal(t, 2, mockClient.ListQueueTagsCalls)
+}
+
+func TestQueuesPerAccountCheckWithUntaggedQueue(t *testing.T) {
+	mockClient := &mockSQSClient{
+		ListQueuesResponses: []*sqs.ListQueuesOutput{
+			testQueues("https://sqs.eu-west-1.amazonaws.com/123456789012/queue1", "https://sqs.eu-west-1.amazonaws.com/123456789012/queue2"),
+		},
+		ListQueueTagsResponses: map[string]*sqs.ListQueueTagsOutput{
+			"https://sqs.eu-west-1.amazonaws.com/123456789012/queue1": {Tags: aws.StringMap(map[string]string{"team": "a"})},
+		},
+	}
+
+	check := QueuesPerAccountCheck{mockClient, []string{"team"}}
+	usage, err := check.Usage(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, usage, 1)
+	assert.Nil(t, usage[0].Tags)
+}